@@ -0,0 +1,325 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+// Campaigns are imported and exported as CSV or as newline-delimited JSON,
+// with a campaign per line in the form ReadCampaign returns it. CSV columns
+// are named after the JSON fields; radius circles, lists, segments, locales
+// and labels are written as JSON arrays within their cells.
+
+const (
+	csvContentType    = "text/csv"
+	ndjsonContentType = "application/x-ndjson"
+	maxImportRows     = 10000
+	maxImportLineSize = 1 << 20
+)
+
+// campaignColumns are the CSV columns of a campaign, in the order exported.
+var campaignColumns = []string{
+	"advertiser_id", "cid", "name", "img", "cta",
+	"app_id", "app_rule", "country", "country_rule", "os", "os_rule",
+	"os_version", "os_version_rule", "app_version", "app_version_rule",
+	"language", "language_rule", "region", "region_rule", "city", "city_rule",
+	"category", "category_rule", "keyword", "keyword_rule",
+	"radius", "target_lists", "segments", "locales", "labels",
+	"status", "created_at", "version",
+}
+
+// jsonColumns hold JSON values rather than plain text.
+var jsonColumns = []string{"advertiser_id", "radius", "target_lists", "segments", "locales", "labels", "version"}
+
+// ignoredColumns are exported but not imported.
+var ignoredColumns = []string{"advertiser_id", "created_at", "version"}
+
+// customMethods routes custom methods such as /campaigns:import to their
+// handlers by name. Gin cannot match a colon within a path segment literally,
+// so the method is routed as a parameter named method and looked up here.
+func customMethods(handlers map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		name, ok := strings.CutPrefix(ctx.Param("method"), ":")
+		handler := handlers[name]
+		if !ok || handler == nil {
+			abortWithError(ctx, http.StatusNotFound, "resource not found")
+			return
+		}
+		handler(ctx)
+	}
+}
+
+// decodeNDJSON reads a campaign per line, skipping blank lines. Lines that
+// are not a campaign are reported as row errors.
+func decodeNDJSON(body io.Reader) ([]db.ImportCampaignRow, []db.ImportRowError, error) {
+	rows := []db.ImportCampaignRow{}
+	rowErrors := []db.ImportRowError{}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, maxImportLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		if len(rows)+len(rowErrors) == maxImportRows {
+			return nil, nil, fmt.Errorf("imports are limited to %d rows", maxImportRows)
+		}
+
+		var campaign db.CompleteCampaign
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&campaign); err != nil {
+			rowErrors = append(rowErrors, db.ImportRowError{Row: line, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, db.ImportCampaignRow{Row: line, Campaign: campaign})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return rows, rowErrors, nil
+}
+
+// decodeCSV reads a header naming the columns, followed by a campaign per
+// record. Rows are numbered from the first record after the header.
+func decodeCSV(body io.Reader) ([]db.ImportCampaignRow, []db.ImportRowError, error) {
+	reader := csv.NewReader(body)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, errors.New("the header row is missing")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, column := range header {
+		if !slices.Contains(campaignColumns, column) {
+			return nil, nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+	if !slices.Contains(header, "cid") {
+		return nil, nil, errors.New("the cid column is missing")
+	}
+
+	rows := []db.ImportCampaignRow{}
+	rowErrors := []db.ImportRowError{}
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if row > maxImportRows {
+			return nil, nil, fmt.Errorf("imports are limited to %d rows", maxImportRows)
+		}
+		if errors.Is(err, csv.ErrFieldCount) {
+			rowErrors = append(rowErrors, db.ImportRowError{Row: row, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		campaign, err := csvCampaign(header, record)
+		if err != nil {
+			rowErrors = append(rowErrors, db.ImportRowError{Row: row, Cid: record[slices.Index(header, "cid")], Error: err.Error()})
+			continue
+		}
+		rows = append(rows, db.ImportCampaignRow{Row: row, Campaign: campaign})
+	}
+	return rows, rowErrors, nil
+}
+
+// csvCampaign turns a record into the JSON form of a campaign and decodes it.
+func csvCampaign(header []string, record []string) (db.CompleteCampaign, error) {
+	fields := make(map[string]json.RawMessage, len(header))
+	for i, column := range header {
+		cell := record[i]
+		switch {
+		case slices.Contains(ignoredColumns, column):
+			continue
+		case slices.Contains(jsonColumns, column):
+			if cell == "" {
+				continue
+			}
+			if !json.Valid([]byte(cell)) {
+				return db.CompleteCampaign{}, fmt.Errorf("%s is not valid JSON", column)
+			}
+			fields[column] = json.RawMessage(cell)
+		default:
+			value, err := json.Marshal(cell)
+			if err != nil {
+				return db.CompleteCampaign{}, err
+			}
+			fields[column] = value
+		}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return db.CompleteCampaign{}, err
+	}
+	var campaign db.CompleteCampaign
+	if err := json.Unmarshal(data, &campaign); err != nil {
+		return db.CompleteCampaign{}, err
+	}
+	return campaign, nil
+}
+
+// campaignRecord is the inverse of csvCampaign.
+func campaignRecord(campaign db.CompleteCampaign) ([]string, error) {
+	data, err := json.Marshal(campaign)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	record := make([]string, len(campaignColumns))
+	for i, column := range campaignColumns {
+		if slices.Contains(jsonColumns, column) {
+			record[i] = string(fields[column])
+			continue
+		}
+		if err := json.Unmarshal(fields[column], &record[i]); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+type importCampaignsRequest struct {
+	Mode string `binding:"omitempty,oneof=partial all_or_nothing" form:"mode"`
+}
+
+// importCampaigns creates the campaigns of a CSV or NDJSON file with their
+// targeting, reporting the rows it could not import.
+func (s *Server) importCampaigns(ctx *gin.Context) {
+	var req importCampaignsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	decode := decodeNDJSON
+	mediaType, _, err := mime.ParseMediaType(ctx.ContentType())
+	switch {
+	case err == nil && mediaType == csvContentType:
+		decode = decodeCSV
+	case err == nil && (mediaType == ndjsonContentType || mediaType == gin.MIMEJSON):
+	default:
+		abortWithError(ctx, http.StatusUnsupportedMediaType, "imports must be sent as "+csvContentType+" or "+ndjsonContentType)
+		return
+	}
+
+	rows, rowErrors, err := decode(ctx.Request.Body)
+	if err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	allOrNothing := req.Mode == "all_or_nothing"
+	result := db.ImportCampaignsResult{Errors: rowErrors}
+	if !allOrNothing || len(rowErrors) == 0 {
+		imported, err := s.store.ImportCampaigns(ctx.Request.Context(), db.ImportCampaignsParams{
+			AdvertiserID: authAdvertiserID(ctx),
+			Rows:         rows,
+			AllOrNothing: allOrNothing,
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		result.Imported = imported.Imported
+		result.Errors = append(result.Errors, imported.Errors...)
+		slices.SortStableFunc(result.Errors, func(a, b db.ImportRowError) int {
+			return a.Row - b.Row
+		})
+	}
+
+	if allOrNothing && len(result.Errors) > 0 {
+		ctx.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+type exportCampaignsRequest struct {
+	Format string `binding:"omitempty,oneof=csv ndjson" form:"format"`
+}
+
+// exportCampaigns streams every campaign of the caller as CSV or NDJSON, in
+// the form importCampaigns reads.
+func (s *Server) exportCampaigns(ctx *gin.Context) {
+	req := exportCampaignsRequest{Format: "ndjson"}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var csvWriter *csv.Writer
+	started := false
+	// The response only starts with the first campaign, so that failing to
+	// read the campaigns still gets an error response.
+	start := func() error {
+		started = true
+		ctx.Header("Content-Disposition", "attachment; filename=campaigns."+req.Format)
+		if req.Format == "ndjson" {
+			ctx.Header("Content-Type", ndjsonContentType)
+			ctx.Status(http.StatusOK)
+			return nil
+		}
+		ctx.Header("Content-Type", csvContentType+"; charset=utf-8")
+		ctx.Status(http.StatusOK)
+		csvWriter = csv.NewWriter(ctx.Writer)
+		return csvWriter.Write(campaignColumns)
+	}
+
+	encoder := json.NewEncoder(ctx.Writer)
+	err := s.store.ExportCampaigns(ctx.Request.Context(), authAdvertiserID(ctx), func(campaign db.CompleteCampaign) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if csvWriter == nil {
+			if err := encoder.Encode(campaign); err != nil {
+				return err
+			}
+		} else {
+			record, err := campaignRecord(campaign)
+			if err != nil {
+				return err
+			}
+			if err := csvWriter.Write(record); err != nil {
+				return err
+			}
+			csvWriter.Flush()
+		}
+		ctx.Writer.Flush()
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if csvWriter != nil {
+		csvWriter.Flush()
+	}
+	if err != nil {
+		// Once the response has started, the error is only logged.
+		ctx.Error(err)
+	}
+}
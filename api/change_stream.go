@@ -0,0 +1,94 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+const (
+	lastEventIDHeaderKey = "Last-Event-ID"
+	// heartbeatInterval keeps proxies from closing streams with no changes.
+	heartbeatInterval = 15 * time.Second
+)
+
+type streamEventsRequest struct {
+	Cid    string   `form:"cid"`
+	Events []string `binding:"dive,oneof=campaign.created campaign.updated campaign.status_changed targeting.updated campaign.deleted" form:"event"`
+}
+
+// streamEvents streams the campaign and targeting changes of the caller as
+// server-sent events, each with the ID of its history entry. Clients
+// reconnecting with a Last-Event-ID header first get the changes they missed;
+// a reset event tells them that more were missed than can be replayed.
+func (s *Server) streamEvents(ctx *gin.Context) {
+	var req streamEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var lastEventID int64
+	if header := ctx.GetHeader(lastEventIDHeaderKey); header != "" {
+		id, err := strconv.ParseInt(header, 10, 32)
+		if err != nil || id < 0 {
+			abortWithError(ctx, http.StatusBadRequest, "Last-Event-ID must be the id of an event")
+			return
+		}
+		lastEventID = id
+	}
+
+	subscription, err := s.store.SubscribeChanges(ctx.Request.Context(), db.SubscribeChangesParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Events:       req.Events,
+		LastEventID:  int32(lastEventID),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Content-Type", sse.ContentType)
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	if subscription.Truncated {
+		ctx.Render(-1, sse.Event{
+			Event: "reset",
+			Data:  gin.H{"message": "more changes were missed than can be replayed, reload the campaigns"},
+		})
+	}
+	for _, event := range subscription.Replay {
+		renderChangeEvent(ctx, event)
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return false
+			}
+			renderChangeEvent(ctx, event)
+		case <-heartbeat.C:
+			io.WriteString(w, ": heartbeat\n\n")
+		}
+		return true
+	})
+}
+
+func renderChangeEvent(ctx *gin.Context, event db.ChangeEvent) {
+	ctx.Render(-1, sse.Event{
+		Id:    strconv.Itoa(int(event.ID)),
+		Event: event.Event,
+		Data:  event,
+	})
+}
@@ -0,0 +1,101 @@
+package api_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+// changeStore replays the changes after the last event ID it is given, and
+// then streams live and closes the stream, as when a client falls behind.
+type changeStore struct {
+	*fakeStore
+	arg  db.SubscribeChangesParams
+	live []db.ChangeEvent
+}
+
+func (store *changeStore) SubscribeChanges(ctx context.Context, arg db.SubscribeChangesParams) (db.ChangeSubscription, error) {
+	store.arg = arg
+	events := make(chan db.ChangeEvent, len(store.live))
+	for _, event := range store.live {
+		events <- event
+	}
+	close(events)
+	return db.ChangeSubscription{
+		Replay: []db.ChangeEvent{
+			changeEvent(42, db.ChangeCampaignStatusChanged, "status"),
+		},
+		Truncated: arg.LastEventID > 0,
+		Events:    events,
+	}, nil
+}
+
+func changeEvent(id int32, event string, field string) db.ChangeEvent {
+	return db.ChangeEvent{
+		Event: event,
+		CampaignHistory: db.CampaignHistory{
+			ID:           id,
+			Cid:          "spotify",
+			FieldChanged: field,
+			AdvertiserID: testAdvertiserID,
+		},
+	}
+}
+
+func TestStreamEventsResume(t *testing.T) {
+	store := &changeStore{
+		fakeStore: newFakeStore(),
+		live:      []db.ChangeEvent{changeEvent(43, db.ChangeCampaignStatusChanged, "status")},
+	}
+	server := httptest.NewServer(newTestServer(t, store).Router())
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/v1/events/stream?cid=spotify&event=campaign.status_changed", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "read_only")
+	req.Header.Set("Last-Event-ID", "41")
+	rsp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+	require.Equal(t, "text/event-stream", rsp.Header.Get("Content-Type"))
+
+	// The stream ends once the store closes it.
+	body, err := io.ReadAll(rsp.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, db.SubscribeChangesParams{
+		AdvertiserID: testAdvertiserID,
+		Cid:          "spotify",
+		Events:       []string{db.ChangeCampaignStatusChanged},
+		LastEventID:  41,
+	}, store.arg)
+
+	// The reset comes first, then the replayed events, then the live ones.
+	stream := string(body)
+	reset := strings.Index(stream, "event:reset\n")
+	replayed := strings.Index(stream, "id:42\nevent:campaign.status_changed\n")
+	live := strings.Index(stream, "id:43\nevent:campaign.status_changed\n")
+	require.GreaterOrEqual(t, reset, 0, stream)
+	require.Greater(t, replayed, reset, stream)
+	require.Greater(t, live, replayed, stream)
+}
+
+func TestStreamEventsInvalid(t *testing.T) {
+	store := &changeStore{fakeStore: newFakeStore()}
+	server := newTestServer(t, store)
+
+	rsp := serve(server, http.MethodGet, "/v1/events/stream", "read_only", http.Header{"Last-Event-Id": {"latest"}}, "")
+	require.Equal(t, http.StatusBadRequest, rsp.Code)
+	requireErrorCode(t, rsp, "invalid_request")
+
+	rsp = serve(server, http.MethodGet, "/v1/events/stream?event=campaign.renamed", "read_only", nil, "")
+	require.Equal(t, http.StatusBadRequest, rsp.Code)
+	requireErrorCode(t, rsp, "invalid_request")
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+// creativeRevisionResponse leaves out the review fields of revisions that
+// were never reviewed, and the locale of revisions of the campaign's own
+// creative.
+type creativeRevisionResponse struct {
+	ID         int32             `json:"id"`
+	Cid        string            `json:"cid"`
+	Locale     string            `json:"locale,omitempty"`
+	Img        string            `json:"img"`
+	Cta        string            `json:"cta"`
+	Status     db.RevisionStatus `json:"status"`
+	Reason     string            `json:"reason,omitempty"`
+	ReviewedBy string            `json:"reviewed_by,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ReviewedAt *time.Time        `json:"reviewed_at,omitempty"`
+}
+
+func newCreativeRevisionResponse(revision db.CreativeRevision) creativeRevisionResponse {
+	rsp := creativeRevisionResponse{
+		ID:         revision.ID,
+		Cid:        revision.Cid,
+		Locale:     revision.Locale,
+		Img:        revision.Img,
+		Cta:        revision.Cta,
+		Status:     revision.Status,
+		Reason:     revision.Reason,
+		ReviewedBy: revision.ReviewedBy,
+		CreatedAt:  revision.CreatedAt,
+	}
+	if revision.ReviewedAt.Valid {
+		rsp.ReviewedAt = &revision.ReviewedAt.Time
+	}
+	return rsp
+}
+
+type listCreativeRevisionsRequest struct {
+	Cid    string `form:"cid"`
+	Status string `binding:"omitempty,oneof=pending approved rejected superseded withdrawn" form:"status"`
+}
+
+// listCreativeRevisions returns the creative revisions of the advertiser,
+// oldest first. The review queue is ?status=pending.
+func (s *Server) listCreativeRevisions(ctx *gin.Context) {
+	var req listCreativeRevisionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	revisions, err := s.store.ListCreativeRevisions(ctx.Request.Context(), db.ListCreativeRevisionsParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          textFilter(req.Cid),
+		Status:       db.NullRevisionStatus{RevisionStatus: db.RevisionStatus(req.Status), Valid: req.Status != ""},
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rsp := make([]creativeRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		rsp = append(rsp, newCreativeRevisionResponse(revision))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type reviewCreativeRevisionUri struct {
+	Cid string `binding:"required" uri:"cid"`
+	ID  int32  `binding:"required,min=1" uri:"id"`
+}
+
+type reviewCreativeRevisionRequest struct {
+	Reason string `binding:"max=256" json:"reason"`
+}
+
+// reviewCreativeRevision returns the handler approving or rejecting a pending
+// creative revision. The body, holding the reason, is optional for approvals;
+// rejections require one.
+func (s *Server) reviewCreativeRevision(approve bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var uri reviewCreativeRevisionUri
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			abortWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var req reviewCreativeRevisionRequest
+		if ctx.Request.ContentLength != 0 {
+			if err := ctx.ShouldBindJSON(&req); err != nil {
+				abortWithError(ctx, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		revision, err := s.store.ReviewCreativeRevision(ctx.Request.Context(), db.ReviewCreativeRevisionParams{
+			AdvertiserID: authAdvertiserID(ctx),
+			Cid:          uri.Cid,
+			ID:           uri.ID,
+			Approve:      approve,
+			Reason:       req.Reason,
+			ReviewedBy:   authActor(ctx),
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, newCreativeRevisionResponse(revision))
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+// errorResponse is the body of every error response. Code is stable for
+// clients to branch on, while Error is meant for people and may change.
+type errorResponse struct {
+	Error     string `json:"error"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id"`
+}
+
+// apiError is an error raised by a handler itself, with the status to respond
+// with.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// statusCodes are the error codes of the statuses handlers respond with.
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            "invalid_request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not_found",
+	http.StatusConflict:              "conflict",
+	http.StatusPreconditionFailed:    "precondition_failed",
+	http.StatusRequestEntityTooLarge: "request_too_large",
+	http.StatusUnsupportedMediaType:  "unsupported_media_type",
+	http.StatusUnprocessableEntity:   "unprocessable",
+	http.StatusPreconditionRequired:  "precondition_required",
+}
+
+// errorStatuses maps errors from the store to responses, checked in order.
+var errorStatuses = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{db.ErrVersionMismatch, http.StatusPreconditionFailed, "version_mismatch"},
+	{db.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused"},
+	{db.ErrIdempotencyKeyInUse, http.StatusConflict, "idempotency_key_in_use"},
+	{db.ErrNotFound, http.StatusNotFound, "not_found"},
+	{db.ErrAlreadyExists, http.StatusConflict, "already_exists"},
+	{db.ErrConflict, http.StatusConflict, "conflict"},
+	{db.ErrValidation, http.StatusBadRequest, "validation_failed"},
+}
+
+// abortWithError stops the request with an error response.
+func abortWithError(ctx *gin.Context, status int, message string) {
+	ctx.Error(&apiError{status: status, message: message})
+	ctx.Abort()
+}
+
+// writeError responds with the last error of the request, unless a response
+// has been written already. Errors that are not known to be safe to show are
+// logged and reported as internal errors.
+func writeError(ctx *gin.Context) {
+	if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+		return
+	}
+	err := db.TranslateError(ctx.Errors.Last().Err)
+	requestID := ctx.GetString(requestIDContextKey)
+
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		ctx.JSON(apiErr.status, errorResponse{Error: apiErr.message, Code: statusCodes[apiErr.status], RequestID: requestID})
+		return
+	}
+	for _, known := range errorStatuses {
+		if errors.Is(err, known.err) {
+			ctx.JSON(known.status, errorResponse{Error: err.Error(), Code: known.code, RequestID: requestID})
+			return
+		}
+	}
+
+	fmt.Printf("Request %s failed: %v\n", requestID, err)
+	ctx.JSON(http.StatusInternalServerError, errorResponse{Error: "internal server error", Code: "internal", RequestID: requestID})
+}
+
+// errorMiddleware turns the errors handlers record with ctx.Error into error
+// responses.
+func errorMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+		writeError(ctx)
+	}
+}
@@ -0,0 +1,86 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+// campaignStore reads and deletes a campaign at version, or fails with err.
+type campaignStore struct {
+	*fakeStore
+	version int32
+	err     error
+
+	deletedKey     db.CampaignKey
+	deletedVersion int32
+}
+
+func (store *campaignStore) ReadCampaign(ctx context.Context, key db.CampaignKey) (db.CompleteCampaign, error) {
+	if store.err != nil {
+		return db.CompleteCampaign{}, store.err
+	}
+	return db.CompleteCampaign{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Version: store.version}, nil
+}
+
+func (store *campaignStore) DeleteVersionedCampaign(ctx context.Context, key db.CampaignKey, version int32) error {
+	if store.err != nil {
+		return store.err
+	}
+	store.deletedKey, store.deletedVersion = key, version
+	return nil
+}
+
+func TestErrorResponses(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", &db.Error{Kind: db.ErrNotFound, Message: "campaign not found"}, http.StatusNotFound, "not_found"},
+		{"already exists", &db.Error{Kind: db.ErrAlreadyExists, Message: "campaign already exists"}, http.StatusConflict, "already_exists"},
+		{"conflict", db.ErrInvalidTransition, http.StatusConflict, "conflict"},
+		{"validation", &db.Error{Kind: db.ErrValidation, Message: "invalid patch"}, http.StatusBadRequest, "validation_failed"},
+		{"version mismatch", db.ErrVersionMismatch, http.StatusPreconditionFailed, "version_mismatch"},
+		{"internal", errors.New("connection refused"), http.StatusInternalServerError, "internal"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, &campaignStore{fakeStore: newFakeStore(), err: tc.err})
+
+			rsp := serve(server, http.MethodGet, "/v1/campaigns/spotify", "read_only", nil, "")
+			require.Equal(t, tc.status, rsp.Code)
+			requireErrorCode(t, rsp, tc.code)
+			if tc.status == http.StatusInternalServerError {
+				require.NotContains(t, rsp.Body.String(), "connection refused")
+			}
+		})
+	}
+}
+
+func TestErrorResponsesAuth(t *testing.T) {
+	server := newTestServer(t, &campaignStore{fakeStore: newFakeStore()})
+
+	rsp := serve(server, http.MethodGet, "/v1/campaigns/spotify", "", nil, "")
+	require.Equal(t, http.StatusUnauthorized, rsp.Code)
+	requireErrorCode(t, rsp, "unauthorized")
+
+	rsp = serve(server, http.MethodGet, "/v1/campaigns/spotify", "unknown", nil, "")
+	require.Equal(t, http.StatusUnauthorized, rsp.Code)
+	requireErrorCode(t, rsp, "unauthorized")
+
+	rsp = serve(server, http.MethodGet, "/v1/campaigns/spotify", "delivery", nil, "")
+	require.Equal(t, http.StatusForbidden, rsp.Code)
+	requireErrorCode(t, rsp, "forbidden")
+
+	// The request ID of the caller is echoed in the envelope.
+	rsp = serve(server, http.MethodGet, "/v1/campaigns/spotify", "", http.Header{"X-Request-Id": {"req-123"}}, "")
+	require.Equal(t, "req-123", rsp.Header().Get("X-Request-ID"))
+	requireErrorCode(t, rsp, "unauthorized")
+}
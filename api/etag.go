@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag sends a campaign's version, quoted, as the response ETag. Updates
+// and deletes must send the ETag they read back in If-Match, so that a change
+// made in between is not silently overwritten.
+func setETag(ctx *gin.Context, version int32) {
+	ctx.Header("ETag", strconv.Quote(strconv.Itoa(int(version))))
+}
+
+// ifMatchVersion returns the campaign version named by the If-Match header,
+// or zero for "*", which matches any version. Without a usable header it
+// aborts the request and returns false.
+func ifMatchVersion(ctx *gin.Context) (int32, bool) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" {
+		abortWithError(ctx, http.StatusPreconditionRequired, "If-Match header is required")
+		return 0, false
+	}
+	if header == "*" {
+		return 0, true
+	}
+
+	value, err := strconv.Unquote(header)
+	if err == nil {
+		version, err := strconv.ParseInt(value, 10, 32)
+		if err == nil && version > 0 {
+			return int32(version), true
+		}
+	}
+	abortWithError(ctx, http.StatusPreconditionFailed, "If-Match does not match the current version")
+	return 0, false
+}
@@ -0,0 +1,70 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+func TestETag(t *testing.T) {
+	store := &campaignStore{fakeStore: newFakeStore(), version: 3}
+	server := newTestServer(t, store)
+
+	rsp := serve(server, http.MethodGet, "/v1/campaigns/spotify", "read_only", nil, "")
+	require.Equal(t, http.StatusOK, rsp.Code)
+	require.Equal(t, `"3"`, rsp.Header().Get("ETag"))
+
+	rsp = serve(server, http.MethodGet, "/v2/campaigns/spotify", "read_only", nil, "")
+	require.Equal(t, http.StatusOK, rsp.Code)
+	require.Equal(t, `"3"`, rsp.Header().Get("ETag"))
+}
+
+func TestIfMatch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		ifMatch string
+		status  int
+		code    string
+		version int32
+	}{
+		{name: "missing", status: http.StatusPreconditionRequired, code: "precondition_required"},
+		{name: "unquoted", ifMatch: "3", status: http.StatusPreconditionFailed, code: "precondition_failed"},
+		{name: "not a version", ifMatch: `"abc"`, status: http.StatusPreconditionFailed, code: "precondition_failed"},
+		{name: "weak", ifMatch: `W/"3"`, status: http.StatusPreconditionFailed, code: "precondition_failed"},
+		{name: "version", ifMatch: `"3"`, status: http.StatusOK, version: 3},
+		{name: "any version", ifMatch: "*", status: http.StatusOK, version: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &campaignStore{fakeStore: newFakeStore(), version: 3, deletedVersion: -1}
+			server := newTestServer(t, store)
+
+			var header http.Header
+			if tc.ifMatch != "" {
+				header = http.Header{"If-Match": {tc.ifMatch}}
+			}
+			rsp := serve(server, http.MethodDelete, "/v1/delete_campaign/spotify", "editor", header, "")
+			require.Equal(t, tc.status, rsp.Code)
+			if tc.code != "" {
+				requireErrorCode(t, rsp, tc.code)
+				// Requests without a usable version never reach the store.
+				require.Equal(t, int32(-1), store.deletedVersion)
+				return
+			}
+			require.Equal(t, db.CampaignKey{AdvertiserID: testAdvertiserID, Cid: "spotify"}, store.deletedKey)
+			require.Equal(t, tc.version, store.deletedVersion)
+		})
+	}
+}
+
+func TestIfMatchStale(t *testing.T) {
+	store := &campaignStore{fakeStore: newFakeStore(), err: db.ErrVersionMismatch}
+	server := newTestServer(t, store)
+
+	rsp := serve(server, http.MethodDelete, "/v1/delete_campaign/spotify", "editor", http.Header{"If-Match": {`"2"`}}, "")
+	require.Equal(t, http.StatusPreconditionFailed, rsp.Code)
+	requireErrorCode(t, rsp, "version_mismatch")
+}
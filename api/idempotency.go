@@ -0,0 +1,174 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+const (
+	idempotencyKeyHeaderKey     = "Idempotency-Key"
+	idempotentReplayedHeaderKey = "Idempotent-Replayed"
+	maxIdempotencyKeyLen        = 255
+	// maxIdempotentBodySize caps the body of requests sent with an
+	// idempotency key, which is held in memory to fingerprint the request.
+	maxIdempotentBodySize = 8 << 20
+)
+
+// idempotentMethods are the methods whose requests may carry an idempotency
+// key.
+var idempotentMethods = []string{http.MethodPost, http.MethodPatch, http.MethodDelete}
+
+// replayedHeaders are the response headers stored along with the body.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// responseRecorder keeps a copy of the response body while writing it.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// requestFingerprint identifies a request by its method, path, query and body.
+func requestFingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", req.Method, req.URL.RequestURI())
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// refreshIdempotencyKey keeps holding a reserved key while its request is
+// served, however long that takes. The returned function stops refreshing
+// and returns once the last refresh is done, so that none overtakes the
+// response being saved. It may be called again.
+func refreshIdempotencyKey(ctx context.Context, store db.Store, scope string, key string) func() {
+	ctx = context.WithoutCancel(ctx)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(db.IdempotencyRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := store.RefreshIdempotencyKey(ctx, scope, key); err != nil {
+					fmt.Printf("Redis Expire error for idempotency key: %v\n", err)
+				}
+			}
+		}
+	}()
+	return sync.OnceFunc(func() {
+		close(stop)
+		<-done
+	})
+}
+
+// idempotencyMiddleware makes POST, PATCH and DELETE requests sent with an
+// Idempotency-Key header safe to retry. The first response to a key is kept
+// for 24 hours and replayed to retries with the same method, path and body,
+// while reusing the key for another request is rejected. Server errors are
+// not kept, so that the request can be retried. It runs after requireRole, so
+// that requests refused for their credentials are not kept either.
+func idempotencyMiddleware(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotencyKeyHeaderKey)
+		if key == "" || !slices.Contains(idempotentMethods, ctx.Request.Method) {
+			ctx.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			abortWithError(ctx, http.StatusBadRequest, fmt.Sprintf("idempotency key is longer than %d characters", maxIdempotencyKeyLen))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				abortWithError(ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("requests with an idempotency key are limited to %d bytes", tooLarge.Limit))
+				return
+			}
+			abortWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := ctx.MustGet(authContextKey).(authPayload).Actor
+		fingerprint := requestFingerprint(ctx.Request, body)
+		response, err := store.ReserveIdempotencyKey(ctx.Request.Context(), scope, key, fingerprint)
+		if err != nil {
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		if response != nil {
+			for name, value := range response.Header {
+				ctx.Header(name, value)
+			}
+			ctx.Header(idempotentReplayedHeaderKey, "true")
+			ctx.Data(response.Status, response.Header["Content-Type"], response.Body)
+			ctx.Abort()
+			return
+		}
+
+		stopRefresh := refreshIdempotencyKey(ctx.Request.Context(), store, scope, key)
+		// A panicking handler stops the refreshes too.
+		defer stopRefresh()
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+		// Error responses are written now so that they are kept too.
+		writeError(ctx)
+		stopRefresh()
+
+		// The caller may have given up on the request, which is why it
+		// will retry, so the outcome is saved regardless.
+		saveCtx := context.WithoutCancel(ctx.Request.Context())
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := store.ReleaseIdempotencyKey(saveCtx, scope, key); err != nil {
+				fmt.Printf("Redis Del error for idempotency key: %v\n", err)
+			}
+			return
+		}
+
+		header := make(map[string]string, len(replayedHeaders))
+		for _, name := range replayedHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				header[name] = value
+			}
+		}
+		err = store.SaveIdempotentResponse(saveCtx, scope, key, db.IdempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			Header:      header,
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			fmt.Printf("Redis Set error for idempotency key: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+// advertiserStore creates advertisers, failing while fail is set.
+type advertiserStore struct {
+	*fakeStore
+	created int32
+	fail    bool
+}
+
+func (store *advertiserStore) CreateAdvertiser(ctx context.Context, name string) (db.Advertiser, error) {
+	if store.fail {
+		return db.Advertiser{}, errors.New("database unavailable")
+	}
+	store.created++
+	return db.Advertiser{ID: store.created, Name: name}, nil
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	store := &advertiserStore{fakeStore: newFakeStore()}
+	server := newTestServer(t, store)
+	header := http.Header{"Idempotency-Key": {"create-acme"}}
+
+	first := serve(server, http.MethodPost, "/v1/create_advertiser", "global_admin", header, `{"name": "acme"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.Empty(t, first.Header().Get("Idempotent-Replayed"))
+
+	// The retry gets the first response, without creating another
+	// advertiser.
+	retry := serve(server, http.MethodPost, "/v1/create_advertiser", "global_admin", header, `{"name": "acme"}`)
+	require.Equal(t, http.StatusCreated, retry.Code)
+	require.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	require.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	require.JSONEq(t, first.Body.String(), retry.Body.String())
+	require.Equal(t, int32(1), store.created)
+
+	// Requests without a key are not replayed.
+	again := serve(server, http.MethodPost, "/v1/create_advertiser", "global_admin", nil, `{"name": "acme"}`)
+	require.Equal(t, http.StatusCreated, again.Code)
+	require.Equal(t, int32(2), store.created)
+}
+
+func TestIdempotencyKeyReused(t *testing.T) {
+	store := &advertiserStore{fakeStore: newFakeStore()}
+	server := newTestServer(t, store)
+	header := http.Header{"Idempotency-Key": {"create-acme"}}
+
+	rsp := serve(server, http.MethodPost, "/v1/create_advertiser", "global_admin", header, `{"name": "acme"}`)
+	require.Equal(t, http.StatusCreated, rsp.Code)
+
+	rsp = serve(server, http.MethodPost, "/v1/create_advertiser", "global_admin", header, `{"name": "globex"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, rsp.Code)
+	requireErrorCode(t, rsp, "idempotency_key_reused")
+	require.Equal(t, int32(1), store.created)
+
+	// Keys are scoped to the caller.
+	rsp = serve(server, http.MethodPost, "/v1/create_advertiser", "global_admin", http.Header{"Idempotency-Key": {"create-globex"}}, `{"name": "globex"}`)
+	require.Equal(t, http.StatusCreated, rsp.Code)
+}
+
+func TestIdempotencyServerErrorsNotKept(t *testing.T) {
+	store := &advertiserStore{fakeStore: newFakeStore(), fail: true}
+	server := newTestServer(t, store)
+	header := http.Header{"Idempotency-Key": {"create-acme"}}
+
+	rsp := serve(server, http.MethodPost, "/v1/create_advertiser", "global_admin", header, `{"name": "acme"}`)
+	require.Equal(t, http.StatusInternalServerError, rsp.Code)
+
+	// The key is released, so the retry runs.
+	store.fail = false
+	rsp = serve(server, http.MethodPost, "/v1/create_advertiser", "global_admin", header, `{"name": "acme"}`)
+	require.Equal(t, http.StatusCreated, rsp.Code)
+	require.Empty(t, rsp.Header().Get("Idempotent-Replayed"))
+
+	var advertiser db.Advertiser
+	require.NoError(t, json.Unmarshal(rsp.Body.Bytes(), &advertiser))
+	require.Equal(t, "acme", advertiser.Name)
+	require.Equal(t, int32(1), store.created)
+}
@@ -0,0 +1,142 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/api"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+// testAdvertiserID is the advertiser of every key fakeStore authenticates.
+const testAdvertiserID = 7
+
+var testRoles = []db.KeyRole{
+	db.KeyRoleGlobalAdmin,
+	db.KeyRoleAdmin,
+	db.KeyRoleEditor,
+	db.KeyRoleReadOnly,
+	db.KeyRoleDelivery,
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	// The server loads its templates relative to the root of the module.
+	if err := os.Chdir(".."); err != nil {
+		log.Fatal("cannot change to the module root: ", err)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeStore serves the API without a database. It authenticates any key
+// named after a role as a key with that role, and keeps idempotent responses
+// in memory. Tests embed it in stores implementing the methods their
+// handlers call; any other method panics through the nil db.Store.
+type fakeStore struct {
+	db.Store
+
+	mu        sync.Mutex
+	responses map[string]db.IdempotentResponse
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{responses: make(map[string]db.IdempotentResponse)}
+}
+
+func (store *fakeStore) AuthenticateApiKey(ctx context.Context, key string) (db.ApiKey, error) {
+	role := db.KeyRole(key)
+	if !slices.Contains(testRoles, role) {
+		return db.ApiKey{}, pgx.ErrNoRows
+	}
+	return db.ApiKey{ID: 1, Role: role, AdvertiserID: testAdvertiserID}, nil
+}
+
+func (store *fakeStore) ReserveIdempotencyKey(ctx context.Context, scope string, key string, fingerprint string) (*db.IdempotentResponse, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	response, ok := store.responses[scope+"\x00"+key]
+	switch {
+	case !ok:
+		store.responses[scope+"\x00"+key] = db.IdempotentResponse{Fingerprint: fingerprint}
+		return nil, nil
+	case response.Fingerprint != fingerprint:
+		return nil, db.ErrIdempotencyKeyReused
+	case response.Status == 0:
+		return nil, db.ErrIdempotencyKeyInUse
+	}
+	return &response, nil
+}
+
+func (store *fakeStore) SaveIdempotentResponse(ctx context.Context, scope string, key string, response db.IdempotentResponse) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.responses[scope+"\x00"+key] = response
+	return nil
+}
+
+func (store *fakeStore) ReleaseIdempotencyKey(ctx context.Context, scope string, key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.responses, scope+"\x00"+key)
+	return nil
+}
+
+func (store *fakeStore) RefreshIdempotencyKey(ctx context.Context, scope string, key string) error {
+	return nil
+}
+
+func newTestServer(t *testing.T, store db.Store) *api.Server {
+	server, err := api.NewServer(util.Config{}, store)
+	require.NoError(t, err)
+	return server
+}
+
+// serve sends a request with the given key and headers to the server, and
+// returns its response.
+func serve(server *api.Server, method string, path string, key string, header http.Header, body string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = bytes.NewBufferString(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	recorder := httptest.NewRecorder()
+	server.Router().ServeHTTP(recorder, req)
+	return recorder
+}
+
+// requireErrorCode checks that a response is an error envelope with code.
+func requireErrorCode(t *testing.T, rsp *httptest.ResponseRecorder, code string) {
+	t.Helper()
+	var body struct {
+		Error     string `json:"error"`
+		Code      string `json:"code"`
+		RequestID string `json:"request_id"`
+	}
+	require.NoError(t, json.Unmarshal(rsp.Body.Bytes(), &body), rsp.Body.String())
+	require.Equal(t, code, body.Code)
+	require.NotEmpty(t, body.Error)
+	require.Equal(t, rsp.Header().Get("X-Request-ID"), body.RequestID)
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+const (
+	authorizationHeaderKey  = "Authorization"
+	apiKeyHeaderKey         = "X-API-Key"
+	authorizationTypeBearer = "bearer"
+	requestIDHeaderKey      = "X-Request-ID"
+	authContextKey          = "auth"
+	requestIDContextKey     = "request_id"
+	maxRequestIDLen         = 64
+)
+
+// authPayload describes the caller, who authenticated with either an API key
+// or a login session. Actor names the caller in the audit log.
+type authPayload struct {
+	Actor        string
+	Role         db.KeyRole
+	AdvertiserID int32
+}
+
+// requestIDMiddleware tags every request with an ID, reusing the caller's
+// X-Request-ID when it sends one, and echoes it in the response.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeaderKey)
+		if requestID == "" || len(requestID) > maxRequestIDLen {
+			b := make([]byte, 16)
+			rand.Read(b)
+			requestID = hex.EncodeToString(b)
+		}
+
+		ctx.Set(requestIDContextKey, requestID)
+		ctx.Header(requestIDHeaderKey, requestID)
+		ctx.Next()
+	}
+}
+
+// requestAPIKey reads the key or session token from either
+// "Authorization: Bearer <key>" or "X-API-Key: <key>".
+func requestAPIKey(ctx *gin.Context) (string, error) {
+	if key := ctx.GetHeader(apiKeyHeaderKey); key != "" {
+		return key, nil
+	}
+
+	header := ctx.GetHeader(authorizationHeaderKey)
+	if header == "" {
+		return "", errors.New("api key is not provided")
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) != 2 || strings.ToLower(fields[0]) != authorizationTypeBearer {
+		return "", errors.New("invalid authorization header format")
+	}
+	return fields[1], nil
+}
+
+// authMiddleware rejects requests without an active API key or session, and
+// stores the caller in the context for requireRole.
+func authMiddleware(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key, err := requestAPIKey(ctx)
+		if err != nil {
+			abortWithError(ctx, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		var payload authPayload
+		if strings.HasPrefix(key, util.SessionTokenPrefix) {
+			session, err := store.AuthenticateSession(ctx.Request.Context(), key)
+			if err != nil {
+				if errors.Is(err, db.ErrSessionNotFound) {
+					abortWithError(ctx, http.StatusUnauthorized, "invalid or expired session")
+					return
+				}
+				ctx.Error(err)
+				ctx.Abort()
+				return
+			}
+			payload = authPayload{
+				Actor:        fmt.Sprintf("user:%s", session.Subject),
+				Role:         session.Role,
+				AdvertiserID: session.AdvertiserID,
+			}
+		} else {
+			apiKey, err := store.AuthenticateApiKey(ctx.Request.Context(), key)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					abortWithError(ctx, http.StatusUnauthorized, "invalid api key")
+					return
+				}
+				ctx.Error(err)
+				ctx.Abort()
+				return
+			}
+			payload = authPayload{
+				Actor:        fmt.Sprintf("api_key:%d", apiKey.ID),
+				Role:         apiKey.Role,
+				AdvertiserID: apiKey.AdvertiserID,
+			}
+		}
+
+		// Changes made while serving the request are attributed to the
+		// caller in the audit log.
+		if ctx.Request.Method != http.MethodGet {
+			ctx.Request = ctx.Request.WithContext(db.WithAuditContext(ctx.Request.Context(), db.AuditContext{
+				Actor:     payload.Actor,
+				SourceIP:  ctx.ClientIP(),
+				RequestID: ctx.GetString(requestIDContextKey),
+			}))
+		}
+
+		ctx.Set(authContextKey, payload)
+		ctx.Next()
+	}
+}
+
+// requireRole lets through callers holding one of the given roles.
+func requireRole(roles ...db.KeyRole) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload, ok := ctx.MustGet(authContextKey).(authPayload)
+		if !ok || !slices.Contains(roles, payload.Role) {
+			abortWithError(ctx, http.StatusForbidden, "api key is not allowed to access this resource")
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// authAdvertiserID returns the advertiser of the caller. Campaign management is
+// always scoped to it, so callers never see other advertisers' campaigns.
+func authAdvertiserID(ctx *gin.Context) int32 {
+	return ctx.MustGet(authContextKey).(authPayload).AdvertiserID
+}
+
+// authScope returns the advertiser that admin routes are scoped to: that of
+// the caller, or none for global admins, who manage every advertiser.
+func authScope(ctx *gin.Context) pgtype.Int4 {
+	payload := ctx.MustGet(authContextKey).(authPayload)
+	if payload.Role == db.KeyRoleGlobalAdmin {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: payload.AdvertiserID, Valid: true}
+}
+
+// authActor names the caller as the audit log does.
+func authActor(ctx *gin.Context) string {
+	return ctx.MustGet(authContextKey).(authPayload).Actor
+}
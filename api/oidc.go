@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/token"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+// sessionRoles lists the roles a login may grant, most privileged first.
+// Delivery stays limited to API keys.
+var sessionRoles = []db.KeyRole{db.KeyRoleAdmin, db.KeyRoleEditor, db.KeyRoleReadOnly}
+
+// roleMap maps the values of the identity provider's role claim, usually
+// group names, to roles.
+type roleMap map[string]db.KeyRole
+
+// parseRoleMap parses "value=role" pairs separated by commas, such as
+// "adrouter-admins=admin,marketing=editor".
+func parseRoleMap(s string) (roleMap, error) {
+	roles := roleMap{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		value, role, ok := strings.Cut(pair, "=")
+		value, role = strings.TrimSpace(value), strings.TrimSpace(role)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid role mapping %q", pair)
+		}
+		if !slices.Contains(sessionRoles, db.KeyRole(role)) {
+			return nil, fmt.Errorf("invalid role %q for %q", role, value)
+		}
+		roles[value] = db.KeyRole(role)
+	}
+
+	if len(roles) == 0 {
+		return nil, errors.New("no roles are mapped")
+	}
+	return roles, nil
+}
+
+// role returns the most privileged role granted by any of values.
+func (roles roleMap) role(values []string) (db.KeyRole, bool) {
+	granted := map[db.KeyRole]bool{}
+	for _, value := range values {
+		if role, ok := roles[value]; ok {
+			granted[role] = true
+		}
+	}
+
+	for _, role := range sessionRoles {
+		if granted[role] {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+// oidcLogin verifies identity provider tokens and maps their claims.
+type oidcLogin struct {
+	verifier        *token.Verifier
+	roles           roleMap
+	roleClaim       string
+	advertiserClaim string
+}
+
+func newOIDCLogin(config util.Config) (*oidcLogin, error) {
+	keys, err := token.LoadKeySet(config.OIDCJWKSSource)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := parseRoleMap(config.OIDCRoleMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC_ROLE_MAP: %w", err)
+	}
+
+	return &oidcLogin{
+		verifier:        token.NewVerifier(keys, config.OIDCIssuer, config.OIDCAudience),
+		roles:           roles,
+		roleClaim:       config.OIDCRoleClaim,
+		advertiserClaim: config.OIDCAdvertiserClaim,
+	}, nil
+}
@@ -1,34 +1,70 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/token"
+	"github.com/vivek-344/AdRouter/util"
 )
 
 type deliveryRequest struct {
-	AppID   string `binding:"required" form:"app"`
-	Country string `binding:"required" form:"country"`
-	Os      string `binding:"required" form:"os"`
+	AppID      string   `binding:"required" form:"app"`
+	Country    string   `binding:"required" form:"country"`
+	Os         string   `binding:"required" form:"os"`
+	OsVersion  string   `form:"os_version"`
+	AppVersion string   `form:"app_version"`
+	Lang       string   `binding:"omitempty,bcp47_language_tag" form:"lang"`
+	Region     string   `form:"region"`
+	City       string   `form:"city"`
+	Lat        *float64 `binding:"required_with=Lon,omitempty,latitude" form:"lat"`
+	Lon        *float64 `binding:"required_with=Lat,omitempty,longitude" form:"lon"`
+	UserID     string   `form:"user_id"`
 }
 
 func (s *Server) delivery(ctx *gin.Context) {
 	var req deliveryRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	if req.Lang == "" {
+		req.Lang = util.PreferredLanguage(ctx.GetHeader("Accept-Language"))
+	}
+
+	if req.UserID != "" {
+		userID, err := util.NormalizeHashedID(req.UserID)
+		if err != nil {
+			abortWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.UserID = userID
+	}
+
 	response, err := s.store.Delivery(ctx.Request.Context(), db.DeliveryParams{
-		AppID:   req.AppID,
-		Country: req.Country,
-		Os:      req.Os,
+		AppID:      req.AppID,
+		Country:    req.Country,
+		Os:         req.Os,
+		OsVersion:  req.OsVersion,
+		AppVersion: req.AppVersion,
+		Lang:       req.Lang,
+		Region:     req.Region,
+		City:       req.City,
+		Lat:        req.Lat,
+		Lon:        req.Lon,
+		UserID:     req.UserID,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -41,52 +77,111 @@ func (s *Server) delivery(ctx *gin.Context) {
 }
 
 type createCampaignRequest struct {
-	Cid         string `binding:"required" json:"cid"`
-	Name        string `binding:"required,min=6,max=32" json:"name"`
-	Img         string `binding:"required" json:"img"`
-	Cta         string `binding:"required" json:"cta"`
-	AppID       string `json:"app"`
-	AppRule     string `binding:"omitempty,oneof=include exclude" json:"app_rule"`
-	Country     string `json:"country"`
-	CountryRule string `binding:"omitempty,oneof=include exclude" json:"country_rule"`
-	Os          string `json:"os"`
-	OsRule      string `binding:"omitempty,oneof=include exclude" json:"os_rule"`
+	Cid            string `binding:"required" json:"cid"`
+	Name           string `binding:"required,min=6,max=32" json:"name"`
+	Img            string `binding:"required" json:"img"`
+	Cta            string `binding:"required" json:"cta"`
+	AppID          string `json:"app"`
+	AppRule        string `binding:"omitempty,oneof=include exclude" json:"app_rule"`
+	Country        string `json:"country"`
+	CountryRule    string `binding:"omitempty,oneof=include exclude" json:"country_rule"`
+	Os             string `json:"os"`
+	OsRule         string `binding:"omitempty,oneof=include exclude" json:"os_rule"`
+	OsVersion      string `binding:"omitempty,version_range" json:"os_version"`
+	OsVersionRule  string `binding:"omitempty,oneof=include exclude" json:"os_version_rule"`
+	AppVersion     string `binding:"omitempty,version_range" json:"app_version"`
+	AppVersionRule string `binding:"omitempty,oneof=include exclude" json:"app_version_rule"`
+	Language       string `json:"language"`
+	LanguageRule   string `binding:"omitempty,oneof=include exclude" json:"language_rule"`
+	Region         string `json:"region"`
+	RegionRule     string `binding:"omitempty,oneof=include exclude" json:"region_rule"`
+	City           string `json:"city"`
+	CityRule       string `binding:"omitempty,oneof=include exclude" json:"city_rule"`
+	Category       string `json:"category"`
+	CategoryRule   string `binding:"omitempty,oneof=include exclude" json:"category_rule"`
+	Keyword        string `json:"keyword"`
+	KeywordRule    string `binding:"omitempty,oneof=include exclude" json:"keyword_rule"`
+	Status         string `binding:"omitempty,oneof=draft" json:"status"`
 }
 
 func (s *Server) createCampaign(ctx *gin.Context) {
 	var req createCampaignRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	if req.AppID != "" && req.AppRule == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "AppRule field is empty"})
+		abortWithError(ctx, http.StatusBadRequest, "AppRule field is empty")
 		return
 	}
 	if req.Country != "" && req.CountryRule == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "CountryRule field is empty"})
+		abortWithError(ctx, http.StatusBadRequest, "CountryRule field is empty")
 		return
 	}
 	if req.Os != "" && req.OsRule == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "OsRule field is empty"})
+		abortWithError(ctx, http.StatusBadRequest, "OsRule field is empty")
+		return
+	}
+	if req.OsVersion != "" && req.OsVersionRule == "" {
+		abortWithError(ctx, http.StatusBadRequest, "OsVersionRule field is empty")
+		return
+	}
+	if req.AppVersion != "" && req.AppVersionRule == "" {
+		abortWithError(ctx, http.StatusBadRequest, "AppVersionRule field is empty")
+		return
+	}
+	if req.Language != "" && req.LanguageRule == "" {
+		abortWithError(ctx, http.StatusBadRequest, "LanguageRule field is empty")
+		return
+	}
+	if req.Region != "" && req.RegionRule == "" {
+		abortWithError(ctx, http.StatusBadRequest, "RegionRule field is empty")
+		return
+	}
+	if req.City != "" && req.CityRule == "" {
+		abortWithError(ctx, http.StatusBadRequest, "CityRule field is empty")
+		return
+	}
+	if req.Category != "" && req.CategoryRule == "" {
+		abortWithError(ctx, http.StatusBadRequest, "CategoryRule field is empty")
+		return
+	}
+	if req.Keyword != "" && req.KeywordRule == "" {
+		abortWithError(ctx, http.StatusBadRequest, "KeywordRule field is empty")
 		return
 	}
 
 	campaign, err := s.store.CreateCampaign(ctx.Request.Context(), db.CreateCampaignParams{
-		Cid:         req.Cid,
-		Name:        req.Name,
-		Img:         req.Img,
-		Cta:         req.Cta,
-		AppID:       req.AppID,
-		AppRule:     db.RuleType(req.AppRule),
-		Country:     req.Country,
-		CountryRule: db.RuleType(req.CountryRule),
-		Os:          req.Os,
-		OsRule:      db.RuleType(req.OsRule),
+		AdvertiserID:   authAdvertiserID(ctx),
+		Cid:            req.Cid,
+		Name:           req.Name,
+		Img:            req.Img,
+		Cta:            req.Cta,
+		AppID:          req.AppID,
+		AppRule:        db.RuleType(req.AppRule),
+		Country:        req.Country,
+		CountryRule:    db.RuleType(req.CountryRule),
+		Os:             req.Os,
+		OsRule:         db.RuleType(req.OsRule),
+		OsVersion:      req.OsVersion,
+		OsVersionRule:  db.RuleType(req.OsVersionRule),
+		AppVersion:     req.AppVersion,
+		AppVersionRule: db.RuleType(req.AppVersionRule),
+		Language:       req.Language,
+		LanguageRule:   db.RuleType(req.LanguageRule),
+		Region:         req.Region,
+		RegionRule:     db.RuleType(req.RegionRule),
+		City:           req.City,
+		CityRule:       db.RuleType(req.CityRule),
+		Category:       req.Category,
+		CategoryRule:   db.RuleType(req.CategoryRule),
+		Keyword:        req.Keyword,
+		KeywordRule:    db.RuleType(req.KeywordRule),
+		Status:         db.StatusType(req.Status),
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -97,48 +192,288 @@ type getCampaignRequest struct {
 	Cid string `binding:"required" uri:"cid"`
 }
 
+type getCampaignQuery struct {
+	AsOf time.Time `form:"as_of"`
+}
+
+// getCampaign returns a campaign with its targeting. With as_of, the campaign
+// is returned as it was at that moment.
 func (s *Server) getCampaign(ctx *gin.Context) {
 	var req getCampaignRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var query getCampaignQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	campaign, err := s.store.ReadCampaign(ctx.Request.Context(), req.Cid)
+	key := db.CampaignKey{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+	}
+
+	var campaign db.CompleteCampaign
+	var err error
+	if query.AsOf.IsZero() {
+		campaign, err = s.store.ReadCampaign(ctx.Request.Context(), key)
+	} else {
+		campaign, err = s.store.ReadCampaignAsOf(ctx.Request.Context(), key, query.AsOf)
+	}
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		ctx.Error(err)
+		return
+	}
+
+	if query.AsOf.IsZero() {
+		setETag(ctx, campaign.Version)
+	}
+	ctx.JSON(http.StatusOK, campaign)
+}
+
+type listCampaignHistoryRequest struct {
+	Fields   []string  `form:"field"`
+	Since    time.Time `form:"since"`
+	Until    time.Time `form:"until"`
+	BeforeID int32     `binding:"omitempty,min=1" form:"before_id"`
+	Limit    int32     `binding:"omitempty,min=1,max=1000" form:"limit"`
+}
+
+// listCampaignHistory returns the recorded changes of a campaign, newest
+// first. Pass the id of the last entry as before_id to fetch the next page.
+func (s *Server) listCampaignHistory(ctx *gin.Context) {
+	var uri getCampaignRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req listCampaignHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+
+	key := db.CampaignKey{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          uri.Cid,
+	}
+	_, err := s.store.GetCampaign(ctx.Request.Context(), db.GetCampaignParams(key))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	history, err := s.store.ListCampaignHistoryPage(ctx.Request.Context(), db.ListCampaignHistoryPageParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Fields:       req.Fields,
+		Since:        timeFilter(req.Since),
+		Until:        timeFilter(req.Until),
+		BeforeID:     pgtype.Int4{Int32: req.BeforeID, Valid: req.BeforeID != 0},
+		RowLimit:     req.Limit,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+type revertCampaignRequest struct {
+	HistoryID int32     `binding:"omitempty,min=1" json:"history_id"`
+	At        time.Time `json:"at"`
+	DryRun    bool      `json:"dry_run"`
+}
+
+// revertCampaign undoes a history entry and every later change, or every
+// change made after a moment. With dry_run the changes are only returned.
+func (s *Server) revertCampaign(ctx *gin.Context) {
+	var uri getCampaignRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req revertCampaignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if (req.HistoryID == 0) == req.At.IsZero() {
+		abortWithError(ctx, http.StatusBadRequest, "pass either history_id or at")
+		return
+	}
+
+	// A dry run changes nothing, so it needs no version.
+	var version int32
+	if !req.DryRun {
+		var ok bool
+		version, ok = ifMatchVersion(ctx)
+		if !ok {
+			return
+		}
+	}
+
+	result, err := s.store.RevertCampaign(ctx.Request.Context(), db.RevertCampaignParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          uri.Cid,
+		HistoryID:    req.HistoryID,
+		At:           req.At,
+		DryRun:       req.DryRun,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if !req.DryRun {
+		setETag(ctx, result.Campaign.Version)
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+type transitionCampaignRequest struct {
+	Reason string `binding:"max=256" json:"reason"`
+}
+
+// transitionCampaign returns the handler moving a campaign through the named
+// transition of its lifecycle. The body, holding the reason for the change,
+// is optional.
+func (s *Server) transitionCampaign(transition string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var uri getCampaignRequest
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			abortWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var req transitionCampaignRequest
+		if ctx.Request.ContentLength != 0 {
+			if err := ctx.ShouldBindJSON(&req); err != nil {
+				abortWithError(ctx, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		version, ok := ifMatchVersion(ctx)
+		if !ok {
+			return
+		}
+
+		campaign, err := s.store.TransitionCampaign(ctx.Request.Context(), db.TransitionCampaignParams{
+			AdvertiserID: authAdvertiserID(ctx),
+			Cid:          uri.Cid,
+			Transition:   transition,
+			Reason:       req.Reason,
+			Version:      version,
+		})
+		if err != nil {
+			ctx.Error(err)
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+
+		setETag(ctx, campaign.Version)
+		ctx.JSON(http.StatusOK, campaign)
+	}
+}
+
+// restoreCampaign brings back a deleted campaign that was not purged yet. It
+// takes no If-Match, as a deleted campaign cannot be read for its version.
+func (s *Server) restoreCampaign(ctx *gin.Context) {
+	var uri getCampaignRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	campaign, err := s.store.RestoreCampaign(ctx.Request.Context(), db.CampaignKey{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          uri.Cid,
+	})
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
+	setETag(ctx, campaign.Version)
 	ctx.JSON(http.StatusOK, campaign)
 }
 
+type cloneCampaignRequest struct {
+	Cid         string `binding:"required" json:"cid"`
+	Name        string `binding:"omitempty,min=6,max=32" json:"name"`
+	Country     string `json:"country"`
+	CountryRule string `binding:"omitempty,oneof=include exclude" json:"country_rule"`
+	Status      string `binding:"omitempty,oneof=draft active" json:"status"`
+}
+
+// cloneCampaign copies a campaign with its targeting under the cid of the
+// body, which may also override the name, countries and status of the copy.
+func (s *Server) cloneCampaign(ctx *gin.Context) {
+	var uri getCampaignRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req cloneCampaignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	campaign, err := s.store.CloneCampaign(ctx.Request.Context(), db.CloneCampaignParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          uri.Cid,
+		NewCid:       req.Cid,
+		Name:         req.Name,
+		Country:      req.Country,
+		CountryRule:  db.RuleType(req.CountryRule),
+		Status:       db.StatusType(req.Status),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	setETag(ctx, campaign.Version)
+	ctx.JSON(http.StatusCreated, campaign)
+}
+
 type addCampaignRequest struct {
-	Cid  string `binding:"required" json:"cid"`
-	Name string `binding:"required" json:"name"`
-	Img  string `binding:"required" json:"img"`
-	Cta  string `binding:"required" json:"cta"`
+	Cid    string `binding:"required" json:"cid"`
+	Name   string `binding:"required" json:"name"`
+	Img    string `binding:"required" json:"img"`
+	Cta    string `binding:"required" json:"cta"`
+	Status string `binding:"omitempty,oneof=draft" json:"status"`
 }
 
 func (s *Server) addCampaign(ctx *gin.Context) {
 	var req addCampaignRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	campaign, err := s.store.CreateCampaign(ctx.Request.Context(), db.CreateCampaignParams{
-		Cid:  req.Cid,
-		Name: req.Name,
-		Img:  req.Img,
-		Cta:  req.Cta,
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Name:         req.Name,
+		Img:          req.Img,
+		Cta:          req.Cta,
+		Status:       db.StatusType(req.Status),
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -154,17 +489,18 @@ type addTargetAppRequest struct {
 func (s *Server) addTargetApp(ctx *gin.Context) {
 	var req addTargetAppRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	target_app, err := s.store.AddTargetApp(ctx.Request.Context(), db.AddTargetAppParams{
-		Cid:   req.Cid,
-		AppID: req.AppID,
-		Rule:  db.RuleType(req.Rule),
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		AppID:        req.AppID,
+		Rule:         db.RuleType(req.Rule),
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -180,17 +516,18 @@ type addTargetCountryRequest struct {
 func (s *Server) addTargetCountry(ctx *gin.Context) {
 	var req addTargetCountryRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	target_country, err := s.store.AddTargetCountry(ctx.Request.Context(), db.AddTargetCountryParams{
-		Cid:     req.Cid,
-		Country: req.Country,
-		Rule:    db.RuleType(req.Rule),
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Country:      req.Country,
+		Rule:         db.RuleType(req.Rule),
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -206,288 +543,2328 @@ type addTargetOsRequest struct {
 func (s *Server) addTargetOs(ctx *gin.Context) {
 	var req addTargetOsRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	target_os, err := s.store.AddTargetOs(ctx.Request.Context(), db.AddTargetOsParams{
-		Cid:  req.Cid,
-		Os:   req.Os,
-		Rule: db.RuleType(req.Rule),
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Os:           req.Os,
+		Rule:         db.RuleType(req.Rule),
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, target_os)
 }
 
-type deleteCampaignRequest struct {
-	Cid string `binding:"required" uri:"cid"`
+type addTargetOsVersionRequest struct {
+	Cid       string `binding:"required" json:"cid"`
+	OsVersion string `binding:"required,version_range" json:"os_version"`
+	Rule      string `binding:"required,oneof=include exclude" json:"rule"`
 }
 
-func (s *Server) deleteCampaign(ctx *gin.Context) {
-	var req deleteCampaignRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (s *Server) addTargetOsVersion(ctx *gin.Context) {
+	var req addTargetOsVersionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err := s.store.DeleteCampaign(ctx.Request.Context(), req.Cid)
+	target_os_version, err := s.store.AddTargetOsVersion(ctx.Request.Context(), db.AddTargetOsVersionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		OsVersion:    req.OsVersion,
+		Rule:         db.RuleType(req.Rule),
+	})
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "campaign not found"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "campaign deleted successfully"})
+	ctx.JSON(http.StatusCreated, target_os_version)
 }
 
-type deleteTargetAppRequest struct {
-	Cid string `binding:"required" uri:"cid"`
+type addTargetAppVersionRequest struct {
+	Cid        string `binding:"required" json:"cid"`
+	AppVersion string `binding:"required,version_range" json:"app_version"`
+	Rule       string `binding:"required,oneof=include exclude" json:"rule"`
 }
 
-func (s *Server) deleteTargetApp(ctx *gin.Context) {
-	var req deleteTargetAppRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (s *Server) addTargetAppVersion(ctx *gin.Context) {
+	var req addTargetAppVersionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err := s.store.DeleteTargetApp(ctx.Request.Context(), req.Cid)
+	target_app_version, err := s.store.AddTargetAppVersion(ctx.Request.Context(), db.AddTargetAppVersionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		AppVersion:   req.AppVersion,
+		Rule:         db.RuleType(req.Rule),
+	})
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+	ctx.JSON(http.StatusCreated, target_app_version)
 }
 
-type deleteTargetCountryRequest struct {
-	Cid string `binding:"required" uri:"cid"`
+type addTargetLanguageRequest struct {
+	Cid      string `binding:"required" json:"cid"`
+	Language string `binding:"required" json:"language"`
+	Rule     string `binding:"required,oneof=include exclude" json:"rule"`
 }
 
-func (s *Server) deleteTargetCountry(ctx *gin.Context) {
-	var req deleteTargetCountryRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (s *Server) addTargetLanguage(ctx *gin.Context) {
+	var req addTargetLanguageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err := s.store.DeleteTargetCountry(ctx.Request.Context(), req.Cid)
+	target_language, err := s.store.AddTargetLanguage(ctx.Request.Context(), db.AddTargetLanguageParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Language:     req.Language,
+		Rule:         db.RuleType(req.Rule),
+	})
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+	ctx.JSON(http.StatusCreated, target_language)
 }
 
-type deleteTargetOsRequest struct {
-	Cid string `binding:"required" uri:"cid"`
+type addTargetRegionRequest struct {
+	Cid    string `binding:"required" json:"cid"`
+	Region string `binding:"required" json:"region"`
+	Rule   string `binding:"required,oneof=include exclude" json:"rule"`
 }
 
-func (s *Server) deleteTargetOs(ctx *gin.Context) {
-	var req deleteTargetOsRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (s *Server) addTargetRegion(ctx *gin.Context) {
+	var req addTargetRegionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err := s.store.DeleteTargetOs(ctx.Request.Context(), req.Cid)
+	target_region, err := s.store.AddTargetRegion(ctx.Request.Context(), db.AddTargetRegionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Region:       req.Region,
+		Rule:         db.RuleType(req.Rule),
+	})
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+	ctx.JSON(http.StatusCreated, target_region)
 }
 
-type toggleStatusRequest struct {
-	Cid string `binding:"required" uri:"cid"`
+type addTargetCityRequest struct {
+	Cid  string `binding:"required" json:"cid"`
+	City string `binding:"required" json:"city"`
+	Rule string `binding:"required,oneof=include exclude" json:"rule"`
 }
 
-func (s *Server) toggleStatus(ctx *gin.Context) {
-	var req toggleStatusRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	err := s.store.ToggleStatus(ctx.Request.Context(), req.Cid)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+func (s *Server) addTargetCity(ctx *gin.Context) {
+	var req addTargetCityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	campaign, err := s.store.GetCampaign(ctx.Request.Context(), req.Cid)
+	target_city, err := s.store.AddTargetCity(ctx.Request.Context(), db.AddTargetCityParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		City:         req.City,
+		Rule:         db.RuleType(req.Rule),
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "campaign " + campaign.Cid + " status toggled to " + string(campaign.Status) + " successfully"})
+	ctx.JSON(http.StatusCreated, target_city)
 }
 
-type updateCampaignNameRequest struct {
-	Cid  string `binding:"required" json:"cid"`
-	Name string `binding:"required,min=6,max=32" json:"name"`
+type addTargetCategoryRequest struct {
+	Cid      string `binding:"required" json:"cid"`
+	Category string `binding:"required" json:"category"`
+	Rule     string `binding:"required,oneof=include exclude" json:"rule"`
 }
 
-func (s *Server) updateCampaignName(ctx *gin.Context) {
-	var req updateCampaignNameRequest
+func (s *Server) addTargetCategory(ctx *gin.Context) {
+	var req addTargetCategoryRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	campaign, err := s.store.UpdateCampaignName(ctx.Request.Context(), db.UpdateCampaignNameParams{
-		Cid:  req.Cid,
-		Name: req.Name,
+	target_category, err := s.store.AddTargetCategory(ctx.Request.Context(), db.AddTargetCategoryParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Category:     req.Category,
+		Rule:         db.RuleType(req.Rule),
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "campaign " + campaign.Cid + " name changed to " + campaign.Name + " successfully"})
+	ctx.JSON(http.StatusCreated, target_category)
 }
 
-type updateCampaignImageRequest struct {
-	Cid string `binding:"required" json:"cid"`
-	Img string `binding:"required" json:"img"`
+type addTargetKeywordRequest struct {
+	Cid     string `binding:"required" json:"cid"`
+	Keyword string `binding:"required" json:"keyword"`
+	Rule    string `binding:"required,oneof=include exclude" json:"rule"`
 }
 
-func (s *Server) updateCampaignImage(ctx *gin.Context) {
-	var req updateCampaignImageRequest
+func (s *Server) addTargetKeyword(ctx *gin.Context) {
+	var req addTargetKeywordRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	campaign, err := s.store.UpdateCampaignImage(ctx.Request.Context(), db.UpdateCampaignImageParams{
-		Cid: req.Cid,
-		Img: req.Img,
+	target_keyword, err := s.store.AddTargetKeyword(ctx.Request.Context(), db.AddTargetKeywordParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Keyword:      req.Keyword,
+		Rule:         db.RuleType(req.Rule),
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "campaign " + campaign.Cid + " image changed to " + campaign.Img + " successfully"})
+	ctx.JSON(http.StatusCreated, target_keyword)
 }
 
-type updateCampaignCtaRequest struct {
-	Cid string `binding:"required" json:"cid"`
-	Cta string `binding:"required" json:"cta"`
+type targetRadiusCircle struct {
+	Lat      float64 `binding:"latitude" json:"lat"`
+	Lon      float64 `binding:"longitude" json:"lon"`
+	RadiusKm float64 `binding:"required,gt=0" json:"radius_km"`
 }
 
-func (s *Server) updateCampaignCta(ctx *gin.Context) {
-	var req updateCampaignCtaRequest
+type addTargetRadiusRequest struct {
+	Cid     string               `binding:"required" json:"cid"`
+	Circles []targetRadiusCircle `binding:"required,min=1,dive" json:"circles"`
+	Rule    string               `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) addTargetRadius(ctx *gin.Context) {
+	var req addTargetRadiusRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	campaign, err := s.store.UpdateCampaignCta(ctx.Request.Context(), db.UpdateCampaignCtaParams{
-		Cid: req.Cid,
-		Cta: req.Cta,
+	circles := make([]util.Circle, len(req.Circles))
+	for i, circle := range req.Circles {
+		circles[i] = util.Circle{Lat: circle.Lat, Lon: circle.Lon, RadiusKm: circle.RadiusKm}
+	}
+
+	target_radius, err := s.store.AddTargetRadiusCircles(ctx.Request.Context(), db.AddTargetRadiusCirclesParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Rule:         db.RuleType(req.Rule),
+		Circles:      circles,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "campaign " + campaign.Cid + " cta changed to " + campaign.Cta + " successfully"})
+	ctx.JSON(http.StatusCreated, target_radius)
 }
 
-type updateTargetAppRequest struct {
-	Cid     string `binding:"required" json:"cid"`
-	AppID   string `binding:"required" json:"app"`
-	AppRule string `binding:"required,oneof=include exclude" json:"rule"`
+type addCampaignLocaleRequest struct {
+	Cid    string `binding:"required" json:"cid"`
+	Locale string `binding:"required,bcp47_language_tag" json:"locale"`
+	Img    string `binding:"required_without=Cta" json:"img"`
+	Cta    string `binding:"required_without=Img" json:"cta"`
 }
 
-func (s *Server) updateTargetApp(ctx *gin.Context) {
-	var req updateTargetAppRequest
+func (s *Server) addCampaignLocale(ctx *gin.Context) {
+	var req addCampaignLocaleRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	target_app, err := s.store.AddTargetApp(ctx.Request.Context(), db.AddTargetAppParams{
-		Cid:   req.Cid,
-		AppID: req.AppID,
-		Rule:  db.RuleType(req.AppRule),
+	locale, err := util.NormalizeLocale(req.Locale)
+	if err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.store.AddCampaignLocale(ctx.Request.Context(), db.AddCampaignLocaleParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Locale:       locale,
+		Img:          req.Img,
+		Cta:          req.Cta,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
+		return
 	}
 
-	ctx.JSON(http.StatusCreated, target_app)
+	if result.Revision != nil {
+		ctx.JSON(http.StatusAccepted, gin.H{
+			"message":  "campaign " + req.Cid + " locale " + locale + " is pending review",
+			"revision": newCreativeRevisionResponse(*result.Revision),
+		})
+		return
+	}
+	ctx.JSON(http.StatusCreated, result.Locale)
 }
 
-type updateTargetCountryRequest struct {
-	Cid         string `binding:"required" json:"cid"`
-	Country     string `binding:"required" json:"country_id"`
-	CountryRule string `binding:"required,oneof=include exclude" json:"rule"`
+type addCampaignLabelRequest struct {
+	Cid   string `binding:"required" json:"cid"`
+	Label string `binding:"required" json:"label"`
 }
 
-func (s *Server) updateTargetCountry(ctx *gin.Context) {
-	var req updateTargetCountryRequest
+func (s *Server) addCampaignLabel(ctx *gin.Context) {
+	var req addCampaignLabelRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	target_country, err := s.store.AddTargetCountry(ctx.Request.Context(), db.AddTargetCountryParams{
-		Cid:     req.Cid,
-		Country: req.Country,
-		Rule:    db.RuleType(req.CountryRule),
+	labels, err := s.store.UpdateCampaignLabels(ctx.Request.Context(), db.UpdateCampaignLabelsParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Add:          []string{req.Label},
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
+		return
 	}
 
-	ctx.JSON(http.StatusCreated, target_country)
+	ctx.JSON(http.StatusCreated, gin.H{"cid": req.Cid, "labels": labels})
 }
 
-type updateTargetOsRequest struct {
-	Cid    string `binding:"required" json:"cid"`
-	Os     string `binding:"required" json:"os"`
-	OsRule string `binding:"required,oneof=include exclude" json:"rule"`
+type deleteCampaignRequest struct {
+	Cid string `binding:"required" uri:"cid"`
 }
 
-func (s *Server) updateTargetOs(ctx *gin.Context) {
-	var req updateTargetOsRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (s *Server) deleteCampaign(ctx *gin.Context) {
+	var req deleteCampaignRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	target_os, err := s.store.AddTargetOs(ctx.Request.Context(), db.AddTargetOsParams{
-		Cid:  req.Cid,
-		Os:   req.Os,
-		Rule: db.RuleType(req.OsRule),
-	})
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteVersionedCampaign(ctx.Request.Context(), db.CampaignKey{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+	}, version)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, pgx.ErrNoRows) {
+			abortWithError(ctx, http.StatusNotFound, "campaign not found")
+			return
+		}
+		ctx.Error(err)
+		return
 	}
 
-	ctx.JSON(http.StatusCreated, target_os)
+	ctx.JSON(http.StatusOK, gin.H{"message": "campaign deleted successfully"})
+}
+
+type deleteTargetAppRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetApp(ctx *gin.Context) {
+	var req deleteTargetAppRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetApp(ctx.Request.Context(), db.DeleteTargetAppParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetCountryRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetCountry(ctx *gin.Context) {
+	var req deleteTargetCountryRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetCountry(ctx.Request.Context(), db.DeleteTargetCountryParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetOsRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetOs(ctx *gin.Context) {
+	var req deleteTargetOsRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetOs(ctx.Request.Context(), db.DeleteTargetOsParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetOsVersionRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetOsVersion(ctx *gin.Context) {
+	var req deleteTargetOsVersionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetOsVersion(ctx.Request.Context(), db.DeleteTargetOsVersionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetAppVersionRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetAppVersion(ctx *gin.Context) {
+	var req deleteTargetAppVersionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetAppVersion(ctx.Request.Context(), db.DeleteTargetAppVersionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetLanguageRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetLanguage(ctx *gin.Context) {
+	var req deleteTargetLanguageRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetLanguage(ctx.Request.Context(), db.DeleteTargetLanguageParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetRegionRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetRegion(ctx *gin.Context) {
+	var req deleteTargetRegionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetRegion(ctx.Request.Context(), db.DeleteTargetRegionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetCityRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetCity(ctx *gin.Context) {
+	var req deleteTargetCityRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetCity(ctx.Request.Context(), db.DeleteTargetCityParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetCategoryRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetCategory(ctx *gin.Context) {
+	var req deleteTargetCategoryRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetCategory(ctx.Request.Context(), db.DeleteTargetCategoryParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetKeywordRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetKeyword(ctx *gin.Context) {
+	var req deleteTargetKeywordRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetKeyword(ctx.Request.Context(), db.DeleteTargetKeywordParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetRadiusRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) deleteTargetRadius(ctx *gin.Context) {
+	var req deleteTargetRadiusRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetRadius(ctx.Request.Context(), db.DeleteTargetRadiusParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteTargetRadiusCircleRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+	ID  int32  `binding:"required,min=1" uri:"id"`
+}
+
+func (s *Server) deleteTargetRadiusCircle(ctx *gin.Context) {
+	var req deleteTargetRadiusCircleRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteTargetRadiusByID(ctx.Request.Context(), db.DeleteTargetRadiusByIDParams{
+		ID:           req.ID,
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteCampaignLocaleRequest struct {
+	Cid    string `binding:"required" uri:"cid"`
+	Locale string `binding:"required,bcp47_language_tag" uri:"locale"`
+}
+
+func (s *Server) deleteCampaignLocale(ctx *gin.Context) {
+	var req deleteCampaignLocaleRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	locale, err := util.NormalizeLocale(req.Locale)
+	if err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err = s.store.DeleteCampaignLocale(ctx.Request.Context(), db.DeleteCampaignLocaleParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Locale:       locale,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type deleteCampaignLabelRequest struct {
+	Cid   string `binding:"required" uri:"cid"`
+	Label string `binding:"required" uri:"label"`
+}
+
+func (s *Server) deleteCampaignLabel(ctx *gin.Context) {
+	var req deleteCampaignLabelRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	_, err := s.store.UpdateCampaignLabels(ctx.Request.Context(), db.UpdateCampaignLabelsParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Remove:       []string{req.Label},
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type toggleStatusRequest struct {
+	Cid string `binding:"required" uri:"cid"`
+}
+
+func (s *Server) toggleStatus(ctx *gin.Context) {
+	var req toggleStatusRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.ToggleStatus(ctx.Request.Context(), db.CampaignKey{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+	}, version)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	campaign, err := s.store.GetCampaign(ctx.Request.Context(), db.GetCampaignParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	setETag(ctx, campaign.Version)
+	ctx.JSON(http.StatusOK, gin.H{"message": "campaign " + campaign.Cid + " status toggled to " + string(campaign.Status) + " successfully"})
+}
+
+type updateCampaignNameRequest struct {
+	Cid  string `binding:"required" json:"cid"`
+	Name string `binding:"required,min=6,max=32" json:"name"`
+}
+
+func (s *Server) updateCampaignName(ctx *gin.Context) {
+	var req updateCampaignNameRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	campaign, err := s.store.UpdateCampaignName(ctx.Request.Context(), db.UpdateCampaignNameParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Name:         req.Name,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	setETag(ctx, campaign.Version)
+	ctx.JSON(http.StatusOK, gin.H{"message": "campaign " + campaign.Cid + " name changed to " + campaign.Name + " successfully"})
+}
+
+type updateCampaignImageRequest struct {
+	Cid string `binding:"required" json:"cid"`
+	Img string `binding:"required" json:"img"`
+}
+
+func (s *Server) updateCampaignImage(ctx *gin.Context) {
+	var req updateCampaignImageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := s.store.UpdateCampaignImage(ctx.Request.Context(), db.UpdateCampaignImageParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Img:          req.Img,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	setETag(ctx, result.Campaign.Version)
+	if result.Revision != nil {
+		ctx.JSON(http.StatusAccepted, gin.H{
+			"message":  "campaign " + req.Cid + " image change to " + req.Img + " is pending review",
+			"revision": newCreativeRevisionResponse(*result.Revision),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "campaign " + result.Campaign.Cid + " image changed to " + result.Campaign.Img + " successfully"})
+}
+
+type updateCampaignCtaRequest struct {
+	Cid string `binding:"required" json:"cid"`
+	Cta string `binding:"required" json:"cta"`
+}
+
+func (s *Server) updateCampaignCta(ctx *gin.Context) {
+	var req updateCampaignCtaRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := s.store.UpdateCampaignCta(ctx.Request.Context(), db.UpdateCampaignCtaParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Cta:          req.Cta,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	setETag(ctx, result.Campaign.Version)
+	if result.Revision != nil {
+		ctx.JSON(http.StatusAccepted, gin.H{
+			"message":  "campaign " + req.Cid + " cta change to " + req.Cta + " is pending review",
+			"revision": newCreativeRevisionResponse(*result.Revision),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "campaign " + result.Campaign.Cid + " cta changed to " + result.Campaign.Cta + " successfully"})
+}
+
+type updateTargetAppRequest struct {
+	Cid     string `binding:"required" json:"cid"`
+	AppID   string `binding:"required" json:"app"`
+	AppRule string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) updateTargetApp(ctx *gin.Context) {
+	var req updateTargetAppRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	target_app, err := s.store.UpdateTargetApp(ctx.Request.Context(), db.UpdateTargetAppParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		AppID:        req.AppID,
+		Rule:         db.RuleType(req.AppRule),
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_app)
+}
+
+type updateTargetCountryRequest struct {
+	Cid         string `binding:"required" json:"cid"`
+	Country     string `binding:"required" json:"country_id"`
+	CountryRule string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) updateTargetCountry(ctx *gin.Context) {
+	var req updateTargetCountryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	target_country, err := s.store.UpdateTargetCountry(ctx.Request.Context(), db.UpdateTargetCountryParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Country:      req.Country,
+		Rule:         db.RuleType(req.CountryRule),
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_country)
+}
+
+type updateTargetOsRequest struct {
+	Cid    string `binding:"required" json:"cid"`
+	Os     string `binding:"required" json:"os"`
+	OsRule string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) updateTargetOs(ctx *gin.Context) {
+	var req updateTargetOsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	target_os, err := s.store.UpdateTargetOs(ctx.Request.Context(), db.UpdateTargetOsParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Os:           req.Os,
+		Rule:         db.RuleType(req.OsRule),
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_os)
+}
+
+type updateTargetOsVersionRequest struct {
+	Cid           string `binding:"required" json:"cid"`
+	OsVersion     string `binding:"required,version_range" json:"os_version"`
+	OsVersionRule string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) updateTargetOsVersion(ctx *gin.Context) {
+	var req updateTargetOsVersionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	target_os_version, err := s.store.UpdateTargetOsVersion(ctx.Request.Context(), db.UpdateTargetOsVersionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		OsVersion:    req.OsVersion,
+		Rule:         db.RuleType(req.OsVersionRule),
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_os_version)
+}
+
+type updateTargetAppVersionRequest struct {
+	Cid            string `binding:"required" json:"cid"`
+	AppVersion     string `binding:"required,version_range" json:"app_version"`
+	AppVersionRule string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) updateTargetAppVersion(ctx *gin.Context) {
+	var req updateTargetAppVersionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	target_app_version, err := s.store.UpdateTargetAppVersion(ctx.Request.Context(), db.UpdateTargetAppVersionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		AppVersion:   req.AppVersion,
+		Rule:         db.RuleType(req.AppVersionRule),
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_app_version)
+}
+
+type updateTargetLanguageRequest struct {
+	Cid          string `binding:"required" json:"cid"`
+	Language     string `binding:"required" json:"language"`
+	LanguageRule string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) updateTargetLanguage(ctx *gin.Context) {
+	var req updateTargetLanguageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	target_language, err := s.store.UpdateTargetLanguage(ctx.Request.Context(), db.UpdateTargetLanguageParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Language:     req.Language,
+		Rule:         db.RuleType(req.LanguageRule),
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_language)
+}
+
+type updateTargetRegionRequest struct {
+	Cid        string `binding:"required" json:"cid"`
+	Region     string `binding:"required" json:"region"`
+	RegionRule string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) updateTargetRegion(ctx *gin.Context) {
+	var req updateTargetRegionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	target_region, err := s.store.UpdateTargetRegion(ctx.Request.Context(), db.UpdateTargetRegionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Region:       req.Region,
+		Rule:         db.RuleType(req.RegionRule),
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_region)
+}
+
+type updateTargetCityRequest struct {
+	Cid      string `binding:"required" json:"cid"`
+	City     string `binding:"required" json:"city"`
+	CityRule string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) updateTargetCity(ctx *gin.Context) {
+	var req updateTargetCityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	target_city, err := s.store.UpdateTargetCity(ctx.Request.Context(), db.UpdateTargetCityParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		City:         req.City,
+		Rule:         db.RuleType(req.CityRule),
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_city)
+}
+
+type updateTargetCategoryRequest struct {
+	Cid          string `binding:"required" json:"cid"`
+	Category     string `binding:"required" json:"category"`
+	CategoryRule string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) updateTargetCategory(ctx *gin.Context) {
+	var req updateTargetCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	target_category, err := s.store.UpdateTargetCategory(ctx.Request.Context(), db.UpdateTargetCategoryParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Category:     req.Category,
+		Rule:         db.RuleType(req.CategoryRule),
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_category)
+}
+
+type updateTargetKeywordRequest struct {
+	Cid         string `binding:"required" json:"cid"`
+	Keyword     string `binding:"required" json:"keyword"`
+	KeywordRule string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) updateTargetKeyword(ctx *gin.Context) {
+	var req updateTargetKeywordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	target_keyword, err := s.store.UpdateTargetKeyword(ctx.Request.Context(), db.UpdateTargetKeywordParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Keyword:      req.Keyword,
+		Rule:         db.RuleType(req.KeywordRule),
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_keyword)
+}
+
+type updateCampaignLocaleRequest struct {
+	Cid    string `binding:"required" json:"cid"`
+	Locale string `binding:"required,bcp47_language_tag" json:"locale"`
+	Img    string `binding:"required_without=Cta" json:"img"`
+	Cta    string `binding:"required_without=Img" json:"cta"`
+}
+
+func (s *Server) updateCampaignLocale(ctx *gin.Context) {
+	var req updateCampaignLocaleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	locale, err := util.NormalizeLocale(req.Locale)
+	if err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := s.store.UpdateCampaignLocale(ctx.Request.Context(), db.UpdateCampaignLocaleParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		Locale:       locale,
+		Img:          req.Img,
+		Cta:          req.Cta,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if result.Revision != nil {
+		ctx.JSON(http.StatusAccepted, gin.H{
+			"message":  "campaign " + req.Cid + " locale " + locale + " change is pending review",
+			"revision": newCreativeRevisionResponse(*result.Revision),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Locale)
+}
+
+type createTargetListRequest struct {
+	Name     string `binding:"required,max=64" json:"name"`
+	ListType string `binding:"required,oneof=app country os" json:"list_type"`
+	Items    string `binding:"required" json:"items"`
+}
+
+func (s *Server) createTargetList(ctx *gin.Context) {
+	var req createTargetListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	target_list, err := s.store.CreateTargetList(ctx.Request.Context(), db.CreateTargetListParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Name:         req.Name,
+		ListType:     db.ListType(req.ListType),
+		Items:        req.Items,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, target_list)
+}
+
+type targetListIDRequest struct {
+	ID int32 `binding:"required,min=1" uri:"id"`
+}
+
+func (s *Server) getTargetList(ctx *gin.Context) {
+	var req targetListIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	target_list, err := s.store.GetTargetList(ctx.Request.Context(), db.GetTargetListParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		ID:           req.ID,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_list)
+}
+
+func (s *Server) listTargetLists(ctx *gin.Context) {
+	target_lists, err := s.store.ListTargetLists(ctx.Request.Context(), authAdvertiserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_lists)
+}
+
+func (s *Server) getTargetListHistory(ctx *gin.Context) {
+	var req targetListIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The history of lists of other advertisers is not found either.
+	_, err := s.store.GetTargetList(ctx.Request.Context(), db.GetTargetListParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		ID:           req.ID,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	history, err := s.store.ListTargetListHistory(ctx.Request.Context(), req.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+type updateTargetListRequest struct {
+	ID    int32  `binding:"required,min=1" json:"id"`
+	Name  string `binding:"required,max=64" json:"name"`
+	Items string `binding:"required" json:"items"`
+}
+
+func (s *Server) updateTargetList(ctx *gin.Context) {
+	var req updateTargetListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	target_list, err := s.store.UpdateTargetList(ctx.Request.Context(), db.UpdateTargetListParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		ID:           req.ID,
+		Name:         req.Name,
+		Items:        req.Items,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target_list)
+}
+
+func (s *Server) deleteTargetList(ctx *gin.Context) {
+	var req targetListIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := s.store.DeleteTargetList(ctx.Request.Context(), db.DeleteTargetListParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		ID:           req.ID,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type addCampaignTargetListRequest struct {
+	Cid    string `binding:"required" json:"cid"`
+	ListID int32  `binding:"required,min=1" json:"list_id"`
+	Rule   string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) addCampaignTargetList(ctx *gin.Context) {
+	var req addCampaignTargetListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	campaign_target_list, err := s.store.AddCampaignTargetList(ctx.Request.Context(), db.AddCampaignTargetListParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		ListID:       req.ListID,
+		Rule:         db.RuleType(req.Rule),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, campaign_target_list)
+}
+
+type deleteCampaignTargetListRequest struct {
+	Cid    string `binding:"required" uri:"cid"`
+	ListID int32  `binding:"required,min=1" uri:"list_id"`
+}
+
+func (s *Server) deleteCampaignTargetList(ctx *gin.Context) {
+	var req deleteCampaignTargetListRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteCampaignTargetList(ctx.Request.Context(), db.DeleteCampaignTargetListParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		ListID:       req.ListID,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type createSegmentRequest struct {
+	Name string `binding:"required,max=64" json:"name"`
+}
+
+func (s *Server) createSegment(ctx *gin.Context) {
+	var req createSegmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	segment, err := s.store.CreateAudienceSegment(ctx.Request.Context(), db.CreateAudienceSegmentParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Name:         req.Name,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, segment)
+}
+
+type segmentIDRequest struct {
+	ID int32 `binding:"required,min=1" uri:"id"`
+}
+
+func (s *Server) getSegment(ctx *gin.Context) {
+	var req segmentIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	segment, err := s.store.GetAudienceSegment(ctx.Request.Context(), db.GetAudienceSegmentParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		ID:           req.ID,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, segment)
+}
+
+func (s *Server) listSegments(ctx *gin.Context) {
+	segments, err := s.store.ListAudienceSegments(ctx.Request.Context(), authAdvertiserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, segments)
+}
+
+type uploadSegmentRequest struct {
+	Storage         string `binding:"omitempty,oneof=set bloom" form:"storage"`
+	ExpectedMembers uint64 `binding:"required_if=Storage bloom,max=100000000" form:"expected_members"`
+}
+
+// uploadSegment streams the "file" part of a multipart body straight into the
+// store, so large ID files are never buffered in memory or on disk.
+func (s *Server) uploadSegment(ctx *gin.Context) {
+	var uri segmentIDRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req uploadSegmentRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reader, err := ctx.Request.MultipartReader()
+	if err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			abortWithError(ctx, http.StatusBadRequest, "file field is missing")
+			return
+		}
+		if err != nil {
+			abortWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		segment, err := s.store.UploadSegment(ctx.Request.Context(), db.UploadSegmentParams{
+			AdvertiserID:    authAdvertiserID(ctx),
+			ID:              uri.ID,
+			Storage:         db.SegmentStorage(req.Storage),
+			ExpectedMembers: req.ExpectedMembers,
+			Members:         part,
+		})
+		if err != nil {
+			var memberErr *db.SegmentMemberError
+			if errors.As(err, &memberErr) {
+				abortWithError(ctx, http.StatusBadRequest, err.Error())
+				return
+			}
+			ctx.Error(err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, segment)
+		return
+	}
+}
+
+func (s *Server) deleteSegment(ctx *gin.Context) {
+	var req segmentIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := s.store.DeleteAudienceSegment(ctx.Request.Context(), db.DeleteAudienceSegmentParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		ID:           req.ID,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type addCampaignSegmentRequest struct {
+	Cid       string `binding:"required" json:"cid"`
+	SegmentID int32  `binding:"required,min=1" json:"segment_id"`
+	Rule      string `binding:"required,oneof=include exclude" json:"rule"`
+}
+
+func (s *Server) addCampaignSegment(ctx *gin.Context) {
+	var req addCampaignSegmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	campaign_segment, err := s.store.AddCampaignSegment(ctx.Request.Context(), db.AddCampaignSegmentParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		SegmentID:    req.SegmentID,
+		Rule:         db.RuleType(req.Rule),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, campaign_segment)
+}
+
+type deleteCampaignSegmentRequest struct {
+	Cid       string `binding:"required" uri:"cid"`
+	SegmentID int32  `binding:"required,min=1" uri:"segment_id"`
+}
+
+func (s *Server) deleteCampaignSegment(ctx *gin.Context) {
+	var req deleteCampaignSegmentRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.store.DeleteCampaignSegment(ctx.Request.Context(), db.DeleteCampaignSegmentParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          req.Cid,
+		SegmentID:    req.SegmentID,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type appMetadataRequest struct {
+	AppID      string `binding:"required" json:"app"`
+	Categories string `json:"categories"`
+	Tags       string `json:"tags"`
+}
+
+func (s *Server) upsertAppMetadata(ctx *gin.Context) {
+	var req appMetadataRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	app_metadata, err := s.store.UpsertAppMetadata(ctx.Request.Context(), db.UpsertAppMetadataParams{
+		AppID:      req.AppID,
+		Categories: req.Categories,
+		Tags:       req.Tags,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, app_metadata)
+}
+
+type importAppMetadataRequest struct {
+	Apps []appMetadataRequest `binding:"required,min=1,max=10000,dive" json:"apps"`
+}
+
+func (s *Server) importAppMetadata(ctx *gin.Context) {
+	var req importAppMetadataRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	args := make([]db.UpsertAppMetadataParams, len(req.Apps))
+	for i, app := range req.Apps {
+		args[i] = db.UpsertAppMetadataParams{
+			AppID:      app.AppID,
+			Categories: app.Categories,
+			Tags:       app.Tags,
+		}
+	}
+
+	apps, err := s.store.ImportAppMetadata(ctx.Request.Context(), args)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"imported": len(apps)})
+}
+
+type appMetadataIDRequest struct {
+	AppID string `binding:"required" uri:"app"`
+}
+
+func (s *Server) getAppMetadata(ctx *gin.Context) {
+	var req appMetadataIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	app_metadata, err := s.store.GetAppMetadata(ctx.Request.Context(), req.AppID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, app_metadata)
+}
+
+func (s *Server) listAppMetadata(ctx *gin.Context) {
+	apps, err := s.store.ListAppMetadata(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apps)
+}
+
+func (s *Server) listUnknownApps(ctx *gin.Context) {
+	apps, err := s.store.ListUnknownApps(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apps)
+}
+
+func (s *Server) deleteAppMetadata(ctx *gin.Context) {
+	var req appMetadataIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := s.store.DeleteAppMetadata(ctx.Request.Context(), req.AppID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+// apiKeyResponse leaves out the key hash, which doubles as the cache key.
+type apiKeyResponse struct {
+	ID           int32      `json:"id"`
+	AdvertiserID int32      `json:"advertiser_id"`
+	Name         string     `json:"name"`
+	Prefix       string     `json:"prefix"`
+	Role         db.KeyRole `json:"role"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+}
+
+func newAPIKeyResponse(apiKey db.ApiKey) apiKeyResponse {
+	rsp := apiKeyResponse{
+		ID:           apiKey.ID,
+		AdvertiserID: apiKey.AdvertiserID,
+		Name:         apiKey.Name,
+		Prefix:       apiKey.Prefix,
+		Role:         apiKey.Role,
+		CreatedAt:    apiKey.CreatedAt,
+	}
+	if apiKey.RevokedAt.Valid {
+		rsp.RevokedAt = &apiKey.RevokedAt.Time
+	}
+	return rsp
+}
+
+type createApiKeyRequest struct {
+	AdvertiserID int32  `binding:"omitempty,min=1" json:"advertiser_id"`
+	Name         string `binding:"required,max=64" json:"name"`
+	Role         string `binding:"required,oneof=global_admin admin editor read_only delivery" json:"role"`
+}
+
+type createApiKeyResponse struct {
+	apiKeyResponse
+	Key string `json:"key"`
+}
+
+func (s *Server) createApiKey(ctx *gin.Context) {
+	var req createApiKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Keys default to the advertiser of the caller. Only global admins mint
+	// keys for other advertisers, or global admin keys.
+	scope := authScope(ctx)
+	if req.AdvertiserID == 0 {
+		req.AdvertiserID = authAdvertiserID(ctx)
+	}
+	if scope.Valid && (req.AdvertiserID != scope.Int32 || req.Role == string(db.KeyRoleGlobalAdmin)) {
+		abortWithError(ctx, http.StatusForbidden, "api key is not allowed to access this resource")
+		return
+	}
+
+	result, err := s.store.MintApiKey(ctx.Request.Context(), db.MintApiKeyParams{
+		AdvertiserID: req.AdvertiserID,
+		Name:         req.Name,
+		Role:         db.KeyRole(req.Role),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createApiKeyResponse{
+		apiKeyResponse: newAPIKeyResponse(result.ApiKey),
+		Key:            result.Key,
+	})
+}
+
+func (s *Server) listApiKeys(ctx *gin.Context) {
+	api_keys, err := s.store.ListApiKeys(ctx.Request.Context(), authScope(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rsp := make([]apiKeyResponse, 0, len(api_keys))
+	for _, api_key := range api_keys {
+		rsp = append(rsp, newAPIKeyResponse(api_key))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type apiKeyIDRequest struct {
+	ID int32 `binding:"required,min=1" uri:"id"`
+}
+
+func (s *Server) revokeApiKey(ctx *gin.Context) {
+	var req apiKeyIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_key, err := s.store.RevokeApiKey(ctx.Request.Context(), db.RevokeApiKeyParams{
+		ID:           req.ID,
+		AdvertiserID: authScope(ctx),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAPIKeyResponse(api_key))
+}
+
+// listCampaigns returns every campaign at once. It is deprecated in favour of
+// the paged searchCampaigns, which the responses point clients to.
+func (s *Server) listCampaigns(ctx *gin.Context) {
+	ctx.Header("Deprecation", "true")
+	ctx.Header("Link", `</v1/campaigns>; rel="successor-version"`)
+	campaigns, err := s.store.ListCampaigns(ctx.Request.Context(), authAdvertiserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, campaigns)
+}
+
+type searchCampaignsRequest struct {
+	Status       string    `binding:"omitempty,oneof=draft pending_review active paused completed archived" form:"status"`
+	Name         string    `form:"name"`
+	CreatedSince time.Time `form:"created_since"`
+	CreatedUntil time.Time `form:"created_until"`
+	Country      string    `form:"country"`
+	App          string    `form:"app"`
+	Os           string    `form:"os"`
+	Sort         string    `binding:"omitempty,oneof=-created_at created_at name -name" form:"sort"`
+	Cursor       string    `form:"cursor"`
+	Limit        int32     `binding:"omitempty,min=1,max=1000" form:"limit"`
+}
+
+func (s *Server) searchCampaigns(ctx *gin.Context) {
+	var req searchCampaignsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+
+	result, err := s.store.SearchCampaigns(ctx.Request.Context(), db.SearchCampaignsParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Status:       db.StatusType(req.Status),
+		Name:         req.Name,
+		CreatedSince: req.CreatedSince,
+		CreatedUntil: req.CreatedUntil,
+		Country:      req.Country,
+		App:          req.App,
+		Os:           req.Os,
+		Sort:         req.Sort,
+		Cursor:       req.Cursor,
+		Limit:        req.Limit,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type campaignSelectorRequest struct {
+	All     bool     `json:"all"`
+	Cids    []string `json:"cids"`
+	Labels  []string `json:"labels"`
+	Status  string   `binding:"omitempty,oneof=draft pending_review active paused completed archived" json:"status"`
+	Country string   `json:"country"`
+	App     string   `json:"app"`
+	Os      string   `json:"os"`
+}
+
+type bulkCampaignsRequest struct {
+	Selector  campaignSelectorRequest `json:"selector"`
+	Operation string                  `binding:"required,oneof=pause resume delete label unlabel" json:"operation"`
+	Labels    []string                `binding:"required_if=Operation label,required_if=Operation unlabel" json:"labels"`
+	Reason    string                  `binding:"max=256" json:"reason"`
+}
+
+// bulkCampaigns applies one operation to every campaign a selector picks, in
+// a single transaction.
+func (s *Server) bulkCampaigns(ctx *gin.Context) {
+	var req bulkCampaignsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.store.BulkUpdateCampaigns(ctx.Request.Context(), db.BulkUpdateCampaignsParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Selector: db.CampaignSelector{
+			All:     req.Selector.All,
+			Cids:    req.Selector.Cids,
+			Labels:  req.Selector.Labels,
+			Status:  db.StatusType(req.Selector.Status),
+			Country: req.Selector.Country,
+			App:     req.Selector.App,
+			Os:      req.Selector.Os,
+		},
+		Operation: req.Operation,
+		Labels:    req.Labels,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type createAdvertiserRequest struct {
+	Name string `binding:"required,max=64" json:"name"`
+}
+
+func (s *Server) createAdvertiser(ctx *gin.Context) {
+	var req createAdvertiserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	advertiser, err := s.store.CreateAdvertiser(ctx.Request.Context(), req.Name)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, advertiser)
+}
+
+type advertiserIDRequest struct {
+	ID int32 `binding:"required,min=1" uri:"id"`
+}
+
+func (s *Server) getAdvertiser(ctx *gin.Context) {
+	var req advertiserIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	advertiser, err := s.store.GetAdvertiser(ctx.Request.Context(), req.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, advertiser)
+}
+
+func (s *Server) listAdvertisers(ctx *gin.Context) {
+	advertisers, err := s.store.ListAdvertisers(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, advertisers)
+}
+
+func (s *Server) deleteAdvertiser(ctx *gin.Context) {
+	var req advertiserIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := s.store.DeleteAdvertiser(ctx.Request.Context(), req.ID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type addPublisherAdvertiserRequest struct {
+	AppID        string `binding:"required" json:"app"`
+	AdvertiserID int32  `binding:"required,min=1" json:"advertiser_id"`
+}
+
+func (s *Server) addPublisherAdvertiser(ctx *gin.Context) {
+	var req addPublisherAdvertiserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	publisher_advertiser, err := s.store.AddPublisherAdvertiser(ctx.Request.Context(), db.AddPublisherAdvertiserParams{
+		AppID:        req.AppID,
+		AdvertiserID: req.AdvertiserID,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, publisher_advertiser)
+}
+
+type listPublisherAdvertisersRequest struct {
+	AppID string `binding:"required" uri:"app"`
+}
+
+func (s *Server) listPublisherAdvertisers(ctx *gin.Context) {
+	var req listPublisherAdvertisersRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	publisher_advertisers, err := s.store.ListPublisherAdvertisers(ctx.Request.Context(), req.AppID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, publisher_advertisers)
+}
+
+type deletePublisherAdvertiserRequest struct {
+	AppID        string `binding:"required" uri:"app"`
+	AdvertiserID int32  `binding:"required,min=1" uri:"advertiser_id"`
+}
+
+func (s *Server) deletePublisherAdvertiser(ctx *gin.Context) {
+	var req deletePublisherAdvertiserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := s.store.DeletePublisherAdvertiser(ctx.Request.Context(), db.DeletePublisherAdvertiserParams{
+		AppID:        req.AppID,
+		AdvertiserID: req.AdvertiserID,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+type loginUserRequest struct {
+	IDToken string `binding:"required" json:"id_token"`
+}
+
+type loginUserResponse struct {
+	Token        string     `json:"token"`
+	Subject      string     `json:"subject"`
+	Role         db.KeyRole `json:"role"`
+	AdvertiserID int32      `json:"advertiser_id"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+}
+
+// loginUser exchanges an identity provider token for a session. The session
+// lasts the configured duration and cannot be refreshed, so the dashboard has
+// to log in again with a fresh token once it expires.
+func (s *Server) loginUser(ctx *gin.Context) {
+	var req loginUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := s.login.verifier.Verify(req.IDToken)
+	if err != nil {
+		if errors.Is(err, token.ErrInvalidToken) || errors.Is(err, token.ErrExpiredToken) {
+			abortWithError(ctx, http.StatusUnauthorized, err.Error())
+			return
+		}
+		ctx.Error(err)
+		return
+	}
+
+	role, ok := s.login.roles.role(claims.Strings(s.login.roleClaim))
+	if !ok {
+		abortWithError(ctx, http.StatusForbidden, "no role is granted to this user")
+		return
+	}
+
+	names := claims.Strings(s.login.advertiserClaim)
+	if len(names) == 0 {
+		abortWithError(ctx, http.StatusForbidden, "no advertiser is granted to this user")
+		return
+	}
+	advertiser, err := s.store.GetAdvertiserByName(ctx.Request.Context(), names[0])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			abortWithError(ctx, http.StatusForbidden, "advertiser not found")
+			return
+		}
+		ctx.Error(err)
+		return
+	}
+
+	// Sessions never outlive the ID token they were created from.
+	expiresAt := time.Now().Add(s.config.SessionDuration)
+	if claims.ExpiresAt.Before(expiresAt) {
+		expiresAt = claims.ExpiresAt
+	}
+
+	result, err := s.store.CreateSession(ctx.Request.Context(), db.Session{
+		Subject:      claims.Subject,
+		Role:         role,
+		AdvertiserID: advertiser.ID,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, loginUserResponse{
+		Token:        result.Token,
+		Subject:      result.Session.Subject,
+		Role:         result.Session.Role,
+		AdvertiserID: result.Session.AdvertiserID,
+		ExpiresAt:    result.Session.ExpiresAt,
+	})
+}
+
+func (s *Server) logoutUser(ctx *gin.Context) {
+	key, _ := requestAPIKey(ctx)
+	if !strings.HasPrefix(key, util.SessionTokenPrefix) {
+		abortWithError(ctx, http.StatusBadRequest, "only sessions can be logged out")
+		return
+	}
+
+	if err := s.store.DeleteSession(ctx.Request.Context(), key); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
+}
+
+type listAuditLogRequest struct {
+	Actor        string    `form:"actor"`
+	RequestID    string    `form:"request_id"`
+	Operation    string    `binding:"omitempty,oneof=create update delete restore" form:"operation"`
+	Entity       string    `form:"entity"`
+	AdvertiserID int32     `binding:"omitempty,min=1" form:"advertiser_id"`
+	EntityID     string    `form:"entity_id"`
+	Since        time.Time `form:"since"`
+	Until        time.Time `form:"until"`
+	BeforeID     int64     `binding:"omitempty,min=1" form:"before_id"`
+	Limit        int32     `binding:"omitempty,min=1,max=1000" form:"limit"`
+}
+
+type auditLogResponse struct {
+	ID           int64             `json:"id"`
+	Actor        string            `json:"actor"`
+	SourceIP     string            `json:"source_ip"`
+	RequestID    string            `json:"request_id"`
+	Operation    db.AuditOperation `json:"operation"`
+	Entity       string            `json:"entity"`
+	AdvertiserID *int32            `json:"advertiser_id"`
+	EntityID     string            `json:"entity_id"`
+	Before       json.RawMessage   `json:"before"`
+	After        json.RawMessage   `json:"after"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+func newAuditLogResponse(entry db.AuditLog) auditLogResponse {
+	rsp := auditLogResponse{
+		ID:        entry.ID,
+		Actor:     entry.Actor,
+		SourceIP:  entry.SourceIp,
+		RequestID: entry.RequestID,
+		Operation: entry.Operation,
+		Entity:    entry.Entity,
+		EntityID:  entry.EntityID,
+		Before:    entry.Before,
+		After:     entry.After,
+		CreatedAt: entry.CreatedAt,
+	}
+	if entry.AdvertiserID.Valid {
+		rsp.AdvertiserID = &entry.AdvertiserID.Int32
+	}
+	return rsp
+}
+
+func textFilter(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func timeFilter(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
+}
+
+// listAuditLog returns audit entries newest first. Pass the id of the last
+// entry as before_id to fetch the next page.
+func (s *Server) listAuditLog(ctx *gin.Context) {
+	var req listAuditLogRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+	// Only global admins read the entries of other advertisers.
+	advertiserID := pgtype.Int4{Int32: req.AdvertiserID, Valid: req.AdvertiserID != 0}
+	if scope := authScope(ctx); scope.Valid {
+		if advertiserID.Valid && advertiserID != scope {
+			abortWithError(ctx, http.StatusForbidden, "api key is not allowed to access this resource")
+			return
+		}
+		advertiserID = scope
+	}
+
+	entries, err := s.store.ListAuditLog(ctx.Request.Context(), db.ListAuditLogParams{
+		Actor:        textFilter(req.Actor),
+		RequestID:    textFilter(req.RequestID),
+		Operation:    db.NullAuditOperation{AuditOperation: db.AuditOperation(req.Operation), Valid: req.Operation != ""},
+		Entity:       textFilter(req.Entity),
+		AdvertiserID: advertiserID,
+		EntityID:     textFilter(req.EntityID),
+		Since:        timeFilter(req.Since),
+		Until:        timeFilter(req.Until),
+		BeforeID:     pgtype.Int8{Int64: req.BeforeID, Valid: req.BeforeID != 0},
+		RowLimit:     req.Limit,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rsp := make([]auditLogResponse, 0, len(entries))
+	for _, entry := range entries {
+		rsp = append(rsp, newAuditLogResponse(entry))
+	}
+	ctx.JSON(http.StatusOK, rsp)
 }
@@ -1,15 +1,21 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
 )
 
 type Server struct {
+	config util.Config
 	store  db.Store
+	login  *oidcLogin
 	router *gin.Engine
 }
 
@@ -17,9 +23,21 @@ func (server *Server) Router() *gin.Engine {
 	return server.router
 }
 
-func NewServer(store db.Store) *Server {
-	server := &Server{store: store}
+func NewServer(config util.Config, store db.Store) (*Server, error) {
+	server := &Server{config: config, store: store}
+	if config.OIDCJWKSSource != "" {
+		login, err := newOIDCLogin(config)
+		if err != nil {
+			return nil, fmt.Errorf("cannot set up login: %w", err)
+		}
+		server.login = login
+	}
 	router := gin.Default()
+	router.Use(requestIDMiddleware(), errorMiddleware())
+
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("version_range", validVersionRange)
+	}
 
 	router.LoadHTMLFiles("templates/index.html")
 	router.GET("/", func(c *gin.Context) {
@@ -33,33 +51,161 @@ func NewServer(store db.Store) *Server {
 		})
 	})
 
-	router.GET("/v1/delivery", server.delivery)
-	router.GET("/v1/get_campaign/:cid", server.getCampaign)
-	router.POST("/v1/create_campaign", server.createCampaign)
-	router.POST("/v1/add_campaign", server.addCampaign)
-	router.POST("/v1/add_target_app", server.addTargetApp)
-	router.POST("/v1/add_target_country", server.addTargetCountry)
-	router.POST("/v1/add_target_os", server.addTargetOs)
-	router.PATCH("/v1/toggle_status/:cid", server.toggleStatus)
-	router.PATCH("/v1/update_campaign_name", server.updateCampaignName)
-	router.PATCH("/v1/update_campaign_image", server.updateCampaignImage)
-	router.PATCH("/v1/update_campaign_cta", server.updateCampaignCta)
-	router.PATCH("/v1/update_target_app", server.updateTargetApp)
-	router.PATCH("/v1/update_target_country", server.updateTargetCountry)
-	router.PATCH("/v1/update_target_os", server.updateTargetOs)
-	router.DELETE("/v1/delete_campaign/:cid", server.deleteCampaign)
-	router.DELETE("/v1/delete_target_app/:cid", server.deleteTargetApp)
-	router.DELETE("/v1/delete_target_country/:cid", server.deleteTargetCountry)
-	router.DELETE("/v1/delete_target_os/:cid", server.deleteTargetOs)
+	if server.login != nil {
+		router.POST("/v1/login", server.loginUser)
+	}
+
+	idempotency := idempotencyMiddleware(store)
+	v1 := router.Group("/v1", authMiddleware(store))
+	v1.POST("/logout", server.logoutUser)
+	deliveryRoutes := v1.Group("", requireRole(db.KeyRoleGlobalAdmin, db.KeyRoleAdmin, db.KeyRoleEditor, db.KeyRoleReadOnly, db.KeyRoleDelivery), idempotency)
+	readRoutes := v1.Group("", requireRole(db.KeyRoleGlobalAdmin, db.KeyRoleAdmin, db.KeyRoleEditor, db.KeyRoleReadOnly), idempotency)
+	writeRoutes := v1.Group("", requireRole(db.KeyRoleGlobalAdmin, db.KeyRoleAdmin, db.KeyRoleEditor), idempotency)
+	adminRoutes := v1.Group("", requireRole(db.KeyRoleGlobalAdmin, db.KeyRoleAdmin), idempotency)
+	globalAdminRoutes := v1.Group("", requireRole(db.KeyRoleGlobalAdmin), idempotency)
+
+	deliveryRoutes.GET("/delivery", server.delivery)
+	readRoutes.GET("/get_campaign/:cid", server.getCampaign)
+	readRoutes.GET("/list_campaigns", server.listCampaigns)
+	readRoutes.GET("/campaigns", server.searchCampaigns)
+	readRoutes.GET("/campaigns:method", customMethods(map[string]gin.HandlerFunc{
+		"export": server.exportCampaigns,
+	}))
+	writeRoutes.POST("/campaigns:method", customMethods(map[string]gin.HandlerFunc{
+		"import": server.importCampaigns,
+		"bulk":   server.bulkCampaigns,
+	}))
+	readRoutes.GET("/campaigns/:cid", server.getCampaign)
+	readRoutes.GET("/campaigns/:cid/history", server.listCampaignHistory)
+	writeRoutes.POST("/create_campaign", server.createCampaign)
+	writeRoutes.POST("/add_campaign", server.addCampaign)
+	writeRoutes.POST("/campaigns/:cid/revert", server.revertCampaign)
+	writeRoutes.POST("/campaigns/:cid/restore", server.restoreCampaign)
+	writeRoutes.POST("/campaigns/:cid/clone", server.cloneCampaign)
+	writeRoutes.POST("/campaigns/:cid/submit", server.transitionCampaign(db.TransitionSubmit))
+	adminRoutes.POST("/campaigns/:cid/approve", server.transitionCampaign(db.TransitionApprove))
+	adminRoutes.POST("/campaigns/:cid/reject", server.transitionCampaign(db.TransitionReject))
+	writeRoutes.POST("/campaigns/:cid/pause", server.transitionCampaign(db.TransitionPause))
+	writeRoutes.POST("/campaigns/:cid/resume", server.transitionCampaign(db.TransitionResume))
+	writeRoutes.POST("/campaigns/:cid/complete", server.transitionCampaign(db.TransitionComplete))
+	writeRoutes.POST("/campaigns/:cid/archive", server.transitionCampaign(db.TransitionArchive))
+	readRoutes.GET("/creative_revisions", server.listCreativeRevisions)
+	adminRoutes.POST("/campaigns/:cid/creative_revisions/:id/approve", server.reviewCreativeRevision(true))
+	adminRoutes.POST("/campaigns/:cid/creative_revisions/:id/reject", server.reviewCreativeRevision(false))
+	writeRoutes.POST("/add_target_app", server.addTargetApp)
+	writeRoutes.POST("/add_target_country", server.addTargetCountry)
+	writeRoutes.POST("/add_target_os", server.addTargetOs)
+	writeRoutes.POST("/add_target_os_version", server.addTargetOsVersion)
+	writeRoutes.POST("/add_target_app_version", server.addTargetAppVersion)
+	writeRoutes.POST("/add_target_language", server.addTargetLanguage)
+	writeRoutes.POST("/add_target_region", server.addTargetRegion)
+	writeRoutes.POST("/add_target_city", server.addTargetCity)
+	writeRoutes.POST("/add_target_radius", server.addTargetRadius)
+	writeRoutes.POST("/add_target_category", server.addTargetCategory)
+	writeRoutes.POST("/add_target_keyword", server.addTargetKeyword)
+	writeRoutes.POST("/add_campaign_locale", server.addCampaignLocale)
+	writeRoutes.POST("/add_campaign_label", server.addCampaignLabel)
+	writeRoutes.PATCH("/toggle_status/:cid", server.toggleStatus)
+	writeRoutes.PATCH("/update_campaign_name", server.updateCampaignName)
+	writeRoutes.PATCH("/update_campaign_image", server.updateCampaignImage)
+	writeRoutes.PATCH("/update_campaign_cta", server.updateCampaignCta)
+	writeRoutes.PATCH("/update_target_app", server.updateTargetApp)
+	writeRoutes.PATCH("/update_target_country", server.updateTargetCountry)
+	writeRoutes.PATCH("/update_target_os", server.updateTargetOs)
+	writeRoutes.PATCH("/update_target_os_version", server.updateTargetOsVersion)
+	writeRoutes.PATCH("/update_target_app_version", server.updateTargetAppVersion)
+	writeRoutes.PATCH("/update_target_language", server.updateTargetLanguage)
+	writeRoutes.PATCH("/update_target_region", server.updateTargetRegion)
+	writeRoutes.PATCH("/update_target_city", server.updateTargetCity)
+	writeRoutes.PATCH("/update_target_category", server.updateTargetCategory)
+	writeRoutes.PATCH("/update_target_keyword", server.updateTargetKeyword)
+	writeRoutes.PATCH("/update_campaign_locale", server.updateCampaignLocale)
+	writeRoutes.DELETE("/delete_campaign/:cid", server.deleteCampaign)
+	writeRoutes.DELETE("/delete_target_app/:cid", server.deleteTargetApp)
+	writeRoutes.DELETE("/delete_target_country/:cid", server.deleteTargetCountry)
+	writeRoutes.DELETE("/delete_target_os/:cid", server.deleteTargetOs)
+	writeRoutes.DELETE("/delete_target_os_version/:cid", server.deleteTargetOsVersion)
+	writeRoutes.DELETE("/delete_target_app_version/:cid", server.deleteTargetAppVersion)
+	writeRoutes.DELETE("/delete_target_language/:cid", server.deleteTargetLanguage)
+	writeRoutes.DELETE("/delete_target_region/:cid", server.deleteTargetRegion)
+	writeRoutes.DELETE("/delete_target_city/:cid", server.deleteTargetCity)
+	writeRoutes.DELETE("/delete_target_category/:cid", server.deleteTargetCategory)
+	writeRoutes.DELETE("/delete_target_keyword/:cid", server.deleteTargetKeyword)
+	writeRoutes.DELETE("/delete_target_radius/:cid", server.deleteTargetRadius)
+	writeRoutes.DELETE("/delete_target_radius/:cid/:id", server.deleteTargetRadiusCircle)
+	writeRoutes.DELETE("/delete_campaign_locale/:cid/:locale", server.deleteCampaignLocale)
+	writeRoutes.DELETE("/delete_campaign_label/:cid/:label", server.deleteCampaignLabel)
+
+	writeRoutes.POST("/create_target_list", server.createTargetList)
+	readRoutes.GET("/get_target_list/:id", server.getTargetList)
+	readRoutes.GET("/list_target_lists", server.listTargetLists)
+	readRoutes.GET("/get_target_list_history/:id", server.getTargetListHistory)
+	writeRoutes.PATCH("/update_target_list", server.updateTargetList)
+	writeRoutes.DELETE("/delete_target_list/:id", server.deleteTargetList)
+	writeRoutes.POST("/add_campaign_target_list", server.addCampaignTargetList)
+	writeRoutes.DELETE("/delete_campaign_target_list/:cid/:list_id", server.deleteCampaignTargetList)
+	writeRoutes.POST("/create_segment", server.createSegment)
+	readRoutes.GET("/get_segment/:id", server.getSegment)
+	readRoutes.GET("/list_segments", server.listSegments)
+	writeRoutes.POST("/upload_segment/:id", server.uploadSegment)
+	writeRoutes.DELETE("/delete_segment/:id", server.deleteSegment)
+	writeRoutes.POST("/add_campaign_segment", server.addCampaignSegment)
+	writeRoutes.DELETE("/delete_campaign_segment/:cid/:segment_id", server.deleteCampaignSegment)
+	writeRoutes.POST("/upsert_app_metadata", server.upsertAppMetadata)
+	writeRoutes.POST("/import_app_metadata", server.importAppMetadata)
+	readRoutes.GET("/get_app_metadata/:app", server.getAppMetadata)
+	readRoutes.GET("/list_app_metadata", server.listAppMetadata)
+	readRoutes.GET("/list_unknown_apps", server.listUnknownApps)
+	writeRoutes.DELETE("/delete_app_metadata/:app", server.deleteAppMetadata)
+
+	adminRoutes.POST("/create_api_key", server.createApiKey)
+	adminRoutes.GET("/list_api_keys", server.listApiKeys)
+	adminRoutes.DELETE("/revoke_api_key/:id", server.revokeApiKey)
+	globalAdminRoutes.POST("/create_advertiser", server.createAdvertiser)
+	globalAdminRoutes.GET("/get_advertiser/:id", server.getAdvertiser)
+	globalAdminRoutes.GET("/list_advertisers", server.listAdvertisers)
+	globalAdminRoutes.DELETE("/delete_advertiser/:id", server.deleteAdvertiser)
+	globalAdminRoutes.POST("/add_publisher_advertiser", server.addPublisherAdvertiser)
+	globalAdminRoutes.GET("/list_publisher_advertisers/:app", server.listPublisherAdvertisers)
+	globalAdminRoutes.DELETE("/delete_publisher_advertiser/:app/:advertiser_id", server.deletePublisherAdvertiser)
+	readRoutes.GET("/events/stream", server.streamEvents)
+	adminRoutes.GET("/audit", server.listAuditLog)
+	adminRoutes.POST("/webhooks", server.createWebhook)
+	adminRoutes.GET("/webhooks", server.listWebhooks)
+	adminRoutes.DELETE("/webhooks/:id", server.deleteWebhook)
+	adminRoutes.GET("/webhook_deliveries", server.listWebhookDeliveries)
+	adminRoutes.GET("/webhook_dead_letters", server.listWebhookDeadLetters)
+	adminRoutes.POST("/webhook_dead_letters/:id/redeliver", server.redeliverWebhook)
+
+	v2 := router.Group("/v2", authMiddleware(store))
+	v2ReadRoutes := v2.Group("", requireRole(db.KeyRoleGlobalAdmin, db.KeyRoleAdmin, db.KeyRoleEditor, db.KeyRoleReadOnly), idempotency)
+	v2WriteRoutes := v2.Group("", requireRole(db.KeyRoleGlobalAdmin, db.KeyRoleAdmin, db.KeyRoleEditor), idempotency)
+	v2AdminRoutes := v2.Group("", requireRole(db.KeyRoleGlobalAdmin, db.KeyRoleAdmin), idempotency)
+
+	v2ReadRoutes.GET("/campaigns", server.searchCampaigns)
+	v2ReadRoutes.GET("/campaigns/:cid", server.getCampaign)
+	v2ReadRoutes.GET("/campaigns/:cid/history", server.listCampaignHistory)
+	v2WriteRoutes.POST("/campaigns", server.createCampaignV2)
+	v2WriteRoutes.PATCH("/campaigns/:cid", server.patchCampaign)
+	v2WriteRoutes.DELETE("/campaigns/:cid", server.deleteCampaignV2)
+	v2WriteRoutes.POST("/campaigns/:cid/revert", server.revertCampaign)
+	v2WriteRoutes.POST("/campaigns/:cid/restore", server.restoreCampaign)
+	v2WriteRoutes.POST("/campaigns/:cid/clone", server.cloneCampaign)
+	v2WriteRoutes.POST("/campaigns/:cid/submit", server.transitionCampaign(db.TransitionSubmit))
+	v2AdminRoutes.POST("/campaigns/:cid/approve", server.transitionCampaign(db.TransitionApprove))
+	v2AdminRoutes.POST("/campaigns/:cid/reject", server.transitionCampaign(db.TransitionReject))
+	v2WriteRoutes.POST("/campaigns/:cid/pause", server.transitionCampaign(db.TransitionPause))
+	v2WriteRoutes.POST("/campaigns/:cid/resume", server.transitionCampaign(db.TransitionResume))
+	v2WriteRoutes.POST("/campaigns/:cid/complete", server.transitionCampaign(db.TransitionComplete))
+	v2WriteRoutes.POST("/campaigns/:cid/archive", server.transitionCampaign(db.TransitionArchive))
+	v2ReadRoutes.GET("/creative_revisions", server.listCreativeRevisions)
+	v2AdminRoutes.POST("/campaigns/:cid/creative_revisions/:id/approve", server.reviewCreativeRevision(true))
+	v2AdminRoutes.POST("/campaigns/:cid/creative_revisions/:id/reject", server.reviewCreativeRevision(false))
 
 	server.router = router
-	return server
+	return server, nil
 }
 
 func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
-
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
-}
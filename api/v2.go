@@ -0,0 +1,186 @@
+package api
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+// The v2 API exposes a campaign as one resource, in the form returned by
+// GET /v2/campaigns/:cid, and changes it with a single JSON merge patch.
+
+const mergePatchContentType = "application/merge-patch+json"
+
+type createCampaignV2Request struct {
+	Cid            string `binding:"required" json:"cid"`
+	Name           string `binding:"required,min=6,max=32" json:"name"`
+	Img            string `binding:"required" json:"img"`
+	Cta            string `binding:"required" json:"cta"`
+	AppID          string `json:"app_id"`
+	AppRule        string `binding:"omitempty,oneof=include exclude" json:"app_rule"`
+	Country        string `json:"country"`
+	CountryRule    string `binding:"omitempty,oneof=include exclude" json:"country_rule"`
+	Os             string `json:"os"`
+	OsRule         string `binding:"omitempty,oneof=include exclude" json:"os_rule"`
+	OsVersion      string `binding:"omitempty,version_range" json:"os_version"`
+	OsVersionRule  string `binding:"omitempty,oneof=include exclude" json:"os_version_rule"`
+	AppVersion     string `binding:"omitempty,version_range" json:"app_version"`
+	AppVersionRule string `binding:"omitempty,oneof=include exclude" json:"app_version_rule"`
+	Language       string `json:"language"`
+	LanguageRule   string `binding:"omitempty,oneof=include exclude" json:"language_rule"`
+	Region         string `json:"region"`
+	RegionRule     string `binding:"omitempty,oneof=include exclude" json:"region_rule"`
+	City           string `json:"city"`
+	CityRule       string `binding:"omitempty,oneof=include exclude" json:"city_rule"`
+	Category       string `json:"category"`
+	CategoryRule   string `binding:"omitempty,oneof=include exclude" json:"category_rule"`
+	Keyword        string `json:"keyword"`
+	KeywordRule    string `binding:"omitempty,oneof=include exclude" json:"keyword_rule"`
+	Status         string `binding:"omitempty,oneof=draft" json:"status"`
+}
+
+// missingRule returns the first targeting given without a rule.
+func (req createCampaignV2Request) missingRule() string {
+	targeting := [][3]string{
+		{"app_id", req.AppID, req.AppRule},
+		{"country", req.Country, req.CountryRule},
+		{"os", req.Os, req.OsRule},
+		{"os_version", req.OsVersion, req.OsVersionRule},
+		{"app_version", req.AppVersion, req.AppVersionRule},
+		{"language", req.Language, req.LanguageRule},
+		{"region", req.Region, req.RegionRule},
+		{"city", req.City, req.CityRule},
+		{"category", req.Category, req.CategoryRule},
+		{"keyword", req.Keyword, req.KeywordRule},
+	}
+	for _, target := range targeting {
+		if target[1] != "" && target[2] == "" {
+			return target[0]
+		}
+	}
+	return ""
+}
+
+func (s *Server) createCampaignV2(ctx *gin.Context) {
+	var req createCampaignV2Request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if field := req.missingRule(); field != "" {
+		abortWithError(ctx, http.StatusBadRequest, fmt.Sprintf("%s requires a rule", field))
+		return
+	}
+
+	result, err := s.store.CreateCampaign(ctx.Request.Context(), db.CreateCampaignParams{
+		AdvertiserID:   authAdvertiserID(ctx),
+		Cid:            req.Cid,
+		Name:           req.Name,
+		Img:            req.Img,
+		Cta:            req.Cta,
+		AppID:          req.AppID,
+		AppRule:        db.RuleType(req.AppRule),
+		Country:        req.Country,
+		CountryRule:    db.RuleType(req.CountryRule),
+		Os:             req.Os,
+		OsRule:         db.RuleType(req.OsRule),
+		OsVersion:      req.OsVersion,
+		OsVersionRule:  db.RuleType(req.OsVersionRule),
+		AppVersion:     req.AppVersion,
+		AppVersionRule: db.RuleType(req.AppVersionRule),
+		Language:       req.Language,
+		LanguageRule:   db.RuleType(req.LanguageRule),
+		Region:         req.Region,
+		RegionRule:     db.RuleType(req.RegionRule),
+		City:           req.City,
+		CityRule:       db.RuleType(req.CityRule),
+		Category:       req.Category,
+		CategoryRule:   db.RuleType(req.CategoryRule),
+		Keyword:        req.Keyword,
+		KeywordRule:    db.RuleType(req.KeywordRule),
+		Status:         db.StatusType(req.Status),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	campaign, err := s.store.ReadCampaign(ctx.Request.Context(), result.Key())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Location", "/v2/campaigns/"+campaign.Cid)
+	setETag(ctx, campaign.Version)
+	ctx.JSON(http.StatusCreated, campaign)
+}
+
+// patchCampaign applies a JSON merge patch (RFC 7386) to a campaign and its
+// targeting in one transaction.
+func (s *Server) patchCampaign(ctx *gin.Context) {
+	var uri getCampaignRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(ctx.ContentType())
+	if err != nil || (mediaType != mergePatchContentType && mediaType != gin.MIMEJSON) {
+		abortWithError(ctx, http.StatusUnsupportedMediaType, "patches must be sent as "+mergePatchContentType)
+		return
+	}
+
+	patch, err := ctx.GetRawData()
+	if err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	campaign, err := s.store.PatchCampaign(ctx.Request.Context(), db.PatchCampaignParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          uri.Cid,
+		Patch:        patch,
+		Version:      version,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	setETag(ctx, campaign.Version)
+	ctx.JSON(http.StatusOK, campaign)
+}
+
+func (s *Server) deleteCampaignV2(ctx *gin.Context) {
+	var uri getCampaignRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	key := db.CampaignKey{
+		AdvertiserID: authAdvertiserID(ctx),
+		Cid:          uri.Cid,
+	}
+	err := s.store.DeleteVersionedCampaign(ctx.Request.Context(), key, version)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
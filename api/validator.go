@@ -0,0 +1,14 @@
+package api
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+var validVersionRange validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if expr, ok := fieldLevel.Field().Interface().(string); ok {
+		_, err := util.ParseVersionRanges(expr)
+		return err == nil
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/webhook"
+)
+
+// webhookSubscriptionResponse leaves out the secret, which is only ever sent
+// by the caller.
+type webhookSubscriptionResponse struct {
+	ID         int32     `json:"id"`
+	Url        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newWebhookSubscriptionResponse(subscription db.WebhookSubscription) webhookSubscriptionResponse {
+	return webhookSubscriptionResponse{
+		ID:         subscription.ID,
+		Url:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+type createWebhookRequest struct {
+	Url        string   `binding:"required,http_url" json:"url"`
+	Secret     string   `binding:"required,min=16,max=256" json:"secret"`
+	EventTypes []string `binding:"required,min=1,dive,oneof=campaign.created campaign.updated campaign.status_changed campaign.deleted" json:"event_types"`
+}
+
+func (s *Server) createWebhook(ctx *gin.Context) {
+	var req createWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !s.config.WebhookAllowPrivateNetworks {
+		if err := webhook.CheckURL(ctx.Request.Context(), req.Url); err != nil {
+			abortWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	subscription, err := s.store.CreateWebhookSubscription(ctx.Request.Context(), db.CreateWebhookSubscriptionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		Url:          req.Url,
+		Secret:       req.Secret,
+		EventTypes:   req.EventTypes,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newWebhookSubscriptionResponse(subscription))
+}
+
+func (s *Server) listWebhooks(ctx *gin.Context) {
+	subscriptions, err := s.store.ListWebhookSubscriptions(ctx.Request.Context(), authAdvertiserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rsp := make([]webhookSubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		rsp = append(rsp, newWebhookSubscriptionResponse(subscription))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type webhookIDRequest struct {
+	ID int32 `binding:"required,min=1" uri:"id"`
+}
+
+// deleteWebhook removes a subscription along with its queued deliveries and
+// dead letters.
+func (s *Server) deleteWebhook(ctx *gin.Context) {
+	var req webhookIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	_, err := s.store.DeleteWebhookSubscription(ctx.Request.Context(), db.DeleteWebhookSubscriptionParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		ID:           req.ID,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "webhook deleted successfully"})
+}
+
+type webhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int32           `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// listWebhookDeliveries returns the deliveries waiting to be sent, or sent
+// again, oldest first.
+func (s *Server) listWebhookDeliveries(ctx *gin.Context) {
+	deliveries, err := s.store.ListWebhookDeliveries(ctx.Request.Context(), authAdvertiserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rsp := make([]webhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		rsp = append(rsp, webhookDeliveryResponse{
+			ID:             delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			EventType:      delivery.EventType,
+			Payload:        delivery.Payload,
+			Attempts:       delivery.Attempts,
+			NextAttemptAt:  delivery.NextAttemptAt,
+			LastError:      delivery.LastError,
+			CreatedAt:      delivery.CreatedAt,
+		})
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type webhookDeadLetterResponse struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int32           `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int32           `json:"attempts"`
+	LastError      string          `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"`
+	FailedAt       time.Time       `json:"failed_at"`
+}
+
+func (s *Server) listWebhookDeadLetters(ctx *gin.Context) {
+	letters, err := s.store.ListWebhookDeadLetters(ctx.Request.Context(), authAdvertiserID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rsp := make([]webhookDeadLetterResponse, 0, len(letters))
+	for _, letter := range letters {
+		rsp = append(rsp, webhookDeadLetterResponse{
+			ID:             letter.ID,
+			SubscriptionID: letter.SubscriptionID,
+			EventType:      letter.EventType,
+			Payload:        letter.Payload,
+			Attempts:       letter.Attempts,
+			LastError:      letter.LastError,
+			CreatedAt:      letter.CreatedAt,
+			FailedAt:       letter.FailedAt,
+		})
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type redeliverWebhookRequest struct {
+	ID int64 `binding:"required,min=1" uri:"id"`
+}
+
+// redeliverWebhook queues a dead letter to be sent again right away.
+func (s *Server) redeliverWebhook(ctx *gin.Context) {
+	var req redeliverWebhookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	delivery, err := s.store.RedeliverWebhook(ctx.Request.Context(), db.RedeliverWebhookParams{
+		AdvertiserID: authAdvertiserID(ctx),
+		ID:           req.ID,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "webhook queued for redelivery", "delivery_id": delivery.ID})
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS target_app_version;
+DROP TABLE IF EXISTS target_os_version;
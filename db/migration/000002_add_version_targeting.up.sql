@@ -0,0 +1,19 @@
+CREATE TABLE "target_os_version" (
+  "cid" text UNIQUE NOT NULL,
+  "os_version" text NOT NULL,
+  "rule" rule_type NOT NULL
+);
+
+CREATE TABLE "target_app_version" (
+  "cid" text UNIQUE NOT NULL,
+  "app_version" text NOT NULL,
+  "rule" rule_type NOT NULL
+);
+
+CREATE INDEX ON "target_os_version" ("cid");
+
+CREATE INDEX ON "target_app_version" ("cid");
+
+ALTER TABLE "target_os_version" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_app_version" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS campaign_locale;
+DROP TABLE IF EXISTS target_language;
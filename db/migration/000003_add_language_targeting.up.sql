@@ -0,0 +1,19 @@
+CREATE TABLE "target_language" (
+  "cid" text UNIQUE NOT NULL,
+  "language" text NOT NULL,
+  "rule" rule_type NOT NULL
+);
+
+CREATE TABLE "campaign_locale" (
+  "cid" text NOT NULL,
+  "locale" text NOT NULL,
+  "img" text NOT NULL DEFAULT '',
+  "cta" text NOT NULL DEFAULT '',
+  PRIMARY KEY ("cid", "locale")
+);
+
+CREATE INDEX ON "target_language" ("cid");
+
+ALTER TABLE "target_language" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "campaign_locale" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS target_radius;
+DROP TABLE IF EXISTS target_city;
+DROP TABLE IF EXISTS target_region;
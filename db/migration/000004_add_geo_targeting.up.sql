@@ -0,0 +1,32 @@
+CREATE TABLE "target_region" (
+  "cid" text UNIQUE NOT NULL,
+  "region" text NOT NULL,
+  "rule" rule_type NOT NULL
+);
+
+CREATE TABLE "target_city" (
+  "cid" text UNIQUE NOT NULL,
+  "city" text NOT NULL,
+  "rule" rule_type NOT NULL
+);
+
+CREATE TABLE "target_radius" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "cid" text NOT NULL,
+  "lat" double precision NOT NULL CHECK ("lat" BETWEEN -90 AND 90),
+  "lon" double precision NOT NULL CHECK ("lon" BETWEEN -180 AND 180),
+  "radius_km" double precision NOT NULL CHECK ("radius_km" > 0),
+  "rule" rule_type NOT NULL
+);
+
+CREATE INDEX ON "target_region" ("cid");
+
+CREATE INDEX ON "target_city" ("cid");
+
+CREATE INDEX ON "target_radius" ("cid");
+
+ALTER TABLE "target_region" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_city" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_radius" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS campaign_target_list;
+DROP TABLE IF EXISTS target_list_history;
+DROP TABLE IF EXISTS target_list;
+DROP TYPE IF EXISTS list_type;
@@ -0,0 +1,44 @@
+CREATE TYPE "list_type" AS ENUM (
+  'app',
+  'country',
+  'os'
+);
+
+CREATE TABLE "target_list" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "name" varchar(64) UNIQUE NOT NULL,
+  "list_type" list_type NOT NULL,
+  "items" text NOT NULL,
+  "version" INT NOT NULL DEFAULT 1,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "target_list_history" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "list_id" INT NOT NULL,
+  "version" INT NOT NULL,
+  "field_changed" text NOT NULL,
+  "old_value" text NOT NULL,
+  "new_value" text NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "campaign_target_list" (
+  "cid" text NOT NULL,
+  "list_id" INT NOT NULL,
+  "rule" rule_type NOT NULL,
+  PRIMARY KEY ("cid", "list_id")
+);
+
+CREATE INDEX ON "target_list_history" ("list_id");
+
+CREATE INDEX ON "campaign_target_list" ("list_id");
+
+ALTER TABLE "target_list_history" ADD FOREIGN KEY ("list_id") REFERENCES "target_list" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "campaign_target_list" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+-- A list still referenced by a campaign cannot be deleted, since silently
+-- dropping a blocklist would widen that campaign's targeting.
+ALTER TABLE "campaign_target_list" ADD FOREIGN KEY ("list_id") REFERENCES "target_list" ("id");
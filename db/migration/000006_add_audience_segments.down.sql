@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS campaign_segment;
+DROP TABLE IF EXISTS audience_segment;
+DROP TYPE IF EXISTS segment_storage;
@@ -0,0 +1,28 @@
+CREATE TYPE "segment_storage" AS ENUM (
+  'set',
+  'bloom'
+);
+
+CREATE TABLE "audience_segment" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "name" varchar(64) UNIQUE NOT NULL,
+  "storage" segment_storage NOT NULL DEFAULT 'set',
+  "member_count" BIGINT NOT NULL DEFAULT 0,
+  "bloom_bits" BIGINT NOT NULL DEFAULT 0,
+  "bloom_hashes" INT NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "campaign_segment" (
+  "cid" text NOT NULL,
+  "segment_id" INT NOT NULL,
+  "rule" rule_type NOT NULL,
+  PRIMARY KEY ("cid", "segment_id")
+);
+
+CREATE INDEX ON "campaign_segment" ("segment_id");
+
+ALTER TABLE "campaign_segment" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "campaign_segment" ADD FOREIGN KEY ("segment_id") REFERENCES "audience_segment" ("id");
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS target_keyword;
+DROP TABLE IF EXISTS target_category;
+DROP TABLE IF EXISTS unknown_app;
+DROP TABLE IF EXISTS app_metadata;
@@ -0,0 +1,33 @@
+CREATE TABLE "app_metadata" (
+  "app_id" text PRIMARY KEY NOT NULL,
+  "categories" text NOT NULL DEFAULT '',
+  "tags" text NOT NULL DEFAULT '',
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "unknown_app" (
+  "app_id" text PRIMARY KEY NOT NULL,
+  "request_count" BIGINT NOT NULL DEFAULT 1,
+  "first_seen_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_seen_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "target_category" (
+  "cid" text UNIQUE NOT NULL,
+  "category" text NOT NULL,
+  "rule" rule_type NOT NULL
+);
+
+CREATE TABLE "target_keyword" (
+  "cid" text UNIQUE NOT NULL,
+  "keyword" text NOT NULL,
+  "rule" rule_type NOT NULL
+);
+
+CREATE INDEX ON "target_category" ("cid");
+
+CREATE INDEX ON "target_keyword" ("cid");
+
+ALTER TABLE "target_category" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_keyword" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS api_key;
+DROP TYPE IF EXISTS key_role;
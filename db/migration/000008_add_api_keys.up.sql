@@ -0,0 +1,18 @@
+CREATE TYPE "key_role" AS ENUM (
+  'admin',
+  'editor',
+  'read_only',
+  'delivery'
+);
+
+CREATE TABLE "api_key" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "name" varchar(64) NOT NULL,
+  "prefix" varchar(16) NOT NULL,
+  "key_hash" text UNIQUE NOT NULL,
+  "role" key_role NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "revoked_at" timestamptz
+);
+
+CREATE INDEX ON "api_key" ("role");
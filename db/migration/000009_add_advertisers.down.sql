@@ -0,0 +1,123 @@
+-- Fails if two advertisers share a cid, since cids become globally unique again.
+ALTER TABLE "campaign_history" DROP CONSTRAINT "campaign_history_advertiser_id_cid_fkey";
+ALTER TABLE "target_app" DROP CONSTRAINT "target_app_advertiser_id_cid_fkey";
+ALTER TABLE "target_country" DROP CONSTRAINT "target_country_advertiser_id_cid_fkey";
+ALTER TABLE "target_os" DROP CONSTRAINT "target_os_advertiser_id_cid_fkey";
+ALTER TABLE "target_os_version" DROP CONSTRAINT "target_os_version_advertiser_id_cid_fkey";
+ALTER TABLE "target_app_version" DROP CONSTRAINT "target_app_version_advertiser_id_cid_fkey";
+ALTER TABLE "target_language" DROP CONSTRAINT "target_language_advertiser_id_cid_fkey";
+ALTER TABLE "campaign_locale" DROP CONSTRAINT "campaign_locale_advertiser_id_cid_fkey";
+ALTER TABLE "target_region" DROP CONSTRAINT "target_region_advertiser_id_cid_fkey";
+ALTER TABLE "target_city" DROP CONSTRAINT "target_city_advertiser_id_cid_fkey";
+ALTER TABLE "target_radius" DROP CONSTRAINT "target_radius_advertiser_id_cid_fkey";
+ALTER TABLE "campaign_target_list" DROP CONSTRAINT "campaign_target_list_advertiser_id_cid_fkey";
+ALTER TABLE "campaign_segment" DROP CONSTRAINT "campaign_segment_advertiser_id_cid_fkey";
+ALTER TABLE "target_category" DROP CONSTRAINT "target_category_advertiser_id_cid_fkey";
+ALTER TABLE "target_keyword" DROP CONSTRAINT "target_keyword_advertiser_id_cid_fkey";
+
+ALTER TABLE "campaign_locale" DROP CONSTRAINT "campaign_locale_pkey";
+
+ALTER TABLE "campaign_locale" ADD PRIMARY KEY ("cid", "locale");
+
+ALTER TABLE "campaign_target_list" DROP CONSTRAINT "campaign_target_list_pkey";
+
+ALTER TABLE "campaign_target_list" ADD PRIMARY KEY ("cid", "list_id");
+
+ALTER TABLE "campaign_segment" DROP CONSTRAINT "campaign_segment_pkey";
+
+ALTER TABLE "campaign_segment" ADD PRIMARY KEY ("cid", "segment_id");
+
+ALTER TABLE "target_app" DROP CONSTRAINT "target_app_advertiser_id_cid_key";
+
+ALTER TABLE "target_app" ADD UNIQUE ("cid");
+
+ALTER TABLE "target_country" DROP CONSTRAINT "target_country_advertiser_id_cid_key";
+
+ALTER TABLE "target_country" ADD UNIQUE ("cid");
+
+ALTER TABLE "target_os" DROP CONSTRAINT "target_os_advertiser_id_cid_key";
+
+ALTER TABLE "target_os" ADD UNIQUE ("cid");
+
+ALTER TABLE "target_os_version" DROP CONSTRAINT "target_os_version_advertiser_id_cid_key";
+
+ALTER TABLE "target_os_version" ADD UNIQUE ("cid");
+
+ALTER TABLE "target_app_version" DROP CONSTRAINT "target_app_version_advertiser_id_cid_key";
+
+ALTER TABLE "target_app_version" ADD UNIQUE ("cid");
+
+ALTER TABLE "target_language" DROP CONSTRAINT "target_language_advertiser_id_cid_key";
+
+ALTER TABLE "target_language" ADD UNIQUE ("cid");
+
+ALTER TABLE "target_region" DROP CONSTRAINT "target_region_advertiser_id_cid_key";
+
+ALTER TABLE "target_region" ADD UNIQUE ("cid");
+
+ALTER TABLE "target_city" DROP CONSTRAINT "target_city_advertiser_id_cid_key";
+
+ALTER TABLE "target_city" ADD UNIQUE ("cid");
+
+ALTER TABLE "target_category" DROP CONSTRAINT "target_category_advertiser_id_cid_key";
+
+ALTER TABLE "target_category" ADD UNIQUE ("cid");
+
+ALTER TABLE "target_keyword" DROP CONSTRAINT "target_keyword_advertiser_id_cid_key";
+
+ALTER TABLE "target_keyword" ADD UNIQUE ("cid");
+
+ALTER TABLE "campaign" DROP CONSTRAINT "campaign_pkey";
+
+ALTER TABLE "campaign" ADD PRIMARY KEY ("cid");
+
+ALTER TABLE "campaign_history" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_app" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_country" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_os" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_os_version" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_app_version" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_language" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "campaign_locale" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_region" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_city" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_radius" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "campaign_target_list" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "campaign_segment" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_category" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_keyword" ADD FOREIGN KEY ("cid") REFERENCES "campaign" ("cid") ON DELETE CASCADE;
+
+ALTER TABLE "campaign" DROP COLUMN "advertiser_id";
+ALTER TABLE "api_key" DROP COLUMN "advertiser_id";
+ALTER TABLE "campaign_history" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_app" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_country" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_os" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_os_version" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_app_version" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_language" DROP COLUMN "advertiser_id";
+ALTER TABLE "campaign_locale" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_region" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_city" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_radius" DROP COLUMN "advertiser_id";
+ALTER TABLE "campaign_target_list" DROP COLUMN "advertiser_id";
+ALTER TABLE "campaign_segment" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_category" DROP COLUMN "advertiser_id";
+ALTER TABLE "target_keyword" DROP COLUMN "advertiser_id";
+
+DROP TABLE IF EXISTS publisher_advertiser;
+DROP TABLE IF EXISTS advertiser;
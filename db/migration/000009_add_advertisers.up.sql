@@ -0,0 +1,233 @@
+CREATE TABLE "advertiser" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "name" varchar(64) UNIQUE NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- Publishers with rows here only receive campaigns of the listed advertisers.
+CREATE TABLE "publisher_advertiser" (
+  "app_id" text NOT NULL,
+  "advertiser_id" INT NOT NULL,
+  PRIMARY KEY ("app_id", "advertiser_id")
+);
+
+-- Campaigns and keys created before advertisers existed move to a default advertiser.
+INSERT INTO "advertiser" ("name") VALUES ('default');
+
+ALTER TABLE "campaign" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "campaign" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "campaign" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "api_key" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "api_key" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "api_key" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "campaign_history" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "campaign_history" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "campaign_history" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_app" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_app" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_app" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_country" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_country" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_country" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_os" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_os" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_os" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_os_version" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_os_version" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_os_version" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_app_version" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_app_version" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_app_version" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_language" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_language" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_language" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "campaign_locale" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "campaign_locale" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "campaign_locale" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_region" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_region" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_region" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_city" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_city" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_city" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_radius" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_radius" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_radius" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "campaign_target_list" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "campaign_target_list" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "campaign_target_list" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "campaign_segment" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "campaign_segment" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "campaign_segment" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_category" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_category" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_category" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "target_keyword" ADD COLUMN "advertiser_id" INT;
+
+UPDATE "target_keyword" SET "advertiser_id" = (SELECT "id" FROM "advertiser" WHERE "name" = 'default');
+
+ALTER TABLE "target_keyword" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+-- Cids are only unique within an advertiser, so every campaign reference
+-- is widened to the (advertiser_id, cid) pair.
+ALTER TABLE "campaign_history" DROP CONSTRAINT "campaign_history_cid_fkey";
+ALTER TABLE "target_app" DROP CONSTRAINT "target_app_cid_fkey";
+ALTER TABLE "target_country" DROP CONSTRAINT "target_country_cid_fkey";
+ALTER TABLE "target_os" DROP CONSTRAINT "target_os_cid_fkey";
+ALTER TABLE "target_os_version" DROP CONSTRAINT "target_os_version_cid_fkey";
+ALTER TABLE "target_app_version" DROP CONSTRAINT "target_app_version_cid_fkey";
+ALTER TABLE "target_language" DROP CONSTRAINT "target_language_cid_fkey";
+ALTER TABLE "campaign_locale" DROP CONSTRAINT "campaign_locale_cid_fkey";
+ALTER TABLE "target_region" DROP CONSTRAINT "target_region_cid_fkey";
+ALTER TABLE "target_city" DROP CONSTRAINT "target_city_cid_fkey";
+ALTER TABLE "target_radius" DROP CONSTRAINT "target_radius_cid_fkey";
+ALTER TABLE "campaign_target_list" DROP CONSTRAINT "campaign_target_list_cid_fkey";
+ALTER TABLE "campaign_segment" DROP CONSTRAINT "campaign_segment_cid_fkey";
+ALTER TABLE "target_category" DROP CONSTRAINT "target_category_cid_fkey";
+ALTER TABLE "target_keyword" DROP CONSTRAINT "target_keyword_cid_fkey";
+
+ALTER TABLE "campaign" DROP CONSTRAINT "campaign_pkey";
+
+ALTER TABLE "campaign" ADD PRIMARY KEY ("advertiser_id", "cid");
+
+ALTER TABLE "target_app" DROP CONSTRAINT "target_app_cid_key";
+
+ALTER TABLE "target_app" ADD UNIQUE ("advertiser_id", "cid");
+
+ALTER TABLE "target_country" DROP CONSTRAINT "target_country_cid_key";
+
+ALTER TABLE "target_country" ADD UNIQUE ("advertiser_id", "cid");
+
+ALTER TABLE "target_os" DROP CONSTRAINT "target_os_cid_key";
+
+ALTER TABLE "target_os" ADD UNIQUE ("advertiser_id", "cid");
+
+ALTER TABLE "target_os_version" DROP CONSTRAINT "target_os_version_cid_key";
+
+ALTER TABLE "target_os_version" ADD UNIQUE ("advertiser_id", "cid");
+
+ALTER TABLE "target_app_version" DROP CONSTRAINT "target_app_version_cid_key";
+
+ALTER TABLE "target_app_version" ADD UNIQUE ("advertiser_id", "cid");
+
+ALTER TABLE "target_language" DROP CONSTRAINT "target_language_cid_key";
+
+ALTER TABLE "target_language" ADD UNIQUE ("advertiser_id", "cid");
+
+ALTER TABLE "target_region" DROP CONSTRAINT "target_region_cid_key";
+
+ALTER TABLE "target_region" ADD UNIQUE ("advertiser_id", "cid");
+
+ALTER TABLE "target_city" DROP CONSTRAINT "target_city_cid_key";
+
+ALTER TABLE "target_city" ADD UNIQUE ("advertiser_id", "cid");
+
+ALTER TABLE "target_category" DROP CONSTRAINT "target_category_cid_key";
+
+ALTER TABLE "target_category" ADD UNIQUE ("advertiser_id", "cid");
+
+ALTER TABLE "target_keyword" DROP CONSTRAINT "target_keyword_cid_key";
+
+ALTER TABLE "target_keyword" ADD UNIQUE ("advertiser_id", "cid");
+
+ALTER TABLE "campaign_locale" DROP CONSTRAINT "campaign_locale_pkey";
+
+ALTER TABLE "campaign_locale" ADD PRIMARY KEY ("advertiser_id", "cid", "locale");
+
+ALTER TABLE "campaign_target_list" DROP CONSTRAINT "campaign_target_list_pkey";
+
+ALTER TABLE "campaign_target_list" ADD PRIMARY KEY ("advertiser_id", "cid", "list_id");
+
+ALTER TABLE "campaign_segment" DROP CONSTRAINT "campaign_segment_pkey";
+
+ALTER TABLE "campaign_segment" ADD PRIMARY KEY ("advertiser_id", "cid", "segment_id");
+
+CREATE INDEX ON "campaign_history" ("advertiser_id", "cid");
+
+CREATE INDEX ON "target_radius" ("advertiser_id", "cid");
+
+CREATE INDEX ON "api_key" ("advertiser_id");
+
+ALTER TABLE "campaign" ADD FOREIGN KEY ("advertiser_id") REFERENCES "advertiser" ("id");
+
+ALTER TABLE "api_key" ADD FOREIGN KEY ("advertiser_id") REFERENCES "advertiser" ("id");
+
+ALTER TABLE "publisher_advertiser" ADD FOREIGN KEY ("advertiser_id") REFERENCES "advertiser" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "campaign_history" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_app" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_country" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_os" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_os_version" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_app_version" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_language" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "campaign_locale" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_region" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_city" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_radius" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "campaign_target_list" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "campaign_segment" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_category" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+ALTER TABLE "target_keyword" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
@@ -0,0 +1,24 @@
+DROP TRIGGER IF EXISTS "campaign_audit" ON "campaign";
+DROP TRIGGER IF EXISTS "target_app_audit" ON "target_app";
+DROP TRIGGER IF EXISTS "target_os_audit" ON "target_os";
+DROP TRIGGER IF EXISTS "target_country_audit" ON "target_country";
+DROP TRIGGER IF EXISTS "target_os_version_audit" ON "target_os_version";
+DROP TRIGGER IF EXISTS "target_app_version_audit" ON "target_app_version";
+DROP TRIGGER IF EXISTS "target_language_audit" ON "target_language";
+DROP TRIGGER IF EXISTS "campaign_locale_audit" ON "campaign_locale";
+DROP TRIGGER IF EXISTS "target_region_audit" ON "target_region";
+DROP TRIGGER IF EXISTS "target_city_audit" ON "target_city";
+DROP TRIGGER IF EXISTS "target_radius_audit" ON "target_radius";
+DROP TRIGGER IF EXISTS "target_list_audit" ON "target_list";
+DROP TRIGGER IF EXISTS "campaign_target_list_audit" ON "campaign_target_list";
+DROP TRIGGER IF EXISTS "audience_segment_audit" ON "audience_segment";
+DROP TRIGGER IF EXISTS "campaign_segment_audit" ON "campaign_segment";
+DROP TRIGGER IF EXISTS "app_metadata_audit" ON "app_metadata";
+DROP TRIGGER IF EXISTS "target_category_audit" ON "target_category";
+DROP TRIGGER IF EXISTS "target_keyword_audit" ON "target_keyword";
+DROP TRIGGER IF EXISTS "api_key_audit" ON "api_key";
+DROP TRIGGER IF EXISTS "advertiser_audit" ON "advertiser";
+DROP TRIGGER IF EXISTS "publisher_advertiser_audit" ON "publisher_advertiser";
+DROP FUNCTION IF EXISTS audit_row();
+DROP TABLE IF EXISTS audit_log;
+DROP TYPE IF EXISTS audit_operation;
@@ -0,0 +1,123 @@
+CREATE TYPE "audit_operation" AS ENUM (
+  'create',
+  'update',
+  'delete'
+);
+
+-- audit_log has no foreign keys, so entries outlive the rows they describe.
+CREATE TABLE "audit_log" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "actor" varchar(160) NOT NULL,
+  "source_ip" varchar(64) NOT NULL,
+  "request_id" varchar(64) NOT NULL,
+  "operation" audit_operation NOT NULL,
+  "entity" varchar(64) NOT NULL,
+  "advertiser_id" INT,
+  "entity_id" text NOT NULL,
+  "before" jsonb,
+  "after" jsonb,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "audit_log" ("created_at");
+CREATE INDEX ON "audit_log" ("actor");
+CREATE INDEX ON "audit_log" ("request_id");
+CREATE INDEX ON "audit_log" ("entity", "advertiser_id", "entity_id");
+
+-- audit_row records every change of the table it is attached to. The actor,
+-- source IP and request ID are read from the adrouter.* settings, which the
+-- application sets on the connection; changes made outside of it are
+-- attributed to "system". API key hashes are never copied into the log.
+CREATE FUNCTION audit_row() RETURNS trigger AS $$
+DECLARE
+  old_row jsonb;
+  new_row jsonb;
+  row_data jsonb;
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    old_row := to_jsonb(OLD) - 'key_hash';
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    new_row := to_jsonb(NEW) - 'key_hash';
+  END IF;
+  row_data := COALESCE(new_row, old_row);
+
+  INSERT INTO audit_log (
+    actor, source_ip, request_id, operation, entity, advertiser_id, entity_id, before, after
+  ) VALUES (
+    COALESCE(NULLIF(current_setting('adrouter.actor', true), ''), 'system'),
+    COALESCE(current_setting('adrouter.source_ip', true), ''),
+    COALESCE(current_setting('adrouter.request_id', true), ''),
+    (CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END)::audit_operation,
+    TG_TABLE_NAME,
+    (row_data->>'advertiser_id')::int,
+    COALESCE(row_data->>'cid', row_data->>'app_id', row_data->>'id', ''),
+    old_row,
+    new_row
+  );
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "campaign_audit" AFTER INSERT OR UPDATE OR DELETE ON "campaign"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_app_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_app"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_os_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_os"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_country_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_country"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_os_version_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_os_version"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_app_version_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_app_version"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_language_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_language"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "campaign_locale_audit" AFTER INSERT OR UPDATE OR DELETE ON "campaign_locale"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_region_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_region"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_city_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_city"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_radius_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_radius"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_list_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_list"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "campaign_target_list_audit" AFTER INSERT OR UPDATE OR DELETE ON "campaign_target_list"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "audience_segment_audit" AFTER INSERT OR UPDATE OR DELETE ON "audience_segment"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "campaign_segment_audit" AFTER INSERT OR UPDATE OR DELETE ON "campaign_segment"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "app_metadata_audit" AFTER INSERT OR UPDATE OR DELETE ON "app_metadata"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_category_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_category"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "target_keyword_audit" AFTER INSERT OR UPDATE OR DELETE ON "target_keyword"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "api_key_audit" AFTER INSERT OR UPDATE OR DELETE ON "api_key"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "advertiser_audit" AFTER INSERT OR UPDATE OR DELETE ON "advertiser"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
+
+CREATE TRIGGER "publisher_advertiser_audit" AFTER INSERT OR UPDATE OR DELETE ON "publisher_advertiser"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
@@ -0,0 +1 @@
+ALTER TABLE "campaign_history" DROP COLUMN "revert_of";
@@ -0,0 +1,3 @@
+-- revert_of marks the history rows written by a revert with the oldest
+-- history entry it undid.
+ALTER TABLE "campaign_history" ADD COLUMN "revert_of" INT;
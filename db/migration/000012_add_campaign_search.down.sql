@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "target_os_items_idx";
+DROP INDEX IF EXISTS "target_app_items_idx";
+DROP INDEX IF EXISTS "target_country_items_idx";
+DROP INDEX IF EXISTS "campaign_name_trgm_idx";
+DROP INDEX IF EXISTS "campaign_name_idx";
+DROP INDEX IF EXISTS "campaign_created_at_idx";
+
+DROP FUNCTION IF EXISTS csv_items(text);
@@ -0,0 +1,16 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- csv_items splits a targeting value such as "US, in" into its lowercase
+-- items, matching how delivery compares them.
+CREATE FUNCTION csv_items(csv text) RETURNS text[] AS $$
+  SELECT string_to_array(lower(replace(csv, ' ', '')), ',')
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Keyset pagination walks these in either direction.
+CREATE INDEX "campaign_created_at_idx" ON "campaign" ("advertiser_id", "created_at", "cid");
+CREATE INDEX "campaign_name_idx" ON "campaign" ("advertiser_id", "name", "cid");
+CREATE INDEX "campaign_name_trgm_idx" ON "campaign" USING GIN ("name" gin_trgm_ops);
+
+CREATE INDEX "target_country_items_idx" ON "target_country" USING GIN (csv_items("country"));
+CREATE INDEX "target_app_items_idx" ON "target_app" USING GIN (csv_items("app_id"));
+CREATE INDEX "target_os_items_idx" ON "target_os" USING GIN (csv_items("os"));
@@ -0,0 +1,14 @@
+-- Labels are free-form tags that group campaigns for bulk operations.
+CREATE TABLE "campaign_label" (
+  "advertiser_id" INT NOT NULL,
+  "cid" text NOT NULL,
+  "label" text NOT NULL,
+  PRIMARY KEY ("advertiser_id", "cid", "label")
+);
+
+CREATE INDEX ON "campaign_label" ("advertiser_id", "label");
+
+ALTER TABLE "campaign_label" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+CREATE TRIGGER "campaign_label_audit" AFTER INSERT OR UPDATE OR DELETE ON "campaign_label"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
@@ -0,0 +1,21 @@
+-- Enum values cannot be dropped, so the type is rebuilt. Every status but
+-- active becomes inactive.
+UPDATE "campaign_history" SET "old_value" = 'inactive' WHERE "field_changed" = 'status' AND "old_value" <> 'active';
+UPDATE "campaign_history" SET "new_value" = 'inactive' WHERE "field_changed" = 'status' AND "new_value" <> 'active';
+UPDATE "campaign_history" SET "old_value" = jsonb_set("old_value"::jsonb, '{status}', '"inactive"')::text
+  WHERE "field_changed" = 'patch' AND "old_value"::jsonb->>'status' <> 'active';
+UPDATE "campaign_history" SET "new_value" = jsonb_set("new_value"::jsonb, '{status}', '"inactive"')::text
+  WHERE "field_changed" = 'patch' AND "new_value"::jsonb->>'status' <> 'active';
+
+ALTER TABLE "campaign_history" DROP COLUMN "reason";
+
+ALTER TABLE "campaign" ALTER COLUMN "status" DROP DEFAULT;
+ALTER TABLE "campaign" ALTER COLUMN "status" TYPE text;
+DROP TYPE "status_type";
+CREATE TYPE "status_type" AS ENUM (
+  'active',
+  'inactive'
+);
+ALTER TABLE "campaign" ALTER COLUMN "status" TYPE "status_type"
+  USING (CASE "status" WHEN 'active' THEN 'active' ELSE 'inactive' END)::status_type;
+ALTER TABLE "campaign" ALTER COLUMN "status" SET DEFAULT 'active';
@@ -0,0 +1,18 @@
+-- Campaigns move through a lifecycle instead of being toggled on and off.
+-- Inactive campaigns become paused, which is what they were.
+ALTER TYPE "status_type" RENAME VALUE 'inactive' TO 'paused';
+ALTER TYPE "status_type" ADD VALUE 'draft' BEFORE 'active';
+ALTER TYPE "status_type" ADD VALUE 'pending_review' BEFORE 'active';
+ALTER TYPE "status_type" ADD VALUE 'completed';
+ALTER TYPE "status_type" ADD VALUE 'archived';
+
+-- reason explains a status change, as given by whoever made it.
+ALTER TABLE "campaign_history" ADD COLUMN "reason" text NOT NULL DEFAULT '';
+
+-- History keeps reverting to the renamed status.
+UPDATE "campaign_history" SET "old_value" = 'paused' WHERE "field_changed" = 'status' AND "old_value" = 'inactive';
+UPDATE "campaign_history" SET "new_value" = 'paused' WHERE "field_changed" = 'status' AND "new_value" = 'inactive';
+UPDATE "campaign_history" SET "old_value" = jsonb_set("old_value"::jsonb, '{status}', '"paused"')::text
+  WHERE "field_changed" = 'patch' AND "old_value"::jsonb->>'status' = 'inactive';
+UPDATE "campaign_history" SET "new_value" = jsonb_set("new_value"::jsonb, '{status}', '"paused"')::text
+  WHERE "field_changed" = 'patch' AND "new_value"::jsonb->>'status' = 'inactive';
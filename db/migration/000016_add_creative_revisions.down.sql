@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "creative_revision";
+
+DROP TYPE IF EXISTS "revision_status";
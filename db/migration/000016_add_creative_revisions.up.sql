@@ -0,0 +1,35 @@
+CREATE TYPE "revision_status" AS ENUM (
+  'pending',
+  'approved',
+  'rejected',
+  'superseded',
+  'withdrawn'
+);
+
+-- A creative revision proposes a new img and cta for a live campaign, which
+-- keeps serving its approved creative until a reviewer approves the revision.
+-- Revisions never change: a later change supersedes the pending revision, or
+-- withdraws it when the approved creative is proposed again. Closed revisions
+-- are kept as the history of the reviews.
+CREATE TABLE "creative_revision" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "advertiser_id" INT NOT NULL,
+  "cid" text NOT NULL,
+  "img" text NOT NULL,
+  "cta" text NOT NULL,
+  "status" revision_status NOT NULL DEFAULT 'pending',
+  "reason" text NOT NULL DEFAULT '',
+  "reviewed_by" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "reviewed_at" timestamptz
+);
+
+CREATE INDEX ON "creative_revision" ("advertiser_id", "cid");
+
+-- A campaign has at most one revision waiting for review.
+CREATE UNIQUE INDEX ON "creative_revision" ("advertiser_id", "cid") WHERE "status" = 'pending';
+
+ALTER TABLE "creative_revision" ADD FOREIGN KEY ("advertiser_id", "cid") REFERENCES "campaign" ("advertiser_id", "cid") ON DELETE CASCADE;
+
+CREATE TRIGGER "creative_revision_audit" AFTER INSERT OR UPDATE OR DELETE ON "creative_revision"
+  FOR EACH ROW EXECUTE FUNCTION audit_row();
@@ -0,0 +1,3 @@
+DELETE FROM "campaign" WHERE "deleted_at" IS NOT NULL;
+
+ALTER TABLE "campaign" DROP COLUMN IF EXISTS "deleted_at";
@@ -0,0 +1,5 @@
+-- Deleted campaigns keep their row, targeting and history until the retention
+-- job purges them, so that a deletion can be undone.
+ALTER TABLE "campaign" ADD COLUMN "deleted_at" timestamptz;
+
+CREATE INDEX ON "campaign" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
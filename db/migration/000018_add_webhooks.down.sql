@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS "campaign_webhook" ON "campaign";
+DROP FUNCTION IF EXISTS enqueue_campaign_event();
+DROP TABLE IF EXISTS "webhook_dead_letter";
+DROP TABLE IF EXISTS "webhook_delivery";
+DROP TABLE IF EXISTS "webhook_subscription";
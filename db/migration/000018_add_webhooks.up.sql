@@ -0,0 +1,107 @@
+-- Webhook subscriptions receive the campaign events of their advertiser
+-- listed in event_types. The secret signs every payload sent.
+CREATE TABLE "webhook_subscription" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "advertiser_id" INT NOT NULL,
+  "url" text NOT NULL,
+  "secret" text NOT NULL,
+  "event_types" text[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("event_types" <@ ARRAY['campaign.created', 'campaign.updated', 'campaign.status_changed', 'campaign.deleted'])
+);
+
+-- webhook_delivery queues the events each subscription is yet to receive.
+-- Delivered events are removed, and events that exhausted their attempts
+-- move to webhook_dead_letter until they are redelivered.
+CREATE TABLE "webhook_delivery" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "subscription_id" INT NOT NULL,
+  "advertiser_id" INT NOT NULL,
+  "event_type" text NOT NULL,
+  "payload" jsonb NOT NULL,
+  "attempts" INT NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_error" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_dead_letter" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY NOT NULL,
+  "subscription_id" INT NOT NULL,
+  "advertiser_id" INT NOT NULL,
+  "event_type" text NOT NULL,
+  "payload" jsonb NOT NULL,
+  "attempts" INT NOT NULL,
+  "last_error" text NOT NULL,
+  "created_at" timestamptz NOT NULL,
+  "failed_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webhook_subscription" ("advertiser_id");
+
+CREATE INDEX ON "webhook_delivery" ("next_attempt_at");
+
+CREATE INDEX ON "webhook_dead_letter" ("advertiser_id");
+
+ALTER TABLE "webhook_subscription" ADD FOREIGN KEY ("advertiser_id") REFERENCES "advertiser" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "webhook_delivery" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscription" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "webhook_dead_letter" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscription" ("id") ON DELETE CASCADE;
+
+-- enqueue_campaign_event queues an event for the subscriptions of a changed
+-- campaign. It runs when the transaction commits, and only for the first
+-- change of each campaign in it, comparing the campaign before that change
+-- with the campaign as current_campaign. A transaction changing a campaign several
+-- times, as a version bump and the update it guards do, so emits one event.
+CREATE FUNCTION enqueue_campaign_event() RETURNS trigger AS $$
+DECLARE
+  seen text := COALESCE(current_setting('adrouter.campaign_events', true), '');
+  campaign_key text := E'\n' || NEW.advertiser_id || ':' || NEW.cid || E'\n';
+  current_campaign campaign;
+  event_name text;
+  event_payload jsonb;
+BEGIN
+  IF position(campaign_key IN seen) > 0 THEN
+    RETURN NULL;
+  END IF;
+  PERFORM set_config('adrouter.campaign_events', seen || campaign_key, true);
+
+  SELECT * INTO current_campaign FROM campaign WHERE advertiser_id = NEW.advertiser_id AND cid = NEW.cid;
+  IF NOT FOUND THEN
+    RETURN NULL;
+  END IF;
+
+  IF TG_OP = 'INSERT' THEN
+    event_name := 'campaign.created';
+  ELSIF OLD.deleted_at IS NULL AND current_campaign.deleted_at IS NOT NULL THEN
+    event_name := 'campaign.deleted';
+  ELSIF OLD.status <> current_campaign.status THEN
+    event_name := 'campaign.status_changed';
+  ELSIF to_jsonb(OLD) <> to_jsonb(current_campaign) THEN
+    event_name := 'campaign.updated';
+  ELSE
+    RETURN NULL;
+  END IF;
+
+  event_payload := jsonb_build_object(
+    'id', gen_random_uuid(),
+    'event', event_name,
+    'occurred_at', now(),
+    'campaign', to_jsonb(current_campaign)
+  );
+  IF event_name = 'campaign.status_changed' THEN
+    event_payload := event_payload || jsonb_build_object('previous_status', OLD.status);
+  END IF;
+
+  INSERT INTO webhook_delivery (subscription_id, advertiser_id, event_type, payload)
+  SELECT id, advertiser_id, event_name, event_payload
+  FROM webhook_subscription
+  WHERE advertiser_id = NEW.advertiser_id AND event_name = ANY(event_types);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER "campaign_webhook" AFTER INSERT OR UPDATE ON "campaign"
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION enqueue_campaign_event();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS "campaign_history_notify" ON "campaign_history";
+DROP FUNCTION IF EXISTS notify_campaign_history();
@@ -0,0 +1,13 @@
+-- notify_campaign_history announces every history entry on the
+-- campaign_history channel. Notifications are only delivered once the
+-- transaction writing the entry commits, in commit order, to every server
+-- listening, whichever of them made the change.
+CREATE FUNCTION notify_campaign_history() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('campaign_history', NEW.id::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "campaign_history_notify" AFTER INSERT ON "campaign_history"
+  FOR EACH ROW EXECUTE FUNCTION notify_campaign_history();
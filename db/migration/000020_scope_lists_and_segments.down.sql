@@ -0,0 +1,21 @@
+ALTER TABLE "campaign_segment" DROP CONSTRAINT IF EXISTS "campaign_segment_advertiser_segment_fkey";
+ALTER TABLE "campaign_target_list" DROP CONSTRAINT IF EXISTS "campaign_target_list_advertiser_list_fkey";
+
+ALTER TABLE "audience_segment" DROP COLUMN IF EXISTS "advertiser_id";
+ALTER TABLE "target_list" DROP COLUMN IF EXISTS "advertiser_id";
+
+ALTER TABLE "audience_segment" ADD UNIQUE ("name");
+ALTER TABLE "target_list" ADD UNIQUE ("name");
+
+-- Enum values cannot be dropped, so the type is rebuilt without global_admin,
+-- whose keys become admin keys.
+UPDATE "api_key" SET "role" = 'admin' WHERE "role" = 'global_admin';
+ALTER TYPE "key_role" RENAME TO "key_role_old";
+CREATE TYPE "key_role" AS ENUM (
+  'admin',
+  'editor',
+  'read_only',
+  'delivery'
+);
+ALTER TABLE "api_key" ALTER COLUMN "role" TYPE "key_role" USING "role"::text::"key_role";
+DROP TYPE "key_role_old";
@@ -0,0 +1,50 @@
+-- Global admins manage advertisers and the keys and audit log of every
+-- advertiser; admins only those of their own advertiser.
+ALTER TYPE "key_role" ADD VALUE 'global_admin';
+
+ALTER TABLE "target_list" ADD COLUMN "advertiser_id" INT;
+
+ALTER TABLE "audience_segment" ADD COLUMN "advertiser_id" INT;
+
+-- Lists and segments go to the advertiser whose campaigns use them, or to the
+-- default advertiser when none does.
+UPDATE "target_list" SET "advertiser_id" = COALESCE(
+  (SELECT min("advertiser_id") FROM "campaign_target_list" WHERE "list_id" = "target_list"."id"),
+  (SELECT "id" FROM "advertiser" WHERE "name" = 'default')
+);
+
+UPDATE "audience_segment" SET "advertiser_id" = COALESCE(
+  (SELECT min("advertiser_id") FROM "campaign_segment" WHERE "segment_id" = "audience_segment"."id"),
+  (SELECT "id" FROM "advertiser" WHERE "name" = 'default')
+);
+
+ALTER TABLE "target_list" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+ALTER TABLE "audience_segment" ALTER COLUMN "advertiser_id" SET NOT NULL;
+
+-- Names only have to be unique within an advertiser.
+ALTER TABLE "target_list" DROP CONSTRAINT "target_list_name_key";
+
+ALTER TABLE "target_list" ADD UNIQUE ("advertiser_id", "name");
+
+ALTER TABLE "audience_segment" DROP CONSTRAINT "audience_segment_name_key";
+
+ALTER TABLE "audience_segment" ADD UNIQUE ("advertiser_id", "name");
+
+ALTER TABLE "target_list" ADD UNIQUE ("advertiser_id", "id");
+
+ALTER TABLE "audience_segment" ADD UNIQUE ("advertiser_id", "id");
+
+ALTER TABLE "target_list" ADD FOREIGN KEY ("advertiser_id") REFERENCES "advertiser" ("id");
+
+ALTER TABLE "audience_segment" ADD FOREIGN KEY ("advertiser_id") REFERENCES "advertiser" ("id");
+
+-- Campaigns can only use lists and segments of their own advertiser. Links
+-- made across advertisers before lists and segments had one are kept, as
+-- dropping them would widen the targeting of those campaigns, so the
+-- constraints only apply to new links.
+ALTER TABLE "campaign_target_list" ADD CONSTRAINT "campaign_target_list_advertiser_list_fkey"
+  FOREIGN KEY ("advertiser_id", "list_id") REFERENCES "target_list" ("advertiser_id", "id") NOT VALID;
+
+ALTER TABLE "campaign_segment" ADD CONSTRAINT "campaign_segment_advertiser_segment_fkey"
+  FOREIGN KEY ("advertiser_id", "segment_id") REFERENCES "audience_segment" ("advertiser_id", "id") NOT VALID;
@@ -0,0 +1,7 @@
+DELETE FROM "creative_revision" WHERE "locale" <> '';
+
+DROP INDEX IF EXISTS "creative_revision_advertiser_id_cid_locale_idx";
+
+ALTER TABLE "creative_revision" DROP COLUMN IF EXISTS "locale";
+
+CREATE UNIQUE INDEX ON "creative_revision" ("advertiser_id", "cid") WHERE "status" = 'pending';
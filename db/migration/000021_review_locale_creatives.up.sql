@@ -0,0 +1,8 @@
+-- A revision may propose the creative of one of the campaign's locales rather
+-- than the campaign's own, which has the empty locale. Each of them has at most
+-- one revision waiting for review.
+ALTER TABLE "creative_revision" ADD COLUMN "locale" text NOT NULL DEFAULT '';
+
+DROP INDEX "creative_revision_advertiser_id_cid_idx1";
+
+CREATE UNIQUE INDEX ON "creative_revision" ("advertiser_id", "cid", "locale") WHERE "status" = 'pending';
@@ -0,0 +1,47 @@
+CREATE OR REPLACE FUNCTION audit_row() RETURNS trigger AS $$
+DECLARE
+  old_row jsonb;
+  new_row jsonb;
+  row_data jsonb;
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    old_row := to_jsonb(OLD) - 'key_hash';
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    new_row := to_jsonb(NEW) - 'key_hash';
+  END IF;
+  IF TG_OP = 'UPDATE' AND old_row - 'version' = new_row - 'version' THEN
+    RETURN NULL;
+  END IF;
+  row_data := COALESCE(new_row, old_row);
+
+  INSERT INTO audit_log (
+    actor, source_ip, request_id, operation, entity, advertiser_id, entity_id, before, after
+  ) VALUES (
+    COALESCE(NULLIF(current_setting('adrouter.actor', true), ''), 'system'),
+    COALESCE(current_setting('adrouter.source_ip', true), ''),
+    COALESCE(current_setting('adrouter.request_id', true), ''),
+    (CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END)::audit_operation,
+    TG_TABLE_NAME,
+    (row_data->>'advertiser_id')::int,
+    COALESCE(row_data->>'cid', row_data->>'app_id', row_data->>'id', ''),
+    old_row,
+    new_row
+  );
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Enum values cannot be dropped, so the type is rebuilt. Soft deletes and
+-- restores go back to being recorded as updates.
+UPDATE "audit_log" SET "operation" = 'update'
+  WHERE "operation" = 'restore' OR ("operation" = 'delete' AND "after" IS NOT NULL);
+ALTER TYPE "audit_operation" RENAME TO "audit_operation_old";
+CREATE TYPE "audit_operation" AS ENUM (
+  'create',
+  'update',
+  'delete'
+);
+ALTER TABLE "audit_log" ALTER COLUMN "operation" TYPE "audit_operation"
+  USING "operation"::text::audit_operation;
+DROP TYPE "audit_operation_old";
@@ -0,0 +1,47 @@
+ALTER TYPE "audit_operation" ADD VALUE 'restore';
+
+-- Deleting a campaign only sets deleted_at, and restoring it clears it again,
+-- so those updates are recorded as the delete and the restore they are.
+CREATE OR REPLACE FUNCTION audit_row() RETURNS trigger AS $$
+DECLARE
+  old_row jsonb;
+  new_row jsonb;
+  row_data jsonb;
+  op text;
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    old_row := to_jsonb(OLD) - 'key_hash';
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    new_row := to_jsonb(NEW) - 'key_hash';
+  END IF;
+  IF TG_OP = 'UPDATE' AND old_row - 'version' = new_row - 'version' THEN
+    RETURN NULL;
+  END IF;
+  row_data := COALESCE(new_row, old_row);
+
+  op := CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END;
+  IF TG_OP = 'UPDATE' AND old_row ? 'deleted_at' THEN
+    IF old_row->'deleted_at' = 'null' AND new_row->'deleted_at' <> 'null' THEN
+      op := 'delete';
+    ELSIF old_row->'deleted_at' <> 'null' AND new_row->'deleted_at' = 'null' THEN
+      op := 'restore';
+    END IF;
+  END IF;
+
+  INSERT INTO audit_log (
+    actor, source_ip, request_id, operation, entity, advertiser_id, entity_id, before, after
+  ) VALUES (
+    COALESCE(NULLIF(current_setting('adrouter.actor', true), ''), 'system'),
+    COALESCE(current_setting('adrouter.source_ip', true), ''),
+    COALESCE(current_setting('adrouter.request_id', true), ''),
+    op::audit_operation,
+    TG_TABLE_NAME,
+    (row_data->>'advertiser_id')::int,
+    COALESCE(row_data->>'cid', row_data->>'app_id', row_data->>'id', ''),
+    old_row,
+    new_row
+  );
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
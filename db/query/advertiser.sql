@@ -0,0 +1,26 @@
+-- name: CreateAdvertiser :one
+INSERT INTO advertiser (
+    name
+) VALUES (
+    $1
+)
+RETURNING *;
+
+-- name: GetAdvertiser :one
+SELECT *
+FROM advertiser
+WHERE id = $1;
+
+-- name: GetAdvertiserByName :one
+SELECT *
+FROM advertiser
+WHERE name = $1;
+
+-- name: ListAdvertisers :many
+SELECT *
+FROM advertiser
+ORDER BY id;
+
+-- name: DeleteAdvertiser :exec
+DELETE FROM advertiser
+WHERE id = $1;
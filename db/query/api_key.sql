@@ -0,0 +1,37 @@
+-- name: CreateApiKey :one
+INSERT INTO api_key (
+    advertiser_id,
+    name,
+    prefix,
+    key_hash,
+    role
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetApiKeyByHash :one
+SELECT *
+FROM api_key
+WHERE key_hash = $1
+  AND revoked_at IS NULL;
+
+-- name: ListApiKeys :many
+SELECT *
+FROM api_key
+WHERE sqlc.narg(advertiser_id)::int IS NULL OR advertiser_id = sqlc.narg(advertiser_id)
+ORDER BY id;
+
+-- name: CountActiveApiKeys :one
+SELECT count(*)
+FROM api_key
+WHERE role = $1
+  AND revoked_at IS NULL;
+
+-- name: RevokeApiKey :one
+UPDATE api_key
+SET revoked_at = now()
+WHERE id = sqlc.arg(id)
+  AND revoked_at IS NULL
+  AND (sqlc.narg(advertiser_id)::int IS NULL OR (advertiser_id = sqlc.narg(advertiser_id) AND role <> 'global_admin'))
+RETURNING *;
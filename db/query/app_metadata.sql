@@ -0,0 +1,27 @@
+-- name: UpsertAppMetadata :one
+INSERT INTO app_metadata (
+    app_id,
+    categories,
+    tags
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (app_id) DO UPDATE
+SET categories = EXCLUDED.categories,
+    tags = EXCLUDED.tags,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetAppMetadata :one
+SELECT *
+FROM app_metadata
+WHERE app_id = $1;
+
+-- name: ListAppMetadata :many
+SELECT *
+FROM app_metadata
+ORDER BY app_id;
+
+-- name: DeleteAppMetadata :exec
+DELETE FROM app_metadata
+WHERE app_id = $1;
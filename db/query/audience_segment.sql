@@ -0,0 +1,38 @@
+-- name: CreateAudienceSegment :one
+INSERT INTO audience_segment (
+    advertiser_id,
+    name
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: GetAudienceSegment :one
+SELECT *
+FROM audience_segment
+WHERE advertiser_id = $1 AND id = $2;
+
+-- name: getAudienceSegmentByID :one
+SELECT *
+FROM audience_segment
+WHERE id = $1;
+
+-- name: ListAudienceSegments :many
+SELECT *
+FROM audience_segment
+WHERE advertiser_id = $1
+ORDER BY id;
+
+-- name: updateAudienceSegmentMembers :one
+UPDATE audience_segment
+SET storage = $2,
+    member_count = $3,
+    bloom_bits = $4,
+    bloom_hashes = $5,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: deleteAudienceSegment :execrows
+DELETE FROM audience_segment
+WHERE advertiser_id = $1 AND id = $2;
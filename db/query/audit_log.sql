@@ -0,0 +1,23 @@
+-- name: ListAuditLog :many
+SELECT *
+FROM audit_log
+WHERE (sqlc.narg(actor)::text IS NULL OR actor = sqlc.narg(actor))
+  AND (sqlc.narg(request_id)::text IS NULL OR request_id = sqlc.narg(request_id))
+  AND (sqlc.narg(operation)::audit_operation IS NULL OR operation = sqlc.narg(operation))
+  AND (sqlc.narg(entity)::text IS NULL OR entity = sqlc.narg(entity))
+  AND (sqlc.narg(advertiser_id)::int IS NULL OR advertiser_id = sqlc.narg(advertiser_id))
+  AND (sqlc.narg(entity_id)::text IS NULL OR entity_id = sqlc.narg(entity_id))
+  AND (sqlc.narg(since)::timestamptz IS NULL OR created_at >= sqlc.narg(since))
+  AND (sqlc.narg(until)::timestamptz IS NULL OR created_at < sqlc.narg(until))
+  AND (sqlc.narg(before_id)::bigint IS NULL OR id < sqlc.narg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: listCampaignAuditSince :many
+SELECT *
+FROM audit_log
+WHERE entity = ANY(sqlc.arg(entities)::text[])
+  AND advertiser_id = sqlc.arg(advertiser_id)::int
+  AND entity_id = sqlc.arg(cid)
+  AND created_at > sqlc.arg(since)
+ORDER BY id;
@@ -1,55 +1,246 @@
 -- name: AddCampaign :one
 INSERT INTO campaign (
+  advertiser_id,
   cid,
   name,
   img,
-  cta
+  cta,
+  status
 ) VALUES (
-    $1, $2, $3, $4
+    sqlc.arg(advertiser_id), sqlc.arg(cid), sqlc.arg(name), sqlc.arg(img), sqlc.arg(cta),
+    COALESCE(sqlc.narg(status)::status_type, 'draft'::status_type)
 )
 RETURNING *;
 
 -- name: GetCampaign :one
 SELECT *
 FROM campaign
-WHERE cid = $1;
+WHERE advertiser_id = $1 AND cid = $2 AND deleted_at IS NULL;
 
 -- name: ListCampaigns :many
 SELECT *
-FROM campaign;
+FROM campaign
+WHERE advertiser_id = $1 AND deleted_at IS NULL
+ORDER BY cid;
 
 -- name: ListActiveCampaigns :many
 SELECT *
 FROM campaign
-WHERE status = 'active'::status_type;
+WHERE status = 'active'::status_type AND deleted_at IS NULL;
+
+-- The search queries share their filters and differ in order, so that each
+-- pages through an index with a keyset cursor.
+
+-- name: CountCampaigns :one
+SELECT count(*)
+FROM campaign c
+WHERE c.advertiser_id = sqlc.arg(advertiser_id) AND c.deleted_at IS NULL
+  AND (sqlc.narg(status)::status_type IS NULL OR c.status = sqlc.narg(status))
+  AND (sqlc.narg(name)::text IS NULL OR c.name ILIKE '%' || sqlc.narg(name) || '%')
+  AND (sqlc.narg(created_since)::timestamptz IS NULL OR c.created_at >= sqlc.narg(created_since))
+  AND (sqlc.narg(created_until)::timestamptz IS NULL OR c.created_at < sqlc.narg(created_until))
+  AND (sqlc.narg(country)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower(sqlc.narg(country))]
+  ))
+  AND (sqlc.narg(app)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower(sqlc.narg(app))]
+  ))
+  AND (sqlc.narg(os)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower(sqlc.narg(os))]
+  ));
+
+-- name: SearchCampaignsByNewest :many
+SELECT c.*
+FROM campaign c
+WHERE c.advertiser_id = sqlc.arg(advertiser_id) AND c.deleted_at IS NULL
+  AND (sqlc.narg(status)::status_type IS NULL OR c.status = sqlc.narg(status))
+  AND (sqlc.narg(name)::text IS NULL OR c.name ILIKE '%' || sqlc.narg(name) || '%')
+  AND (sqlc.narg(created_since)::timestamptz IS NULL OR c.created_at >= sqlc.narg(created_since))
+  AND (sqlc.narg(created_until)::timestamptz IS NULL OR c.created_at < sqlc.narg(created_until))
+  AND (sqlc.narg(country)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower(sqlc.narg(country))]
+  ))
+  AND (sqlc.narg(app)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower(sqlc.narg(app))]
+  ))
+  AND (sqlc.narg(os)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower(sqlc.narg(os))]
+  ))
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL OR (c.created_at, c.cid) < (sqlc.narg(after_created_at), sqlc.arg(after_cid)::text))
+ORDER BY c.created_at DESC, c.cid DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: SearchCampaignsByOldest :many
+SELECT c.*
+FROM campaign c
+WHERE c.advertiser_id = sqlc.arg(advertiser_id) AND c.deleted_at IS NULL
+  AND (sqlc.narg(status)::status_type IS NULL OR c.status = sqlc.narg(status))
+  AND (sqlc.narg(name)::text IS NULL OR c.name ILIKE '%' || sqlc.narg(name) || '%')
+  AND (sqlc.narg(created_since)::timestamptz IS NULL OR c.created_at >= sqlc.narg(created_since))
+  AND (sqlc.narg(created_until)::timestamptz IS NULL OR c.created_at < sqlc.narg(created_until))
+  AND (sqlc.narg(country)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower(sqlc.narg(country))]
+  ))
+  AND (sqlc.narg(app)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower(sqlc.narg(app))]
+  ))
+  AND (sqlc.narg(os)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower(sqlc.narg(os))]
+  ))
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL OR (c.created_at, c.cid) > (sqlc.narg(after_created_at), sqlc.arg(after_cid)::text))
+ORDER BY c.created_at ASC, c.cid ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: SearchCampaignsByName :many
+SELECT c.*
+FROM campaign c
+WHERE c.advertiser_id = sqlc.arg(advertiser_id) AND c.deleted_at IS NULL
+  AND (sqlc.narg(status)::status_type IS NULL OR c.status = sqlc.narg(status))
+  AND (sqlc.narg(name)::text IS NULL OR c.name ILIKE '%' || sqlc.narg(name) || '%')
+  AND (sqlc.narg(created_since)::timestamptz IS NULL OR c.created_at >= sqlc.narg(created_since))
+  AND (sqlc.narg(created_until)::timestamptz IS NULL OR c.created_at < sqlc.narg(created_until))
+  AND (sqlc.narg(country)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower(sqlc.narg(country))]
+  ))
+  AND (sqlc.narg(app)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower(sqlc.narg(app))]
+  ))
+  AND (sqlc.narg(os)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower(sqlc.narg(os))]
+  ))
+  AND (sqlc.narg(after_name)::text IS NULL OR (c.name, c.cid) > (sqlc.narg(after_name), sqlc.arg(after_cid)::text))
+ORDER BY c.name ASC, c.cid ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: SearchCampaignsByNameDesc :many
+SELECT c.*
+FROM campaign c
+WHERE c.advertiser_id = sqlc.arg(advertiser_id) AND c.deleted_at IS NULL
+  AND (sqlc.narg(status)::status_type IS NULL OR c.status = sqlc.narg(status))
+  AND (sqlc.narg(name)::text IS NULL OR c.name ILIKE '%' || sqlc.narg(name) || '%')
+  AND (sqlc.narg(created_since)::timestamptz IS NULL OR c.created_at >= sqlc.narg(created_since))
+  AND (sqlc.narg(created_until)::timestamptz IS NULL OR c.created_at < sqlc.narg(created_until))
+  AND (sqlc.narg(country)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower(sqlc.narg(country))]
+  ))
+  AND (sqlc.narg(app)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower(sqlc.narg(app))]
+  ))
+  AND (sqlc.narg(os)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower(sqlc.narg(os))]
+  ))
+  AND (sqlc.narg(after_name)::text IS NULL OR (c.name, c.cid) < (sqlc.narg(after_name), sqlc.arg(after_cid)::text))
+ORDER BY c.name DESC, c.cid DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: selectBulkCampaigns :many
+SELECT c.cid, c.status
+FROM campaign c
+WHERE c.advertiser_id = sqlc.arg(advertiser_id) AND c.deleted_at IS NULL
+  AND (sqlc.narg(cids)::text[] IS NULL OR c.cid = ANY(sqlc.narg(cids)::text[]))
+  AND (sqlc.narg(labels)::text[] IS NULL OR EXISTS (
+    SELECT 1 FROM campaign_label l
+    WHERE l.advertiser_id = c.advertiser_id AND l.cid = c.cid
+      AND l.label = ANY(sqlc.narg(labels)::text[])
+  ))
+  AND (sqlc.narg(status)::status_type IS NULL OR c.status = sqlc.narg(status))
+  AND (sqlc.narg(country)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower(sqlc.narg(country))]
+  ))
+  AND (sqlc.narg(app)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower(sqlc.narg(app))]
+  ))
+  AND (sqlc.narg(os)::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower(sqlc.narg(os))]
+  ))
+ORDER BY c.cid
+FOR UPDATE OF c;
+
+-- name: bumpCampaignVersion :one
+UPDATE campaign
+SET version = version + 1
+WHERE advertiser_id = sqlc.arg(advertiser_id) AND cid = sqlc.arg(cid) AND deleted_at IS NULL
+  AND (sqlc.narg(version)::int IS NULL OR version = sqlc.narg(version))
+RETURNING version;
 
--- name: toggleStatus :one
+-- name: setCampaignStatus :one
 UPDATE campaign
-SET status = CASE 
-    WHEN status = 'active'::status_type THEN 'inactive'::status_type
-    ELSE 'active'::status_type
-END
-WHERE cid = $1
+SET status = $3
+WHERE advertiser_id = $1 AND cid = $2
 RETURNING status;
 
 -- name: updateCampaignName :one
 UPDATE campaign
-SET name = $2
-WHERE cid = $1
+SET name = $3
+WHERE advertiser_id = $1 AND cid = $2
 RETURNING *;
 
 -- name: updateCampaignImage :one
 UPDATE campaign
-SET img = $2
-WHERE cid = $1
+SET img = $3
+WHERE advertiser_id = $1 AND cid = $2
 RETURNING *;
 
 -- name: updateCampaignCta :one
 UPDATE campaign
-SET cta = $2
-WHERE cid = $1
+SET cta = $3
+WHERE advertiser_id = $1 AND cid = $2
 RETURNING *;
 
+-- Deleting a campaign only marks it, which leaves it out of every read above
+-- and of delivery until it is restored or purged.
+
 -- name: DeleteCampaign :exec
+UPDATE campaign
+SET deleted_at = now()
+WHERE advertiser_id = $1 AND cid = $2 AND deleted_at IS NULL;
+
+-- name: restoreCampaign :one
+UPDATE campaign
+SET deleted_at = NULL, version = version + 1
+WHERE advertiser_id = $1 AND cid = $2 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeDeletedCampaigns :execrows
+DELETE FROM campaign
+WHERE deleted_at < sqlc.arg(before)::timestamptz;
+
+-- name: purgeAdvertiserDeletedCampaigns :exec
 DELETE FROM campaign
-WHERE cid = $1;
\ No newline at end of file
+WHERE advertiser_id = $1 AND deleted_at IS NOT NULL;
\ No newline at end of file
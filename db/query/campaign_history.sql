@@ -1,29 +1,88 @@
 -- name: createCampaignHistory :exec
 INSERT INTO campaign_history (
+    advertiser_id,
     cid,
     field_changed,
     old_value,
-    new_value
+    new_value,
+    reason
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5, $6
 );
 
+-- name: createCampaignRevertHistory :exec
+INSERT INTO campaign_history (
+    advertiser_id,
+    cid,
+    field_changed,
+    old_value,
+    new_value,
+    revert_of
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: GetCampaignHistoryEntry :one
+SELECT *
+FROM campaign_history
+WHERE advertiser_id = $1 AND cid = $2 AND id = $3;
+
+-- name: ListCampaignHistoryFrom :many
+SELECT *
+FROM campaign_history
+WHERE advertiser_id = $1 AND cid = $2 AND id >= $3
+ORDER BY id DESC;
+
 -- name: GetCampaignHistory :one
 SELECT *
 FROM campaign_history
-WHERE cid = $1
+WHERE advertiser_id = $1 AND cid = $2
 ORDER BY updated_at DESC
 LIMIT 1;
 
 -- name: GetLastTwoCampaignHistory :many
 SELECT *
 FROM campaign_history
-WHERE cid = $1
+WHERE advertiser_id = $1 AND cid = $2
 ORDER BY updated_at DESC
 LIMIT 2;
 
 -- name: ListCampaignHistory :many
 SELECT *
 FROM campaign_history
-WHERE cid = $1
-ORDER BY updated_at DESC;
\ No newline at end of file
+WHERE advertiser_id = $1 AND cid = $2
+ORDER BY updated_at DESC;
+-- name: ListCampaignHistoryPage :many
+SELECT *
+FROM campaign_history
+WHERE advertiser_id = sqlc.arg(advertiser_id)
+  AND cid = sqlc.arg(cid)
+  AND (sqlc.narg(fields)::text[] IS NULL
+    OR field_changed = ANY(sqlc.narg(fields)::text[])
+    -- Patches match the fields they changed, the keys of their values.
+    OR CASE WHEN field_changed = 'patch' THEN new_value::jsonb ?| sqlc.narg(fields)::text[] ELSE false END)
+  AND (sqlc.narg(since)::timestamptz IS NULL OR updated_at >= sqlc.narg(since))
+  AND (sqlc.narg(until)::timestamptz IS NULL OR updated_at < sqlc.narg(until))
+  AND (sqlc.narg(before_id)::int IS NULL OR id < sqlc.narg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListCampaignHistorySince :many
+SELECT *
+FROM campaign_history
+WHERE advertiser_id = $1 AND cid = $2 AND updated_at > $3
+ORDER BY id DESC;
+
+-- name: getCampaignHistoryByID :one
+SELECT *
+FROM campaign_history
+WHERE id = $1;
+
+-- name: listCampaignHistoryAfter :many
+SELECT *
+FROM campaign_history
+WHERE advertiser_id = sqlc.arg(advertiser_id)
+  AND id > sqlc.arg(after_id)
+  AND (sqlc.narg(cid)::text IS NULL OR cid = sqlc.narg(cid))
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
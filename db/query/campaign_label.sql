@@ -0,0 +1,19 @@
+-- name: addCampaignLabel :exec
+INSERT INTO campaign_label (
+    advertiser_id,
+    cid,
+    label
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: ListCampaignLabels :many
+SELECT label
+FROM campaign_label
+WHERE advertiser_id = $1 AND cid = $2
+ORDER BY label COLLATE "C";
+
+-- name: deleteCampaignLabel :exec
+DELETE FROM campaign_label
+WHERE advertiser_id = $1 AND cid = $2 AND label = $3;
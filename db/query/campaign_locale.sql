@@ -0,0 +1,32 @@
+-- name: addCampaignLocale :one
+INSERT INTO campaign_locale (
+    advertiser_id,
+    cid,
+    locale,
+    img,
+    cta
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetCampaignLocale :one
+SELECT *
+FROM campaign_locale
+WHERE advertiser_id = $1 AND cid = $2 AND locale = $3;
+
+-- name: ListCampaignLocales :many
+SELECT *
+FROM campaign_locale
+WHERE advertiser_id = $1 AND cid = $2
+ORDER BY locale;
+
+-- name: updateCampaignLocale :one
+UPDATE campaign_locale
+SET img = $4, cta = $5
+WHERE advertiser_id = $1 AND cid = $2 AND locale = $3
+RETURNING *;
+
+-- name: deleteCampaignLocale :exec
+DELETE FROM campaign_locale
+WHERE advertiser_id = $1 AND cid = $2 AND locale = $3;
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- name: addCampaignSegment :one
+INSERT INTO campaign_segment (
+    advertiser_id,
+    cid,
+    segment_id,
+    rule
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListCampaignSegments :many
+SELECT *
+FROM campaign_segment
+WHERE advertiser_id = $1 AND cid = $2
+ORDER BY segment_id;
+
+-- name: deleteCampaignSegment :many
+DELETE FROM campaign_segment
+WHERE advertiser_id = $1 AND cid = $2 AND segment_id = $3
+RETURNING *;
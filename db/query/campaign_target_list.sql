@@ -0,0 +1,21 @@
+-- name: addCampaignTargetList :one
+INSERT INTO campaign_target_list (
+    advertiser_id,
+    cid,
+    list_id,
+    rule
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListCampaignTargetLists :many
+SELECT *
+FROM campaign_target_list
+WHERE advertiser_id = $1 AND cid = $2
+ORDER BY list_id;
+
+-- name: deleteCampaignTargetList :many
+DELETE FROM campaign_target_list
+WHERE advertiser_id = $1 AND cid = $2 AND list_id = $3
+RETURNING *;
@@ -0,0 +1,41 @@
+-- name: createCreativeRevision :one
+INSERT INTO creative_revision (
+    advertiser_id,
+    cid,
+    locale,
+    img,
+    cta
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: getPendingCreativeRevision :one
+SELECT *
+FROM creative_revision
+WHERE advertiser_id = $1 AND cid = $2 AND locale = $3 AND status = 'pending';
+
+-- name: GetCreativeRevision :one
+SELECT *
+FROM creative_revision
+WHERE advertiser_id = $1 AND cid = $2 AND id = $3;
+
+-- name: ListCreativeRevisions :many
+SELECT r.*
+FROM creative_revision r
+JOIN campaign c ON c.advertiser_id = r.advertiser_id AND c.cid = r.cid
+WHERE r.advertiser_id = sqlc.arg(advertiser_id) AND c.deleted_at IS NULL
+  AND (sqlc.narg(cid)::text IS NULL OR r.cid = sqlc.narg(cid))
+  AND (sqlc.narg(status)::revision_status IS NULL OR r.status = sqlc.narg(status))
+ORDER BY r.id;
+
+-- name: closeCreativeRevision :exec
+UPDATE creative_revision
+SET status = $2
+WHERE id = $1 AND status = 'pending';
+
+-- name: reviewCreativeRevision :one
+UPDATE creative_revision
+SET status = $2, reason = $3, reviewed_by = $4, reviewed_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
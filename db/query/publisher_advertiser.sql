@@ -0,0 +1,18 @@
+-- name: AddPublisherAdvertiser :one
+INSERT INTO publisher_advertiser (
+    app_id,
+    advertiser_id
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: ListPublisherAdvertisers :many
+SELECT *
+FROM publisher_advertiser
+WHERE app_id = $1
+ORDER BY advertiser_id;
+
+-- name: DeletePublisherAdvertiser :exec
+DELETE FROM publisher_advertiser
+WHERE app_id = $1 AND advertiser_id = $2;
@@ -1,24 +1,25 @@
--- name: AddTargetApp :one
+-- name: addTargetApp :one
 INSERT INTO target_app (
+    advertiser_id,
     cid,
     app_id,
     rule
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 )
 RETURNING *;
 
 -- name: GetTargetApp :one
 SELECT *
 FROM target_app
-WHERE cid = $1;
+WHERE advertiser_id = $1 AND cid = $2;
 
 -- name: updateTargetApp :one
 UPDATE target_app
-SET app_id = $2, rule = $3
-WHERE cid = $1
+SET app_id = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
 RETURNING *;
 
--- name: DeleteTargetApp :exec
+-- name: deleteTargetApp :exec
 DELETE FROM target_app
-WHERE cid = $1;
\ No newline at end of file
+WHERE advertiser_id = $1 AND cid = $2;
\ No newline at end of file
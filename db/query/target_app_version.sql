@@ -0,0 +1,25 @@
+-- name: addTargetAppVersion :one
+INSERT INTO target_app_version (
+    advertiser_id,
+    cid,
+    app_version,
+    rule
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetTargetAppVersion :one
+SELECT *
+FROM target_app_version
+WHERE advertiser_id = $1 AND cid = $2;
+
+-- name: updateTargetAppVersion :one
+UPDATE target_app_version
+SET app_version = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING *;
+
+-- name: deleteTargetAppVersion :exec
+DELETE FROM target_app_version
+WHERE advertiser_id = $1 AND cid = $2;
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- name: addTargetCategory :one
+INSERT INTO target_category (
+    advertiser_id,
+    cid,
+    category,
+    rule
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetTargetCategory :one
+SELECT *
+FROM target_category
+WHERE advertiser_id = $1 AND cid = $2;
+
+-- name: updateTargetCategory :one
+UPDATE target_category
+SET category = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING *;
+
+-- name: deleteTargetCategory :exec
+DELETE FROM target_category
+WHERE advertiser_id = $1 AND cid = $2;
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- name: addTargetCity :one
+INSERT INTO target_city (
+    advertiser_id,
+    cid,
+    city,
+    rule
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetTargetCity :one
+SELECT *
+FROM target_city
+WHERE advertiser_id = $1 AND cid = $2;
+
+-- name: updateTargetCity :one
+UPDATE target_city
+SET city = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING *;
+
+-- name: deleteTargetCity :exec
+DELETE FROM target_city
+WHERE advertiser_id = $1 AND cid = $2;
\ No newline at end of file
@@ -1,24 +1,25 @@
--- name: AddTargetCountry :one
+-- name: addTargetCountry :one
 INSERT INTO target_country (
+    advertiser_id,
     cid,
     country,
     rule
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 )
 RETURNING *;
 
 -- name: GetTargetCountry :one
 SELECT *
 FROM target_country
-WHERE cid = $1;
+WHERE advertiser_id = $1 AND cid = $2;
 
 -- name: updateTargetCountry :one
 UPDATE target_country
-SET country = $2, rule = $3
-WHERE cid = $1
+SET country = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
 RETURNING *;
 
--- name: DeleteTargetCountry :exec
+-- name: deleteTargetCountry :exec
 DELETE FROM target_country
-WHERE cid = $1;
\ No newline at end of file
+WHERE advertiser_id = $1 AND cid = $2;
\ No newline at end of file
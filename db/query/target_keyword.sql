@@ -0,0 +1,25 @@
+-- name: addTargetKeyword :one
+INSERT INTO target_keyword (
+    advertiser_id,
+    cid,
+    keyword,
+    rule
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetTargetKeyword :one
+SELECT *
+FROM target_keyword
+WHERE advertiser_id = $1 AND cid = $2;
+
+-- name: updateTargetKeyword :one
+UPDATE target_keyword
+SET keyword = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING *;
+
+-- name: deleteTargetKeyword :exec
+DELETE FROM target_keyword
+WHERE advertiser_id = $1 AND cid = $2;
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- name: addTargetLanguage :one
+INSERT INTO target_language (
+    advertiser_id,
+    cid,
+    language,
+    rule
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetTargetLanguage :one
+SELECT *
+FROM target_language
+WHERE advertiser_id = $1 AND cid = $2;
+
+-- name: updateTargetLanguage :one
+UPDATE target_language
+SET language = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING *;
+
+-- name: deleteTargetLanguage :exec
+DELETE FROM target_language
+WHERE advertiser_id = $1 AND cid = $2;
\ No newline at end of file
@@ -0,0 +1,39 @@
+-- name: CreateTargetList :one
+INSERT INTO target_list (
+    advertiser_id,
+    name,
+    list_type,
+    items
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetTargetList :one
+SELECT *
+FROM target_list
+WHERE advertiser_id = $1 AND id = $2;
+
+-- name: getTargetListByID :one
+SELECT *
+FROM target_list
+WHERE id = $1;
+
+-- name: ListTargetLists :many
+SELECT *
+FROM target_list
+WHERE advertiser_id = $1
+ORDER BY id;
+
+-- name: updateTargetList :one
+UPDATE target_list
+SET name = $2,
+    items = $3,
+    version = version + 1,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: deleteTargetList :execrows
+DELETE FROM target_list
+WHERE advertiser_id = $1 AND id = $2;
@@ -0,0 +1,16 @@
+-- name: createTargetListHistory :exec
+INSERT INTO target_list_history (
+    list_id,
+    version,
+    field_changed,
+    old_value,
+    new_value
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ListTargetListHistory :many
+SELECT *
+FROM target_list_history
+WHERE list_id = $1
+ORDER BY updated_at DESC;
@@ -1,24 +1,25 @@
--- name: AddTargetOs :one
+-- name: addTargetOs :one
 INSERT INTO target_os (
+    advertiser_id,
     cid,
     os,
     rule
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 )
 RETURNING *;
 
 -- name: GetTargetOs :one
 SELECT *
 FROM target_os
-WHERE cid = $1;
+WHERE advertiser_id = $1 AND cid = $2;
 
 -- name: updateTargetOs :one
 UPDATE target_os
-SET os = $2, rule = $3
-WHERE cid = $1
+SET os = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
 RETURNING *;
 
--- name: DeleteTargetOs :exec
+-- name: deleteTargetOs :exec
 DELETE FROM target_os
-WHERE cid = $1;
\ No newline at end of file
+WHERE advertiser_id = $1 AND cid = $2;
\ No newline at end of file
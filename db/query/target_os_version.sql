@@ -0,0 +1,25 @@
+-- name: addTargetOsVersion :one
+INSERT INTO target_os_version (
+    advertiser_id,
+    cid,
+    os_version,
+    rule
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetTargetOsVersion :one
+SELECT *
+FROM target_os_version
+WHERE advertiser_id = $1 AND cid = $2;
+
+-- name: updateTargetOsVersion :one
+UPDATE target_os_version
+SET os_version = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING *;
+
+-- name: deleteTargetOsVersion :exec
+DELETE FROM target_os_version
+WHERE advertiser_id = $1 AND cid = $2;
\ No newline at end of file
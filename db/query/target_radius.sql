@@ -0,0 +1,28 @@
+-- name: addTargetRadius :one
+INSERT INTO target_radius (
+    advertiser_id,
+    cid,
+    lat,
+    lon,
+    radius_km,
+    rule
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListTargetRadius :many
+SELECT *
+FROM target_radius
+WHERE advertiser_id = $1 AND cid = $2
+ORDER BY id;
+
+-- name: deleteTargetRadiusByID :many
+DELETE FROM target_radius
+WHERE advertiser_id = $1 AND cid = $2 AND id = $3
+RETURNING *;
+
+-- name: deleteTargetRadius :many
+DELETE FROM target_radius
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING *;
\ No newline at end of file
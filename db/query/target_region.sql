@@ -0,0 +1,25 @@
+-- name: addTargetRegion :one
+INSERT INTO target_region (
+    advertiser_id,
+    cid,
+    region,
+    rule
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetTargetRegion :one
+SELECT *
+FROM target_region
+WHERE advertiser_id = $1 AND cid = $2;
+
+-- name: updateTargetRegion :one
+UPDATE target_region
+SET region = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING *;
+
+-- name: deleteTargetRegion :exec
+DELETE FROM target_region
+WHERE advertiser_id = $1 AND cid = $2;
\ No newline at end of file
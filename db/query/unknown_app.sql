@@ -0,0 +1,18 @@
+-- name: RecordUnknownApp :exec
+INSERT INTO unknown_app (
+    app_id
+) VALUES (
+    $1
+)
+ON CONFLICT (app_id) DO UPDATE
+SET request_count = unknown_app.request_count + 1,
+    last_seen_at = now();
+
+-- name: ListUnknownApps :many
+SELECT *
+FROM unknown_app
+ORDER BY request_count DESC, app_id;
+
+-- name: DeleteUnknownApp :exec
+DELETE FROM unknown_app
+WHERE app_id = $1;
@@ -0,0 +1,102 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscription (
+  advertiser_id,
+  url,
+  secret,
+  event_types
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListWebhookSubscriptions :many
+SELECT *
+FROM webhook_subscription
+WHERE advertiser_id = $1
+ORDER BY id;
+
+-- GetWebhookSubscription is not scoped to an advertiser, as it serves the
+-- delivery worker rather than callers of the API.
+
+-- name: GetWebhookSubscription :one
+SELECT *
+FROM webhook_subscription
+WHERE id = $1;
+
+-- name: DeleteWebhookSubscription :one
+DELETE FROM webhook_subscription
+WHERE advertiser_id = $1 AND id = $2
+RETURNING *;
+
+-- ClaimWebhookDeliveries takes the deliveries that are due, counting an
+-- attempt and holding them off until lease_until, so that other workers skip
+-- them while they are sent.
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_delivery
+SET attempts = attempts + 1, next_attempt_at = sqlc.arg(lease_until)::timestamptz
+WHERE id IN (
+  SELECT d.id
+  FROM webhook_delivery d
+  WHERE d.next_attempt_at <= now()
+  ORDER BY d.next_attempt_at, d.id
+  LIMIT sqlc.arg(row_limit)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ListWebhookDeliveries :many
+SELECT *
+FROM webhook_delivery
+WHERE advertiser_id = $1
+ORDER BY id;
+
+-- name: DeleteWebhookDelivery :exec
+DELETE FROM webhook_delivery
+WHERE id = $1;
+
+-- name: RetryWebhookDelivery :exec
+UPDATE webhook_delivery
+SET next_attempt_at = $2, last_error = $3
+WHERE id = $1;
+
+-- name: takeWebhookDelivery :one
+DELETE FROM webhook_delivery
+WHERE id = $1
+RETURNING *;
+
+-- name: createWebhookDeadLetter :one
+INSERT INTO webhook_dead_letter (
+  subscription_id,
+  advertiser_id,
+  event_type,
+  payload,
+  attempts,
+  last_error,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListWebhookDeadLetters :many
+SELECT *
+FROM webhook_dead_letter
+WHERE advertiser_id = $1
+ORDER BY id;
+
+-- name: takeWebhookDeadLetter :one
+DELETE FROM webhook_dead_letter
+WHERE advertiser_id = $1 AND id = $2
+RETURNING *;
+
+-- name: createWebhookDelivery :one
+INSERT INTO webhook_delivery (
+  subscription_id,
+  advertiser_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
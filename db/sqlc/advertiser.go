@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// DefaultAdvertiserName is the advertiser that owned every campaign before
+// advertisers were introduced, and that owns the bootstrap admin key.
+const DefaultAdvertiserName = "default"
+
+func (store *SQLStore) getCachedPublisherAdvertisers(ctx context.Context, appID string) (*[]PublisherAdvertiser, error) {
+	return getCached(ctx, store, fmt.Sprintf("publisher_advertiser:%s", appID), func(ctx context.Context) ([]PublisherAdvertiser, error) {
+		return store.ListPublisherAdvertisers(ctx, appID)
+	})
+}
+
+// allowsAdvertiser reports whether a publisher may be served campaigns of the
+// advertiser. Publishers without restrictions are served every advertiser.
+func allowsAdvertiser(allowed []PublisherAdvertiser, advertiserID int32) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(allowed, func(ref PublisherAdvertiser) bool {
+		return ref.AdvertiserID == advertiserID
+	})
+}
+
+func (store *SQLStore) invalidatePublisherAdvertisers(ctx context.Context, appID string) {
+	cacheKey := fmt.Sprintf("publisher_advertiser:%s", appID)
+	if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+	}
+	store.invalidateDelivery(ctx)
+}
+
+func (store *SQLStore) AddPublisherAdvertiser(ctx context.Context, arg AddPublisherAdvertiserParams) (PublisherAdvertiser, error) {
+	ref, err := store.Queries.AddPublisherAdvertiser(ctx, arg)
+	if err == nil {
+		store.invalidatePublisherAdvertisers(ctx, arg.AppID)
+	}
+	return ref, err
+}
+
+func (store *SQLStore) DeletePublisherAdvertiser(ctx context.Context, arg DeletePublisherAdvertiserParams) error {
+	err := store.Queries.DeletePublisherAdvertiser(ctx, arg)
+	if err == nil {
+		store.invalidatePublisherAdvertisers(ctx, arg.AppID)
+	}
+	return err
+}
+
+// DeleteAdvertiser removes an advertiser without campaigns, API keys, lists or
+// segments. Its deleted campaigns are purged, and its publisher restrictions are deleted
+// with it, which may open publishers that only allowed this advertiser to
+// every advertiser.
+func (store *SQLStore) DeleteAdvertiser(ctx context.Context, id int32) error {
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := q.purgeAdvertiserDeletedCampaigns(ctx, id); err != nil {
+			return err
+		}
+		return q.DeleteAdvertiser(ctx, id)
+	})
+	if err == nil {
+		store.deleteMatching(ctx, "publisher_advertiser:*")
+		store.invalidateDelivery(ctx)
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func createRandomAdvertiser(t *testing.T) db.Advertiser {
+	name := util.RandomName()
+
+	advertiser, err := testStore.CreateAdvertiser(context.Background(), name)
+	require.NoError(t, err)
+	require.NotZero(t, advertiser.ID)
+	require.Equal(t, name, advertiser.Name)
+	require.NotEmpty(t, advertiser.CreatedAt)
+
+	return advertiser
+}
+
+func TestCreateAdvertiser(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
+
+func TestGetAdvertiser(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+
+	get_advertiser, err := testStore.GetAdvertiser(context.Background(), advertiser.ID)
+	require.NoError(t, err)
+	require.Equal(t, advertiser, get_advertiser)
+
+	get_advertiser, err = testStore.GetAdvertiserByName(context.Background(), advertiser.Name)
+	require.NoError(t, err)
+	require.Equal(t, advertiser, get_advertiser)
+
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
+
+func TestListAdvertisers(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+
+	advertisers, err := testStore.ListAdvertisers(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, advertisers, advertiser)
+	require.Contains(t, advertisers, testAdvertiser)
+
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
+
+func TestDeleteAdvertiser(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+
+	err := testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+	require.NoError(t, err)
+
+	_, err = testStore.GetAdvertiser(context.Background(), advertiser.ID)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+}
+
+func TestDeleteAdvertiserWithCampaigns(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+	campaign, err := testStore.AddCampaign(context.Background(), db.AddCampaignParams{
+		AdvertiserID: advertiser.ID,
+		Cid:          util.RandomCid(),
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+	})
+	require.NoError(t, err)
+
+	err = testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+	require.Error(t, err)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	err = testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+	require.NoError(t, err)
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+// ErrAdminKeyExists is returned when bootstrapping a store that already has an
+// active global admin key; further keys must be minted through the API.
+var ErrAdminKeyExists = errors.New("an active global admin key already exists")
+
+type MintApiKeyParams struct {
+	AdvertiserID int32   `json:"advertiser_id"`
+	Name         string  `json:"name"`
+	Role         KeyRole `json:"role"`
+}
+
+// MintApiKeyResult carries the plaintext key, which is never stored and
+// cannot be recovered once this result is discarded.
+type MintApiKeyResult struct {
+	ApiKey ApiKey `json:"api_key"`
+	Key    string `json:"key"`
+}
+
+func mintApiKey(ctx context.Context, q *Queries, arg MintApiKeyParams) (MintApiKeyResult, error) {
+	key, err := util.NewAPIKey()
+	if err != nil {
+		return MintApiKeyResult{}, err
+	}
+
+	apiKey, err := q.CreateApiKey(ctx, CreateApiKeyParams{
+		AdvertiserID: arg.AdvertiserID,
+		Name:         arg.Name,
+		Prefix:       key[:util.APIKeyPrefixLen],
+		KeyHash:      util.HashAPIKey(key),
+		Role:         arg.Role,
+	})
+	if err != nil {
+		return MintApiKeyResult{}, err
+	}
+	return MintApiKeyResult{ApiKey: apiKey, Key: key}, nil
+}
+
+// MintApiKey generates a new key with the given role.
+func (store *SQLStore) MintApiKey(ctx context.Context, arg MintApiKeyParams) (MintApiKeyResult, error) {
+	return mintApiKey(ctx, store.Queries, arg)
+}
+
+// BootstrapAdminKey mints the first global admin key, owned by the default
+// advertiser. It refuses to run while another global admin key is active, so
+// it cannot be used to take over a running deployment.
+func (store *SQLStore) BootstrapAdminKey(ctx context.Context, name string) (MintApiKeyResult, error) {
+	var result MintApiKeyResult
+	err := store.execTx(ctx, func(q *Queries) error {
+		count, err := q.CountActiveApiKeys(ctx, KeyRoleGlobalAdmin)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAdminKeyExists
+		}
+
+		advertiser, err := q.GetAdvertiserByName(ctx, DefaultAdvertiserName)
+		if err != nil {
+			return err
+		}
+
+		result, err = mintApiKey(ctx, q, MintApiKeyParams{
+			AdvertiserID: advertiser.ID,
+			Name:         name,
+			Role:         KeyRoleGlobalAdmin,
+		})
+		return err
+	})
+	return result, err
+}
+
+// AuthenticateApiKey resolves a plaintext key to its active record. Unknown
+// and revoked keys return pgx.ErrNoRows.
+func (store *SQLStore) AuthenticateApiKey(ctx context.Context, key string) (ApiKey, error) {
+	hash := util.HashAPIKey(key)
+	apiKey, err := getCached(ctx, store, fmt.Sprintf("api_key:%s", hash), func(ctx context.Context) (ApiKey, error) {
+		return store.GetApiKeyByHash(ctx, hash)
+	})
+	if err != nil {
+		return ApiKey{}, err
+	}
+	if apiKey.ID == 0 {
+		return ApiKey{}, pgx.ErrNoRows
+	}
+	return *apiKey, nil
+}
+
+// RevokeApiKey revokes a key and drops it from the cache, so that it stops
+// working immediately rather than when the cache expires. With an advertiser,
+// only keys of that advertiser other than global admin keys are revoked.
+func (store *SQLStore) RevokeApiKey(ctx context.Context, arg RevokeApiKeyParams) (ApiKey, error) {
+	apiKey, err := store.Queries.RevokeApiKey(ctx, arg)
+	if err != nil {
+		return ApiKey{}, err
+	}
+
+	cacheKey := fmt.Sprintf("api_key:%s", apiKey.KeyHash)
+	if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+	}
+	return apiKey, nil
+}
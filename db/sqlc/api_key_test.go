@@ -0,0 +1,107 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func mintRandomApiKey(t *testing.T, role db.KeyRole) db.MintApiKeyResult {
+	arg := db.MintApiKeyParams{
+		AdvertiserID: testAdvertiser.ID,
+		Name:         util.RandomName(),
+		Role:         role,
+	}
+
+	result, err := testStore.MintApiKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, result.ApiKey.ID)
+	require.Equal(t, arg.AdvertiserID, result.ApiKey.AdvertiserID)
+	require.Equal(t, arg.Name, result.ApiKey.Name)
+	require.Equal(t, arg.Role, result.ApiKey.Role)
+	require.True(t, strings.HasPrefix(result.Key, result.ApiKey.Prefix))
+	require.Equal(t, util.HashAPIKey(result.Key), result.ApiKey.KeyHash)
+	require.NotEmpty(t, result.ApiKey.CreatedAt)
+	require.False(t, result.ApiKey.RevokedAt.Valid)
+
+	return result
+}
+
+func TestMintApiKey(t *testing.T) {
+	result := mintRandomApiKey(t, db.KeyRoleEditor)
+	testStore.RevokeApiKey(context.Background(), db.RevokeApiKeyParams{ID: result.ApiKey.ID})
+}
+
+func TestListApiKeys(t *testing.T) {
+	result := mintRandomApiKey(t, db.KeyRoleReadOnly)
+
+	api_keys, err := testStore.ListApiKeys(context.Background(), pgtype.Int4{})
+	require.NoError(t, err)
+	require.Contains(t, api_keys, result.ApiKey)
+
+	advertiser := createRandomAdvertiser(t)
+	api_keys, err = testStore.ListApiKeys(context.Background(), pgtype.Int4{Int32: advertiser.ID, Valid: true})
+	require.NoError(t, err)
+	require.Empty(t, api_keys)
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+
+	testStore.RevokeApiKey(context.Background(), db.RevokeApiKeyParams{ID: result.ApiKey.ID})
+}
+
+func TestAuthenticateApiKey(t *testing.T) {
+	result := mintRandomApiKey(t, db.KeyRoleDelivery)
+
+	api_key, err := testStore.AuthenticateApiKey(context.Background(), result.Key)
+	require.NoError(t, err)
+	require.Equal(t, result.ApiKey, api_key)
+
+	_, err = testStore.AuthenticateApiKey(context.Background(), result.Key+"0")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	testStore.RevokeApiKey(context.Background(), db.RevokeApiKeyParams{ID: result.ApiKey.ID})
+}
+
+func TestRevokeApiKey(t *testing.T) {
+	result := mintRandomApiKey(t, db.KeyRoleEditor)
+
+	// Warm the cache so the revocation has to clear it.
+	_, err := testStore.AuthenticateApiKey(context.Background(), result.Key)
+	require.NoError(t, err)
+
+	// Admins of another advertiser cannot revoke it.
+	advertiser := createRandomAdvertiser(t)
+	_, err = testStore.RevokeApiKey(context.Background(), db.RevokeApiKeyParams{
+		ID:           result.ApiKey.ID,
+		AdvertiserID: pgtype.Int4{Int32: advertiser.ID, Valid: true},
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+
+	api_key, err := testStore.RevokeApiKey(context.Background(), db.RevokeApiKeyParams{
+		ID:           result.ApiKey.ID,
+		AdvertiserID: pgtype.Int4{Int32: testAdvertiser.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, api_key.RevokedAt.Valid)
+
+	_, err = testStore.AuthenticateApiKey(context.Background(), result.Key)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = testStore.RevokeApiKey(context.Background(), db.RevokeApiKeyParams{ID: result.ApiKey.ID})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestBootstrapAdminKey(t *testing.T) {
+	result := mintRandomApiKey(t, db.KeyRoleGlobalAdmin)
+
+	_, err := testStore.BootstrapAdminKey(context.Background(), util.RandomName())
+	require.ErrorIs(t, err, db.ErrAdminKeyExists)
+
+	testStore.RevokeApiKey(context.Background(), db.RevokeApiKeyParams{ID: result.ApiKey.ID})
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// resolveApp looks up the categories and tags of the requesting app. Apps
+// missing from the registry resolve to empty metadata, so category and keyword
+// targets treat them as matching nothing, and are recorded for classification.
+// The miss is cached like any other lookup, so an unknown app is recorded at
+// most once per cache period.
+func (store *SQLStore) resolveApp(ctx context.Context, appID string) (AppMetadata, error) {
+	app, err := getCached(ctx, store, fmt.Sprintf("app_metadata:%s", appID), func(ctx context.Context) (AppMetadata, error) {
+		return store.GetAppMetadata(ctx, appID)
+	})
+	if err == pgx.ErrNoRows {
+		if err := store.RecordUnknownApp(ctx, appID); err != nil {
+			fmt.Printf("failed to record unknown app %s: %v\n", appID, err)
+		}
+		return AppMetadata{AppID: appID}, nil
+	}
+	if err != nil {
+		return AppMetadata{}, err
+	}
+	return *app, nil
+}
+
+func (store *SQLStore) invalidateAppMetadata(ctx context.Context, appID string) {
+	cacheKey := fmt.Sprintf("app_metadata:%s", appID)
+	if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+	}
+}
+
+// UpsertAppMetadata classifies an app, clearing it from the unknown apps.
+func (store *SQLStore) UpsertAppMetadata(ctx context.Context, arg UpsertAppMetadataParams) (AppMetadata, error) {
+	apps, err := store.ImportAppMetadata(ctx, []UpsertAppMetadataParams{arg})
+	if err != nil {
+		return AppMetadata{}, err
+	}
+	return apps[0], nil
+}
+
+// ImportAppMetadata classifies a batch of apps in a single transaction.
+func (store *SQLStore) ImportAppMetadata(ctx context.Context, args []UpsertAppMetadataParams) ([]AppMetadata, error) {
+	apps := make([]AppMetadata, 0, len(args))
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, arg := range args {
+			app, err := q.UpsertAppMetadata(ctx, arg)
+			if err != nil {
+				return fmt.Errorf("failed to import %s: %v", arg.AppID, err)
+			}
+			if err := q.DeleteUnknownApp(ctx, arg.AppID); err != nil {
+				return err
+			}
+			apps = append(apps, app)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range apps {
+		store.invalidateAppMetadata(ctx, app.AppID)
+	}
+	store.invalidateDelivery(ctx)
+	return apps, nil
+}
+
+func (store *SQLStore) DeleteAppMetadata(ctx context.Context, appID string) error {
+	err := store.Queries.DeleteAppMetadata(ctx, appID)
+	if err == nil {
+		store.invalidateAppMetadata(ctx, appID)
+		store.invalidateDelivery(ctx)
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func upsertRandomAppMetadata(t *testing.T) db.AppMetadata {
+	arg := db.UpsertAppMetadataParams{
+		AppID:      util.RandomString(12),
+		Categories: util.RandomCategory(),
+		Tags:       util.RandomKeyword(),
+	}
+
+	app_metadata, err := testStore.UpsertAppMetadata(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.AppID, app_metadata.AppID)
+	require.Equal(t, arg.Categories, app_metadata.Categories)
+	require.Equal(t, arg.Tags, app_metadata.Tags)
+	require.NotEmpty(t, app_metadata.UpdatedAt)
+
+	return app_metadata
+}
+
+func TestUpsertAppMetadata(t *testing.T) {
+	app_metadata := upsertRandomAppMetadata(t)
+
+	arg := db.UpsertAppMetadataParams{
+		AppID:      app_metadata.AppID,
+		Categories: "IAB1-2",
+		Tags:       "",
+	}
+	updated_app_metadata, err := testStore.UpsertAppMetadata(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Categories, updated_app_metadata.Categories)
+	require.Empty(t, updated_app_metadata.Tags)
+	require.True(t, updated_app_metadata.UpdatedAt.After(app_metadata.UpdatedAt))
+
+	testStore.DeleteAppMetadata(context.Background(), app_metadata.AppID)
+}
+
+func TestGetAppMetadata(t *testing.T) {
+	app_metadata := upsertRandomAppMetadata(t)
+
+	get_app_metadata, err := testStore.GetAppMetadata(context.Background(), app_metadata.AppID)
+	require.NoError(t, err)
+	require.Equal(t, app_metadata, get_app_metadata)
+
+	testStore.DeleteAppMetadata(context.Background(), app_metadata.AppID)
+}
+
+func TestDeleteAppMetadata(t *testing.T) {
+	app_metadata := upsertRandomAppMetadata(t)
+
+	err := testStore.DeleteAppMetadata(context.Background(), app_metadata.AppID)
+	require.NoError(t, err)
+
+	get_app_metadata, err := testStore.GetAppMetadata(context.Background(), app_metadata.AppID)
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, get_app_metadata)
+}
@@ -0,0 +1,63 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func createRandomAudienceSegment(t *testing.T) db.AudienceSegment {
+	name := util.RandomName()
+
+	segment, err := testStore.CreateAudienceSegment(context.Background(), db.CreateAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, Name: name})
+	require.NoError(t, err)
+	require.NotZero(t, segment.ID)
+	require.Equal(t, name, segment.Name)
+	require.Equal(t, db.SegmentStorageSet, segment.Storage)
+	require.Zero(t, segment.MemberCount)
+	require.NotEmpty(t, segment.CreatedAt)
+	require.NotEmpty(t, segment.UpdatedAt)
+
+	return segment
+}
+
+func TestCreateAudienceSegment(t *testing.T) {
+	segment := createRandomAudienceSegment(t)
+	testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment.ID})
+}
+
+func TestGetAudienceSegment(t *testing.T) {
+	segment := createRandomAudienceSegment(t)
+
+	get_segment, err := testStore.GetAudienceSegment(context.Background(), db.GetAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment.ID})
+	require.NoError(t, err)
+	require.Equal(t, segment, get_segment)
+
+	testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment.ID})
+}
+
+func TestListAudienceSegments(t *testing.T) {
+	segment := createRandomAudienceSegment(t)
+
+	segments, err := testStore.ListAudienceSegments(context.Background(), testAdvertiser.ID)
+	require.NoError(t, err)
+	require.Contains(t, segments, segment)
+
+	testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment.ID})
+}
+
+func TestDeleteAudienceSegment(t *testing.T) {
+	segment := createRandomAudienceSegment(t)
+
+	err := testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment.ID})
+	require.NoError(t, err)
+
+	get_segment, err := testStore.GetAudienceSegment(context.Background(), db.GetAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment.ID})
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, get_segment)
+}
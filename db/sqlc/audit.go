@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AuditContext describes who is behind the changes made with a context. The
+// audit_row trigger reads it from the adrouter.* settings of the connection.
+type AuditContext struct {
+	Actor     string
+	SourceIP  string
+	RequestID string
+}
+
+type auditContextKey struct{}
+
+// WithAuditContext attributes the changes made with ctx to audit.
+func WithAuditContext(ctx context.Context, audit AuditContext) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, audit)
+}
+
+func auditContextFrom(ctx context.Context) (AuditContext, bool) {
+	audit, ok := ctx.Value(auditContextKey{}).(AuditContext)
+	return audit, ok
+}
+
+const (
+	setAuditContext = `SELECT set_config('adrouter.actor', $1, false),
+  set_config('adrouter.source_ip', $2, false),
+  set_config('adrouter.request_id', $3, false)`
+	resetAuditContext = `RESET adrouter.actor; RESET adrouter.source_ip; RESET adrouter.request_id`
+)
+
+// NewPool connects to the database with a pool that copies the AuditContext
+// of each query's context onto the connection running it, and clears it when
+// the connection is released. Transactions acquire their connection with the
+// context passed to Begin, so every statement in them is attributed alike.
+func NewPool(ctx context.Context, source string) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var audited sync.Map
+	config.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		audit, ok := auditContextFrom(ctx)
+		if !ok {
+			return true
+		}
+		if _, err := conn.Exec(ctx, setAuditContext, audit.Actor, audit.SourceIP, audit.RequestID); err != nil {
+			return false
+		}
+		audited.Store(conn, struct{}{})
+		return true
+	}
+	config.AfterRelease = func(conn *pgx.Conn) bool {
+		if _, ok := audited.LoadAndDelete(conn); !ok {
+			return true
+		}
+		_, err := conn.Exec(context.Background(), resetAuditContext)
+		return err == nil
+	}
+
+	return pgxpool.NewWithConfig(ctx, config)
+}
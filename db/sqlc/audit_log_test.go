@@ -0,0 +1,98 @@
+package db_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestAuditLog(t *testing.T) {
+	audit := db.AuditContext{
+		Actor:     "user:" + util.RandomName(),
+		SourceIP:  "203.0.113.7",
+		RequestID: util.RandomName(),
+	}
+	ctx := db.WithAuditContext(context.Background(), audit)
+
+	campaign, err := testStore.AddCampaign(ctx, db.AddCampaignParams{
+		AdvertiserID: testAdvertiser.ID,
+		Cid:          util.RandomCid(),
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+		Status:       db.NullStatusType{StatusType: db.StatusTypeActive, Valid: true},
+	})
+	require.NoError(t, err)
+
+	err = testStore.ToggleStatus(ctx, campaign.Key(), 0)
+	require.NoError(t, err)
+
+	err = testStore.DeleteCampaign(ctx, db.DeleteCampaignParams(campaign.Key()))
+	require.NoError(t, err)
+
+	_, err = testStore.RestoreCampaign(ctx, campaign.Key())
+	require.NoError(t, err)
+
+	// Deleting a campaign only marks it, but the log records it as a delete,
+	// and clearing the mark as a restore.
+	entries, err := testStore.ListAuditLog(context.Background(), db.ListAuditLogParams{
+		RequestID: pgtype.Text{String: audit.RequestID, Valid: true},
+		RowLimit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	operations := []db.AuditOperation{db.AuditOperationRestore, db.AuditOperationDelete, db.AuditOperationUpdate, db.AuditOperationCreate}
+	for i, entry := range entries {
+		require.Equal(t, operations[i], entry.Operation)
+		require.Equal(t, audit.Actor, entry.Actor)
+		require.Equal(t, audit.SourceIP, entry.SourceIp)
+		require.Equal(t, "campaign", entry.Entity)
+		require.Equal(t, campaign.AdvertiserID, entry.AdvertiserID.Int32)
+		require.Equal(t, campaign.Cid, entry.EntityID)
+	}
+
+	var before, after struct {
+		Status db.StatusType `json:"status"`
+	}
+	require.NoError(t, json.Unmarshal(entries[2].Before, &before))
+	require.NoError(t, json.Unmarshal(entries[2].After, &after))
+	require.Equal(t, db.StatusTypeActive, before.Status)
+	require.Equal(t, db.StatusTypePaused, after.Status)
+
+	var deleted struct {
+		DeletedAt *time.Time `json:"deleted_at"`
+	}
+	require.NoError(t, json.Unmarshal(entries[1].After, &deleted))
+	require.NotNil(t, deleted.DeletedAt)
+	require.NoError(t, json.Unmarshal(entries[0].After, &deleted))
+	require.Nil(t, deleted.DeletedAt)
+	require.Nil(t, entries[3].Before)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestAuditLogWithoutContext(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+
+	entries, err := testStore.ListAuditLog(context.Background(), db.ListAuditLogParams{
+		Entity:       pgtype.Text{String: "campaign", Valid: true},
+		AdvertiserID: pgtype.Int4{Int32: campaign.AdvertiserID, Valid: true},
+		EntityID:     pgtype.Text{String: campaign.Cid, Valid: true},
+		RowLimit:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	for _, entry := range entries {
+		require.Equal(t, "system", entry.Actor)
+		require.Empty(t, entry.RequestID)
+	}
+}
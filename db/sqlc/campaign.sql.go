@@ -7,33 +7,43 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addCampaign = `-- name: AddCampaign :one
 INSERT INTO campaign (
+  advertiser_id,
   cid,
   name,
   img,
-  cta
+  cta,
+  status
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5,
+    COALESCE($6::status_type, 'draft'::status_type)
 )
-RETURNING cid, name, img, cta, status, created_at
+RETURNING cid, name, img, cta, status, created_at, advertiser_id, version, deleted_at
 `
 
 type AddCampaignParams struct {
-	Cid  string `json:"cid"`
-	Name string `json:"name"`
-	Img  string `json:"img"`
-	Cta  string `json:"cta"`
+	AdvertiserID int32          `json:"advertiser_id"`
+	Cid          string         `json:"cid"`
+	Name         string         `json:"name"`
+	Img          string         `json:"img"`
+	Cta          string         `json:"cta"`
+	Status       NullStatusType `json:"status"`
 }
 
 func (q *Queries) AddCampaign(ctx context.Context, arg AddCampaignParams) (Campaign, error) {
 	row := q.db.QueryRow(ctx, addCampaign,
+		arg.AdvertiserID,
 		arg.Cid,
 		arg.Name,
 		arg.Img,
 		arg.Cta,
+		arg.Status,
 	)
 	var i Campaign
 	err := row.Scan(
@@ -43,28 +53,94 @@ func (q *Queries) AddCampaign(ctx context.Context, arg AddCampaignParams) (Campa
 		&i.Cta,
 		&i.Status,
 		&i.CreatedAt,
+		&i.AdvertiserID,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const countCampaigns = `-- name: CountCampaigns :one
+SELECT count(*)
+FROM campaign c
+WHERE c.advertiser_id = $1 AND c.deleted_at IS NULL
+  AND ($2::status_type IS NULL OR c.status = $2)
+  AND ($3::text IS NULL OR c.name ILIKE '%' || $3 || '%')
+  AND ($4::timestamptz IS NULL OR c.created_at >= $4)
+  AND ($5::timestamptz IS NULL OR c.created_at < $5)
+  AND ($6::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower($6)]
+  ))
+  AND ($7::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower($7)]
+  ))
+  AND ($8::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower($8)]
+  ))
+`
+
+type CountCampaignsParams struct {
+	AdvertiserID int32              `json:"advertiser_id"`
+	Status       NullStatusType     `json:"status"`
+	Name         pgtype.Text        `json:"name"`
+	CreatedSince pgtype.Timestamptz `json:"created_since"`
+	CreatedUntil pgtype.Timestamptz `json:"created_until"`
+	Country      pgtype.Text        `json:"country"`
+	App          pgtype.Text        `json:"app"`
+	Os           pgtype.Text        `json:"os"`
+}
+
+func (q *Queries) CountCampaigns(ctx context.Context, arg CountCampaignsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countCampaigns,
+		arg.AdvertiserID,
+		arg.Status,
+		arg.Name,
+		arg.CreatedSince,
+		arg.CreatedUntil,
+		arg.Country,
+		arg.App,
+		arg.Os,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const deleteCampaign = `-- name: DeleteCampaign :exec
-DELETE FROM campaign
-WHERE cid = $1
+UPDATE campaign
+SET deleted_at = now()
+WHERE advertiser_id = $1 AND cid = $2 AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteCampaign(ctx context.Context, cid string) error {
-	_, err := q.db.Exec(ctx, deleteCampaign, cid)
+type DeleteCampaignParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+}
+
+func (q *Queries) DeleteCampaign(ctx context.Context, arg DeleteCampaignParams) error {
+	_, err := q.db.Exec(ctx, deleteCampaign, arg.AdvertiserID, arg.Cid)
 	return err
 }
 
 const getCampaign = `-- name: GetCampaign :one
-SELECT cid, name, img, cta, status, created_at
+SELECT cid, name, img, cta, status, created_at, advertiser_id, version, deleted_at
 FROM campaign
-WHERE cid = $1
+WHERE advertiser_id = $1 AND cid = $2 AND deleted_at IS NULL
 `
 
-func (q *Queries) GetCampaign(ctx context.Context, cid string) (Campaign, error) {
-	row := q.db.QueryRow(ctx, getCampaign, cid)
+type GetCampaignParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+}
+
+func (q *Queries) GetCampaign(ctx context.Context, arg GetCampaignParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, getCampaign, arg.AdvertiserID, arg.Cid)
 	var i Campaign
 	err := row.Scan(
 		&i.Cid,
@@ -73,14 +149,17 @@ func (q *Queries) GetCampaign(ctx context.Context, cid string) (Campaign, error)
 		&i.Cta,
 		&i.Status,
 		&i.CreatedAt,
+		&i.AdvertiserID,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const listActiveCampaigns = `-- name: ListActiveCampaigns :many
-SELECT cid, name, img, cta, status, created_at
+SELECT cid, name, img, cta, status, created_at, advertiser_id, version, deleted_at
 FROM campaign
-WHERE status = 'active'::status_type
+WHERE status = 'active'::status_type AND deleted_at IS NULL
 `
 
 func (q *Queries) ListActiveCampaigns(ctx context.Context) ([]Campaign, error) {
@@ -99,6 +178,9 @@ func (q *Queries) ListActiveCampaigns(ctx context.Context) ([]Campaign, error) {
 			&i.Cta,
 			&i.Status,
 			&i.CreatedAt,
+			&i.AdvertiserID,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -111,12 +193,363 @@ func (q *Queries) ListActiveCampaigns(ctx context.Context) ([]Campaign, error) {
 }
 
 const listCampaigns = `-- name: ListCampaigns :many
-SELECT cid, name, img, cta, status, created_at
+SELECT cid, name, img, cta, status, created_at, advertiser_id, version, deleted_at
 FROM campaign
+WHERE advertiser_id = $1 AND deleted_at IS NULL
+ORDER BY cid
+`
+
+func (q *Queries) ListCampaigns(ctx context.Context, advertiserID int32) ([]Campaign, error) {
+	rows, err := q.db.Query(ctx, listCampaigns, advertiserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Campaign{}
+	for rows.Next() {
+		var i Campaign
+		if err := rows.Scan(
+			&i.Cid,
+			&i.Name,
+			&i.Img,
+			&i.Cta,
+			&i.Status,
+			&i.CreatedAt,
+			&i.AdvertiserID,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedCampaigns = `-- name: PurgeDeletedCampaigns :execrows
+DELETE FROM campaign
+WHERE deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeDeletedCampaigns(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedCampaigns, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const searchCampaignsByName = `-- name: SearchCampaignsByName :many
+SELECT c.cid, c.name, c.img, c.cta, c.status, c.created_at, c.advertiser_id, c.version, c.deleted_at
+FROM campaign c
+WHERE c.advertiser_id = $1 AND c.deleted_at IS NULL
+  AND ($2::status_type IS NULL OR c.status = $2)
+  AND ($3::text IS NULL OR c.name ILIKE '%' || $3 || '%')
+  AND ($4::timestamptz IS NULL OR c.created_at >= $4)
+  AND ($5::timestamptz IS NULL OR c.created_at < $5)
+  AND ($6::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower($6)]
+  ))
+  AND ($7::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower($7)]
+  ))
+  AND ($8::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower($8)]
+  ))
+  AND ($9::text IS NULL OR (c.name, c.cid) > ($9, $10::text))
+ORDER BY c.name ASC, c.cid ASC
+LIMIT $11
+`
+
+type SearchCampaignsByNameParams struct {
+	AdvertiserID int32              `json:"advertiser_id"`
+	Status       NullStatusType     `json:"status"`
+	Name         pgtype.Text        `json:"name"`
+	CreatedSince pgtype.Timestamptz `json:"created_since"`
+	CreatedUntil pgtype.Timestamptz `json:"created_until"`
+	Country      pgtype.Text        `json:"country"`
+	App          pgtype.Text        `json:"app"`
+	Os           pgtype.Text        `json:"os"`
+	AfterName    pgtype.Text        `json:"after_name"`
+	AfterCid     string             `json:"after_cid"`
+	RowLimit     int32              `json:"row_limit"`
+}
+
+func (q *Queries) SearchCampaignsByName(ctx context.Context, arg SearchCampaignsByNameParams) ([]Campaign, error) {
+	rows, err := q.db.Query(ctx, searchCampaignsByName,
+		arg.AdvertiserID,
+		arg.Status,
+		arg.Name,
+		arg.CreatedSince,
+		arg.CreatedUntil,
+		arg.Country,
+		arg.App,
+		arg.Os,
+		arg.AfterName,
+		arg.AfterCid,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Campaign{}
+	for rows.Next() {
+		var i Campaign
+		if err := rows.Scan(
+			&i.Cid,
+			&i.Name,
+			&i.Img,
+			&i.Cta,
+			&i.Status,
+			&i.CreatedAt,
+			&i.AdvertiserID,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchCampaignsByNameDesc = `-- name: SearchCampaignsByNameDesc :many
+SELECT c.cid, c.name, c.img, c.cta, c.status, c.created_at, c.advertiser_id, c.version, c.deleted_at
+FROM campaign c
+WHERE c.advertiser_id = $1 AND c.deleted_at IS NULL
+  AND ($2::status_type IS NULL OR c.status = $2)
+  AND ($3::text IS NULL OR c.name ILIKE '%' || $3 || '%')
+  AND ($4::timestamptz IS NULL OR c.created_at >= $4)
+  AND ($5::timestamptz IS NULL OR c.created_at < $5)
+  AND ($6::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower($6)]
+  ))
+  AND ($7::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower($7)]
+  ))
+  AND ($8::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower($8)]
+  ))
+  AND ($9::text IS NULL OR (c.name, c.cid) < ($9, $10::text))
+ORDER BY c.name DESC, c.cid DESC
+LIMIT $11
+`
+
+type SearchCampaignsByNameDescParams struct {
+	AdvertiserID int32              `json:"advertiser_id"`
+	Status       NullStatusType     `json:"status"`
+	Name         pgtype.Text        `json:"name"`
+	CreatedSince pgtype.Timestamptz `json:"created_since"`
+	CreatedUntil pgtype.Timestamptz `json:"created_until"`
+	Country      pgtype.Text        `json:"country"`
+	App          pgtype.Text        `json:"app"`
+	Os           pgtype.Text        `json:"os"`
+	AfterName    pgtype.Text        `json:"after_name"`
+	AfterCid     string             `json:"after_cid"`
+	RowLimit     int32              `json:"row_limit"`
+}
+
+func (q *Queries) SearchCampaignsByNameDesc(ctx context.Context, arg SearchCampaignsByNameDescParams) ([]Campaign, error) {
+	rows, err := q.db.Query(ctx, searchCampaignsByNameDesc,
+		arg.AdvertiserID,
+		arg.Status,
+		arg.Name,
+		arg.CreatedSince,
+		arg.CreatedUntil,
+		arg.Country,
+		arg.App,
+		arg.Os,
+		arg.AfterName,
+		arg.AfterCid,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Campaign{}
+	for rows.Next() {
+		var i Campaign
+		if err := rows.Scan(
+			&i.Cid,
+			&i.Name,
+			&i.Img,
+			&i.Cta,
+			&i.Status,
+			&i.CreatedAt,
+			&i.AdvertiserID,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchCampaignsByNewest = `-- name: SearchCampaignsByNewest :many
+SELECT c.cid, c.name, c.img, c.cta, c.status, c.created_at, c.advertiser_id, c.version, c.deleted_at
+FROM campaign c
+WHERE c.advertiser_id = $1 AND c.deleted_at IS NULL
+  AND ($2::status_type IS NULL OR c.status = $2)
+  AND ($3::text IS NULL OR c.name ILIKE '%' || $3 || '%')
+  AND ($4::timestamptz IS NULL OR c.created_at >= $4)
+  AND ($5::timestamptz IS NULL OR c.created_at < $5)
+  AND ($6::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower($6)]
+  ))
+  AND ($7::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower($7)]
+  ))
+  AND ($8::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower($8)]
+  ))
+  AND ($9::timestamptz IS NULL OR (c.created_at, c.cid) < ($9, $10::text))
+ORDER BY c.created_at DESC, c.cid DESC
+LIMIT $11
+`
+
+type SearchCampaignsByNewestParams struct {
+	AdvertiserID   int32              `json:"advertiser_id"`
+	Status         NullStatusType     `json:"status"`
+	Name           pgtype.Text        `json:"name"`
+	CreatedSince   pgtype.Timestamptz `json:"created_since"`
+	CreatedUntil   pgtype.Timestamptz `json:"created_until"`
+	Country        pgtype.Text        `json:"country"`
+	App            pgtype.Text        `json:"app"`
+	Os             pgtype.Text        `json:"os"`
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterCid       string             `json:"after_cid"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+func (q *Queries) SearchCampaignsByNewest(ctx context.Context, arg SearchCampaignsByNewestParams) ([]Campaign, error) {
+	rows, err := q.db.Query(ctx, searchCampaignsByNewest,
+		arg.AdvertiserID,
+		arg.Status,
+		arg.Name,
+		arg.CreatedSince,
+		arg.CreatedUntil,
+		arg.Country,
+		arg.App,
+		arg.Os,
+		arg.AfterCreatedAt,
+		arg.AfterCid,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Campaign{}
+	for rows.Next() {
+		var i Campaign
+		if err := rows.Scan(
+			&i.Cid,
+			&i.Name,
+			&i.Img,
+			&i.Cta,
+			&i.Status,
+			&i.CreatedAt,
+			&i.AdvertiserID,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchCampaignsByOldest = `-- name: SearchCampaignsByOldest :many
+SELECT c.cid, c.name, c.img, c.cta, c.status, c.created_at, c.advertiser_id, c.version, c.deleted_at
+FROM campaign c
+WHERE c.advertiser_id = $1 AND c.deleted_at IS NULL
+  AND ($2::status_type IS NULL OR c.status = $2)
+  AND ($3::text IS NULL OR c.name ILIKE '%' || $3 || '%')
+  AND ($4::timestamptz IS NULL OR c.created_at >= $4)
+  AND ($5::timestamptz IS NULL OR c.created_at < $5)
+  AND ($6::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower($6)]
+  ))
+  AND ($7::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower($7)]
+  ))
+  AND ($8::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower($8)]
+  ))
+  AND ($9::timestamptz IS NULL OR (c.created_at, c.cid) > ($9, $10::text))
+ORDER BY c.created_at ASC, c.cid ASC
+LIMIT $11
 `
 
-func (q *Queries) ListCampaigns(ctx context.Context) ([]Campaign, error) {
-	rows, err := q.db.Query(ctx, listCampaigns)
+type SearchCampaignsByOldestParams struct {
+	AdvertiserID   int32              `json:"advertiser_id"`
+	Status         NullStatusType     `json:"status"`
+	Name           pgtype.Text        `json:"name"`
+	CreatedSince   pgtype.Timestamptz `json:"created_since"`
+	CreatedUntil   pgtype.Timestamptz `json:"created_until"`
+	Country        pgtype.Text        `json:"country"`
+	App            pgtype.Text        `json:"app"`
+	Os             pgtype.Text        `json:"os"`
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterCid       string             `json:"after_cid"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+func (q *Queries) SearchCampaignsByOldest(ctx context.Context, arg SearchCampaignsByOldestParams) ([]Campaign, error) {
+	rows, err := q.db.Query(ctx, searchCampaignsByOldest,
+		arg.AdvertiserID,
+		arg.Status,
+		arg.Name,
+		arg.CreatedSince,
+		arg.CreatedUntil,
+		arg.Country,
+		arg.App,
+		arg.Os,
+		arg.AfterCreatedAt,
+		arg.AfterCid,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +564,9 @@ func (q *Queries) ListCampaigns(ctx context.Context) ([]Campaign, error) {
 			&i.Cta,
 			&i.Status,
 			&i.CreatedAt,
+			&i.AdvertiserID,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -142,18 +578,154 @@ func (q *Queries) ListCampaigns(ctx context.Context) ([]Campaign, error) {
 	return items, nil
 }
 
-const toggleStatus = `-- name: toggleStatus :one
+const bumpCampaignVersion = `-- name: bumpCampaignVersion :one
+UPDATE campaign
+SET version = version + 1
+WHERE advertiser_id = $1 AND cid = $2 AND deleted_at IS NULL
+  AND ($3::int IS NULL OR version = $3)
+RETURNING version
+`
+
+type bumpCampaignVersionParams struct {
+	AdvertiserID int32       `json:"advertiser_id"`
+	Cid          string      `json:"cid"`
+	Version      pgtype.Int4 `json:"version"`
+}
+
+func (q *Queries) bumpCampaignVersion(ctx context.Context, arg bumpCampaignVersionParams) (int32, error) {
+	row := q.db.QueryRow(ctx, bumpCampaignVersion, arg.AdvertiserID, arg.Cid, arg.Version)
+	var version int32
+	err := row.Scan(&version)
+	return version, err
+}
+
+const purgeAdvertiserDeletedCampaigns = `-- name: purgeAdvertiserDeletedCampaigns :exec
+DELETE FROM campaign
+WHERE advertiser_id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) purgeAdvertiserDeletedCampaigns(ctx context.Context, advertiserID int32) error {
+	_, err := q.db.Exec(ctx, purgeAdvertiserDeletedCampaigns, advertiserID)
+	return err
+}
+
+const restoreCampaign = `-- name: restoreCampaign :one
+UPDATE campaign
+SET deleted_at = NULL, version = version + 1
+WHERE advertiser_id = $1 AND cid = $2 AND deleted_at IS NOT NULL
+RETURNING cid, name, img, cta, status, created_at, advertiser_id, version, deleted_at
+`
+
+type restoreCampaignParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+}
+
+func (q *Queries) restoreCampaign(ctx context.Context, arg restoreCampaignParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, restoreCampaign, arg.AdvertiserID, arg.Cid)
+	var i Campaign
+	err := row.Scan(
+		&i.Cid,
+		&i.Name,
+		&i.Img,
+		&i.Cta,
+		&i.Status,
+		&i.CreatedAt,
+		&i.AdvertiserID,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const selectBulkCampaigns = `-- name: selectBulkCampaigns :many
+SELECT c.cid, c.status
+FROM campaign c
+WHERE c.advertiser_id = $1 AND c.deleted_at IS NULL
+  AND ($2::text[] IS NULL OR c.cid = ANY($2::text[]))
+  AND ($3::text[] IS NULL OR EXISTS (
+    SELECT 1 FROM campaign_label l
+    WHERE l.advertiser_id = c.advertiser_id AND l.cid = c.cid
+      AND l.label = ANY($3::text[])
+  ))
+  AND ($4::status_type IS NULL OR c.status = $4)
+  AND ($5::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_country t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.country) @> ARRAY[lower($5)]
+  ))
+  AND ($6::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_app t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.app_id) @> ARRAY[lower($6)]
+  ))
+  AND ($7::text IS NULL OR EXISTS (
+    SELECT 1 FROM target_os t
+    WHERE t.advertiser_id = c.advertiser_id AND t.cid = c.cid
+      AND csv_items(t.os) @> ARRAY[lower($7)]
+  ))
+ORDER BY c.cid
+FOR UPDATE OF c
+`
+
+type selectBulkCampaignsParams struct {
+	AdvertiserID int32          `json:"advertiser_id"`
+	Cids         []string       `json:"cids"`
+	Labels       []string       `json:"labels"`
+	Status       NullStatusType `json:"status"`
+	Country      pgtype.Text    `json:"country"`
+	App          pgtype.Text    `json:"app"`
+	Os           pgtype.Text    `json:"os"`
+}
+
+type selectBulkCampaignsRow struct {
+	Cid    string     `json:"cid"`
+	Status StatusType `json:"status"`
+}
+
+func (q *Queries) selectBulkCampaigns(ctx context.Context, arg selectBulkCampaignsParams) ([]selectBulkCampaignsRow, error) {
+	rows, err := q.db.Query(ctx, selectBulkCampaigns,
+		arg.AdvertiserID,
+		arg.Cids,
+		arg.Labels,
+		arg.Status,
+		arg.Country,
+		arg.App,
+		arg.Os,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []selectBulkCampaignsRow{}
+	for rows.Next() {
+		var i selectBulkCampaignsRow
+		if err := rows.Scan(&i.Cid, &i.Status); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setCampaignStatus = `-- name: setCampaignStatus :one
 UPDATE campaign
-SET status = CASE 
-    WHEN status = 'active'::status_type THEN 'inactive'::status_type
-    ELSE 'active'::status_type
-END
-WHERE cid = $1
+SET status = $3
+WHERE advertiser_id = $1 AND cid = $2
 RETURNING status
 `
 
-func (q *Queries) toggleStatus(ctx context.Context, cid string) (StatusType, error) {
-	row := q.db.QueryRow(ctx, toggleStatus, cid)
+type setCampaignStatusParams struct {
+	AdvertiserID int32      `json:"advertiser_id"`
+	Cid          string     `json:"cid"`
+	Status       StatusType `json:"status"`
+}
+
+func (q *Queries) setCampaignStatus(ctx context.Context, arg setCampaignStatusParams) (StatusType, error) {
+	row := q.db.QueryRow(ctx, setCampaignStatus, arg.AdvertiserID, arg.Cid, arg.Status)
 	var status StatusType
 	err := row.Scan(&status)
 	return status, err
@@ -161,18 +733,19 @@ func (q *Queries) toggleStatus(ctx context.Context, cid string) (StatusType, err
 
 const updateCampaignCta = `-- name: updateCampaignCta :one
 UPDATE campaign
-SET cta = $2
-WHERE cid = $1
-RETURNING cid, name, img, cta, status, created_at
+SET cta = $3
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING cid, name, img, cta, status, created_at, advertiser_id, version, deleted_at
 `
 
 type updateCampaignCtaParams struct {
-	Cid string `json:"cid"`
-	Cta string `json:"cta"`
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Cta          string `json:"cta"`
 }
 
 func (q *Queries) updateCampaignCta(ctx context.Context, arg updateCampaignCtaParams) (Campaign, error) {
-	row := q.db.QueryRow(ctx, updateCampaignCta, arg.Cid, arg.Cta)
+	row := q.db.QueryRow(ctx, updateCampaignCta, arg.AdvertiserID, arg.Cid, arg.Cta)
 	var i Campaign
 	err := row.Scan(
 		&i.Cid,
@@ -181,24 +754,28 @@ func (q *Queries) updateCampaignCta(ctx context.Context, arg updateCampaignCtaPa
 		&i.Cta,
 		&i.Status,
 		&i.CreatedAt,
+		&i.AdvertiserID,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const updateCampaignImage = `-- name: updateCampaignImage :one
 UPDATE campaign
-SET img = $2
-WHERE cid = $1
-RETURNING cid, name, img, cta, status, created_at
+SET img = $3
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING cid, name, img, cta, status, created_at, advertiser_id, version, deleted_at
 `
 
 type updateCampaignImageParams struct {
-	Cid string `json:"cid"`
-	Img string `json:"img"`
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Img          string `json:"img"`
 }
 
 func (q *Queries) updateCampaignImage(ctx context.Context, arg updateCampaignImageParams) (Campaign, error) {
-	row := q.db.QueryRow(ctx, updateCampaignImage, arg.Cid, arg.Img)
+	row := q.db.QueryRow(ctx, updateCampaignImage, arg.AdvertiserID, arg.Cid, arg.Img)
 	var i Campaign
 	err := row.Scan(
 		&i.Cid,
@@ -207,24 +784,28 @@ func (q *Queries) updateCampaignImage(ctx context.Context, arg updateCampaignIma
 		&i.Cta,
 		&i.Status,
 		&i.CreatedAt,
+		&i.AdvertiserID,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const updateCampaignName = `-- name: updateCampaignName :one
 UPDATE campaign
-SET name = $2
-WHERE cid = $1
-RETURNING cid, name, img, cta, status, created_at
+SET name = $3
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING cid, name, img, cta, status, created_at, advertiser_id, version, deleted_at
 `
 
 type updateCampaignNameParams struct {
-	Cid  string `json:"cid"`
-	Name string `json:"name"`
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Name         string `json:"name"`
 }
 
 func (q *Queries) updateCampaignName(ctx context.Context, arg updateCampaignNameParams) (Campaign, error) {
-	row := q.db.QueryRow(ctx, updateCampaignName, arg.Cid, arg.Name)
+	row := q.db.QueryRow(ctx, updateCampaignName, arg.AdvertiserID, arg.Cid, arg.Name)
 	var i Campaign
 	err := row.Scan(
 		&i.Cid,
@@ -233,6 +814,9 @@ func (q *Queries) updateCampaignName(ctx context.Context, arg updateCampaignName
 		&i.Cta,
 		&i.Status,
 		&i.CreatedAt,
+		&i.AdvertiserID,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
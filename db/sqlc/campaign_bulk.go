@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Operations accepted by BulkUpdateCampaigns.
+const (
+	BulkPause   = "pause"
+	BulkResume  = "resume"
+	BulkDelete  = "delete"
+	BulkLabel   = "label"
+	BulkUnlabel = "unlabel"
+)
+
+// CampaignSelector picks the campaigns of an advertiser that a bulk operation
+// applies to: those matching every given filter. Cids and Labels match any of
+// their values. A selector without filters must set All, so that an empty
+// request cannot touch every campaign by mistake.
+type CampaignSelector struct {
+	All     bool       `json:"all"`
+	Cids    []string   `json:"cids"`
+	Labels  []string   `json:"labels"`
+	Status  StatusType `json:"status"`
+	Country string     `json:"country"`
+	App     string     `json:"app"`
+	Os      string     `json:"os"`
+}
+
+func (selector CampaignSelector) empty() bool {
+	return len(selector.Cids) == 0 && len(selector.Labels) == 0 && selector.Status == "" &&
+		selector.Country == "" && selector.App == "" && selector.Os == ""
+}
+
+type BulkUpdateCampaignsParams struct {
+	AdvertiserID int32            `json:"advertiser_id"`
+	Selector     CampaignSelector `json:"selector"`
+	Operation    string           `json:"operation"`
+	// Labels are added or removed by the label and unlabel operations.
+	Labels []string `json:"labels"`
+	// Reason is recorded with the status changes of pause and resume.
+	Reason string `json:"reason"`
+}
+
+// BulkUpdateCampaignsResult counts the campaigns a bulk operation selected and
+// lists those it changed, in cid order. Pausing a paused or draft campaign,
+// for one, selects it without changing it.
+type BulkUpdateCampaignsResult struct {
+	Matched int      `json:"matched"`
+	Changed []string `json:"changed"`
+}
+
+// bulkTransitions are the lifecycle transitions of the pause and resume
+// operations. Unlike ToggleStatus, they only ever move a campaign one way, and
+// leave alone the campaigns the transition does not apply to.
+var bulkTransitions = map[string]string{
+	BulkPause:  TransitionPause,
+	BulkResume: TransitionResume,
+}
+
+// BulkUpdateCampaigns applies one operation to every campaign the selector
+// picks, in one transaction, so that either every campaign is changed or none
+// is. Each changed campaign gets a new version and, except when deleted, a
+// history row of its own.
+func (store *SQLStore) BulkUpdateCampaigns(ctx context.Context, arg BulkUpdateCampaignsParams) (BulkUpdateCampaignsResult, error) {
+	selector := arg.Selector
+	if !selector.All && selector.empty() {
+		return BulkUpdateCampaignsResult{}, &Error{Kind: ErrValidation, Message: "the selector matches every campaign; set all to confirm"}
+	}
+
+	var labels []string
+	switch arg.Operation {
+	case BulkPause, BulkResume, BulkDelete:
+	case BulkLabel, BulkUnlabel:
+		var err error
+		labels, err = validateLabels(arg.Labels)
+		if err != nil {
+			return BulkUpdateCampaignsResult{}, err
+		}
+		if len(labels) == 0 {
+			return BulkUpdateCampaignsResult{}, &Error{Kind: ErrValidation, Message: fmt.Sprintf("the %s operation requires labels", arg.Operation)}
+		}
+	default:
+		return BulkUpdateCampaignsResult{}, &Error{Kind: ErrValidation, Message: fmt.Sprintf("unknown operation %q", arg.Operation)}
+	}
+
+	// An empty list filters nothing, as if it were not given.
+	if len(selector.Cids) == 0 {
+		selector.Cids = nil
+	}
+	if len(selector.Labels) == 0 {
+		selector.Labels = nil
+	}
+
+	result := BulkUpdateCampaignsResult{Changed: []string{}}
+	err := store.execTx(ctx, func(q *Queries) error {
+		// The selected rows stay locked until the transaction ends.
+		campaigns, err := q.selectBulkCampaigns(ctx, selectBulkCampaignsParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cids:         selector.Cids,
+			Labels:       selector.Labels,
+			Status:       NullStatusType{StatusType: selector.Status, Valid: selector.Status != ""},
+			Country:      pgtype.Text{String: selector.Country, Valid: selector.Country != ""},
+			App:          pgtype.Text{String: selector.App, Valid: selector.App != ""},
+			Os:           pgtype.Text{String: selector.Os, Valid: selector.Os != ""},
+		})
+		if err != nil {
+			return err
+		}
+		result.Matched = len(campaigns)
+
+		for _, campaign := range campaigns {
+			key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: campaign.Cid}
+			changed := true
+			switch arg.Operation {
+			case BulkPause, BulkResume:
+				status, transitionErr := nextStatus(bulkTransitions[arg.Operation], campaign.Status)
+				if transitionErr != nil {
+					changed = false
+					break
+				}
+				changed, err = store.setStatus(ctx, q, key, campaign.Status, status, arg.Reason)
+			case BulkDelete:
+				err = softDeleteCampaign(ctx, q, key)
+			case BulkLabel:
+				_, changed, err = store.updateLabels(ctx, q, key, labels, nil)
+			case BulkUnlabel:
+				_, changed, err = store.updateLabels(ctx, q, key, nil, labels)
+			}
+			if err != nil {
+				return fmt.Errorf("campaign %s: %w", campaign.Cid, err)
+			}
+			if !changed {
+				continue
+			}
+
+			if arg.Operation != BulkDelete {
+				if err := bumpVersion(ctx, q, key, 0); err != nil {
+					return err
+				}
+			}
+			result.Changed = append(result.Changed, campaign.Cid)
+		}
+		return nil
+	})
+	if err != nil {
+		return BulkUpdateCampaignsResult{}, err
+	}
+	// Pausing, resuming and deleting change which campaigns are delivered,
+	// while labels do not.
+	switch arg.Operation {
+	case BulkPause, BulkResume, BulkDelete:
+		if len(result.Changed) > 0 {
+			store.invalidateCampaigns(ctx)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestUpdateCampaignLabels(t *testing.T) {
+	campaign := addRandomCampaign(t)
+
+	labels, err := testStore.UpdateCampaignLabels(context.Background(), db.UpdateCampaignLabelsParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Add:          []string{" spring-sale ", "brand", "brand"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"brand", "spring-sale"}, labels)
+
+	labels, err = testStore.UpdateCampaignLabels(context.Background(), db.UpdateCampaignLabelsParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Remove:       []string{"brand"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"spring-sale"}, labels)
+
+	_, err = testStore.UpdateCampaignLabels(context.Background(), db.UpdateCampaignLabelsParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Add:          []string{"a,b"},
+	})
+	require.ErrorIs(t, err, db.ErrValidation)
+
+	complete, err := testStore.ReadCampaign(context.Background(), campaign.Key())
+	require.NoError(t, err)
+	require.Equal(t, []string{"spring-sale"}, complete.Labels)
+	require.Equal(t, campaign.Version+2, complete.Version)
+
+	history, err := testStore.ListCampaignHistory(context.Background(), db.ListCampaignHistoryParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "labels", history[0].FieldChanged)
+
+	// Reverting to before the first change drops the labels again.
+	result, err := testStore.RevertCampaign(context.Background(), db.RevertCampaignParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		At:           campaign.CreatedAt,
+	})
+	require.NoError(t, err)
+	require.Empty(t, result.Campaign.Labels)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestBulkUpdateCampaigns(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+	var keys []db.CampaignKey
+	for range 3 {
+		campaign, err := testStore.AddCampaign(context.Background(), db.AddCampaignParams{
+			AdvertiserID: advertiser.ID,
+			Cid:          util.RandomCid(),
+			Name:         util.RandomName(),
+			Img:          util.RandomImg(),
+			Cta:          util.RandomCta(),
+			Status:       db.NullStatusType{StatusType: db.StatusTypeActive, Valid: true},
+		})
+		require.NoError(t, err)
+		keys = append(keys, campaign.Key())
+	}
+
+	_, err := testStore.BulkUpdateCampaigns(context.Background(), db.BulkUpdateCampaignsParams{
+		AdvertiserID: advertiser.ID,
+		Operation:    db.BulkPause,
+	})
+	require.ErrorIs(t, err, db.ErrValidation)
+
+	result, err := testStore.BulkUpdateCampaigns(context.Background(), db.BulkUpdateCampaignsParams{
+		AdvertiserID: advertiser.ID,
+		Selector:     db.CampaignSelector{Cids: []string{keys[0].Cid, keys[1].Cid}},
+		Operation:    db.BulkLabel,
+		Labels:       []string{"incident"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Matched)
+	require.Len(t, result.Changed, 2)
+
+	// Pausing by label pauses every labelled campaign, and pausing again
+	// changes nothing.
+	pause := db.BulkUpdateCampaignsParams{
+		AdvertiserID: advertiser.ID,
+		Selector:     db.CampaignSelector{Labels: []string{"incident"}},
+		Operation:    db.BulkPause,
+	}
+	result, err = testStore.BulkUpdateCampaigns(context.Background(), pause)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Matched)
+	require.Len(t, result.Changed, 2)
+	result, err = testStore.BulkUpdateCampaigns(context.Background(), pause)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Matched)
+	require.Empty(t, result.Changed)
+
+	for i, key := range keys {
+		campaign, err := testStore.GetCampaign(context.Background(), db.GetCampaignParams(key))
+		require.NoError(t, err)
+		if i < 2 {
+			require.Equal(t, db.StatusTypePaused, campaign.Status)
+		} else {
+			require.Equal(t, db.StatusTypeActive, campaign.Status)
+		}
+	}
+
+	history, err := testStore.ListCampaignHistory(context.Background(), db.ListCampaignHistoryParams(keys[0]))
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	result, err = testStore.BulkUpdateCampaigns(context.Background(), db.BulkUpdateCampaignsParams{
+		AdvertiserID: advertiser.ID,
+		Selector:     db.CampaignSelector{All: true},
+		Operation:    db.BulkResume,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Matched)
+	require.Len(t, result.Changed, 2)
+
+	result, err = testStore.BulkUpdateCampaigns(context.Background(), db.BulkUpdateCampaignsParams{
+		AdvertiserID: advertiser.ID,
+		Selector:     db.CampaignSelector{All: true},
+		Operation:    db.BulkDelete,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Changed, 3)
+
+	campaigns, err := testStore.ListCampaigns(context.Background(), advertiser.ID)
+	require.NoError(t, err)
+	require.Empty(t, campaigns)
+
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
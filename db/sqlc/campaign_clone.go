@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// clonedFromHistoryField names the history row a clone starts with. Its new
+// value is the cid of the campaign it was copied from.
+const clonedFromHistoryField = "cloned_from"
+
+// CloneCampaignParams names the campaign to copy and the cid of the copy.
+// Empty overrides keep what the copied campaign has, except for the status,
+// which defaults to draft so that the copy is not delivered before it is
+// launched. A copy is only active when the creative of the copied campaign
+// was approved, and a draft otherwise.
+type CloneCampaignParams struct {
+	AdvertiserID int32      `json:"advertiser_id"`
+	Cid          string     `json:"cid"`
+	NewCid       string     `json:"new_cid"`
+	Name         string     `json:"name"`
+	Country      string     `json:"country"`
+	CountryRule  RuleType   `json:"country_rule"`
+	Status       StatusType `json:"status"`
+}
+
+// CloneCampaign copies a campaign with all of its targeting, radius circles,
+// lists, segments, locales and labels under a new cid, applying the overrides
+// of arg. The copy gets the approved creative of the campaign, not one
+// waiting for review, and a history that starts with where it came from.
+func (store *SQLStore) CloneCampaign(ctx context.Context, arg CloneCampaignParams) (CompleteCampaign, error) {
+	status := arg.Status
+	switch status {
+	case "":
+		status = StatusTypeDraft
+	case StatusTypeDraft, StatusTypeActive:
+	default:
+		return CompleteCampaign{}, &Error{Kind: ErrValidation, Message: fmt.Sprintf("campaigns cannot be cloned %s", status)}
+	}
+	source := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.NewCid}
+
+	var clone CompleteCampaign
+	err := store.execTx(ctx, func(q *Queries) error {
+		campaign, err := readCampaign(ctx, q, source)
+		if err != nil {
+			return err
+		}
+
+		status := status
+		if status == StatusTypeActive {
+			approved, err := creativeApproved(ctx, q, source, campaign.Status)
+			if err != nil {
+				return err
+			}
+			if !approved {
+				status = StatusTypeDraft
+			}
+		}
+		campaign.Cid = arg.NewCid
+		campaign.Status = status
+		if arg.Name != "" {
+			campaign.Name = arg.Name
+		}
+		if arg.Country != "" {
+			campaign.Country = arg.Country
+		}
+		if arg.CountryRule != "" {
+			campaign.CountryRule = arg.CountryRule
+		}
+		if err := validateImport(&campaign); err != nil {
+			return &Error{Kind: ErrValidation, Message: err.Error()}
+		}
+
+		if err := importCampaign(ctx, q, key, campaign); err != nil {
+			return err
+		}
+		err = store.createHistory(ctx, q, []createCampaignHistoryParams{{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			FieldChanged: clonedFromHistoryField,
+			NewValue:     source.Cid,
+		}})
+		if err != nil {
+			return err
+		}
+
+		clone, err = readCampaign(ctx, q, key)
+		return err
+	})
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+
+	store.invalidateImported(ctx, key)
+	if clone.Status == StatusTypeActive {
+		store.invalidateCampaigns(ctx)
+	}
+	return clone, nil
+}
+
+// creativeApproved reports whether the creatives of a campaign in status,
+// including those of its locales, all passed review: the campaign went live
+// and has no revision waiting for review.
+func creativeApproved(ctx context.Context, q *Queries, key CampaignKey, status StatusType) (bool, error) {
+	if status == StatusTypeDraft || status == StatusTypePendingReview {
+		return false, nil
+	}
+	pending, err := q.ListCreativeRevisions(ctx, ListCreativeRevisionsParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          pgtype.Text{String: key.Cid, Valid: true},
+		Status:       NullRevisionStatus{RevisionStatus: RevisionStatusPending, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(pending) == 0, nil
+}
@@ -0,0 +1,78 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestCloneCampaign(t *testing.T) {
+	source := createRandomCampaign(t)
+	key := db.CampaignKey{AdvertiserID: source.AdvertiserID, Cid: source.Cid}
+	_, err := testStore.UpdateCampaignLabels(context.Background(), db.UpdateCampaignLabelsParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Add:          []string{"summer"},
+	})
+	require.NoError(t, err)
+
+	arg := db.CloneCampaignParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		NewCid:       util.RandomCid(),
+		Name:         util.RandomName(),
+		Country:      "us,ca",
+		CountryRule:  db.RuleTypeInclude,
+	}
+	clone, err := testStore.CloneCampaign(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.NewCid, clone.Cid)
+	require.Equal(t, arg.Name, clone.Name)
+	require.Equal(t, source.Img, clone.Img)
+	require.Equal(t, source.Cta, clone.Cta)
+	require.Equal(t, db.StatusTypeDraft, clone.Status)
+	require.Equal(t, "us,ca", clone.Country)
+	require.Equal(t, db.RuleTypeInclude, clone.CountryRule)
+	require.Equal(t, source.AppID, clone.AppID)
+	require.Equal(t, source.AppRule, clone.AppRule)
+	require.Equal(t, source.Os, clone.Os)
+	require.Equal(t, source.OsRule, clone.OsRule)
+	require.Equal(t, []string{"summer"}, clone.Labels)
+
+	history, err := testStore.GetCampaignHistory(context.Background(), db.GetCampaignHistoryParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          clone.Cid,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "cloned_from", history.FieldChanged)
+	require.Equal(t, source.Cid, history.NewValue)
+
+	// The cid of the copy must be free.
+	arg.NewCid = clone.Cid
+	_, err = testStore.CloneCampaign(context.Background(), arg)
+	require.Error(t, err)
+
+	arg.NewCid = util.RandomCid()
+	arg.Status = db.StatusTypeCompleted
+	_, err = testStore.CloneCampaign(context.Background(), arg)
+	require.ErrorIs(t, err, db.ErrValidation)
+
+	// A draft's creative was never reviewed, so its copy stays a draft.
+	arg.Status = db.StatusTypeActive
+	unreviewed, err := testStore.CloneCampaign(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, db.StatusTypeDraft, unreviewed.Status)
+
+	launchCampaign(t, key)
+	arg.NewCid = util.RandomCid()
+	live, err := testStore.CloneCampaign(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, db.StatusTypeActive, live.Status)
+
+	for _, cid := range []string{clone.Cid, unreviewed.Cid, live.Cid, key.Cid} {
+		testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams{AdvertiserID: key.AdvertiserID, Cid: cid})
+	}
+}
@@ -7,18 +7,55 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const getCampaignHistory = `-- name: GetCampaignHistory :one
-SELECT id, cid, field_changed, old_value, new_value, updated_at
+SELECT id, cid, field_changed, old_value, new_value, updated_at, advertiser_id, revert_of, reason
 FROM campaign_history
-WHERE cid = $1
+WHERE advertiser_id = $1 AND cid = $2
 ORDER BY updated_at DESC
 LIMIT 1
 `
 
-func (q *Queries) GetCampaignHistory(ctx context.Context, cid string) (CampaignHistory, error) {
-	row := q.db.QueryRow(ctx, getCampaignHistory, cid)
+type GetCampaignHistoryParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+}
+
+func (q *Queries) GetCampaignHistory(ctx context.Context, arg GetCampaignHistoryParams) (CampaignHistory, error) {
+	row := q.db.QueryRow(ctx, getCampaignHistory, arg.AdvertiserID, arg.Cid)
+	var i CampaignHistory
+	err := row.Scan(
+		&i.ID,
+		&i.Cid,
+		&i.FieldChanged,
+		&i.OldValue,
+		&i.NewValue,
+		&i.UpdatedAt,
+		&i.AdvertiserID,
+		&i.RevertOf,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const getCampaignHistoryEntry = `-- name: GetCampaignHistoryEntry :one
+SELECT id, cid, field_changed, old_value, new_value, updated_at, advertiser_id, revert_of, reason
+FROM campaign_history
+WHERE advertiser_id = $1 AND cid = $2 AND id = $3
+`
+
+type GetCampaignHistoryEntryParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	ID           int32  `json:"id"`
+}
+
+func (q *Queries) GetCampaignHistoryEntry(ctx context.Context, arg GetCampaignHistoryEntryParams) (CampaignHistory, error) {
+	row := q.db.QueryRow(ctx, getCampaignHistoryEntry, arg.AdvertiserID, arg.Cid, arg.ID)
 	var i CampaignHistory
 	err := row.Scan(
 		&i.ID,
@@ -27,20 +64,28 @@ func (q *Queries) GetCampaignHistory(ctx context.Context, cid string) (CampaignH
 		&i.OldValue,
 		&i.NewValue,
 		&i.UpdatedAt,
+		&i.AdvertiserID,
+		&i.RevertOf,
+		&i.Reason,
 	)
 	return i, err
 }
 
 const getLastTwoCampaignHistory = `-- name: GetLastTwoCampaignHistory :many
-SELECT id, cid, field_changed, old_value, new_value, updated_at
+SELECT id, cid, field_changed, old_value, new_value, updated_at, advertiser_id, revert_of, reason
 FROM campaign_history
-WHERE cid = $1
+WHERE advertiser_id = $1 AND cid = $2
 ORDER BY updated_at DESC
 LIMIT 2
 `
 
-func (q *Queries) GetLastTwoCampaignHistory(ctx context.Context, cid string) ([]CampaignHistory, error) {
-	rows, err := q.db.Query(ctx, getLastTwoCampaignHistory, cid)
+type GetLastTwoCampaignHistoryParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+}
+
+func (q *Queries) GetLastTwoCampaignHistory(ctx context.Context, arg GetLastTwoCampaignHistoryParams) ([]CampaignHistory, error) {
+	rows, err := q.db.Query(ctx, getLastTwoCampaignHistory, arg.AdvertiserID, arg.Cid)
 	if err != nil {
 		return nil, err
 	}
@@ -55,6 +100,9 @@ func (q *Queries) GetLastTwoCampaignHistory(ctx context.Context, cid string) ([]
 			&i.OldValue,
 			&i.NewValue,
 			&i.UpdatedAt,
+			&i.AdvertiserID,
+			&i.RevertOf,
+			&i.Reason,
 		); err != nil {
 			return nil, err
 		}
@@ -67,14 +115,169 @@ func (q *Queries) GetLastTwoCampaignHistory(ctx context.Context, cid string) ([]
 }
 
 const listCampaignHistory = `-- name: ListCampaignHistory :many
-SELECT id, cid, field_changed, old_value, new_value, updated_at
+SELECT id, cid, field_changed, old_value, new_value, updated_at, advertiser_id, revert_of, reason
 FROM campaign_history
-WHERE cid = $1
+WHERE advertiser_id = $1 AND cid = $2
 ORDER BY updated_at DESC
 `
 
-func (q *Queries) ListCampaignHistory(ctx context.Context, cid string) ([]CampaignHistory, error) {
-	rows, err := q.db.Query(ctx, listCampaignHistory, cid)
+type ListCampaignHistoryParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+}
+
+func (q *Queries) ListCampaignHistory(ctx context.Context, arg ListCampaignHistoryParams) ([]CampaignHistory, error) {
+	rows, err := q.db.Query(ctx, listCampaignHistory, arg.AdvertiserID, arg.Cid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CampaignHistory{}
+	for rows.Next() {
+		var i CampaignHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.Cid,
+			&i.FieldChanged,
+			&i.OldValue,
+			&i.NewValue,
+			&i.UpdatedAt,
+			&i.AdvertiserID,
+			&i.RevertOf,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignHistoryFrom = `-- name: ListCampaignHistoryFrom :many
+SELECT id, cid, field_changed, old_value, new_value, updated_at, advertiser_id, revert_of, reason
+FROM campaign_history
+WHERE advertiser_id = $1 AND cid = $2 AND id >= $3
+ORDER BY id DESC
+`
+
+type ListCampaignHistoryFromParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	ID           int32  `json:"id"`
+}
+
+func (q *Queries) ListCampaignHistoryFrom(ctx context.Context, arg ListCampaignHistoryFromParams) ([]CampaignHistory, error) {
+	rows, err := q.db.Query(ctx, listCampaignHistoryFrom, arg.AdvertiserID, arg.Cid, arg.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CampaignHistory{}
+	for rows.Next() {
+		var i CampaignHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.Cid,
+			&i.FieldChanged,
+			&i.OldValue,
+			&i.NewValue,
+			&i.UpdatedAt,
+			&i.AdvertiserID,
+			&i.RevertOf,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignHistoryPage = `-- name: ListCampaignHistoryPage :many
+SELECT id, cid, field_changed, old_value, new_value, updated_at, advertiser_id, revert_of, reason
+FROM campaign_history
+WHERE advertiser_id = $1
+  AND cid = $2
+  AND ($3::text[] IS NULL
+    OR field_changed = ANY($3::text[])
+    -- Patches match the fields they changed, the keys of their values.
+    OR CASE WHEN field_changed = 'patch' THEN new_value::jsonb ?| $3::text[] ELSE false END)
+  AND ($4::timestamptz IS NULL OR updated_at >= $4)
+  AND ($5::timestamptz IS NULL OR updated_at < $5)
+  AND ($6::int IS NULL OR id < $6)
+ORDER BY id DESC
+LIMIT $7
+`
+
+type ListCampaignHistoryPageParams struct {
+	AdvertiserID int32              `json:"advertiser_id"`
+	Cid          string             `json:"cid"`
+	Fields       []string           `json:"fields"`
+	Since        pgtype.Timestamptz `json:"since"`
+	Until        pgtype.Timestamptz `json:"until"`
+	BeforeID     pgtype.Int4        `json:"before_id"`
+	RowLimit     int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListCampaignHistoryPage(ctx context.Context, arg ListCampaignHistoryPageParams) ([]CampaignHistory, error) {
+	rows, err := q.db.Query(ctx, listCampaignHistoryPage,
+		arg.AdvertiserID,
+		arg.Cid,
+		arg.Fields,
+		arg.Since,
+		arg.Until,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CampaignHistory{}
+	for rows.Next() {
+		var i CampaignHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.Cid,
+			&i.FieldChanged,
+			&i.OldValue,
+			&i.NewValue,
+			&i.UpdatedAt,
+			&i.AdvertiserID,
+			&i.RevertOf,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCampaignHistorySince = `-- name: ListCampaignHistorySince :many
+SELECT id, cid, field_changed, old_value, new_value, updated_at, advertiser_id, revert_of, reason
+FROM campaign_history
+WHERE advertiser_id = $1 AND cid = $2 AND updated_at > $3
+ORDER BY id DESC
+`
+
+type ListCampaignHistorySinceParams struct {
+	AdvertiserID int32     `json:"advertiser_id"`
+	Cid          string    `json:"cid"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (q *Queries) ListCampaignHistorySince(ctx context.Context, arg ListCampaignHistorySinceParams) ([]CampaignHistory, error) {
+	rows, err := q.db.Query(ctx, listCampaignHistorySince, arg.AdvertiserID, arg.Cid, arg.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +292,9 @@ func (q *Queries) ListCampaignHistory(ctx context.Context, cid string) ([]Campai
 			&i.OldValue,
 			&i.NewValue,
 			&i.UpdatedAt,
+			&i.AdvertiserID,
+			&i.RevertOf,
+			&i.Reason,
 		); err != nil {
 			return nil, err
 		}
@@ -102,28 +308,143 @@ func (q *Queries) ListCampaignHistory(ctx context.Context, cid string) ([]Campai
 
 const createCampaignHistory = `-- name: createCampaignHistory :exec
 INSERT INTO campaign_history (
+    advertiser_id,
     cid,
     field_changed,
     old_value,
-    new_value
+    new_value,
+    reason
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5, $6
 )
 `
 
 type createCampaignHistoryParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
 	Cid          string `json:"cid"`
 	FieldChanged string `json:"field_changed"`
 	OldValue     string `json:"old_value"`
 	NewValue     string `json:"new_value"`
+	Reason       string `json:"reason"`
 }
 
 func (q *Queries) createCampaignHistory(ctx context.Context, arg createCampaignHistoryParams) error {
 	_, err := q.db.Exec(ctx, createCampaignHistory,
+		arg.AdvertiserID,
 		arg.Cid,
 		arg.FieldChanged,
 		arg.OldValue,
 		arg.NewValue,
+		arg.Reason,
 	)
 	return err
 }
+
+const createCampaignRevertHistory = `-- name: createCampaignRevertHistory :exec
+INSERT INTO campaign_history (
+    advertiser_id,
+    cid,
+    field_changed,
+    old_value,
+    new_value,
+    revert_of
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+`
+
+type createCampaignRevertHistoryParams struct {
+	AdvertiserID int32       `json:"advertiser_id"`
+	Cid          string      `json:"cid"`
+	FieldChanged string      `json:"field_changed"`
+	OldValue     string      `json:"old_value"`
+	NewValue     string      `json:"new_value"`
+	RevertOf     pgtype.Int4 `json:"revert_of"`
+}
+
+func (q *Queries) createCampaignRevertHistory(ctx context.Context, arg createCampaignRevertHistoryParams) error {
+	_, err := q.db.Exec(ctx, createCampaignRevertHistory,
+		arg.AdvertiserID,
+		arg.Cid,
+		arg.FieldChanged,
+		arg.OldValue,
+		arg.NewValue,
+		arg.RevertOf,
+	)
+	return err
+}
+
+const getCampaignHistoryByID = `-- name: getCampaignHistoryByID :one
+SELECT id, cid, field_changed, old_value, new_value, updated_at, advertiser_id, revert_of, reason
+FROM campaign_history
+WHERE id = $1
+`
+
+func (q *Queries) getCampaignHistoryByID(ctx context.Context, id int32) (CampaignHistory, error) {
+	row := q.db.QueryRow(ctx, getCampaignHistoryByID, id)
+	var i CampaignHistory
+	err := row.Scan(
+		&i.ID,
+		&i.Cid,
+		&i.FieldChanged,
+		&i.OldValue,
+		&i.NewValue,
+		&i.UpdatedAt,
+		&i.AdvertiserID,
+		&i.RevertOf,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const listCampaignHistoryAfter = `-- name: listCampaignHistoryAfter :many
+SELECT id, cid, field_changed, old_value, new_value, updated_at, advertiser_id, revert_of, reason
+FROM campaign_history
+WHERE advertiser_id = $1
+  AND id > $2
+  AND ($3::text IS NULL OR cid = $3)
+ORDER BY id DESC
+LIMIT $4
+`
+
+type listCampaignHistoryAfterParams struct {
+	AdvertiserID int32       `json:"advertiser_id"`
+	AfterID      int32       `json:"after_id"`
+	Cid          pgtype.Text `json:"cid"`
+	RowLimit     int32       `json:"row_limit"`
+}
+
+func (q *Queries) listCampaignHistoryAfter(ctx context.Context, arg listCampaignHistoryAfterParams) ([]CampaignHistory, error) {
+	rows, err := q.db.Query(ctx, listCampaignHistoryAfter,
+		arg.AdvertiserID,
+		arg.AfterID,
+		arg.Cid,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CampaignHistory{}
+	for rows.Next() {
+		var i CampaignHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.Cid,
+			&i.FieldChanged,
+			&i.OldValue,
+			&i.NewValue,
+			&i.UpdatedAt,
+			&i.AdvertiserID,
+			&i.RevertOf,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
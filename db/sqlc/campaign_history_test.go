@@ -3,20 +3,276 @@ package db_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
 )
 
 func TestListCampaignHistory(t *testing.T) {
 	campaign := addRandomCampaign(t)
 
 	for range 10 {
-		testStore.ToggleStatus(context.Background(), campaign.Cid)
+		testStore.ToggleStatus(context.Background(), campaign.Key(), 0)
 	}
 
-	history, err := testStore.ListCampaignHistory(context.Background(), campaign.Cid)
+	history, err := testStore.ListCampaignHistory(context.Background(), db.ListCampaignHistoryParams(campaign.Key()))
 	require.NoError(t, err)
 	require.Equal(t, len(history), 10)
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestListCampaignHistoryPage(t *testing.T) {
+	campaign := addRandomCampaign(t)
+
+	for range 5 {
+		testStore.ToggleStatus(context.Background(), campaign.Key(), 0)
+		_, err := testStore.UpdateCampaignName(context.Background(), db.UpdateCampaignNameParams{
+			AdvertiserID: campaign.AdvertiserID,
+			Cid:          campaign.Cid,
+			Name:         util.RandomName(),
+		})
+		require.NoError(t, err)
+	}
+
+	page, err := testStore.ListCampaignHistoryPage(context.Background(), db.ListCampaignHistoryPageParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Fields:       []string{"name"},
+		RowLimit:     3,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 3)
+	for _, entry := range page {
+		require.Equal(t, "name", entry.FieldChanged)
+	}
+
+	next, err := testStore.ListCampaignHistoryPage(context.Background(), db.ListCampaignHistoryPageParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Fields:       []string{"name"},
+		BeforeID:     pgtype.Int4{Int32: page[2].ID, Valid: true},
+		RowLimit:     3,
+	})
+	require.NoError(t, err)
+	require.Len(t, next, 2)
+	require.Less(t, next[0].ID, page[2].ID)
+
+	all, err := testStore.ListCampaignHistoryPage(context.Background(), db.ListCampaignHistoryPageParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		RowLimit:     100,
+	})
+	require.NoError(t, err)
+	require.Len(t, all, 10)
+
+	// Patches match the fields they changed.
+	_, err = patchCampaign(campaign.Key(), `{"name": "`+util.RandomName()+`", "os": "ios", "os_rule": "include"}`, 0)
+	require.NoError(t, err)
+	page, err = testStore.ListCampaignHistoryPage(context.Background(), db.ListCampaignHistoryPageParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Fields:       []string{"name"},
+		RowLimit:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, "patch", page[0].FieldChanged)
+	page, err = testStore.ListCampaignHistoryPage(context.Background(), db.ListCampaignHistoryPageParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Fields:       []string{"status"},
+		RowLimit:     1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "status", page[0].FieldChanged)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestReadCampaignAsOf(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	_, err := testStore.AddTargetCountry(context.Background(), db.AddTargetCountryParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Country:      "in,us",
+		Rule:         db.RuleType("include"),
+	})
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	original, err := testStore.ReadCampaign(context.Background(), campaign.Key())
+	require.NoError(t, err)
+	asOf := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	testStore.ToggleStatus(context.Background(), campaign.Key(), 0)
+	_, err = testStore.UpdateCampaignCta(context.Background(), db.UpdateCampaignCtaParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Cta:          util.RandomCta(),
+	})
+	require.NoError(t, err)
+	_, err = testStore.UpdateTargetCountry(context.Background(), db.UpdateTargetCountryParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Country:      "gb",
+		Rule:         db.RuleType("exclude"),
+	})
+	require.NoError(t, err)
+	// Targeting and circles added or removed since are rebuilt from the
+	// audit log.
+	_, err = testStore.AddTargetOs(context.Background(), db.AddTargetOsParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Os:           "android",
+		Rule:         db.RuleType("include"),
+	})
+	require.NoError(t, err)
+	_, err = testStore.AddTargetRadius(context.Background(), db.AddTargetRadiusParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Lat:          12.97,
+		Lon:          77.59,
+		RadiusKm:     5,
+		Rule:         db.RuleType("include"),
+	})
+	require.NoError(t, err)
+
+	current, err := testStore.ReadCampaign(context.Background(), campaign.Key())
+	require.NoError(t, err)
+	require.NotEqual(t, original, current)
+	// Past states have no version, nor a creative waiting for review.
+	original.Version, current.Version = 0, 0
+	current.PendingCreative = nil
+
+	reconstructed, err := testStore.ReadCampaignAsOf(context.Background(), campaign.Key(), asOf)
+	require.NoError(t, err)
+	require.Equal(t, original, reconstructed)
+
+	reconstructed, err = testStore.ReadCampaignAsOf(context.Background(), campaign.Key(), time.Now())
+	require.NoError(t, err)
+	require.Equal(t, current, reconstructed)
+
+	// A deleted targeting is shown again before its deletion.
+	asOf = time.Now()
+	time.Sleep(10 * time.Millisecond)
+	err = testStore.DeleteTargetCountry(context.Background(), db.DeleteTargetCountryParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+	})
+	require.NoError(t, err)
+	reconstructed, err = testStore.ReadCampaignAsOf(context.Background(), campaign.Key(), asOf)
+	require.NoError(t, err)
+	require.Equal(t, current, reconstructed)
+
+	_, err = testStore.ReadCampaignAsOf(context.Background(), campaign.Key(), campaign.CreatedAt.Add(-time.Hour))
+	require.ErrorIs(t, err, db.ErrCampaignNotCreated)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestRevertCampaign(t *testing.T) {
+	campaign := addRandomCampaign(t)
+
+	for range 2 {
+		_, err := testStore.UpdateCampaignName(context.Background(), db.UpdateCampaignNameParams{
+			AdvertiserID: campaign.AdvertiserID,
+			Cid:          campaign.Cid,
+			Name:         util.RandomName(),
+		})
+		require.NoError(t, err)
+	}
+	testStore.ToggleStatus(context.Background(), campaign.Key(), 0)
+
+	history, err := testStore.ListCampaignHistory(context.Background(), db.ListCampaignHistoryParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	first := history[2]
+	require.Equal(t, "name", first.FieldChanged)
+
+	arg := db.RevertCampaignParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		HistoryID:    first.ID,
+		DryRun:       true,
+	}
+	preview, err := testStore.RevertCampaign(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, preview.Applied)
+	require.ElementsMatch(t, []db.FieldChange{
+		{Field: "name", From: history[1].NewValue, To: campaign.Name},
+		{Field: "status", From: string(db.StatusTypePaused), To: string(db.StatusTypeActive)},
+	}, preview.Changes)
+	require.Equal(t, campaign.Name, preview.Campaign.Name)
+
+	unchanged, err := testStore.GetCampaign(context.Background(), db.GetCampaignParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, history[1].NewValue, unchanged.Name)
+
+	arg.DryRun = false
+	result, err := testStore.RevertCampaign(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.Applied)
+	require.Equal(t, preview.Changes, result.Changes)
+	require.Equal(t, preview.Campaign.Version+1, result.Campaign.Version)
+	preview.Campaign.Version = result.Campaign.Version
+	require.Equal(t, preview.Campaign, result.Campaign)
+
+	reverted, err := testStore.GetCampaign(context.Background(), db.GetCampaignParams(campaign.Key()))
+	require.NoError(t, err)
+	campaign.Version = result.Campaign.Version
+	require.Equal(t, campaign, reverted)
+
+	history, err = testStore.ListCampaignHistory(context.Background(), db.ListCampaignHistoryParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+	for _, entry := range history[:2] {
+		require.Equal(t, first.ID, entry.RevertOf.Int32)
+	}
+
+	arg.HistoryID = 0
+	arg.At = time.Now().Add(time.Hour)
+	result, err = testStore.RevertCampaign(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, result.Changes)
+	require.False(t, result.Applied)
+
+	arg.HistoryID = history[0].ID + 1000
+	_, err = testStore.RevertCampaign(context.Background(), arg)
+	require.ErrorIs(t, err, db.ErrHistoryNotFound)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestRevertCampaignReview(t *testing.T) {
+	campaign := addRandomDraftCampaign(t)
+	_, err := testStore.TransitionCampaign(context.Background(), db.TransitionCampaignParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Transition:   db.TransitionSubmit,
+	})
+	require.NoError(t, err)
+
+	history, err := testStore.ListCampaignHistory(context.Background(), db.ListCampaignHistoryParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, "status", history[0].FieldChanged)
+
+	// Going back to a draft would reject the campaign, which only its
+	// reviewers may do.
+	_, err = testStore.RevertCampaign(context.Background(), db.RevertCampaignParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		HistoryID:    history[0].ID,
+	})
+	require.ErrorIs(t, err, db.ErrRevertConflict)
+
+	unchanged, err := testStore.GetCampaign(context.Background(), db.GetCampaignParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, db.StatusTypePendingReview, unchanged.Status)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
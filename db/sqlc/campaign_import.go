@@ -0,0 +1,273 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+// errImportFailed rolls back an all-or-nothing import with failed rows.
+var errImportFailed = errors.New("import failed")
+
+// ImportCampaignRow is a campaign to import, numbered by its position in the
+// imported file. The advertiser, creation time, version, status and pending
+// creative of the campaign are ignored; imported campaigns start as drafts.
+type ImportCampaignRow struct {
+	Row      int              `json:"row"`
+	Campaign CompleteCampaign `json:"campaign"`
+}
+
+// ImportRowError explains why a row was not imported.
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Cid   string `json:"cid,omitempty"`
+	Error string `json:"error"`
+}
+
+type ImportCampaignsParams struct {
+	AdvertiserID int32               `json:"advertiser_id"`
+	Rows         []ImportCampaignRow `json:"rows"`
+	// AllOrNothing imports no row at all when any row fails.
+	AllOrNothing bool `json:"all_or_nothing"`
+}
+
+type ImportCampaignsResult struct {
+	Imported int              `json:"imported"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// validateImport applies the checks of the v1 endpoints to a campaign to be
+// imported, including its radius circles, lists, segments, locales and
+// labels. It normalizes locale tags and labels.
+func validateImport(campaign *CompleteCampaign) error {
+	if campaign.Cid == "" {
+		return errors.New("cid is required")
+	}
+	campaign.clearUnusedRules()
+	if err := validateCampaign(*campaign, CompleteCampaign{}); err != nil {
+		return err
+	}
+
+	validRule := func(rule RuleType) bool {
+		return rule == RuleTypeInclude || rule == RuleTypeExclude
+	}
+	for _, radius := range campaign.Radius {
+		switch {
+		case !validRule(radius.Rule):
+			return fmt.Errorf("invalid radius rule %q", radius.Rule)
+		case radius.Lat < -90 || radius.Lat > 90 || radius.Lon < -180 || radius.Lon > 180:
+			return fmt.Errorf("invalid radius center %v,%v", radius.Lat, radius.Lon)
+		case radius.RadiusKm <= 0:
+			return errors.New("radius_km must be positive")
+		}
+	}
+	for _, list := range campaign.TargetLists {
+		if !validRule(list.Rule) {
+			return fmt.Errorf("invalid rule %q for list %d", list.Rule, list.ListID)
+		}
+	}
+	for _, segment := range campaign.Segments {
+		if !validRule(segment.Rule) {
+			return fmt.Errorf("invalid rule %q for segment %d", segment.Rule, segment.SegmentID)
+		}
+	}
+	for i, locale := range campaign.Locales {
+		tag, err := util.NormalizeLocale(locale.Locale)
+		if err != nil {
+			return err
+		}
+		if locale.Img == "" && locale.Cta == "" {
+			return fmt.Errorf("locale %s requires an img or a cta", tag)
+		}
+		campaign.Locales[i].Locale = tag
+	}
+
+	labels, err := validateLabels(campaign.Labels)
+	if err != nil {
+		return err
+	}
+	campaign.Labels = labels
+	return nil
+}
+
+// importCampaign adds a campaign with all of its targeting.
+func importCampaign(ctx context.Context, q *Queries, key CampaignKey, campaign CompleteCampaign) error {
+	created, err := q.AddCampaign(ctx, AddCampaignParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Name:         campaign.Name,
+		Img:          campaign.Img,
+		Cta:          campaign.Cta,
+		Status:       NullStatusType{StatusType: campaign.Status, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	from := CompleteCampaign{Name: created.Name, Img: created.Img, Cta: created.Cta, Status: created.Status}
+	to := campaign
+	to.Locales = nil
+	if err := applyCampaignChanges(ctx, q, key, from, to); err != nil {
+		return err
+	}
+
+	for _, radius := range campaign.Radius {
+		_, err := q.addTargetRadius(ctx, addTargetRadiusParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			Lat:          radius.Lat,
+			Lon:          radius.Lon,
+			RadiusKm:     radius.RadiusKm,
+			Rule:         radius.Rule,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, list := range campaign.TargetLists {
+		_, err := q.addCampaignTargetList(ctx, addCampaignTargetListParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			ListID:       list.ListID,
+			Rule:         list.Rule,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, segment := range campaign.Segments {
+		_, err := q.addCampaignSegment(ctx, addCampaignSegmentParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			SegmentID:    segment.SegmentID,
+			Rule:         segment.Rule,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, locale := range campaign.Locales {
+		_, err := q.addCampaignLocale(ctx, addCampaignLocaleParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			Locale:       locale.Locale,
+			Img:          locale.Img,
+			Cta:          locale.Cta,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportCampaigns adds campaigns with their targeting in one transaction.
+// Rows that are invalid or clash with existing data are reported and skipped,
+// each behind a savepoint so that the rows around it still apply; with
+// AllOrNothing, any such row rolls the whole import back. Other errors abort
+// the import.
+func (store *SQLStore) ImportCampaigns(ctx context.Context, arg ImportCampaignsParams) (ImportCampaignsResult, error) {
+	result := ImportCampaignsResult{Errors: []ImportRowError{}}
+	var imported []CompleteCampaign
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, row := range arg.Rows {
+			campaign := cloneCampaign(row.Campaign)
+			// Imported campaigns go live only once approved.
+			campaign.Status = StatusTypeDraft
+			rowError := ImportRowError{Row: row.Row, Cid: campaign.Cid}
+			if err := validateImport(&campaign); err != nil {
+				rowError.Error = err.Error()
+				result.Errors = append(result.Errors, rowError)
+				continue
+			}
+
+			if _, err := q.db.Exec(ctx, "SAVEPOINT import_row"); err != nil {
+				return err
+			}
+			key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: campaign.Cid}
+			err := TranslateError(importCampaign(ctx, q, key, campaign))
+			var domainErr *Error
+			if errors.As(err, &domainErr) {
+				if _, err := q.db.Exec(ctx, "ROLLBACK TO SAVEPOINT import_row"); err != nil {
+					return err
+				}
+				rowError.Error = err.Error()
+				result.Errors = append(result.Errors, rowError)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := q.db.Exec(ctx, "RELEASE SAVEPOINT import_row"); err != nil {
+				return err
+			}
+			if err := createCreatedHistory(ctx, q, key, campaign.Status); err != nil {
+				return err
+			}
+			imported = append(imported, campaign)
+		}
+
+		if arg.AllOrNothing && len(result.Errors) > 0 {
+			return errImportFailed
+		}
+		return nil
+	})
+	if errors.Is(err, errImportFailed) {
+		return result, nil
+	}
+	if err != nil {
+		return ImportCampaignsResult{}, err
+	}
+
+	for _, campaign := range imported {
+		store.invalidateImported(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: campaign.Cid})
+	}
+	result.Imported = len(imported)
+	return result, nil
+}
+
+// invalidateImported drops everything cached for the cid of a campaign added
+// by importCampaign, such as the absence of targeting cached for an earlier
+// campaign with the same cid.
+func (store *SQLStore) invalidateImported(ctx context.Context, key CampaignKey) {
+	store.invalidateRadius(ctx, key)
+	cacheKeys := []string{
+		fmt.Sprintf("campaign_target_list:%s", key),
+		fmt.Sprintf("campaign_segment:%s", key),
+		fmt.Sprintf("campaign_locale:%s", key),
+	}
+	for entity := range targetingEntities {
+		cacheKeys = append(cacheKeys, fmt.Sprintf("%s:%s", entity, key))
+	}
+	if err := store.rClient.Del(ctx, cacheKeys...).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", key, err)
+	}
+}
+
+// ExportCampaigns reads every campaign of an advertiser in cid order, passing
+// each to fn as soon as it is read. It stops at the first error fn returns.
+func (store *SQLStore) ExportCampaigns(ctx context.Context, advertiserID int32, fn func(CompleteCampaign) error) error {
+	campaigns, err := store.ListCampaigns(ctx, advertiserID)
+	if err != nil {
+		return err
+	}
+
+	for _, campaign := range campaigns {
+		complete, err := readCampaign(ctx, store.Queries, CampaignKey{AdvertiserID: advertiserID, Cid: campaign.Cid})
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Deleted since it was listed.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(complete); err != nil {
+			return err
+		}
+	}
+	return nil
+}
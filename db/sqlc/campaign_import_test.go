@@ -0,0 +1,102 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func randomImportCampaign() db.CompleteCampaign {
+	return db.CompleteCampaign{
+		Cid:         util.RandomCid(),
+		Name:        util.RandomName(),
+		Img:         util.RandomImg(),
+		Cta:         util.RandomCta(),
+		Country:     "us",
+		CountryRule: db.RuleTypeInclude,
+		Radius:      []db.TargetRadius{{Lat: 12.97, Lon: 77.59, RadiusKm: 5, Rule: db.RuleTypeInclude}},
+		Locales:     []db.CampaignLocale{{Locale: "pt-br", Cta: util.RandomCta()}},
+		Status:      db.StatusTypePaused,
+	}
+}
+
+func TestImportCampaigns(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+	valid := randomImportCampaign()
+	invalid := randomImportCampaign()
+	invalid.Name = "short"
+
+	result, err := testStore.ImportCampaigns(context.Background(), db.ImportCampaignsParams{
+		AdvertiserID: advertiser.ID,
+		Rows: []db.ImportCampaignRow{
+			{Row: 1, Campaign: valid},
+			{Row: 2, Campaign: invalid},
+			{Row: 3, Campaign: valid},
+		},
+		AllOrNothing: true,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Imported)
+	require.Len(t, result.Errors, 2)
+	require.Equal(t, 2, result.Errors[0].Row)
+	require.Equal(t, 3, result.Errors[1].Row)
+	require.Equal(t, "campaign already exists", result.Errors[1].Error)
+
+	_, err = testStore.GetCampaign(context.Background(), db.GetCampaignParams{AdvertiserID: advertiser.ID, Cid: valid.Cid})
+	require.Error(t, err)
+
+	// Without AllOrNothing the rows around the failed ones still apply.
+	result, err = testStore.ImportCampaigns(context.Background(), db.ImportCampaignsParams{
+		AdvertiserID: advertiser.ID,
+		Rows: []db.ImportCampaignRow{
+			{Row: 1, Campaign: valid},
+			{Row: 2, Campaign: invalid},
+			{Row: 3, Campaign: valid},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Imported)
+	require.Len(t, result.Errors, 2)
+
+	key := db.CampaignKey{AdvertiserID: advertiser.ID, Cid: valid.Cid}
+	campaign, err := testStore.ReadCampaign(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, valid.Name, campaign.Name)
+	require.Equal(t, valid.Country, campaign.Country)
+	// Imported campaigns start as drafts whatever their status.
+	require.Equal(t, db.StatusTypeDraft, campaign.Status)
+	require.Len(t, campaign.Radius, 1)
+	require.Len(t, campaign.Locales, 1)
+	require.Equal(t, "pt-BR", campaign.Locales[0].Locale)
+
+	var exported []db.CompleteCampaign
+	err = testStore.ExportCampaigns(context.Background(), advertiser.ID, func(campaign db.CompleteCampaign) error {
+		exported = append(exported, campaign)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, exported, 1)
+	require.Equal(t, campaign, exported[0])
+
+	// An exported campaign imports back as it was.
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(key))
+	result, err = testStore.ImportCampaigns(context.Background(), db.ImportCampaignsParams{
+		AdvertiserID: advertiser.ID,
+		Rows:         []db.ImportCampaignRow{{Row: 1, Campaign: exported[0]}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Imported)
+	reimported, err := testStore.ReadCampaign(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, campaign.Name, reimported.Name)
+	require.Equal(t, campaign.CountryRule, reimported.CountryRule)
+	require.Equal(t, campaign.Status, reimported.Status)
+	require.Equal(t, campaign.Locales, reimported.Locales)
+	require.Equal(t, campaign.Radius[0].Lat, reimported.Radius[0].Lat)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(key))
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
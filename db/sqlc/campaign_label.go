@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxLabelLength bounds a label, which is free-form otherwise.
+const maxLabelLength = 64
+
+// validateLabels trims labels and checks them, returning them sorted and
+// without duplicates. Labels are recorded in history joined by commas, so
+// they may not contain one.
+func validateLabels(labels []string) ([]string, error) {
+	valid := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		switch {
+		case label == "":
+			return nil, &Error{Kind: ErrValidation, Message: "labels may not be empty"}
+		case len(label) > maxLabelLength:
+			return nil, &Error{Kind: ErrValidation, Message: fmt.Sprintf("label %q is longer than %d characters", label, maxLabelLength)}
+		case strings.Contains(label, ","):
+			return nil, &Error{Kind: ErrValidation, Message: fmt.Sprintf("label %q may not contain a comma", label)}
+		}
+		valid = append(valid, label)
+	}
+	slices.Sort(valid)
+	return slices.Compact(valid), nil
+}
+
+// joinLabels is the form of labels in campaign_history.
+func joinLabels(labels []string) string {
+	return strings.Join(slices.Sorted(slices.Values(labels)), ",")
+}
+
+// splitLabels is the inverse of joinLabels.
+func splitLabels(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+// applyLabelChanges adds the labels of to missing from from and deletes the
+// labels of from missing from to.
+func applyLabelChanges(ctx context.Context, q *Queries, key CampaignKey, from, to []string) error {
+	for _, label := range to {
+		if slices.Contains(from, label) {
+			continue
+		}
+		err := q.addCampaignLabel(ctx, addCampaignLabelParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Label: label})
+		if err != nil {
+			return err
+		}
+	}
+	for _, label := range from {
+		if slices.Contains(to, label) {
+			continue
+		}
+		err := q.deleteCampaignLabel(ctx, deleteCampaignLabelParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Label: label})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relabel returns labels with add added and remove removed, sorted.
+func relabel(labels []string, add []string, remove []string) []string {
+	result := slices.Clone(labels)
+	for _, label := range add {
+		if !slices.Contains(result, label) {
+			result = append(result, label)
+		}
+	}
+	result = slices.DeleteFunc(result, func(label string) bool {
+		return slices.Contains(remove, label)
+	})
+	slices.Sort(result)
+	return result
+}
+
+// updateLabels applies add and remove to the labels of a campaign, recording
+// the change in its history. It reports whether the labels changed.
+func (store *SQLStore) updateLabels(ctx context.Context, q *Queries, key CampaignKey, add []string, remove []string) ([]string, bool, error) {
+	current, err := q.ListCampaignLabels(ctx, ListCampaignLabelsParams(key))
+	if err != nil {
+		return nil, false, err
+	}
+
+	labels := relabel(current, add, remove)
+	if joinLabels(current) == joinLabels(labels) {
+		return labels, false, nil
+	}
+	if err := applyLabelChanges(ctx, q, key, current, labels); err != nil {
+		return nil, false, err
+	}
+
+	err = store.createHistory(ctx, q, []createCampaignHistoryParams{
+		{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			FieldChanged: "labels",
+			OldValue:     joinLabels(current),
+			NewValue:     joinLabels(labels),
+		},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return labels, true, nil
+}
+
+type UpdateCampaignLabelsParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Add          []string `json:"add"`
+	Remove       []string `json:"remove"`
+	Version      int32    `json:"version"`
+}
+
+// UpdateCampaignLabels adds and removes labels of a campaign, returning its
+// labels afterwards. Adding a label the campaign has, or removing one it does
+// not have, changes nothing.
+func (store *SQLStore) UpdateCampaignLabels(ctx context.Context, arg UpdateCampaignLabelsParams) ([]string, error) {
+	add, err := validateLabels(arg.Add)
+	if err != nil {
+		return nil, err
+	}
+	remove := make([]string, len(arg.Remove))
+	for i, label := range arg.Remove {
+		remove[i] = strings.TrimSpace(label)
+	}
+
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	var labels []string
+	err = store.execTx(ctx, func(q *Queries) error {
+		if err := bumpVersion(ctx, q, key, arg.Version); err != nil {
+			return err
+		}
+		labels, _, err = store.updateLabels(ctx, q, key, add, remove)
+		return err
+	})
+	return labels, err
+}
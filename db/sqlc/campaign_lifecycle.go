@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ErrInvalidTransition is returned when a campaign cannot move from its
+// status to the one asked for.
+var ErrInvalidTransition = &Error{Kind: ErrConflict, Message: "invalid status transition"}
+
+// Transitions of the campaign lifecycle. A campaign is drafted, submitted for
+// review and approved, or rejected back to a draft. Once active it can be
+// paused and resumed, and completed. Campaigns that are no longer needed are
+// archived, for good.
+const (
+	TransitionSubmit   = "submit"
+	TransitionApprove  = "approve"
+	TransitionReject   = "reject"
+	TransitionPause    = "pause"
+	TransitionResume   = "resume"
+	TransitionComplete = "complete"
+	TransitionArchive  = "archive"
+)
+
+type transition struct {
+	name string
+	from []StatusType
+	to   StatusType
+}
+
+var transitions = []transition{
+	{TransitionSubmit, []StatusType{StatusTypeDraft}, StatusTypePendingReview},
+	{TransitionApprove, []StatusType{StatusTypePendingReview}, StatusTypeActive},
+	{TransitionReject, []StatusType{StatusTypePendingReview}, StatusTypeDraft},
+	{TransitionPause, []StatusType{StatusTypeActive}, StatusTypePaused},
+	{TransitionResume, []StatusType{StatusTypePaused}, StatusTypeActive},
+	{TransitionComplete, []StatusType{StatusTypeActive, StatusTypePaused}, StatusTypeCompleted},
+	{TransitionArchive, []StatusType{StatusTypeDraft, StatusTypePaused, StatusTypeCompleted}, StatusTypeArchived},
+}
+
+// validStatus reports whether status is a state of the lifecycle.
+func validStatus(status StatusType) bool {
+	switch status {
+	case StatusTypeDraft, StatusTypePendingReview, StatusTypeActive,
+		StatusTypePaused, StatusTypeCompleted, StatusTypeArchived:
+		return true
+	}
+	return false
+}
+
+// findTransition returns the name of the transition moving a campaign from
+// one status to the other, if there is one.
+func findTransition(from StatusType, to StatusType) (string, bool) {
+	for _, t := range transitions {
+		if t.to == to && slices.Contains(t.from, from) {
+			return t.name, true
+		}
+	}
+	return "", false
+}
+
+// reviewTransitions can only be made through their own endpoints, which are
+// limited to admins.
+var reviewTransitions = []string{TransitionApprove, TransitionReject}
+
+// nextStatus returns the status the named transition moves a campaign to
+// from status.
+func nextStatus(name string, status StatusType) (StatusType, error) {
+	for _, t := range transitions {
+		if t.name != name {
+			continue
+		}
+		if !slices.Contains(t.from, status) {
+			return "", fmt.Errorf("%w: cannot %s a %s campaign", ErrInvalidTransition, name, status)
+		}
+		return t.to, nil
+	}
+	return "", &Error{Kind: ErrValidation, Message: fmt.Sprintf("unknown transition %q", name)}
+}
+
+// setStatus sets the status of a campaign, recording the change and its
+// reason in the history. It reports whether the status changed. The caller
+// checks that the transition is allowed.
+func (store *SQLStore) setStatus(ctx context.Context, q *Queries, key CampaignKey, from StatusType, to StatusType, reason string) (bool, error) {
+	if from == to {
+		return false, nil
+	}
+	status, err := q.setCampaignStatus(ctx, setCampaignStatusParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Status: to})
+	if err != nil {
+		return false, err
+	}
+	err = store.createHistory(ctx, q, []createCampaignHistoryParams{
+		{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			FieldChanged: "status",
+			OldValue:     string(from),
+			NewValue:     string(status),
+			Reason:       reason,
+		},
+	})
+	return err == nil, err
+}
+
+type TransitionCampaignParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Transition   string `json:"transition"`
+	Reason       string `json:"reason"`
+	Version      int32  `json:"version"`
+}
+
+// TransitionCampaign moves a campaign through the named transition of its
+// lifecycle. The campaign row is locked while its status is checked, so of
+// two concurrent transitions from the same status only the first applies and
+// the second fails with ErrInvalidTransition.
+func (store *SQLStore) TransitionCampaign(ctx context.Context, arg TransitionCampaignParams) (Campaign, error) {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+
+	var campaign Campaign
+	var changed bool
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := bumpVersion(ctx, q, key, arg.Version); err != nil {
+			return err
+		}
+
+		current, err := q.GetCampaign(ctx, GetCampaignParams(key))
+		if err != nil {
+			return err
+		}
+
+		status, err := nextStatus(arg.Transition, current.Status)
+		if err != nil {
+			return err
+		}
+		changed, err = store.setStatus(ctx, q, key, current.Status, status, arg.Reason)
+		if err != nil {
+			return err
+		}
+
+		campaign, err = q.GetCampaign(ctx, GetCampaignParams(key))
+		return err
+	})
+	if err == nil && changed {
+		store.invalidateCampaigns(ctx)
+	}
+	return campaign, err
+}
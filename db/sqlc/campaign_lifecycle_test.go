@@ -0,0 +1,76 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestTransitionCampaign(t *testing.T) {
+	created, err := testStore.CreateCampaign(context.Background(), db.CreateCampaignParams{
+		AdvertiserID: testAdvertiser.ID,
+		Cid:          util.RandomCid(),
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+		Status:       db.StatusTypeDraft,
+	})
+	require.NoError(t, err)
+	require.Equal(t, db.StatusTypeDraft, created.Status)
+	key := created.Key()
+
+	transition := func(name string, reason string) (db.Campaign, error) {
+		return testStore.TransitionCampaign(context.Background(), db.TransitionCampaignParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			Transition:   name,
+			Reason:       reason,
+		})
+	}
+
+	_, err = transition(db.TransitionPause, "")
+	require.ErrorIs(t, err, db.ErrInvalidTransition)
+	require.ErrorIs(t, err, db.ErrConflict)
+
+	campaign, err := transition(db.TransitionSubmit, "")
+	require.NoError(t, err)
+	require.Equal(t, db.StatusTypePendingReview, campaign.Status)
+
+	// Approval cannot be patched in.
+	_, err = patchCampaign(key, `{"status": "active"}`, 0)
+	require.ErrorIs(t, err, db.ErrInvalidTransition)
+
+	campaign, err = transition(db.TransitionApprove, "looks good")
+	require.NoError(t, err)
+	require.Equal(t, db.StatusTypeActive, campaign.Status)
+
+	history, err := testStore.GetCampaignHistory(context.Background(), db.GetCampaignHistoryParams(key))
+	require.NoError(t, err)
+	require.Equal(t, "status", history.FieldChanged)
+	require.Equal(t, string(db.StatusTypePendingReview), history.OldValue)
+	require.Equal(t, string(db.StatusTypeActive), history.NewValue)
+	require.Equal(t, "looks good", history.Reason)
+
+	_, err = transition(db.TransitionArchive, "")
+	require.ErrorIs(t, err, db.ErrInvalidTransition)
+
+	campaign, err = transition(db.TransitionComplete, "")
+	require.NoError(t, err)
+	require.Equal(t, db.StatusTypeCompleted, campaign.Status)
+
+	// Completed campaigns are no longer toggled back on.
+	err = testStore.ToggleStatus(context.Background(), key, 0)
+	require.ErrorIs(t, err, db.ErrInvalidTransition)
+
+	campaign, err = transition(db.TransitionArchive, "")
+	require.NoError(t, err)
+	require.Equal(t, db.StatusTypeArchived, campaign.Status)
+
+	_, err = transition("launch", "")
+	require.ErrorIs(t, err, db.ErrValidation)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(key))
+}
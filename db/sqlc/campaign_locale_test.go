@@ -0,0 +1,88 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomCampaignLocale(t *testing.T, key db.CampaignKey, locale string) db.CampaignLocale {
+	arg := db.AddCampaignLocaleParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Locale:       locale,
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+	}
+
+	result, err := testStore.AddCampaignLocale(context.Background(), arg)
+	require.NoError(t, err)
+	require.Nil(t, result.Revision)
+	campaign_locale := result.Locale
+	require.Equal(t, arg.Cid, campaign_locale.Cid)
+	require.Equal(t, arg.Locale, campaign_locale.Locale)
+	require.Equal(t, arg.Img, campaign_locale.Img)
+	require.Equal(t, arg.Cta, campaign_locale.Cta)
+
+	return campaign_locale
+}
+
+func TestAddCampaignLocale(t *testing.T) {
+	campaign := addRandomDraftCampaign(t)
+	addRandomCampaignLocale(t, campaign.Key(), "pt-BR")
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestGetCampaignLocale(t *testing.T) {
+	campaign := addRandomDraftCampaign(t)
+	campaign_locale := addRandomCampaignLocale(t, campaign.Key(), "pt-BR")
+
+	get_campaign_locale, err := testStore.GetCampaignLocale(context.Background(), db.GetCampaignLocaleParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Locale:       "pt-BR",
+	})
+	require.NoError(t, err)
+	require.Equal(t, campaign_locale, get_campaign_locale)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestListCampaignLocales(t *testing.T) {
+	campaign := addRandomDraftCampaign(t)
+	pt := addRandomCampaignLocale(t, campaign.Key(), "pt")
+	ptBR := addRandomCampaignLocale(t, campaign.Key(), "pt-BR")
+
+	campaign_locales, err := testStore.ListCampaignLocales(context.Background(), db.ListCampaignLocalesParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, []db.CampaignLocale{pt, ptBR}, campaign_locales)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestDeleteCampaignLocale(t *testing.T) {
+	campaign := addRandomDraftCampaign(t)
+	addRandomCampaignLocale(t, campaign.Key(), "pt-BR")
+
+	err := testStore.DeleteCampaignLocale(context.Background(), db.DeleteCampaignLocaleParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Locale:       "pt-BR",
+	})
+	require.NoError(t, err)
+
+	campaign_locale, err := testStore.GetCampaignLocale(context.Background(), db.GetCampaignLocaleParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Locale:       "pt-BR",
+	})
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, campaign_locale)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
@@ -0,0 +1,485 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/vivek-344/AdRouter/util"
+)
+
+// ErrInvalidPatch is returned for patches that are malformed, change a
+// read-only field or leave the campaign invalid.
+var ErrInvalidPatch = &Error{Kind: ErrValidation, Message: "invalid patch"}
+
+// patchHistoryField names the history rows written by PatchCampaign. Their
+// old and new values are JSON objects holding every field the patch changed.
+const patchHistoryField = "patch"
+
+// readOnlyFields are the members of a campaign that a patch may not touch.
+// Radius circles, lists, segments, locales and labels have endpoints of their
+// own, and pending creatives are reviewed through theirs.
+var readOnlyFields = []string{"advertiser_id", "cid", "radius", "target_lists", "segments", "locales", "labels", "created_at", "version", "pending_creative"}
+
+// FieldChange is a campaign field changed from one value to another. Fields
+// are named as in campaign_history.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// campaignChanges lists the fields that differ between from and to.
+func campaignChanges(from, to CompleteCampaign) []FieldChange {
+	fromFields, toFields := from.historyFields(), to.historyFields()
+
+	changes := []FieldChange{}
+	for i := range fromFields {
+		if fromFields[i].value != toFields[i].value {
+			changes = append(changes, FieldChange{
+				Field: fromFields[i].name,
+				From:  fromFields[i].value,
+				To:    toFields[i].value,
+			})
+		}
+	}
+	return changes
+}
+
+// targetingField is a targeting value of a campaign with its rule.
+type targetingField struct {
+	field string
+	value string
+	rule  RuleType
+}
+
+func (campaign *CompleteCampaign) targeting() []targetingField {
+	return []targetingField{
+		{"app_id", campaign.AppID, campaign.AppRule},
+		{"country", campaign.Country, campaign.CountryRule},
+		{"os", campaign.Os, campaign.OsRule},
+		{"os_version", campaign.OsVersion, campaign.OsVersionRule},
+		{"app_version", campaign.AppVersion, campaign.AppVersionRule},
+		{"language", campaign.Language, campaign.LanguageRule},
+		{"region", campaign.Region, campaign.RegionRule},
+		{"city", campaign.City, campaign.CityRule},
+		{"category", campaign.Category, campaign.CategoryRule},
+		{"keyword", campaign.Keyword, campaign.KeywordRule},
+	}
+}
+
+// missingRule reports the first targeting that has a value but no rule.
+func missingRule(campaign CompleteCampaign) (string, bool) {
+	for _, target := range campaign.targeting() {
+		if target.value != "" && target.rule == "" {
+			return target.field, true
+		}
+	}
+	return "", false
+}
+
+// validateCampaign applies the rules the v1 endpoints enforce when a campaign
+// is created or updated. The name and version ranges are only checked when
+// they differ from current, so older campaigns can still be patched.
+func validateCampaign(campaign CompleteCampaign, current CompleteCampaign) error {
+	if campaign.Name != current.Name && (len(campaign.Name) < 6 || len(campaign.Name) > 32) {
+		return errors.New("name must be 6 to 32 characters long")
+	}
+	if campaign.Img == "" {
+		return errors.New("img is required")
+	}
+	if campaign.Cta == "" {
+		return errors.New("cta is required")
+	}
+	if !validStatus(campaign.Status) {
+		return fmt.Errorf("invalid status %q", campaign.Status)
+	}
+
+	for _, target := range campaign.targeting() {
+		switch target.rule {
+		case "", RuleTypeInclude, RuleTypeExclude:
+		default:
+			return fmt.Errorf("invalid %s rule %q", target.field, target.rule)
+		}
+	}
+	if field, ok := missingRule(campaign); ok {
+		return fmt.Errorf("%s requires a rule", field)
+	}
+
+	versions := [][2]string{
+		{campaign.OsVersion, current.OsVersion},
+		{campaign.AppVersion, current.AppVersion},
+	}
+	for _, version := range versions {
+		expr := version[0]
+		if expr == "" || expr == version[1] {
+			continue
+		}
+		if _, err := util.ParseVersionRanges(expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearUnusedRules drops the rule of targeting without a value, so that
+// setting a value to null removes the targeting whole.
+func (campaign *CompleteCampaign) clearUnusedRules() {
+	rules := []struct {
+		value string
+		rule  *RuleType
+	}{
+		{campaign.AppID, &campaign.AppRule},
+		{campaign.Country, &campaign.CountryRule},
+		{campaign.Os, &campaign.OsRule},
+		{campaign.OsVersion, &campaign.OsVersionRule},
+		{campaign.AppVersion, &campaign.AppVersionRule},
+		{campaign.Language, &campaign.LanguageRule},
+		{campaign.Region, &campaign.RegionRule},
+		{campaign.City, &campaign.CityRule},
+		{campaign.Category, &campaign.CategoryRule},
+		{campaign.Keyword, &campaign.KeywordRule},
+	}
+	for _, r := range rules {
+		if r.value == "" {
+			*r.rule = ""
+		}
+	}
+}
+
+// mergeCampaign applies a JSON merge patch to the JSON form of campaign.
+func mergeCampaign(campaign CompleteCampaign, patch []byte) (CompleteCampaign, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil || members == nil {
+		return CompleteCampaign{}, fmt.Errorf("%w: the patch must be a JSON object", ErrInvalidPatch)
+	}
+	for _, field := range readOnlyFields {
+		if _, ok := members[field]; ok {
+			return CompleteCampaign{}, fmt.Errorf("%w: %s is read-only", ErrInvalidPatch, field)
+		}
+	}
+
+	doc, err := json.Marshal(campaign)
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+	merged, err := util.MergePatch(doc, patch)
+	if err != nil {
+		return CompleteCampaign{}, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	var target CompleteCampaign
+	if err := decoder.Decode(&target); err != nil {
+		return CompleteCampaign{}, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	target.clearUnusedRules()
+	if err := validateCampaign(target, campaign); err != nil {
+		return CompleteCampaign{}, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return target, nil
+}
+
+type PatchCampaignParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Patch        []byte `json:"patch"`
+	Version      int32  `json:"version"`
+}
+
+// PatchCampaign applies a JSON merge patch to a campaign, as returned by
+// ReadCampaign. Targeting set to null is deleted and targeting given to a
+// campaign without it is added. Every change is applied in one transaction
+// and recorded as a single history row, except img and cta changes of a live
+// campaign, which go to its pending creative revision.
+func (store *SQLStore) PatchCampaign(ctx context.Context, arg PatchCampaignParams) (CompleteCampaign, error) {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+
+	var campaign CompleteCampaign
+	var changes []FieldChange
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := bumpVersion(ctx, q, key, arg.Version)
+		if err != nil {
+			return err
+		}
+
+		current, err := readCampaign(ctx, q, key)
+		if err != nil {
+			return err
+		}
+
+		target, err := mergeCampaign(current, arg.Patch)
+		if err != nil {
+			return err
+		}
+		if target.Status != current.Status {
+			transition, ok := findTransition(current.Status, target.Status)
+			if !ok || slices.Contains(reviewTransitions, transition) {
+				return fmt.Errorf("%w: a patch cannot change the status from %s to %s", ErrInvalidTransition, current.Status, target.Status)
+			}
+		}
+
+		if err := proposeHeld(ctx, q, key, holdCreative(current, &target)); err != nil {
+			return err
+		}
+
+		changes = campaignChanges(current, target)
+		if len(changes) == 0 {
+			campaign, err = readCampaign(ctx, q, key)
+			return err
+		}
+
+		if err := applyCampaignChanges(ctx, q, key, current, target); err != nil {
+			return err
+		}
+
+		oldValues := make(map[string]string, len(changes))
+		newValues := make(map[string]string, len(changes))
+		for _, change := range changes {
+			oldValues[change.Field] = change.From
+			newValues[change.Field] = change.To
+		}
+		oldValue, err := json.Marshal(oldValues)
+		if err != nil {
+			return err
+		}
+		newValue, err := json.Marshal(newValues)
+		if err != nil {
+			return err
+		}
+		err = store.createHistory(ctx, q, []createCampaignHistoryParams{
+			{
+				AdvertiserID: key.AdvertiserID,
+				Cid:          key.Cid,
+				FieldChanged: patchHistoryField,
+				OldValue:     string(oldValue),
+				NewValue:     string(newValue),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		campaign, err = readCampaign(ctx, q, key)
+		return err
+	})
+	if err == nil {
+		store.invalidateChanges(ctx, key, changes)
+	}
+	return campaign, err
+}
+
+// targetChange writes one kind of targeting, whose value and rule are stored
+// together in a row of their own.
+type targetChange struct {
+	from, to         string
+	fromRule, toRule RuleType
+	add              func(value string, rule RuleType) error
+	update           func(value string, rule RuleType) error
+	remove           func() error
+}
+
+func (change targetChange) apply() error {
+	switch {
+	case change.from == change.to && change.fromRule == change.toRule:
+		return nil
+	case change.to == "":
+		return change.remove()
+	case change.from == "":
+		return change.add(change.to, change.toRule)
+	}
+	return change.update(change.to, change.toRule)
+}
+
+// applyCampaignChanges writes every row of the campaign whose fields differ
+// between from and to. Targeting is added or deleted as its value becomes set
+// or empty, and so are labels. Locales are only updated; to must hold the same
+// locales as from.
+func applyCampaignChanges(ctx context.Context, q *Queries, key CampaignKey, from, to CompleteCampaign) error {
+	var err error
+	if from.Name != to.Name {
+		_, err = q.updateCampaignName(ctx, updateCampaignNameParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Name: to.Name})
+		if err != nil {
+			return err
+		}
+	}
+	if from.Img != to.Img {
+		_, err = q.updateCampaignImage(ctx, updateCampaignImageParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Img: to.Img})
+		if err != nil {
+			return err
+		}
+	}
+	if from.Cta != to.Cta {
+		_, err = q.updateCampaignCta(ctx, updateCampaignCtaParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Cta: to.Cta})
+		if err != nil {
+			return err
+		}
+	}
+	if from.Status != to.Status {
+		_, err = q.setCampaignStatus(ctx, setCampaignStatusParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Status: to.Status})
+		if err != nil {
+			return err
+		}
+	}
+
+	changes := []targetChange{
+		{
+			from.AppID, to.AppID, from.AppRule, to.AppRule,
+			func(value string, rule RuleType) error {
+				_, err := q.addTargetApp(ctx, addTargetAppParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, AppID: value, Rule: rule})
+				return err
+			},
+			func(value string, rule RuleType) error {
+				_, err := q.updateTargetApp(ctx, updateTargetAppParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, AppID: value, Rule: rule})
+				return err
+			},
+			func() error { return q.deleteTargetApp(ctx, deleteTargetAppParams(key)) },
+		},
+		{
+			from.Country, to.Country, from.CountryRule, to.CountryRule,
+			func(value string, rule RuleType) error {
+				_, err := q.addTargetCountry(ctx, addTargetCountryParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Country: value, Rule: rule})
+				return err
+			},
+			func(value string, rule RuleType) error {
+				_, err := q.updateTargetCountry(ctx, updateTargetCountryParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Country: value, Rule: rule})
+				return err
+			},
+			func() error { return q.deleteTargetCountry(ctx, deleteTargetCountryParams(key)) },
+		},
+		{
+			from.Os, to.Os, from.OsRule, to.OsRule,
+			func(value string, rule RuleType) error {
+				_, err := q.addTargetOs(ctx, addTargetOsParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Os: value, Rule: rule})
+				return err
+			},
+			func(value string, rule RuleType) error {
+				_, err := q.updateTargetOs(ctx, updateTargetOsParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Os: value, Rule: rule})
+				return err
+			},
+			func() error { return q.deleteTargetOs(ctx, deleteTargetOsParams(key)) },
+		},
+		{
+			from.OsVersion, to.OsVersion, from.OsVersionRule, to.OsVersionRule,
+			func(value string, rule RuleType) error {
+				_, err := q.addTargetOsVersion(ctx, addTargetOsVersionParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, OsVersion: value, Rule: rule})
+				return err
+			},
+			func(value string, rule RuleType) error {
+				_, err := q.updateTargetOsVersion(ctx, updateTargetOsVersionParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, OsVersion: value, Rule: rule})
+				return err
+			},
+			func() error { return q.deleteTargetOsVersion(ctx, deleteTargetOsVersionParams(key)) },
+		},
+		{
+			from.AppVersion, to.AppVersion, from.AppVersionRule, to.AppVersionRule,
+			func(value string, rule RuleType) error {
+				_, err := q.addTargetAppVersion(ctx, addTargetAppVersionParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, AppVersion: value, Rule: rule})
+				return err
+			},
+			func(value string, rule RuleType) error {
+				_, err := q.updateTargetAppVersion(ctx, updateTargetAppVersionParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, AppVersion: value, Rule: rule})
+				return err
+			},
+			func() error { return q.deleteTargetAppVersion(ctx, deleteTargetAppVersionParams(key)) },
+		},
+		{
+			from.Language, to.Language, from.LanguageRule, to.LanguageRule,
+			func(value string, rule RuleType) error {
+				_, err := q.addTargetLanguage(ctx, addTargetLanguageParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Language: value, Rule: rule})
+				return err
+			},
+			func(value string, rule RuleType) error {
+				_, err := q.updateTargetLanguage(ctx, updateTargetLanguageParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Language: value, Rule: rule})
+				return err
+			},
+			func() error { return q.deleteTargetLanguage(ctx, deleteTargetLanguageParams(key)) },
+		},
+		{
+			from.Region, to.Region, from.RegionRule, to.RegionRule,
+			func(value string, rule RuleType) error {
+				_, err := q.addTargetRegion(ctx, addTargetRegionParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Region: value, Rule: rule})
+				return err
+			},
+			func(value string, rule RuleType) error {
+				_, err := q.updateTargetRegion(ctx, updateTargetRegionParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Region: value, Rule: rule})
+				return err
+			},
+			func() error { return q.deleteTargetRegion(ctx, deleteTargetRegionParams(key)) },
+		},
+		{
+			from.City, to.City, from.CityRule, to.CityRule,
+			func(value string, rule RuleType) error {
+				_, err := q.addTargetCity(ctx, addTargetCityParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, City: value, Rule: rule})
+				return err
+			},
+			func(value string, rule RuleType) error {
+				_, err := q.updateTargetCity(ctx, updateTargetCityParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, City: value, Rule: rule})
+				return err
+			},
+			func() error { return q.deleteTargetCity(ctx, deleteTargetCityParams(key)) },
+		},
+		{
+			from.Category, to.Category, from.CategoryRule, to.CategoryRule,
+			func(value string, rule RuleType) error {
+				_, err := q.addTargetCategory(ctx, addTargetCategoryParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Category: value, Rule: rule})
+				return err
+			},
+			func(value string, rule RuleType) error {
+				_, err := q.updateTargetCategory(ctx, updateTargetCategoryParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Category: value, Rule: rule})
+				return err
+			},
+			func() error { return q.deleteTargetCategory(ctx, deleteTargetCategoryParams(key)) },
+		},
+		{
+			from.Keyword, to.Keyword, from.KeywordRule, to.KeywordRule,
+			func(value string, rule RuleType) error {
+				_, err := q.addTargetKeyword(ctx, addTargetKeywordParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Keyword: value, Rule: rule})
+				return err
+			},
+			func(value string, rule RuleType) error {
+				_, err := q.updateTargetKeyword(ctx, updateTargetKeywordParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Keyword: value, Rule: rule})
+				return err
+			},
+			func() error { return q.deleteTargetKeyword(ctx, deleteTargetKeywordParams(key)) },
+		},
+	}
+	for _, change := range changes {
+		if err := change.apply(); err != nil {
+			return err
+		}
+	}
+
+	for i, locale := range to.Locales {
+		if i < len(from.Locales) && locale == from.Locales[i] {
+			continue
+		}
+		_, err = q.updateCampaignLocale(ctx, updateCampaignLocaleParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			Locale:       locale.Locale,
+			Img:          locale.Img,
+			Cta:          locale.Cta,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return applyLabelChanges(ctx, q, key, from.Labels, to.Labels)
+}
+
+// cloneCampaign copies a campaign, so that its slices can be changed without
+// touching the original.
+func cloneCampaign(campaign CompleteCampaign) CompleteCampaign {
+	campaign.Radius = slices.Clone(campaign.Radius)
+	campaign.TargetLists = slices.Clone(campaign.TargetLists)
+	campaign.Segments = slices.Clone(campaign.Segments)
+	campaign.Locales = slices.Clone(campaign.Locales)
+	campaign.Labels = slices.Clone(campaign.Labels)
+	return campaign
+}
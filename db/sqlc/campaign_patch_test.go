@@ -0,0 +1,141 @@
+package db_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func patchCampaign(key db.CampaignKey, patch string, version int32) (db.CompleteCampaign, error) {
+	return testStore.PatchCampaign(context.Background(), db.PatchCampaignParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Patch:        []byte(patch),
+		Version:      version,
+	})
+}
+
+func TestPatchCampaign(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	_, err := testStore.AddTargetOs(context.Background(), db.AddTargetOsParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Os:           "android",
+		Rule:         db.RuleType("include"),
+	})
+	require.NoError(t, err)
+
+	original, err := testStore.ReadCampaign(context.Background(), campaign.Key())
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	name := util.RandomName()
+	patched, err := patchCampaign(campaign.Key(), `{
+		"name": "`+name+`",
+		"status": "paused",
+		"country": "in,us",
+		"country_rule": "include",
+		"os": null
+	}`, 0)
+	require.NoError(t, err)
+	require.Equal(t, name, patched.Name)
+	require.Equal(t, db.StatusTypePaused, patched.Status)
+	require.Equal(t, "in,us", patched.Country)
+	require.Equal(t, db.RuleType("include"), patched.CountryRule)
+	require.Empty(t, patched.Os)
+	require.Empty(t, patched.OsRule)
+	require.Equal(t, original.Img, patched.Img)
+
+	_, err = testStore.GetTargetOs(context.Background(), db.GetTargetOsParams(campaign.Key()))
+	require.Error(t, err)
+
+	history, err := testStore.ListCampaignHistory(context.Background(), db.ListCampaignHistoryParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, "patch", history[0].FieldChanged)
+	require.ElementsMatch(t, []string{"os", "os_rule"}, []string{history[1].FieldChanged, history[2].FieldChanged})
+
+	var oldValues, newValues map[string]string
+	require.NoError(t, json.Unmarshal([]byte(history[0].OldValue), &oldValues))
+	require.NoError(t, json.Unmarshal([]byte(history[0].NewValue), &newValues))
+	require.Equal(t, map[string]string{
+		"name":         original.Name,
+		"status":       "active",
+		"country":      "",
+		"country_rule": "",
+		"os":           "android",
+		"os_rule":      "include",
+	}, oldValues)
+	require.Equal(t, name, newValues["name"])
+
+	// The consolidated row is replayed like any other.
+	reconstructed, err := testStore.ReadCampaignAsOf(context.Background(), campaign.Key(), history[0].UpdatedAt.Add(-time.Millisecond))
+	require.NoError(t, err)
+	expected := original
+	expected.Version = 0
+	require.Equal(t, expected, reconstructed)
+
+	// Reverting the patch adds the deleted targeting back.
+	result, err := testStore.RevertCampaign(context.Background(), db.RevertCampaignParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		HistoryID:    history[0].ID,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Applied)
+	require.Equal(t, original.Version+2, result.Campaign.Version)
+	original.Version = result.Campaign.Version
+	require.Equal(t, original, result.Campaign)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestPatchCampaignNoChanges(t *testing.T) {
+	campaign := addRandomCampaign(t)
+
+	patched, err := patchCampaign(campaign.Key(), `{"name": "`+campaign.Name+`"}`, 0)
+	require.NoError(t, err)
+	require.Equal(t, campaign.Name, patched.Name)
+
+	history, err := testStore.ListCampaignHistory(context.Background(), db.ListCampaignHistoryParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestPatchCampaignInvalid(t *testing.T) {
+	campaign := addRandomCampaign(t)
+
+	patches := []string{
+		`[]`,
+		`"name"`,
+		`{"cid": "other"}`,
+		`{"locales": []}`,
+		`{"unknown": 1}`,
+		`{"name": "short"}`,
+		`{"img": null}`,
+		`{"status": "paused"}`,
+		`{"country": "us"}`,
+		`{"country": "us", "country_rule": "maybe"}`,
+		`{"os_version": "not a range", "os_version_rule": "include"}`,
+	}
+	for _, patch := range patches {
+		_, err := patchCampaign(campaign.Key(), patch, 0)
+		require.ErrorIs(t, err, db.ErrInvalidPatch, patch)
+	}
+
+	current, err := testStore.GetCampaign(context.Background(), db.GetCampaignParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, campaign, current)
+
+	_, err = patchCampaign(db.CampaignKey{AdvertiserID: campaign.AdvertiserID, Cid: util.RandomCid()}, `{}`, 0)
+	require.Error(t, err)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrInvalidCursor is returned when a search cursor was not produced by the
+// same search order.
+var ErrInvalidCursor = &Error{Kind: ErrValidation, Message: "invalid cursor"}
+
+// Search orders accepted by SearchCampaigns. A leading "-" sorts descending.
+const (
+	SortNewest   = "-created_at"
+	SortOldest   = "created_at"
+	SortName     = "name"
+	SortNameDesc = "-name"
+)
+
+type SearchCampaignsParams struct {
+	AdvertiserID int32      `json:"advertiser_id"`
+	Status       StatusType `json:"status"`
+	Name         string     `json:"name"`
+	CreatedSince time.Time  `json:"created_since"`
+	CreatedUntil time.Time  `json:"created_until"`
+	Country      string     `json:"country"`
+	App          string     `json:"app"`
+	Os           string     `json:"os"`
+	Sort         string     `json:"sort"`
+	Cursor       string     `json:"cursor"`
+	Limit        int32      `json:"limit"`
+}
+
+// SearchCampaignsResult holds one page of a search, the number of campaigns
+// matching the filters across all pages and the cursor of the next page,
+// empty on the last one.
+type SearchCampaignsResult struct {
+	Campaigns  []Campaign `json:"campaigns"`
+	Total      int64      `json:"total"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// searchCursor is the position after the last campaign of a page: its sort
+// key and cid, which breaks ties.
+type searchCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	Cid   string `json:"c"`
+}
+
+func encodeCursor(cursor searchCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string, sort string) (searchCursor, error) {
+	var cursor searchCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != sort {
+		return cursor, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// likePattern escapes the wildcards of an ILIKE pattern so that s matches
+// literally.
+func likePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// SearchCampaigns lists the campaigns of an advertiser matching every given
+// filter, one page at a time. Pages are read with keyset pagination, so their
+// cost does not grow with the number of pages skipped, and the total is
+// counted with the same filters.
+func (store *SQLStore) SearchCampaigns(ctx context.Context, arg SearchCampaignsParams) (SearchCampaignsResult, error) {
+	if arg.Sort == "" {
+		arg.Sort = SortNewest
+	}
+
+	var cursor searchCursor
+	var err error
+	if arg.Cursor != "" {
+		cursor, err = decodeCursor(arg.Cursor, arg.Sort)
+		if err != nil {
+			return SearchCampaignsResult{}, err
+		}
+	}
+
+	filters := CountCampaignsParams{
+		AdvertiserID: arg.AdvertiserID,
+		Status:       NullStatusType{StatusType: arg.Status, Valid: arg.Status != ""},
+		Name:         pgtype.Text{String: likePattern(arg.Name), Valid: arg.Name != ""},
+		CreatedSince: pgtype.Timestamptz{Time: arg.CreatedSince, Valid: !arg.CreatedSince.IsZero()},
+		CreatedUntil: pgtype.Timestamptz{Time: arg.CreatedUntil, Valid: !arg.CreatedUntil.IsZero()},
+		Country:      pgtype.Text{String: arg.Country, Valid: arg.Country != ""},
+		App:          pgtype.Text{String: arg.App, Valid: arg.App != ""},
+		Os:           pgtype.Text{String: arg.Os, Valid: arg.Os != ""},
+	}
+
+	// One more row than asked for tells whether there is a next page.
+	rowLimit := arg.Limit + 1
+	afterName := pgtype.Text{String: cursor.Value, Valid: arg.Cursor != ""}
+	var afterCreatedAt pgtype.Timestamptz
+	if arg.Cursor != "" && (arg.Sort == SortNewest || arg.Sort == SortOldest) {
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return SearchCampaignsResult{}, ErrInvalidCursor
+		}
+		afterCreatedAt = pgtype.Timestamptz{Time: createdAt, Valid: true}
+	}
+
+	var campaigns []Campaign
+	switch arg.Sort {
+	case SortNewest:
+		campaigns, err = store.SearchCampaignsByNewest(ctx, SearchCampaignsByNewestParams{
+			AdvertiserID:   filters.AdvertiserID,
+			Status:         filters.Status,
+			Name:           filters.Name,
+			CreatedSince:   filters.CreatedSince,
+			CreatedUntil:   filters.CreatedUntil,
+			Country:        filters.Country,
+			App:            filters.App,
+			Os:             filters.Os,
+			AfterCreatedAt: afterCreatedAt,
+			AfterCid:       cursor.Cid,
+			RowLimit:       rowLimit,
+		})
+	case SortOldest:
+		campaigns, err = store.SearchCampaignsByOldest(ctx, SearchCampaignsByOldestParams{
+			AdvertiserID:   filters.AdvertiserID,
+			Status:         filters.Status,
+			Name:           filters.Name,
+			CreatedSince:   filters.CreatedSince,
+			CreatedUntil:   filters.CreatedUntil,
+			Country:        filters.Country,
+			App:            filters.App,
+			Os:             filters.Os,
+			AfterCreatedAt: afterCreatedAt,
+			AfterCid:       cursor.Cid,
+			RowLimit:       rowLimit,
+		})
+	case SortName:
+		campaigns, err = store.SearchCampaignsByName(ctx, SearchCampaignsByNameParams{
+			AdvertiserID: filters.AdvertiserID,
+			Status:       filters.Status,
+			Name:         filters.Name,
+			CreatedSince: filters.CreatedSince,
+			CreatedUntil: filters.CreatedUntil,
+			Country:      filters.Country,
+			App:          filters.App,
+			Os:           filters.Os,
+			AfterName:    afterName,
+			AfterCid:     cursor.Cid,
+			RowLimit:     rowLimit,
+		})
+	case SortNameDesc:
+		campaigns, err = store.SearchCampaignsByNameDesc(ctx, SearchCampaignsByNameDescParams{
+			AdvertiserID: filters.AdvertiserID,
+			Status:       filters.Status,
+			Name:         filters.Name,
+			CreatedSince: filters.CreatedSince,
+			CreatedUntil: filters.CreatedUntil,
+			Country:      filters.Country,
+			App:          filters.App,
+			Os:           filters.Os,
+			AfterName:    afterName,
+			AfterCid:     cursor.Cid,
+			RowLimit:     rowLimit,
+		})
+	default:
+		return SearchCampaignsResult{}, fmt.Errorf("unknown sort %q", arg.Sort)
+	}
+	if err != nil {
+		return SearchCampaignsResult{}, err
+	}
+
+	result := SearchCampaignsResult{Campaigns: campaigns}
+	if len(campaigns) > int(arg.Limit) {
+		result.Campaigns = campaigns[:arg.Limit]
+		last := result.Campaigns[len(result.Campaigns)-1]
+		next := searchCursor{Sort: arg.Sort, Value: last.Name, Cid: last.Cid}
+		if arg.Sort == SortNewest || arg.Sort == SortOldest {
+			next.Value = last.CreatedAt.Format(time.RFC3339Nano)
+		}
+		result.NextCursor = encodeCursor(next)
+	}
+
+	result.Total, err = store.CountCampaigns(ctx, filters)
+	if err != nil {
+		return SearchCampaignsResult{}, err
+	}
+	return result, nil
+}
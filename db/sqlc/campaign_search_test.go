@@ -0,0 +1,109 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestSearchCampaigns(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+
+	names := []string{"Summer_sale", "summer sale 2", "winter sale", "spring promo", "Summer 100%"}
+	var campaigns []db.Campaign
+	for _, name := range names {
+		campaign, err := testStore.AddCampaign(context.Background(), db.AddCampaignParams{
+			AdvertiserID: advertiser.ID,
+			Cid:          util.RandomCid(),
+			Name:         name,
+			Img:          util.RandomImg(),
+			Cta:          util.RandomCta(),
+			Status:       db.NullStatusType{StatusType: db.StatusTypeActive, Valid: true},
+		})
+		require.NoError(t, err)
+		campaigns = append(campaigns, campaign)
+	}
+
+	_, err := testStore.AddTargetCountry(context.Background(), db.AddTargetCountryParams{
+		AdvertiserID: advertiser.ID,
+		Cid:          campaigns[1].Cid,
+		Country:      "us, IN",
+		Rule:         db.RuleType("include"),
+	})
+	require.NoError(t, err)
+	err = testStore.ToggleStatus(context.Background(), campaigns[2].Key(), 0)
+	require.NoError(t, err)
+
+	search := func(arg db.SearchCampaignsParams) db.SearchCampaignsResult {
+		arg.AdvertiserID = advertiser.ID
+		if arg.Limit == 0 {
+			arg.Limit = 10
+		}
+		result, err := testStore.SearchCampaigns(context.Background(), arg)
+		require.NoError(t, err)
+		return result
+	}
+
+	result := search(db.SearchCampaignsParams{Name: "summer"})
+	require.Equal(t, int64(3), result.Total)
+	require.Len(t, result.Campaigns, 3)
+	require.Empty(t, result.NextCursor)
+
+	// Wildcards in the name filter match literally.
+	result = search(db.SearchCampaignsParams{Name: "100%"})
+	require.Len(t, result.Campaigns, 1)
+	require.Equal(t, campaigns[4].Cid, result.Campaigns[0].Cid)
+
+	result = search(db.SearchCampaignsParams{Country: "in"})
+	require.Len(t, result.Campaigns, 1)
+	require.Equal(t, campaigns[1].Cid, result.Campaigns[0].Cid)
+
+	result = search(db.SearchCampaignsParams{Status: db.StatusTypePaused})
+	require.Len(t, result.Campaigns, 1)
+	require.Equal(t, campaigns[2].Cid, result.Campaigns[0].Cid)
+
+	// Paging by name visits every campaign once.
+	var paged []string
+	arg := db.SearchCampaignsParams{Sort: db.SortName, Limit: 2}
+	for {
+		result = search(arg)
+		require.Equal(t, int64(len(names)), result.Total)
+		for _, campaign := range result.Campaigns {
+			paged = append(paged, campaign.Name)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		arg.Cursor = result.NextCursor
+	}
+	require.ElementsMatch(t, names, paged)
+
+	result = search(db.SearchCampaignsParams{Sort: db.SortOldest})
+	require.Equal(t, campaigns[0].Cid, result.Campaigns[0].Cid)
+	result = search(db.SearchCampaignsParams{})
+	require.Equal(t, campaigns[len(campaigns)-1].Cid, result.Campaigns[0].Cid)
+
+	// A cursor is only valid for the order it was produced by.
+	result = search(db.SearchCampaignsParams{Sort: db.SortName, Limit: 1})
+	_, err = testStore.SearchCampaigns(context.Background(), db.SearchCampaignsParams{
+		AdvertiserID: advertiser.ID,
+		Sort:         db.SortNewest,
+		Cursor:       result.NextCursor,
+		Limit:        1,
+	})
+	require.ErrorIs(t, err, db.ErrInvalidCursor)
+	_, err = testStore.SearchCampaigns(context.Background(), db.SearchCampaignsParams{
+		AdvertiserID: advertiser.ID,
+		Cursor:       "not a cursor",
+		Limit:        1,
+	})
+	require.ErrorIs(t, err, db.ErrInvalidCursor)
+
+	for _, campaign := range campaigns {
+		testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	}
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
@@ -0,0 +1,94 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomCampaignSegment(t *testing.T, key db.CampaignKey, segmentID int32) db.CampaignSegment {
+	arg := db.AddCampaignSegmentParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		SegmentID:    segmentID,
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	campaign_segment, err := testStore.AddCampaignSegment(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Cid, campaign_segment.Cid)
+	require.Equal(t, arg.SegmentID, campaign_segment.SegmentID)
+	require.Equal(t, arg.Rule, campaign_segment.Rule)
+
+	return campaign_segment
+}
+
+func TestAddCampaignSegment(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	segment := createRandomAudienceSegment(t)
+	addRandomCampaignSegment(t, campaign.Key(), segment.ID)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment.ID})
+}
+
+func TestListCampaignSegments(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	segment1 := createRandomAudienceSegment(t)
+	segment2 := createRandomAudienceSegment(t)
+	campaign_segment1 := addRandomCampaignSegment(t, campaign.Key(), segment1.ID)
+	campaign_segment2 := addRandomCampaignSegment(t, campaign.Key(), segment2.ID)
+
+	campaign_segments, err := testStore.ListCampaignSegments(context.Background(), db.ListCampaignSegmentsParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, []db.CampaignSegment{campaign_segment1, campaign_segment2}, campaign_segments)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment1.ID})
+	testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment2.ID})
+}
+
+func TestDeleteCampaignSegment(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	segment := createRandomAudienceSegment(t)
+	addRandomCampaignSegment(t, campaign.Key(), segment.ID)
+
+	err := testStore.DeleteCampaignSegment(context.Background(), db.DeleteCampaignSegmentParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		SegmentID:    segment.ID,
+	})
+	require.NoError(t, err)
+
+	campaign_segments, err := testStore.ListCampaignSegments(context.Background(), db.ListCampaignSegmentsParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Empty(t, campaign_segments)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment.ID})
+}
+
+func TestAddCampaignSegmentOfAnotherAdvertiser(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	advertiser := createRandomAdvertiser(t)
+	segment, err := testStore.CreateAudienceSegment(context.Background(), db.CreateAudienceSegmentParams{
+		AdvertiserID: advertiser.ID,
+		Name:         util.RandomName(),
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.AddCampaignSegment(context.Background(), db.AddCampaignSegmentParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		SegmentID:    segment.ID,
+		Rule:         db.RuleTypeInclude,
+	})
+	require.ErrorIs(t, db.TranslateError(err), db.ErrNotFound)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: advertiser.ID, ID: segment.ID})
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
@@ -0,0 +1,103 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomCampaignTargetList(t *testing.T, key db.CampaignKey, listID int32) db.CampaignTargetList {
+	arg := db.AddCampaignTargetListParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		ListID:       listID,
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	campaign_target_list, err := testStore.AddCampaignTargetList(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Cid, campaign_target_list.Cid)
+	require.Equal(t, arg.ListID, campaign_target_list.ListID)
+	require.Equal(t, arg.Rule, campaign_target_list.Rule)
+
+	return campaign_target_list
+}
+
+func TestAddCampaignTargetList(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_list := createRandomTargetList(t)
+	addRandomCampaignTargetList(t, campaign.Key(), target_list.ID)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list.ID})
+}
+
+func TestListCampaignTargetLists(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_list1 := createRandomTargetList(t)
+	target_list2 := createRandomTargetList(t)
+	campaign_target_list1 := addRandomCampaignTargetList(t, campaign.Key(), target_list1.ID)
+	campaign_target_list2 := addRandomCampaignTargetList(t, campaign.Key(), target_list2.ID)
+
+	campaign_target_lists, err := testStore.ListCampaignTargetLists(context.Background(), db.ListCampaignTargetListsParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, []db.CampaignTargetList{campaign_target_list1, campaign_target_list2}, campaign_target_lists)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list1.ID})
+	testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list2.ID})
+}
+
+func TestDeleteCampaignTargetList(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_list := createRandomTargetList(t)
+	addRandomCampaignTargetList(t, campaign.Key(), target_list.ID)
+
+	err := testStore.DeleteCampaignTargetList(context.Background(), db.DeleteCampaignTargetListParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		ListID:       target_list.ID,
+	})
+	require.NoError(t, err)
+
+	campaign_target_lists, err := testStore.ListCampaignTargetLists(context.Background(), db.ListCampaignTargetListsParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Empty(t, campaign_target_lists)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list.ID})
+}
+
+func TestAddCampaignTargetListOfAnotherAdvertiser(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	advertiser := createRandomAdvertiser(t)
+	target_list, err := testStore.CreateTargetList(context.Background(), db.CreateTargetListParams{
+		AdvertiserID: advertiser.ID,
+		Name:         util.RandomName(),
+		ListType:     db.ListTypeApp,
+		Items:        util.RandomAppID(),
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.AddCampaignTargetList(context.Background(), db.AddCampaignTargetListParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		ListID:       target_list.ID,
+		Rule:         db.RuleTypeInclude,
+	})
+	require.ErrorIs(t, db.TranslateError(err), db.ErrNotFound)
+
+	// Nor can the list be read or deleted from the campaign's advertiser.
+	_, err = testStore.GetTargetList(context.Background(), db.GetTargetListParams{AdvertiserID: campaign.AdvertiserID, ID: target_list.ID})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	err = testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: campaign.AdvertiserID, ID: target_list.ID})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: advertiser.ID, ID: target_list.ID})
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
@@ -0,0 +1,487 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// updateCampaignTx runs fn in a transaction that first moves the campaign to
+// its next version, as every change to a campaign or its targeting does. A
+// deleted campaign has no version to move, so it cannot be changed.
+func (store *SQLStore) updateCampaignTx(ctx context.Context, key CampaignKey, version int32, fn func(*Queries) error) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := bumpVersion(ctx, q, key, version); err != nil {
+			return err
+		}
+		return fn(q)
+	})
+}
+
+// targetingTx runs fn, which adds or deletes targeting of a campaign, as
+// updateCampaignTx does, and records the values and rules it changed in the
+// history, as updating them would.
+func (store *SQLStore) targetingTx(ctx context.Context, key CampaignKey, version int32, fn func(*Queries) error) error {
+	return store.updateCampaignTx(ctx, key, version, func(q *Queries) error {
+		before, err := readCampaign(ctx, q, key)
+		if err != nil {
+			return err
+		}
+		if err := fn(q); err != nil {
+			return err
+		}
+		after, err := readCampaign(ctx, q, key)
+		if err != nil {
+			return err
+		}
+		return createChangesHistory(ctx, q, key, campaignChanges(before, after))
+	})
+}
+
+type AddTargetAppParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	AppID        string   `json:"app_id"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetApp(ctx context.Context, arg AddTargetAppParams) (TargetApp, error) {
+	var targetApp TargetApp
+	err := store.targetingTx(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version, func(q *Queries) error {
+		var err error
+		targetApp, err = q.addTargetApp(ctx, addTargetAppParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			AppID:        arg.AppID,
+			Rule:         arg.Rule,
+		})
+		return err
+	})
+	return targetApp, err
+}
+
+type DeleteTargetAppParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetApp(ctx context.Context, arg DeleteTargetAppParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.targetingTx(ctx, key, arg.Version, func(q *Queries) error {
+		return q.deleteTargetApp(ctx, deleteTargetAppParams(key))
+	})
+}
+
+type AddTargetCountryParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Country      string   `json:"country"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetCountry(ctx context.Context, arg AddTargetCountryParams) (TargetCountry, error) {
+	var targetCountry TargetCountry
+	err := store.targetingTx(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version, func(q *Queries) error {
+		var err error
+		targetCountry, err = q.addTargetCountry(ctx, addTargetCountryParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Country:      arg.Country,
+			Rule:         arg.Rule,
+		})
+		return err
+	})
+	return targetCountry, err
+}
+
+type DeleteTargetCountryParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetCountry(ctx context.Context, arg DeleteTargetCountryParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.targetingTx(ctx, key, arg.Version, func(q *Queries) error {
+		return q.deleteTargetCountry(ctx, deleteTargetCountryParams(key))
+	})
+}
+
+type AddTargetOsParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Os           string   `json:"os"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetOs(ctx context.Context, arg AddTargetOsParams) (TargetOs, error) {
+	var targetOs TargetOs
+	err := store.targetingTx(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version, func(q *Queries) error {
+		var err error
+		targetOs, err = q.addTargetOs(ctx, addTargetOsParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Os:           arg.Os,
+			Rule:         arg.Rule,
+		})
+		return err
+	})
+	return targetOs, err
+}
+
+type DeleteTargetOsParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetOs(ctx context.Context, arg DeleteTargetOsParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.targetingTx(ctx, key, arg.Version, func(q *Queries) error {
+		return q.deleteTargetOs(ctx, deleteTargetOsParams(key))
+	})
+}
+
+type AddTargetOsVersionParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	OsVersion    string   `json:"os_version"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetOsVersion(ctx context.Context, arg AddTargetOsVersionParams) (TargetOsVersion, error) {
+	var targetOsVersion TargetOsVersion
+	err := store.targetingTx(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version, func(q *Queries) error {
+		var err error
+		targetOsVersion, err = q.addTargetOsVersion(ctx, addTargetOsVersionParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			OsVersion:    arg.OsVersion,
+			Rule:         arg.Rule,
+		})
+		return err
+	})
+	return targetOsVersion, err
+}
+
+type DeleteTargetOsVersionParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetOsVersion(ctx context.Context, arg DeleteTargetOsVersionParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.targetingTx(ctx, key, arg.Version, func(q *Queries) error {
+		return q.deleteTargetOsVersion(ctx, deleteTargetOsVersionParams(key))
+	})
+}
+
+type AddTargetAppVersionParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	AppVersion   string   `json:"app_version"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetAppVersion(ctx context.Context, arg AddTargetAppVersionParams) (TargetAppVersion, error) {
+	var targetAppVersion TargetAppVersion
+	err := store.targetingTx(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version, func(q *Queries) error {
+		var err error
+		targetAppVersion, err = q.addTargetAppVersion(ctx, addTargetAppVersionParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			AppVersion:   arg.AppVersion,
+			Rule:         arg.Rule,
+		})
+		return err
+	})
+	return targetAppVersion, err
+}
+
+type DeleteTargetAppVersionParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetAppVersion(ctx context.Context, arg DeleteTargetAppVersionParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.targetingTx(ctx, key, arg.Version, func(q *Queries) error {
+		return q.deleteTargetAppVersion(ctx, deleteTargetAppVersionParams(key))
+	})
+}
+
+type AddTargetLanguageParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Language     string   `json:"language"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetLanguage(ctx context.Context, arg AddTargetLanguageParams) (TargetLanguage, error) {
+	var targetLanguage TargetLanguage
+	err := store.targetingTx(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version, func(q *Queries) error {
+		var err error
+		targetLanguage, err = q.addTargetLanguage(ctx, addTargetLanguageParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Language:     arg.Language,
+			Rule:         arg.Rule,
+		})
+		return err
+	})
+	return targetLanguage, err
+}
+
+type DeleteTargetLanguageParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetLanguage(ctx context.Context, arg DeleteTargetLanguageParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.targetingTx(ctx, key, arg.Version, func(q *Queries) error {
+		return q.deleteTargetLanguage(ctx, deleteTargetLanguageParams(key))
+	})
+}
+
+type AddTargetRegionParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Region       string   `json:"region"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetRegion(ctx context.Context, arg AddTargetRegionParams) (TargetRegion, error) {
+	var targetRegion TargetRegion
+	err := store.targetingTx(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version, func(q *Queries) error {
+		var err error
+		targetRegion, err = q.addTargetRegion(ctx, addTargetRegionParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Region:       arg.Region,
+			Rule:         arg.Rule,
+		})
+		return err
+	})
+	return targetRegion, err
+}
+
+type DeleteTargetRegionParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetRegion(ctx context.Context, arg DeleteTargetRegionParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.targetingTx(ctx, key, arg.Version, func(q *Queries) error {
+		return q.deleteTargetRegion(ctx, deleteTargetRegionParams(key))
+	})
+}
+
+type AddTargetCityParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	City         string   `json:"city"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetCity(ctx context.Context, arg AddTargetCityParams) (TargetCity, error) {
+	var targetCity TargetCity
+	err := store.targetingTx(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version, func(q *Queries) error {
+		var err error
+		targetCity, err = q.addTargetCity(ctx, addTargetCityParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			City:         arg.City,
+			Rule:         arg.Rule,
+		})
+		return err
+	})
+	return targetCity, err
+}
+
+type DeleteTargetCityParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetCity(ctx context.Context, arg DeleteTargetCityParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.targetingTx(ctx, key, arg.Version, func(q *Queries) error {
+		return q.deleteTargetCity(ctx, deleteTargetCityParams(key))
+	})
+}
+
+type AddTargetCategoryParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Category     string   `json:"category"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetCategory(ctx context.Context, arg AddTargetCategoryParams) (TargetCategory, error) {
+	var targetCategory TargetCategory
+	err := store.targetingTx(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version, func(q *Queries) error {
+		var err error
+		targetCategory, err = q.addTargetCategory(ctx, addTargetCategoryParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Category:     arg.Category,
+			Rule:         arg.Rule,
+		})
+		return err
+	})
+	return targetCategory, err
+}
+
+type DeleteTargetCategoryParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetCategory(ctx context.Context, arg DeleteTargetCategoryParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.targetingTx(ctx, key, arg.Version, func(q *Queries) error {
+		return q.deleteTargetCategory(ctx, deleteTargetCategoryParams(key))
+	})
+}
+
+type AddTargetKeywordParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Keyword      string   `json:"keyword"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetKeyword(ctx context.Context, arg AddTargetKeywordParams) (TargetKeyword, error) {
+	var targetKeyword TargetKeyword
+	err := store.targetingTx(ctx, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version, func(q *Queries) error {
+		var err error
+		targetKeyword, err = q.addTargetKeyword(ctx, addTargetKeywordParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Keyword:      arg.Keyword,
+			Rule:         arg.Rule,
+		})
+		return err
+	})
+	return targetKeyword, err
+}
+
+type DeleteTargetKeywordParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetKeyword(ctx context.Context, arg DeleteTargetKeywordParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.targetingTx(ctx, key, arg.Version, func(q *Queries) error {
+		return q.deleteTargetKeyword(ctx, deleteTargetKeywordParams(key))
+	})
+}
+
+type AddCampaignLocaleParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Locale       string `json:"locale"`
+	Img          string `json:"img"`
+	Cta          string `json:"cta"`
+	Version      int32  `json:"version"`
+}
+
+// AddCampaignLocale adds a locale specific creative to a campaign. The locale
+// of a live campaign is added once a reviewer approves the creative revision
+// proposing it.
+func (store *SQLStore) AddCampaignLocale(ctx context.Context, arg AddCampaignLocaleParams) (LocaleUpdateResult, error) {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+
+	var result LocaleUpdateResult
+	err := store.updateCampaignTx(ctx, key, arg.Version, func(q *Queries) error {
+		campaign, err := q.GetCampaign(ctx, GetCampaignParams(key))
+		if err != nil {
+			return err
+		}
+		if !liveStatus(campaign.Status) {
+			result.Locale, err = q.addCampaignLocale(ctx, addCampaignLocaleParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				Locale:       arg.Locale,
+				Img:          arg.Img,
+				Cta:          arg.Cta,
+			})
+			return err
+		}
+
+		_, err = q.GetCampaignLocale(ctx, GetCampaignLocaleParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Locale:       arg.Locale,
+		})
+		if err == nil {
+			return &Error{Kind: ErrAlreadyExists, Message: "campaign locale already exists"}
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		result.Locale = CampaignLocale{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid, Locale: arg.Locale}
+		result.Revision, err = proposeCreative(ctx, q, key, arg.Locale, creative{}, creative{img: arg.Img, cta: arg.Cta})
+		return err
+	})
+	if err == nil {
+		store.invalidateCampaignLocales(ctx, key)
+	}
+	return result, err
+}
+
+type DeleteCampaignLocaleParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Locale       string `json:"locale"`
+	Version      int32  `json:"version"`
+}
+
+// DeleteCampaignLocale deletes a locale specific creative from a campaign,
+// withdrawing the creative revision of the locale waiting for review.
+func (store *SQLStore) DeleteCampaignLocale(ctx context.Context, arg DeleteCampaignLocaleParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	err := store.updateCampaignTx(ctx, key, arg.Version, func(q *Queries) error {
+		pending, err := pendingCreative(ctx, q, key, arg.Locale)
+		if err != nil {
+			return err
+		}
+		if pending != nil {
+			err := q.closeCreativeRevision(ctx, closeCreativeRevisionParams{ID: pending.ID, Status: RevisionStatusWithdrawn})
+			if err != nil {
+				return err
+			}
+		}
+		return q.deleteCampaignLocale(ctx, deleteCampaignLocaleParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Locale:       arg.Locale,
+		})
+	})
+	if err == nil {
+		store.invalidateCampaignLocales(ctx, key)
+	}
+	return err
+}
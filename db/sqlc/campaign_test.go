@@ -3,6 +3,7 @@ package db_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/require"
@@ -17,13 +18,13 @@ func TestListCampaigns(t *testing.T) {
 		all_campaign = append(all_campaign, campaign)
 	}
 
-	listed_campaigns, err := testStore.ListCampaigns(context.Background())
+	listed_campaigns, err := testStore.ListCampaigns(context.Background(), testAdvertiser.ID)
 	require.NoError(t, err)
 	require.GreaterOrEqual(t, len(listed_campaigns), 10)
 
 	for _, campaign := range all_campaign {
 		require.Contains(t, listed_campaigns, campaign)
-		testStore.DeleteCampaign(context.Background(), campaign.Cid)
+		testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 	}
 }
 
@@ -34,8 +35,8 @@ func TestListActiveCampaigns(t *testing.T) {
 	for range 10 {
 		campaign := addRandomCampaign(t)
 		if util.RandomBool() {
-			testStore.ToggleStatus(context.Background(), campaign.Cid)
-			campaign, err := testStore.GetCampaign(context.Background(), campaign.Cid)
+			testStore.ToggleStatus(context.Background(), campaign.Key(), 0)
+			campaign, err := testStore.GetCampaign(context.Background(), db.GetCampaignParams(campaign.Key()))
 			require.NoError(t, err)
 			all_campaign = append(all_campaign, campaign)
 		} else {
@@ -53,17 +54,74 @@ func TestListActiveCampaigns(t *testing.T) {
 		if campaign.Status == db.StatusType("active") {
 			require.Contains(t, listed_campaigns, campaign)
 		}
-		testStore.DeleteCampaign(context.Background(), campaign.Cid)
+		testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 	}
 }
 
 func TestDeleteCampaign(t *testing.T) {
 	campaign := addRandomCampaign(t)
-	err := testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	err := testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 	require.NoError(t, err)
 
-	campaign, err = testStore.GetCampaign(context.Background(), campaign.Cid)
+	campaign, err = testStore.GetCampaign(context.Background(), db.GetCampaignParams(campaign.Key()))
 	require.Error(t, err)
 	require.EqualError(t, err, pgx.ErrNoRows.Error())
 	require.Empty(t, campaign)
 }
+
+func TestRestoreCampaign(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	key := campaign.Key()
+	err := testStore.DeleteVersionedCampaign(context.Background(), key, campaign.Version)
+	require.NoError(t, err)
+
+	// Deleted campaigns are left out of reads and later writes.
+	campaigns, err := testStore.ListCampaigns(context.Background(), testAdvertiser.ID)
+	require.NoError(t, err)
+	for _, listed := range campaigns {
+		require.NotEqual(t, campaign.Cid, listed.Cid)
+	}
+	err = testStore.DeleteVersionedCampaign(context.Background(), key, 0)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = testStore.AddTargetCountry(context.Background(), db.AddTargetCountryParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Country:      "us",
+		Rule:         db.RuleTypeInclude,
+	})
+	require.ErrorIs(t, err, db.ErrNotFound)
+
+	restored, err := testStore.RestoreCampaign(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, campaign.Name, restored.Name)
+	require.Equal(t, campaign.Status, restored.Status)
+	require.Greater(t, restored.Version, campaign.Version)
+	require.False(t, restored.DeletedAt.Valid)
+
+	_, err = testStore.RestoreCampaign(context.Background(), key)
+	require.ErrorIs(t, err, db.ErrNotFound)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(key))
+}
+
+func TestPurgeDeletedCampaigns(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	key := campaign.Key()
+	err := testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(key))
+	require.NoError(t, err)
+
+	// Campaigns deleted within the retention period stay restorable.
+	_, err = testStore.PurgeDeletedCampaigns(context.Background(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	_, err = testStore.RestoreCampaign(context.Background(), key)
+	require.NoError(t, err)
+
+	err = testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(key))
+	require.NoError(t, err)
+	purged, err := testStore.PurgeDeletedCampaigns(context.Background(), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, purged, int64(1))
+
+	_, err = testStore.RestoreCampaign(context.Background(), key)
+	require.ErrorIs(t, err, db.ErrNotFound)
+}
@@ -0,0 +1,85 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestCampaignVersion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	require.Equal(t, int32(1), campaign.Version)
+
+	updated, err := testStore.UpdateCampaignName(context.Background(), db.UpdateCampaignNameParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Name:         util.RandomName(),
+		Version:      campaign.Version,
+	})
+	require.NoError(t, err)
+	require.Equal(t, campaign.Version+1, updated.Version)
+
+	// An update from the version read before is rejected and changes nothing.
+	_, err = testStore.UpdateCampaignCta(context.Background(), db.UpdateCampaignCtaParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Cta:          util.RandomCta(),
+		Version:      campaign.Version,
+	})
+	require.ErrorIs(t, err, db.ErrVersionMismatch)
+	err = testStore.ToggleStatus(context.Background(), campaign.Key(), campaign.Version)
+	require.ErrorIs(t, err, db.ErrVersionMismatch)
+	_, err = patchCampaign(campaign.Key(), `{"name": "`+util.RandomName()+`"}`, campaign.Version)
+	require.ErrorIs(t, err, db.ErrVersionMismatch)
+
+	current, err := testStore.GetCampaign(context.Background(), db.GetCampaignParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, updated, current)
+
+	// Targeting adds, updates and deletes bump the version of their campaign.
+	_, err = testStore.AddTargetOs(context.Background(), db.AddTargetOsParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Os:           "android",
+		Rule:         db.RuleType("include"),
+	})
+	require.NoError(t, err)
+	_, err = testStore.UpdateTargetOs(context.Background(), db.UpdateTargetOsParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Os:           "ios",
+		Rule:         db.RuleType("include"),
+		Version:      current.Version + 1,
+	})
+	require.NoError(t, err)
+	err = testStore.DeleteTargetOs(context.Background(), db.DeleteTargetOsParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Version:      current.Version + 1,
+	})
+	require.ErrorIs(t, err, db.ErrVersionMismatch)
+	err = testStore.DeleteTargetOs(context.Background(), db.DeleteTargetOsParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Version:      current.Version + 2,
+	})
+	require.NoError(t, err)
+
+	complete, err := testStore.ReadCampaign(context.Background(), campaign.Key())
+	require.NoError(t, err)
+	require.Equal(t, current.Version+3, complete.Version)
+
+	err = testStore.DeleteVersionedCampaign(context.Background(), campaign.Key(), current.Version)
+	require.ErrorIs(t, err, db.ErrVersionMismatch)
+	err = testStore.DeleteVersionedCampaign(context.Background(), campaign.Key(), complete.Version)
+	require.NoError(t, err)
+
+	_, err = testStore.GetCampaign(context.Background(), db.GetCampaignParams(campaign.Key()))
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	err = testStore.DeleteVersionedCampaign(context.Background(), campaign.Key(), complete.Version)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
@@ -0,0 +1,342 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Change events streamed to subscribers, one or more per campaign history
+// entry.
+const (
+	ChangeCampaignCreated       = "campaign.created"
+	ChangeCampaignUpdated       = "campaign.updated"
+	ChangeCampaignStatusChanged = "campaign.status_changed"
+	ChangeTargetingUpdated      = "targeting.updated"
+	ChangeCampaignDeleted       = "campaign.deleted"
+)
+
+const (
+	// ChangeReplayLimit bounds the events replayed to a subscriber resuming
+	// after the last event it received.
+	ChangeReplayLimit = 1000
+	// changeChannel is the channel the campaign_history_notify trigger
+	// announces history entries on.
+	changeChannel          = "campaign_history"
+	changeSubscriberBuffer = 256
+	changeRetryDelay       = time.Second
+)
+
+// targetingHistoryFields are the targeting fields of campaign_history; their
+// rules are recorded with a _rule suffix.
+var targetingHistoryFields = []string{
+	"app_id",
+	"country",
+	"os",
+	"os_version",
+	"app_version",
+	"language",
+	"region",
+	"city",
+	"category",
+	"keyword",
+}
+
+// changeEventOrder is the order in which the events of a patch entry are
+// sent.
+var changeEventOrder = []string{
+	ChangeCampaignStatusChanged,
+	ChangeTargetingUpdated,
+	ChangeCampaignUpdated,
+}
+
+// ChangeEvent is a campaign history entry, as a change event. Its ID is that
+// of the entry, so events are resumed from the history; an entry may yield
+// several events sharing its ID.
+type ChangeEvent struct {
+	Event string `json:"event"`
+	CampaignHistory
+}
+
+// changeEventOf classifies a change of a history field. A restored campaign
+// is updated, as its deletion is undone.
+func changeEventOf(field, newValue string) string {
+	switch {
+	case field == createdHistoryField || field == clonedFromHistoryField:
+		return ChangeCampaignCreated
+	case field == deletedHistoryField && newValue == "true":
+		return ChangeCampaignDeleted
+	case field == "status":
+		return ChangeCampaignStatusChanged
+	case field == radiusHistoryField || field == listsHistoryField || field == segmentsHistoryField,
+		slices.Contains(targetingHistoryFields, strings.TrimSuffix(field, "_rule")):
+		return ChangeTargetingUpdated
+	}
+	return ChangeCampaignUpdated
+}
+
+// newChangeEvents lists the events of a history entry. Patch entries yield an
+// event for each kind of field they change.
+func newChangeEvents(entry CampaignHistory) []ChangeEvent {
+	kinds := []string{changeEventOf(entry.FieldChanged, entry.NewValue)}
+	if entry.FieldChanged == patchHistoryField {
+		var fields map[string]string
+		if err := json.Unmarshal([]byte(entry.NewValue), &fields); err == nil && len(fields) > 0 {
+			changed := make(map[string]bool, len(fields))
+			for field, value := range fields {
+				changed[changeEventOf(field, value)] = true
+			}
+			kinds = kinds[:0]
+			for _, kind := range changeEventOrder {
+				if changed[kind] {
+					kinds = append(kinds, kind)
+				}
+			}
+		}
+	}
+
+	events := make([]ChangeEvent, len(kinds))
+	for i, kind := range kinds {
+		events[i] = ChangeEvent{Event: kind, CampaignHistory: entry}
+	}
+	return events
+}
+
+type SubscribeChangesParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Events       []string `json:"events"`
+	// LastEventID is the ID of the last event received before, to replay
+	// the events committed since. Zero replays nothing.
+	LastEventID int32 `json:"last_event_id"`
+}
+
+func (arg SubscribeChangesParams) matches(event ChangeEvent) bool {
+	if arg.Cid != "" && event.Cid != arg.Cid {
+		return false
+	}
+	return len(arg.Events) == 0 || slices.Contains(arg.Events, event.Event)
+}
+
+// ChangeSubscription holds the events missed since the last event ID, oldest
+// first, and the channel receiving the events committed from then on.
+// Truncated reports that more events were missed than ChangeReplayLimit, in
+// which case Replay only holds the latest of them. Events is closed once the
+// context of the subscription ends, or when the subscriber falls behind or
+// the server loses its connection to the database; the subscriber then
+// resumes from the last event it received.
+type ChangeSubscription struct {
+	Replay    []ChangeEvent
+	Truncated bool
+	Events    <-chan ChangeEvent
+}
+
+// changeFeed fans the history entries announced on changeChannel out to the
+// subscribers of their advertiser. It listens from the first subscription on,
+// on a connection of its own.
+type changeFeed struct {
+	start     sync.Once
+	ready     sync.Once
+	listening chan struct{}
+
+	mu   sync.Mutex
+	subs map[*changeSubscriber]struct{}
+}
+
+type changeSubscriber struct {
+	advertiserID int32
+	entries      chan CampaignHistory
+}
+
+func newChangeFeed() *changeFeed {
+	return &changeFeed{
+		listening: make(chan struct{}),
+		subs:      make(map[*changeSubscriber]struct{}),
+	}
+}
+
+func (feed *changeFeed) subscribe(advertiserID int32) *changeSubscriber {
+	sub := &changeSubscriber{
+		advertiserID: advertiserID,
+		entries:      make(chan CampaignHistory, changeSubscriberBuffer),
+	}
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+	feed.subs[sub] = struct{}{}
+	return sub
+}
+
+func (feed *changeFeed) unsubscribe(sub *changeSubscriber) {
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+	if _, ok := feed.subs[sub]; ok {
+		delete(feed.subs, sub)
+		close(sub.entries)
+	}
+}
+
+func (feed *changeFeed) publish(entry CampaignHistory) {
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+	for sub := range feed.subs {
+		if sub.advertiserID != entry.AdvertiserID {
+			continue
+		}
+		select {
+		case sub.entries <- entry:
+		default:
+			// A subscriber that cannot keep up is dropped rather than
+			// holding up the others.
+			delete(feed.subs, sub)
+			close(sub.entries)
+		}
+	}
+}
+
+// dropAll ends every subscription, once changes may have been missed.
+func (feed *changeFeed) dropAll() {
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+	for sub := range feed.subs {
+		delete(feed.subs, sub)
+		close(sub.entries)
+	}
+}
+
+// SubscribeChanges streams the history entries of an advertiser as change
+// events, as the transactions writing them commit, on any server. Events are
+// filtered by campaign and event type. With a last event ID, the events
+// committed since are first read back from the history, up to
+// ChangeReplayLimit of them.
+func (store *SQLStore) SubscribeChanges(ctx context.Context, arg SubscribeChangesParams) (ChangeSubscription, error) {
+	feed := store.changes
+	feed.start.Do(func() {
+		go store.listenChanges()
+	})
+	select {
+	case <-feed.listening:
+	case <-ctx.Done():
+		return ChangeSubscription{}, ctx.Err()
+	}
+
+	// Subscribing before reading the history leaves no gap between the
+	// replay and the live events; entries in both are only sent once.
+	sub := feed.subscribe(arg.AdvertiserID)
+	var result ChangeSubscription
+	replayed := make(map[int32]bool)
+	if arg.LastEventID > 0 {
+		history, err := store.listCampaignHistoryAfter(ctx, listCampaignHistoryAfterParams{
+			AdvertiserID: arg.AdvertiserID,
+			AfterID:      arg.LastEventID,
+			Cid:          pgtype.Text{String: arg.Cid, Valid: arg.Cid != ""},
+			RowLimit:     ChangeReplayLimit + 1,
+		})
+		if err != nil {
+			feed.unsubscribe(sub)
+			return ChangeSubscription{}, err
+		}
+		if len(history) > ChangeReplayLimit {
+			result.Truncated = true
+			history = history[:ChangeReplayLimit]
+		}
+		for i := len(history) - 1; i >= 0; i-- {
+			replayed[history[i].ID] = true
+			for _, event := range newChangeEvents(history[i]) {
+				if arg.matches(event) {
+					result.Replay = append(result.Replay, event)
+				}
+			}
+		}
+	}
+
+	events := make(chan ChangeEvent)
+	go func() {
+		defer close(events)
+		defer feed.unsubscribe(sub)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case entry, ok := <-sub.entries:
+				if !ok {
+					return
+				}
+				if replayed[entry.ID] {
+					delete(replayed, entry.ID)
+					continue
+				}
+				for _, event := range newChangeEvents(entry) {
+					if !arg.matches(event) {
+						continue
+					}
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+	result.Events = events
+	return result, nil
+}
+
+// listenChanges relays the history entries announced on changeChannel to the
+// change feed for as long as the server runs. Entries committed while it
+// reconnects are not announced, so it ends every subscription when the
+// connection is lost.
+func (store *SQLStore) listenChanges() {
+	for {
+		err := store.relayChanges(context.Background())
+		log.Printf("change feed: %v", err)
+		store.changes.dropAll()
+		time.Sleep(changeRetryDelay)
+	}
+}
+
+func (store *SQLStore) relayChanges(ctx context.Context) error {
+	pooled, err := store.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The listening connection never goes back to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+changeChannel); err != nil {
+		return err
+	}
+	store.changes.ready.Do(func() {
+		close(store.changes.listening)
+	})
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.ParseInt(notification.Payload, 10, 32)
+		if err != nil {
+			continue
+		}
+		entry, err := store.getCampaignHistoryByID(ctx, int32(id))
+		if err != nil {
+			// Entries of campaigns purged since are gone.
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return err
+		}
+		store.changes.publish(entry)
+	}
+}
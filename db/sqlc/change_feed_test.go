@@ -0,0 +1,142 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestSubscribeChanges(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	key := campaign.Key()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	subscription, err := testStore.SubscribeChanges(ctx, db.SubscribeChangesParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Events:       []string{db.ChangeCampaignStatusChanged},
+	})
+	require.NoError(t, err)
+	require.Empty(t, subscription.Replay)
+
+	// The rename is filtered out, the toggle is not.
+	_, err = testStore.UpdateCampaignName(context.Background(), db.UpdateCampaignNameParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Name:         util.RandomName(),
+	})
+	require.NoError(t, err)
+	err = testStore.ToggleStatus(context.Background(), key, 0)
+	require.NoError(t, err)
+
+	var event db.ChangeEvent
+	select {
+	case event = <-subscription.Events:
+	case <-ctx.Done():
+		t.Fatal("no change event received")
+	}
+	require.Equal(t, db.ChangeCampaignStatusChanged, event.Event)
+	require.Equal(t, key.Cid, event.Cid)
+	require.Equal(t, "status", event.FieldChanged)
+	require.Equal(t, string(db.StatusTypeActive), event.OldValue)
+	require.Equal(t, string(db.StatusTypePaused), event.NewValue)
+
+	// Resuming after the rename replays the toggle.
+	history, err := testStore.ListCampaignHistory(context.Background(), db.ListCampaignHistoryParams(key))
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	resumed, err := testStore.SubscribeChanges(ctx, db.SubscribeChangesParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		LastEventID:  history[1].ID,
+	})
+	require.NoError(t, err)
+	require.False(t, resumed.Truncated)
+	require.Len(t, resumed.Replay, 1)
+	require.Equal(t, event.ID, resumed.Replay[0].ID)
+
+	cancel()
+	_, ok := <-resumed.Events
+	require.False(t, ok)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(key))
+}
+
+func TestSubscribeChangesEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	subscription, err := testStore.SubscribeChanges(ctx, db.SubscribeChangesParams{
+		AdvertiserID: testAdvertiser.ID,
+	})
+	require.NoError(t, err)
+	next := func(key db.CampaignKey) db.ChangeEvent {
+		for {
+			select {
+			case event := <-subscription.Events:
+				if event.Cid == key.Cid {
+					return event
+				}
+			case <-ctx.Done():
+				t.Fatal("no change event received")
+			}
+		}
+	}
+
+	created := createRandomCampaign(t)
+	draft := db.CampaignKey{AdvertiserID: created.AdvertiserID, Cid: created.Cid}
+	event := next(draft)
+	require.Equal(t, db.ChangeCampaignCreated, event.Event)
+	require.Equal(t, "created", event.FieldChanged)
+	require.Equal(t, string(db.StatusTypeDraft), event.NewValue)
+
+	err = testStore.DeleteVersionedCampaign(context.Background(), draft, 0)
+	require.NoError(t, err)
+	event = next(draft)
+	require.Equal(t, db.ChangeCampaignDeleted, event.Event)
+	require.Equal(t, "deleted", event.FieldChanged)
+
+	// Restoring a campaign updates it.
+	_, err = testStore.RestoreCampaign(context.Background(), draft)
+	require.NoError(t, err)
+	event = next(draft)
+	require.Equal(t, db.ChangeCampaignUpdated, event.Event)
+	require.Equal(t, "deleted", event.FieldChanged)
+	require.Equal(t, "false", event.NewValue)
+
+	// A patch changing the status and the targeting is announced as both,
+	// and as an update of the rest.
+	key := addRandomCampaign(t).Key()
+	_, err = patchCampaign(key, `{"name": "`+util.RandomName()+`", "status": "paused", "keyword": "shoes", "keyword_rule": "include"}`, 0)
+	require.NoError(t, err)
+	event = next(key)
+	require.Equal(t, db.ChangeCampaignStatusChanged, event.Event)
+	require.Equal(t, "patch", event.FieldChanged)
+	patched := event.ID
+	event = next(key)
+	require.Equal(t, db.ChangeTargetingUpdated, event.Event)
+	require.Equal(t, patched, event.ID)
+	event = next(key)
+	require.Equal(t, db.ChangeCampaignUpdated, event.Event)
+	require.Equal(t, patched, event.ID)
+
+	_, err = testStore.AddTargetRadius(context.Background(), db.AddTargetRadiusParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Lat:          12.97,
+		Lon:          77.59,
+		RadiusKm:     5,
+		Rule:         db.RuleType("include"),
+	})
+	require.NoError(t, err)
+	event = next(key)
+	require.Equal(t, db.ChangeTargetingUpdated, event.Event)
+	require.Equal(t, "radius", event.FieldChanged)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(draft))
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(key))
+}
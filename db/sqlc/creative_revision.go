@@ -0,0 +1,395 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrRevisionNotPending is returned when reviewing a creative revision that
+// was reviewed, superseded or withdrawn already.
+var ErrRevisionNotPending = &Error{Kind: ErrConflict, Message: "creative revision is no longer pending"}
+
+// liveStatus reports whether a campaign in status serves, or serves again
+// once resumed. The creative of a live campaign only changes once a reviewer
+// approves it; until then the campaign keeps serving its approved creative.
+func liveStatus(status StatusType) bool {
+	return status == StatusTypeActive || status == StatusTypePaused
+}
+
+// creative is the img and cta of a campaign. An empty member of a change
+// leaves that part of the creative as it is.
+type creative struct {
+	img string
+	cta string
+}
+
+// creativeChange is a change of a creative and the approved creative it
+// changes.
+type creativeChange struct {
+	approved creative
+	change   creative
+}
+
+// diffCreative returns the parts of to that differ from from.
+func diffCreative(from creative, to creative) creative {
+	var change creative
+	if to.img != from.img {
+		change.img = to.img
+	}
+	if to.cta != from.cta {
+		change.cta = to.cta
+	}
+	return change
+}
+
+// holdCreative takes the creative changes of a live campaign out of target,
+// which keeps the approved creatives of current, its own and those of its
+// locales. It returns the changes held by locale, with the campaign's own
+// creative under the empty locale; they go to review instead.
+func holdCreative(current CompleteCampaign, target *CompleteCampaign) map[string]creativeChange {
+	if !liveStatus(current.Status) {
+		return nil
+	}
+	held := make(map[string]creativeChange)
+	approved := creative{img: current.Img, cta: current.Cta}
+	if change := diffCreative(approved, creative{img: target.Img, cta: target.Cta}); change != (creative{}) {
+		held[""] = creativeChange{approved: approved, change: change}
+	}
+	target.Img, target.Cta = current.Img, current.Cta
+
+	for i, locale := range target.Locales {
+		if i >= len(current.Locales) || current.Locales[i].Locale != locale.Locale {
+			continue
+		}
+		approved := creative{img: current.Locales[i].Img, cta: current.Locales[i].Cta}
+		applied, change := holdLocale(approved, creative{img: locale.Img, cta: locale.Cta})
+		if change != (creative{}) {
+			held[locale.Locale] = creativeChange{approved: applied, change: change}
+		}
+		target.Locales[i].Img, target.Locales[i].Cta = applied.img, applied.cta
+	}
+	return held
+}
+
+// holdLocale splits a change of the creative of a live campaign's locale from
+// approved to to. Clearing an override falls back to the approved creative of
+// the campaign, so it applies at once, while new overrides are held for
+// review. It returns the creative to apply and the change held.
+func holdLocale(approved creative, to creative) (creative, creative) {
+	applied := approved
+	change := diffCreative(approved, to)
+	if change.img == "" && to.img == "" {
+		applied.img = ""
+	}
+	if change.cta == "" && to.cta == "" {
+		applied.cta = ""
+	}
+	return applied, change
+}
+
+// proposeHeld sends the creative changes taken out by holdCreative to review.
+func proposeHeld(ctx context.Context, q *Queries, key CampaignKey, held map[string]creativeChange) error {
+	for locale, held := range held {
+		if _, err := proposeCreative(ctx, q, key, locale, held.approved, held.change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// proposeCreative applies change to the creative proposed for a campaign, or
+// one of its locales, serving approved: that of its pending revision, or the
+// approved one. The result becomes a new pending revision, superseding the
+// previous one, so that a reviewer always approves the creative they were
+// shown. Proposing the approved creative withdraws the pending revision
+// instead, as there is nothing left to review. It returns the pending
+// revision, or nil if none is left.
+func proposeCreative(ctx context.Context, q *Queries, key CampaignKey, locale string, approved creative, change creative) (*CreativeRevision, error) {
+	pending, err := pendingCreative(ctx, q, key, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	proposed := approved
+	if pending != nil {
+		proposed = creative{img: pending.Img, cta: pending.Cta}
+	}
+	if change.img != "" {
+		proposed.img = change.img
+	}
+	if change.cta != "" {
+		proposed.cta = change.cta
+	}
+	if pending != nil && proposed == (creative{img: pending.Img, cta: pending.Cta}) {
+		return pending, nil
+	}
+
+	if pending != nil {
+		status := RevisionStatusSuperseded
+		if proposed == approved {
+			status = RevisionStatusWithdrawn
+		}
+		err := q.closeCreativeRevision(ctx, closeCreativeRevisionParams{ID: pending.ID, Status: status})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if proposed == approved {
+		return nil, nil
+	}
+
+	revision, err := q.createCreativeRevision(ctx, createCreativeRevisionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Locale:       locale,
+		Img:          proposed.img,
+		Cta:          proposed.cta,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// pendingCreative returns the revision of a campaign's creative, or of the
+// creative of one of its locales, waiting for review, if there is one.
+func pendingCreative(ctx context.Context, q *Queries, key CampaignKey, locale string) (*CreativeRevision, error) {
+	pending, err := q.getPendingCreativeRevision(ctx, getPendingCreativeRevisionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Locale:       locale,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// CreativeUpdateResult is a campaign after a change of its creative. The
+// change of a live campaign waits for review in Revision, and the campaign
+// keeps its creative.
+type CreativeUpdateResult struct {
+	Campaign Campaign          `json:"campaign"`
+	Revision *CreativeRevision `json:"revision"`
+}
+
+// updateCreative applies change to the creative of a campaign, or, for a live
+// campaign, to its pending revision. The history records creative changes
+// once they apply.
+func (store *SQLStore) updateCreative(ctx context.Context, key CampaignKey, change creative, version int32) (CreativeUpdateResult, error) {
+	var result CreativeUpdateResult
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := bumpVersion(ctx, q, key, version)
+		if err != nil {
+			return err
+		}
+		oldCampaign, err := q.GetCampaign(ctx, GetCampaignParams(key))
+		if err != nil {
+			return err
+		}
+
+		if liveStatus(oldCampaign.Status) {
+			approved := creative{img: oldCampaign.Img, cta: oldCampaign.Cta}
+			result.Revision, err = proposeCreative(ctx, q, key, "", approved, change)
+		} else {
+			err = store.applyCreative(ctx, q, oldCampaign, change, "")
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Campaign, err = q.GetCampaign(ctx, GetCampaignParams(key))
+		return err
+	})
+	return result, err
+}
+
+// applyCreative writes change to the creative of a campaign, recording it in
+// the history with reason.
+func (store *SQLStore) applyCreative(ctx context.Context, q *Queries, campaign Campaign, change creative, reason string) error {
+	key := campaign.Key()
+	var history []createCampaignHistoryParams
+	if change.img != "" && change.img != campaign.Img {
+		_, err := q.updateCampaignImage(ctx, updateCampaignImageParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Img: change.img})
+		if err != nil {
+			return err
+		}
+		history = append(history, createCampaignHistoryParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			FieldChanged: "img",
+			OldValue:     campaign.Img,
+			NewValue:     change.img,
+			Reason:       reason,
+		})
+	}
+	if change.cta != "" && change.cta != campaign.Cta {
+		_, err := q.updateCampaignCta(ctx, updateCampaignCtaParams{AdvertiserID: key.AdvertiserID, Cid: key.Cid, Cta: change.cta})
+		if err != nil {
+			return err
+		}
+		history = append(history, createCampaignHistoryParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			FieldChanged: "cta",
+			OldValue:     campaign.Cta,
+			NewValue:     change.cta,
+			Reason:       reason,
+		})
+	}
+	return store.createHistory(ctx, q, history)
+}
+
+// LocaleUpdateResult is a locale of a campaign after a change of its
+// creative. The change of a live campaign waits for review in Revision, and
+// Locale keeps the creative it had, which is empty for a locale being added.
+type LocaleUpdateResult struct {
+	Locale   CampaignLocale    `json:"locale"`
+	Revision *CreativeRevision `json:"revision"`
+}
+
+// applyLocale sets the creative of a locale to to, recording the change in
+// the history with reason against the locale qualified fields, e.g.
+// "cta:pt-BR".
+func (store *SQLStore) applyLocale(ctx context.Context, q *Queries, locale CampaignLocale, to creative, reason string) (CampaignLocale, error) {
+	updated, err := q.updateCampaignLocale(ctx, updateCampaignLocaleParams{
+		AdvertiserID: locale.AdvertiserID,
+		Cid:          locale.Cid,
+		Locale:       locale.Locale,
+		Img:          to.img,
+		Cta:          to.cta,
+	})
+	if err != nil {
+		return CampaignLocale{}, err
+	}
+
+	err = store.createHistory(ctx, q, []createCampaignHistoryParams{
+		{
+			AdvertiserID: locale.AdvertiserID,
+			Cid:          locale.Cid,
+			FieldChanged: "img:" + locale.Locale,
+			OldValue:     locale.Img,
+			NewValue:     updated.Img,
+			Reason:       reason,
+		},
+		{
+			AdvertiserID: locale.AdvertiserID,
+			Cid:          locale.Cid,
+			FieldChanged: "cta:" + locale.Locale,
+			OldValue:     locale.Cta,
+			NewValue:     updated.Cta,
+			Reason:       reason,
+		},
+	})
+	return updated, err
+}
+
+// approveLocale applies an approved revision to its locale, adding the locale
+// if the revision proposed a new one.
+func (store *SQLStore) approveLocale(ctx context.Context, q *Queries, revision CreativeRevision, reason string) error {
+	locale, err := q.GetCampaignLocale(ctx, GetCampaignLocaleParams{
+		AdvertiserID: revision.AdvertiserID,
+		Cid:          revision.Cid,
+		Locale:       revision.Locale,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		_, err = q.addCampaignLocale(ctx, addCampaignLocaleParams{
+			AdvertiserID: revision.AdvertiserID,
+			Cid:          revision.Cid,
+			Locale:       revision.Locale,
+			Img:          revision.Img,
+			Cta:          revision.Cta,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	_, err = store.applyLocale(ctx, q, locale, creative{img: revision.Img, cta: revision.Cta}, reason)
+	return err
+}
+
+type ReviewCreativeRevisionParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	ID           int32  `json:"id"`
+	Approve      bool   `json:"approve"`
+	Reason       string `json:"reason"`
+	ReviewedBy   string `json:"reviewed_by"`
+}
+
+// ReviewCreativeRevision approves or rejects the pending creative revision of
+// a campaign. An approved revision becomes the creative of the campaign, with
+// history rows carrying the reason, and delivery stops serving the creative it
+// replaces. A rejection requires a reason, which the revision keeps. Either
+// way the campaign gets a new version, as its pending creative is gone.
+func (store *SQLStore) ReviewCreativeRevision(ctx context.Context, arg ReviewCreativeRevisionParams) (CreativeRevision, error) {
+	if !arg.Approve && strings.TrimSpace(arg.Reason) == "" {
+		return CreativeRevision{}, &Error{Kind: ErrValidation, Message: "a rejection requires a reason"}
+	}
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+
+	var revision CreativeRevision
+	err := store.execTx(ctx, func(q *Queries) error {
+		// The campaign row stays locked, so no creative change of the
+		// campaign slips in while the revision is reviewed.
+		if err := bumpVersion(ctx, q, key, 0); err != nil {
+			return err
+		}
+		current, err := q.GetCreativeRevision(ctx, GetCreativeRevisionParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			ID:           arg.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if current.Status != RevisionStatusPending {
+			return fmt.Errorf("%w: revision %d is %s", ErrRevisionNotPending, current.ID, current.Status)
+		}
+
+		status := RevisionStatusRejected
+		if arg.Approve {
+			status = RevisionStatusApproved
+		}
+		revision, err = q.reviewCreativeRevision(ctx, reviewCreativeRevisionParams{
+			ID:         current.ID,
+			Status:     status,
+			Reason:     arg.Reason,
+			ReviewedBy: arg.ReviewedBy,
+		})
+		if err != nil || !arg.Approve {
+			return err
+		}
+
+		reason := arg.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("approved creative revision %d", revision.ID)
+		}
+		if revision.Locale != "" {
+			return store.approveLocale(ctx, q, revision, reason)
+		}
+		campaign, err := q.GetCampaign(ctx, GetCampaignParams(key))
+		if err != nil {
+			return err
+		}
+		return store.applyCreative(ctx, q, campaign, creative{img: revision.Img, cta: revision.Cta}, reason)
+	})
+	if err != nil {
+		return CreativeRevision{}, err
+	}
+	if arg.Approve {
+		// Delivery is rebuilt from the cached campaigns and locales, which
+		// still hold the creative replaced.
+		store.invalidateCampaigns(ctx)
+		store.invalidateCampaignLocales(ctx, key)
+	}
+	return revision, nil
+}
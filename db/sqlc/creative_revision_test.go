@@ -0,0 +1,195 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestCreativeRevision(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	key := campaign.Key()
+
+	newImg := campaign.Img + "?v=2"
+	result, err := testStore.UpdateCampaignImage(context.Background(), db.UpdateCampaignImageParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Img:          newImg,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Revision)
+	require.Equal(t, db.RevisionStatusPending, result.Revision.Status)
+	require.Equal(t, newImg, result.Revision.Img)
+	require.Equal(t, campaign.Cta, result.Revision.Cta)
+	// The campaign keeps its approved creative until the review.
+	require.Equal(t, campaign.Img, result.Campaign.Img)
+	first := *result.Revision
+
+	// A later change supersedes the pending revision.
+	newCta := util.RandomCta() + "!"
+	patched, err := patchCampaign(key, `{"cta": "`+newCta+`", "name": "`+util.RandomName()+`"}`, 0)
+	require.NoError(t, err)
+	require.Equal(t, campaign.Cta, patched.Cta)
+	require.NotNil(t, patched.PendingCreative)
+	require.Equal(t, newImg, patched.PendingCreative.Img)
+	require.Equal(t, newCta, patched.PendingCreative.Cta)
+	second := *patched.PendingCreative
+
+	_, err = testStore.ReviewCreativeRevision(context.Background(), db.ReviewCreativeRevisionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		ID:           first.ID,
+		Approve:      true,
+	})
+	require.ErrorIs(t, err, db.ErrRevisionNotPending)
+
+	// Rejections need a reason.
+	_, err = testStore.ReviewCreativeRevision(context.Background(), db.ReviewCreativeRevisionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		ID:           second.ID,
+	})
+	require.ErrorIs(t, err, db.ErrValidation)
+
+	revision, err := testStore.ReviewCreativeRevision(context.Background(), db.ReviewCreativeRevisionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		ID:           second.ID,
+		Approve:      true,
+		Reason:       "brand safe",
+		ReviewedBy:   "user:reviewer",
+	})
+	require.NoError(t, err)
+	require.Equal(t, db.RevisionStatusApproved, revision.Status)
+	require.Equal(t, "user:reviewer", revision.ReviewedBy)
+	require.True(t, revision.ReviewedAt.Valid)
+
+	current, err := testStore.ReadCampaign(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, newImg, current.Img)
+	require.Equal(t, newCta, current.Cta)
+	require.Nil(t, current.PendingCreative)
+
+	history, err := testStore.GetCampaignHistory(context.Background(), db.GetCampaignHistoryParams(key))
+	require.NoError(t, err)
+	require.Equal(t, "brand safe", history.Reason)
+
+	// A rejected revision leaves the approved creative serving.
+	result, err = testStore.UpdateCampaignCta(context.Background(), db.UpdateCampaignCtaParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Cta:          util.RandomCta() + "?",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Revision)
+	revision, err = testStore.ReviewCreativeRevision(context.Background(), db.ReviewCreativeRevisionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		ID:           result.Revision.ID,
+		Reason:       "misleading cta",
+	})
+	require.NoError(t, err)
+	require.Equal(t, db.RevisionStatusRejected, revision.Status)
+	require.Equal(t, "misleading cta", revision.Reason)
+
+	current, err = testStore.ReadCampaign(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, newCta, current.Cta)
+
+	revisions, err := testStore.ListCreativeRevisions(context.Background(), db.ListCreativeRevisionsParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          pgtype.Text{String: key.Cid, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	require.Equal(t, db.RevisionStatusSuperseded, revisions[0].Status)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(key))
+}
+
+func TestCreativeRevisionLocale(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	key := campaign.Key()
+
+	// The locale of a live campaign is added once it is approved.
+	img := util.RandomImg()
+	added, err := testStore.AddCampaignLocale(context.Background(), db.AddCampaignLocaleParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Locale:       "pt-BR",
+		Img:          img,
+		Cta:          "Baixar",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, added.Revision)
+	require.Equal(t, "pt-BR", added.Revision.Locale)
+	require.Equal(t, img, added.Revision.Img)
+	require.Equal(t, "Baixar", added.Revision.Cta)
+
+	_, err = testStore.GetCampaignLocale(context.Background(), db.GetCampaignLocaleParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Locale:       "pt-BR",
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	// The pending locale revision does not hold the main creative back.
+	current, err := testStore.ReadCampaign(context.Background(), key)
+	require.NoError(t, err)
+	require.Nil(t, current.PendingCreative)
+
+	_, err = testStore.ReviewCreativeRevision(context.Background(), db.ReviewCreativeRevisionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		ID:           added.Revision.ID,
+		Approve:      true,
+	})
+	require.NoError(t, err)
+
+	locale, err := testStore.GetCampaignLocale(context.Background(), db.GetCampaignLocaleParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Locale:       "pt-BR",
+	})
+	require.NoError(t, err)
+	require.Equal(t, img, locale.Img)
+	require.Equal(t, "Baixar", locale.Cta)
+
+	// Changing the override waits for review, clearing it does not.
+	updated, err := testStore.UpdateCampaignLocale(context.Background(), db.UpdateCampaignLocaleParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Locale:       "pt-BR",
+		Cta:          "Instalar",
+	})
+	require.NoError(t, err)
+	require.Empty(t, updated.Locale.Img)
+	require.Equal(t, "Baixar", updated.Locale.Cta)
+	require.NotNil(t, updated.Revision)
+	require.Equal(t, "Instalar", updated.Revision.Cta)
+
+	// Deleting the locale withdraws its pending revision.
+	err = testStore.DeleteCampaignLocale(context.Background(), db.DeleteCampaignLocaleParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Locale:       "pt-BR",
+	})
+	require.NoError(t, err)
+
+	revisions, err := testStore.ListCreativeRevisions(context.Background(), db.ListCreativeRevisionsParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          pgtype.Text{String: key.Cid, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	for _, revision := range revisions {
+		require.NotEqual(t, db.RevisionStatusPending, revision.Status)
+	}
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(key))
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// The kinds of domain errors. Errors returned by the store are matched against
+// them with errors.Is after TranslateError.
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrConflict      = errors.New("conflict")
+	ErrValidation    = errors.New("invalid")
+)
+
+// Error is a domain error of one of the kinds above. Its message can be shown
+// to callers, unlike the error that caused it, which it also unwraps to.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Postgres error codes translated by TranslateError.
+const (
+	notNullViolation          = "23502"
+	foreignKeyViolation       = "23503"
+	uniqueViolation           = "23505"
+	checkViolation            = "23514"
+	stringDataRightTruncation = "22001"
+	invalidTextRepresentation = "22P02"
+	serializationFailure      = "40001"
+)
+
+var (
+	missingReferencePattern = regexp.MustCompile(`is not present in table "(\w+)"`)
+	referencedByPattern     = regexp.MustCompile(`is still referenced from table "(\w+)"`)
+	referencedTablePattern  = regexp.MustCompile(`^update or delete on table "(\w+)"`)
+	duplicateKeyPattern     = regexp.MustCompile(`^Key \(([^)]*)\)=`)
+)
+
+// entityNames are the names used in messages for tables not named after
+// their entity.
+var entityNames = map[string]string{
+	"target_list":      "list",
+	"audience_segment": "segment",
+	"api_key":          "API key",
+}
+
+// linkTables are the tables attaching one entity to another, with the message
+// for attaching the same one twice and the entity owning the links.
+var linkTables = map[string]struct {
+	duplicate string
+	owner     string
+}{
+	"campaign_target_list": {duplicate: "list already attached to campaign", owner: "campaign"},
+	"campaign_segment":     {duplicate: "segment already attached to campaign", owner: "campaign"},
+	"publisher_advertiser": {duplicate: "advertiser already allowed for app", owner: "app"},
+}
+
+// entityName turns a table name into words for messages.
+func entityName(table string) string {
+	if name, ok := entityNames[table]; ok {
+		return name
+	}
+	return strings.ReplaceAll(table, "_", " ")
+}
+
+// duplicateMessage describes a unique violation. Names unique per advertiser
+// are named, as in "list name already exists".
+func duplicateMessage(pgErr *pgconn.PgError) string {
+	if link, ok := linkTables[pgErr.TableName]; ok {
+		return link.duplicate
+	}
+	if match := duplicateKeyPattern.FindStringSubmatch(pgErr.Detail); match != nil {
+		column := strings.TrimPrefix(match[1], "advertiser_id, ")
+		if column == "name" {
+			return fmt.Sprintf("%s name already exists", entityName(pgErr.TableName))
+		}
+	}
+	return fmt.Sprintf("%s already exists", entityName(pgErr.TableName))
+}
+
+// referencedMessage describes a delete refused because other rows still use
+// the row, as in "list is still used by campaigns".
+func referencedMessage(pgErr *pgconn.PgError, referencing string) string {
+	user := entityName(referencing)
+	if link, ok := linkTables[referencing]; ok {
+		user = link.owner
+	}
+	if match := referencedTablePattern.FindStringSubmatch(pgErr.Message); match != nil {
+		return fmt.Sprintf("%s is still used by %ss", entityName(match[1]), user)
+	}
+	return fmt.Sprintf("still used by %ss", user)
+}
+
+// TranslateError turns missing rows and Postgres constraint violations into
+// domain errors that name the entity involved. Other errors, including those
+// that already are domain errors, are returned unchanged.
+func TranslateError(err error) error {
+	var domainErr *Error
+	if err == nil || errors.As(err, &domainErr) {
+		return err
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &Error{Kind: ErrNotFound, Message: "resource not found", Err: err}
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case uniqueViolation:
+		return &Error{Kind: ErrAlreadyExists, Message: duplicateMessage(pgErr), Err: err}
+	case foreignKeyViolation:
+		// Inserts name the missing row, deletes the rows still using theirs.
+		if match := missingReferencePattern.FindStringSubmatch(pgErr.Detail); match != nil {
+			return &Error{Kind: ErrNotFound, Message: fmt.Sprintf("%s not found", entityName(match[1])), Err: err}
+		}
+		if match := referencedByPattern.FindStringSubmatch(pgErr.Detail); match != nil {
+			return &Error{Kind: ErrConflict, Message: referencedMessage(pgErr, match[1]), Err: err}
+		}
+		return &Error{Kind: ErrConflict, Message: "conflicts with a related resource", Err: err}
+	case notNullViolation:
+		return &Error{Kind: ErrValidation, Message: fmt.Sprintf("%s is required", pgErr.ColumnName), Err: err}
+	case checkViolation:
+		return &Error{Kind: ErrValidation, Message: fmt.Sprintf("invalid %s", entityName(pgErr.TableName)), Err: err}
+	case stringDataRightTruncation:
+		return &Error{Kind: ErrValidation, Message: "value is too long", Err: err}
+	case invalidTextRepresentation:
+		return &Error{Kind: ErrValidation, Message: "value has an invalid format", Err: err}
+	case serializationFailure:
+		return &Error{Kind: ErrConflict, Message: "changed by a concurrent request, retry", Err: err}
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestTranslateError(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+	arg := db.AddCampaignParams{
+		AdvertiserID: advertiser.ID,
+		Cid:          util.RandomCid(),
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+	}
+	campaign, err := testStore.AddCampaign(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = testStore.AddCampaign(context.Background(), arg)
+	err = db.TranslateError(err)
+	require.ErrorIs(t, err, db.ErrAlreadyExists)
+	require.EqualError(t, err, "campaign already exists")
+
+	_, err = testStore.AddTargetApp(context.Background(), db.AddTargetAppParams{
+		AdvertiserID: advertiser.ID,
+		Cid:          util.RandomCid(),
+		AppID:        util.RandomAppID(),
+		Rule:         db.RuleType(util.RandomRule()),
+	})
+	err = db.TranslateError(err)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	require.EqualError(t, err, "campaign not found")
+
+	err = testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+	err = db.TranslateError(err)
+	require.ErrorIs(t, err, db.ErrConflict)
+	require.EqualError(t, err, "advertiser is still used by campaigns")
+
+	_, err = testStore.GetAdvertiser(context.Background(), 0)
+	err = db.TranslateError(err)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	// Domain errors keep their own kind and message.
+	require.Equal(t, db.ErrInvalidCursor, db.TranslateError(db.ErrInvalidCursor))
+	require.ErrorIs(t, db.ErrInvalidCursor, db.ErrValidation)
+	require.NoError(t, db.TranslateError(nil))
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
@@ -0,0 +1,249 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vivek-344/AdRouter/util"
+)
+
+const (
+	// geoGridCellDeg is the cell size of the radius grids, roughly 55km at the equator.
+	geoGridCellDeg = 0.5
+	geoGridTTL     = time.Minute
+)
+
+// radiusGrids holds the include and exclude circles of one campaign.
+// A nil grid means the campaign has no circles with that rule.
+type radiusGrids struct {
+	include   *util.GeoGrid
+	exclude   *util.GeoGrid
+	expiresAt time.Time
+}
+
+// contains applies the radius rules to a point. Campaigns restricted to
+// include circles are skipped when the request carries no coordinates.
+func (g *radiusGrids) contains(lat, lon *float64) bool {
+	if lat == nil || lon == nil {
+		return g.include == nil
+	}
+	if g.include != nil && !g.include.Contains(*lat, *lon) {
+		return false
+	}
+	if g.exclude != nil && g.exclude.Contains(*lat, *lon) {
+		return false
+	}
+	return true
+}
+
+// geoGridCache keeps built grids in process memory, since rebuilding them
+// from Redis on every delivery would defeat the point of indexing.
+type geoGridCache struct {
+	mu    sync.Mutex
+	grids map[string]*radiusGrids
+}
+
+func newGeoGridCache() *geoGridCache {
+	return &geoGridCache{grids: make(map[string]*radiusGrids)}
+}
+
+func (c *geoGridCache) get(key CampaignKey) (*radiusGrids, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	grids, ok := c.grids[key.String()]
+	if !ok || time.Now().After(grids.expiresAt) {
+		return nil, false
+	}
+	return grids, true
+}
+
+func (c *geoGridCache) set(key CampaignKey, grids *radiusGrids) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.grids[key.String()] = grids
+}
+
+func (c *geoGridCache) invalidate(key CampaignKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.grids, key.String())
+}
+
+func buildRadiusGrids(targets []TargetRadius) *radiusGrids {
+	var include, exclude []util.Circle
+	for _, target := range targets {
+		circle := util.Circle{Lat: target.Lat, Lon: target.Lon, RadiusKm: target.RadiusKm}
+		if target.Rule == RuleTypeExclude {
+			exclude = append(exclude, circle)
+		} else {
+			include = append(include, circle)
+		}
+	}
+
+	grids := &radiusGrids{expiresAt: time.Now().Add(geoGridTTL)}
+	if len(include) > 0 {
+		grids.include = util.NewGeoGrid(include, geoGridCellDeg)
+	}
+	if len(exclude) > 0 {
+		grids.exclude = util.NewGeoGrid(exclude, geoGridCellDeg)
+	}
+	return grids
+}
+
+func (store *SQLStore) getRadiusGrids(ctx context.Context, key CampaignKey) (*radiusGrids, error) {
+	if grids, ok := store.geoGrids.get(key); ok {
+		return grids, nil
+	}
+
+	targets, err := getCached(ctx, store, fmt.Sprintf("target_radius:%s", key), func(ctx context.Context) ([]TargetRadius, error) {
+		return store.ListTargetRadius(ctx, ListTargetRadiusParams(key))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	grids := buildRadiusGrids(*targets)
+	store.geoGrids.set(key, grids)
+	return grids, nil
+}
+
+func (store *SQLStore) filterByRadius(ctx context.Context, results []DeliveryResult, lat, lon *float64) ([]DeliveryResult, error) {
+	filtered := []DeliveryResult{}
+	for _, result := range results {
+		grids, err := store.getRadiusGrids(ctx, result.Key())
+		if err != nil {
+			return []DeliveryResult{}, err
+		}
+		if grids.contains(lat, lon) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered, nil
+}
+
+func (store *SQLStore) invalidateRadius(ctx context.Context, key CampaignKey) {
+	store.geoGrids.invalidate(key)
+	cacheKey := fmt.Sprintf("target_radius:%s", key)
+	if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+	}
+}
+
+type AddTargetRadiusCirclesParams struct {
+	AdvertiserID int32         `json:"advertiser_id"`
+	Cid          string        `json:"cid"`
+	Rule         RuleType      `json:"rule"`
+	Circles      []util.Circle `json:"circles"`
+	Version      int32         `json:"version"`
+}
+
+// AddTargetRadiusCircles adds a batch of circles sharing one rule in a single transaction.
+func (store *SQLStore) AddTargetRadiusCircles(ctx context.Context, arg AddTargetRadiusCirclesParams) ([]TargetRadius, error) {
+	targets := []TargetRadius{}
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	err := store.updateCampaignTx(ctx, key, arg.Version, func(q *Queries) error {
+		for _, circle := range arg.Circles {
+			target, err := q.addTargetRadius(ctx, addTargetRadiusParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				Lat:          circle.Lat,
+				Lon:          circle.Lon,
+				RadiusKm:     circle.RadiusKm,
+				Rule:         arg.Rule,
+			})
+			if err != nil {
+				return err
+			}
+			targets = append(targets, target)
+		}
+		return createRowsHistory(ctx, q, key, radiusHistoryField, nil, targets)
+	})
+	if err == nil {
+		store.invalidateRadius(ctx, key)
+	}
+	return targets, err
+}
+
+type AddTargetRadiusParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Lat          float64  `json:"lat"`
+	Lon          float64  `json:"lon"`
+	RadiusKm     float64  `json:"radius_km"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddTargetRadius(ctx context.Context, arg AddTargetRadiusParams) (TargetRadius, error) {
+	var target TargetRadius
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	err := store.updateCampaignTx(ctx, key, arg.Version, func(q *Queries) error {
+		var err error
+		target, err = q.addTargetRadius(ctx, addTargetRadiusParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Lat:          arg.Lat,
+			Lon:          arg.Lon,
+			RadiusKm:     arg.RadiusKm,
+			Rule:         arg.Rule,
+		})
+		if err != nil {
+			return err
+		}
+		return createRowsHistory(ctx, q, key, radiusHistoryField, nil, []TargetRadius{target})
+	})
+	if err == nil {
+		store.invalidateRadius(ctx, key)
+	}
+	return target, err
+}
+
+type DeleteTargetRadiusParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetRadius(ctx context.Context, arg DeleteTargetRadiusParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	err := store.updateCampaignTx(ctx, key, arg.Version, func(q *Queries) error {
+		deleted, err := q.deleteTargetRadius(ctx, deleteTargetRadiusParams(key))
+		if err != nil {
+			return err
+		}
+		return createRowsHistory(ctx, q, key, radiusHistoryField, deleted, nil)
+	})
+	if err == nil {
+		store.invalidateRadius(ctx, key)
+	}
+	return err
+}
+
+type DeleteTargetRadiusByIDParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	ID           int32  `json:"id"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteTargetRadiusByID(ctx context.Context, arg DeleteTargetRadiusByIDParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	err := store.updateCampaignTx(ctx, key, arg.Version, func(q *Queries) error {
+		deleted, err := q.deleteTargetRadiusByID(ctx, deleteTargetRadiusByIDParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			ID:           arg.ID,
+		})
+		if err != nil {
+			return err
+		}
+		return createRowsHistory(ctx, q, key, radiusHistoryField, deleted, nil)
+	})
+	if err == nil {
+		store.invalidateRadius(ctx, key)
+	}
+	return err
+}
@@ -0,0 +1,516 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrCampaignNotCreated is returned when a campaign is read as of a moment
+	// before it was created.
+	ErrCampaignNotCreated = &Error{Kind: ErrNotFound, Message: "campaign did not exist at that time"}
+	// ErrHistoryNotFound is returned when a revert names a history entry
+	// that does not belong to the campaign.
+	ErrHistoryNotFound = &Error{Kind: ErrNotFound, Message: "history entry not found"}
+	// ErrRevertConflict is returned when a revert would restore targeting
+	// that has been deleted since without knowing its rule.
+	ErrRevertConflict = &Error{Kind: ErrConflict, Message: "cannot revert"}
+)
+
+// targetingEntities maps the tables holding one targeting of a campaign to
+// the field of campaign_history their value is recorded as.
+var targetingEntities = map[string]string{
+	"target_app":         "app_id",
+	"target_country":     "country",
+	"target_os":          "os",
+	"target_os_version":  "os_version",
+	"target_app_version": "app_version",
+	"target_language":    "language",
+	"target_region":      "region",
+	"target_city":        "city",
+	"target_category":    "category",
+	"target_keyword":     "keyword",
+}
+
+// History fields that are not fields of a campaign. A campaign's history
+// starts with a created row, whose new value is the status it was created
+// in, or with a cloned_from row. Deleting a campaign records deleted going
+// from "false" to "true", and restoring it the other way round. Radius
+// circles, lists and segments being added and removed are recorded under
+// their member of CompleteCampaign, with the rows removed in the old value
+// and those added in the new value, as JSON arrays. Reverts leave these rows
+// alone.
+const (
+	createdHistoryField  = "created"
+	deletedHistoryField  = "deleted"
+	radiusHistoryField   = "radius"
+	listsHistoryField    = "target_lists"
+	segmentsHistoryField = "segments"
+)
+
+// rowEntities are the tables holding the radius circles, lists, segments and
+// locales of a campaign, any number of rows each.
+var rowEntities = []string{
+	"target_radius",
+	"campaign_target_list",
+	"campaign_segment",
+	"campaign_locale",
+}
+
+// ReadCampaignAsOf reconstructs a campaign as it was at asOf, starting from its
+// current state and undoing, newest first, every change recorded in its
+// history after that moment. Targeting added or deleted before the history
+// recorded it, and radius circles, lists, segments and locales, whose rows
+// the history does not rebuild, are then set back to the state the audit log
+// recorded before their first change after that moment.
+func (store *SQLStore) ReadCampaignAsOf(ctx context.Context, key CampaignKey, asOf time.Time) (CompleteCampaign, error) {
+	campaign, err := store.ReadCampaign(ctx, key)
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+	if campaign.CreatedAt.After(asOf) {
+		return CompleteCampaign{}, ErrCampaignNotCreated
+	}
+
+	history, err := store.ListCampaignHistorySince(ctx, ListCampaignHistorySinceParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		UpdatedAt:    asOf,
+	})
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+
+	for _, entry := range history {
+		campaign.revert(entry.FieldChanged, entry.OldValue)
+	}
+
+	entities := slices.Concat(slices.Collect(maps.Keys(targetingEntities)), rowEntities)
+	audit, err := store.listCampaignAuditSince(ctx, listCampaignAuditSinceParams{
+		Entities:     entities,
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Since:        asOf,
+	})
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+	if err := campaign.revertRows(audit); err != nil {
+		return CompleteCampaign{}, err
+	}
+
+	// A past state cannot be updated, so it has no version, nor a creative
+	// waiting for review.
+	campaign.Version = 0
+	campaign.PendingCreative = nil
+	return campaign, nil
+}
+
+// revert sets a field back to an older value. Fields are named as in
+// campaign_history, with locale overrides qualified by their locale, e.g.
+// "cta:pt-BR", and patches holding the old values of all fields they changed.
+func (campaign *CompleteCampaign) revert(field string, value string) {
+	if field == patchHistoryField {
+		var values map[string]string
+		if err := json.Unmarshal([]byte(value), &values); err == nil {
+			for name, value := range values {
+				campaign.revert(name, value)
+			}
+		}
+		return
+	}
+	if name, locale, ok := strings.Cut(field, ":"); ok {
+		for i := range campaign.Locales {
+			if campaign.Locales[i].Locale != locale {
+				continue
+			}
+			switch name {
+			case "img":
+				campaign.Locales[i].Img = value
+			case "cta":
+				campaign.Locales[i].Cta = value
+			}
+		}
+		return
+	}
+
+	switch field {
+	case "name":
+		campaign.Name = value
+	case "img":
+		campaign.Img = value
+	case "cta":
+		campaign.Cta = value
+	case "status":
+		campaign.Status = StatusType(value)
+	case "app_id":
+		campaign.AppID = value
+	case "app_rule":
+		campaign.AppRule = RuleType(value)
+	case "country":
+		campaign.Country = value
+	case "country_rule":
+		campaign.CountryRule = RuleType(value)
+	case "os":
+		campaign.Os = value
+	case "os_rule":
+		campaign.OsRule = RuleType(value)
+	case "os_version":
+		campaign.OsVersion = value
+	case "os_version_rule":
+		campaign.OsVersionRule = RuleType(value)
+	case "app_version":
+		campaign.AppVersion = value
+	case "app_version_rule":
+		campaign.AppVersionRule = RuleType(value)
+	case "language":
+		campaign.Language = value
+	case "language_rule":
+		campaign.LanguageRule = RuleType(value)
+	case "region":
+		campaign.Region = value
+	case "region_rule":
+		campaign.RegionRule = RuleType(value)
+	case "city":
+		campaign.City = value
+	case "city_rule":
+		campaign.CityRule = RuleType(value)
+	case "category":
+		campaign.Category = value
+	case "category_rule":
+		campaign.CategoryRule = RuleType(value)
+	case "keyword":
+		campaign.Keyword = value
+	case "keyword_rule":
+		campaign.KeywordRule = RuleType(value)
+	case "labels":
+		campaign.Labels = splitLabels(value)
+	}
+}
+
+// auditRowKey identifies a row of a campaign in the audit log, within its
+// table.
+type auditRowKey struct {
+	ID        int32  `json:"id"`
+	ListID    int32  `json:"list_id"`
+	SegmentID int32  `json:"segment_id"`
+	Locale    string `json:"locale"`
+}
+
+// revertRows sets every row changed in the audit entries, oldest first, back
+// to its state before the first of its changes.
+func (campaign *CompleteCampaign) revertRows(entries []AuditLog) error {
+	type changedRow struct {
+		entity string
+		key    auditRowKey
+	}
+	reverted := make(map[changedRow]bool)
+	for _, entry := range entries {
+		row := entry.Before
+		if row == nil {
+			row = entry.After
+		}
+		changed := changedRow{entity: entry.Entity}
+		if err := json.Unmarshal(row, &changed.key); err != nil {
+			return err
+		}
+		if reverted[changed] {
+			continue
+		}
+		reverted[changed] = true
+		if err := campaign.revertRow(entry.Entity, changed.key, entry.Before); err != nil {
+			return fmt.Errorf("cannot revert %s: %w", entry.Entity, err)
+		}
+	}
+
+	slices.SortFunc(campaign.Radius, func(a, b TargetRadius) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(campaign.TargetLists, func(a, b CampaignTargetList) int { return cmp.Compare(a.ListID, b.ListID) })
+	slices.SortFunc(campaign.Segments, func(a, b CampaignSegment) int { return cmp.Compare(a.SegmentID, b.SegmentID) })
+	slices.SortFunc(campaign.Locales, func(a, b CampaignLocale) int { return cmp.Compare(a.Locale, b.Locale) })
+	return nil
+}
+
+// revertRow replaces a row of a campaign with before, its state as the audit
+// log recorded it, or removes it when before is nil.
+func (campaign *CompleteCampaign) revertRow(entity string, key auditRowKey, before []byte) error {
+	if field, ok := targetingEntities[entity]; ok {
+		var row map[string]any
+		if before != nil {
+			if err := json.Unmarshal(before, &row); err != nil {
+				return err
+			}
+		}
+		value, _ := row[field].(string)
+		rule, _ := row["rule"].(string)
+		campaign.revert(field, value)
+		campaign.revert(field+"_rule", rule)
+		return nil
+	}
+
+	var err error
+	switch entity {
+	case "target_radius":
+		campaign.Radius, err = replaceRow(campaign.Radius, before, func(row TargetRadius) bool { return row.ID == key.ID })
+	case "campaign_target_list":
+		campaign.TargetLists, err = replaceRow(campaign.TargetLists, before, func(row CampaignTargetList) bool { return row.ListID == key.ListID })
+	case "campaign_segment":
+		campaign.Segments, err = replaceRow(campaign.Segments, before, func(row CampaignSegment) bool { return row.SegmentID == key.SegmentID })
+	case "campaign_locale":
+		campaign.Locales, err = replaceRow(campaign.Locales, before, func(row CampaignLocale) bool { return row.Locale == key.Locale })
+	}
+	return err
+}
+
+// replaceRow drops the rows matching same and appends before, unless nil.
+func replaceRow[T any](rows []T, before []byte, same func(T) bool) ([]T, error) {
+	rows = slices.DeleteFunc(rows, same)
+	if before == nil {
+		return rows, nil
+	}
+	var row T
+	if err := json.Unmarshal(before, &row); err != nil {
+		return nil, err
+	}
+	return append(rows, row), nil
+}
+
+// historyField is a field of a campaign as named in campaign_history.
+type historyField struct {
+	name  string
+	value string
+}
+
+// historyFields lists the fields of a campaign that history records, in a
+// fixed order.
+func (campaign *CompleteCampaign) historyFields() []historyField {
+	fields := []historyField{
+		{"name", campaign.Name},
+		{"img", campaign.Img},
+		{"cta", campaign.Cta},
+		{"status", string(campaign.Status)},
+		{"app_id", campaign.AppID},
+		{"app_rule", string(campaign.AppRule)},
+		{"country", campaign.Country},
+		{"country_rule", string(campaign.CountryRule)},
+		{"os", campaign.Os},
+		{"os_rule", string(campaign.OsRule)},
+		{"os_version", campaign.OsVersion},
+		{"os_version_rule", string(campaign.OsVersionRule)},
+		{"app_version", campaign.AppVersion},
+		{"app_version_rule", string(campaign.AppVersionRule)},
+		{"language", campaign.Language},
+		{"language_rule", string(campaign.LanguageRule)},
+		{"region", campaign.Region},
+		{"region_rule", string(campaign.RegionRule)},
+		{"city", campaign.City},
+		{"city_rule", string(campaign.CityRule)},
+		{"category", campaign.Category},
+		{"category_rule", string(campaign.CategoryRule)},
+		{"keyword", campaign.Keyword},
+		{"keyword_rule", string(campaign.KeywordRule)},
+		{"labels", joinLabels(campaign.Labels)},
+	}
+	for _, locale := range campaign.Locales {
+		fields = append(fields,
+			historyField{"img:" + locale.Locale, locale.Img},
+			historyField{"cta:" + locale.Locale, locale.Cta},
+		)
+	}
+	return fields
+}
+
+type RevertCampaignParams struct {
+	AdvertiserID int32     `json:"advertiser_id"`
+	Cid          string    `json:"cid"`
+	HistoryID    int32     `json:"history_id"`
+	At           time.Time `json:"at"`
+	DryRun       bool      `json:"dry_run"`
+	Version      int32     `json:"version"`
+}
+
+// RevertCampaignResult holds the changes of a revert and the campaign after
+// it, or as it would be after it for a dry run.
+type RevertCampaignResult struct {
+	Changes  []FieldChange    `json:"changes"`
+	Campaign CompleteCampaign `json:"campaign"`
+	Applied  bool             `json:"applied"`
+}
+
+// RevertCampaign undoes the history entry arg.HistoryID and every later one,
+// or, without a history ID, every change made after arg.At. The inverse
+// changes are applied in one transaction and recorded as new history rows
+// whose revert_of names the oldest entry undone. The img and cta of a live
+// campaign go to its pending creative revision instead, and are left out of
+// the changes. A dry run only computes the changes and leaves the version
+// alone.
+func (store *SQLStore) RevertCampaign(ctx context.Context, arg RevertCampaignParams) (RevertCampaignResult, error) {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+
+	var result RevertCampaignResult
+	err := store.execTx(ctx, func(q *Queries) error {
+		if !arg.DryRun {
+			if err := bumpVersion(ctx, q, key, arg.Version); err != nil {
+				return err
+			}
+		}
+
+		current, err := readCampaign(ctx, q, key)
+		if err != nil {
+			return err
+		}
+
+		var history []CampaignHistory
+		if arg.HistoryID != 0 {
+			_, err = q.GetCampaignHistoryEntry(ctx, GetCampaignHistoryEntryParams{
+				AdvertiserID: key.AdvertiserID,
+				Cid:          key.Cid,
+				ID:           arg.HistoryID,
+			})
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrHistoryNotFound
+				}
+				return err
+			}
+			history, err = q.ListCampaignHistoryFrom(ctx, ListCampaignHistoryFromParams{
+				AdvertiserID: key.AdvertiserID,
+				Cid:          key.Cid,
+				ID:           arg.HistoryID,
+			})
+		} else {
+			history, err = q.ListCampaignHistorySince(ctx, ListCampaignHistorySinceParams{
+				AdvertiserID: key.AdvertiserID,
+				Cid:          key.Cid,
+				UpdatedAt:    arg.At,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		target := cloneCampaign(current)
+		for _, entry := range history {
+			target.revert(entry.FieldChanged, entry.OldValue)
+		}
+		held := holdCreative(current, &target)
+
+		result.Changes = campaignChanges(current, target)
+		result.Campaign = target
+		if arg.DryRun || (len(result.Changes) == 0 && len(held) == 0) {
+			return nil
+		}
+
+		if field, ok := missingRule(target); ok {
+			return fmt.Errorf("%w: the rule of the deleted %s targeting is not in the history", ErrRevertConflict, field)
+		}
+		if target.Status != current.Status {
+			// Approving and rejecting are left to the admins reviewing
+			// campaigns.
+			transition, ok := findTransition(current.Status, target.Status)
+			if !ok || slices.Contains(reviewTransitions, transition) {
+				return fmt.Errorf("%w: the status cannot change from %s to %s", ErrRevertConflict, current.Status, target.Status)
+			}
+		}
+
+		if err := proposeHeld(ctx, q, key, held); err != nil {
+			return err
+		}
+		if len(result.Changes) > 0 {
+			revertOf := history[len(history)-1].ID
+			if err := applyCampaignChanges(ctx, q, key, current, target); err != nil {
+				return err
+			}
+			if err := createRevertHistory(ctx, q, key, result.Changes, revertOf); err != nil {
+				return err
+			}
+		}
+
+		result.Campaign, err = readCampaign(ctx, q, key)
+		result.Applied = err == nil
+		return err
+	})
+	if err == nil && result.Applied {
+		store.invalidateChanges(ctx, key, result.Changes)
+	}
+	return result, err
+}
+
+func createRevertHistory(ctx context.Context, q *Queries, key CampaignKey, changes []FieldChange, revertOf int32) error {
+	for _, change := range changes {
+		err := q.createCampaignRevertHistory(ctx, createCampaignRevertHistoryParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			FieldChanged: change.Field,
+			OldValue:     change.From,
+			NewValue:     change.To,
+			RevertOf:     pgtype.Int4{Int32: revertOf, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create history for %s: %v", change.Field, err)
+		}
+	}
+	return nil
+}
+
+// createChangesHistory records each of changes as a history row of its own.
+func createChangesHistory(ctx context.Context, q *Queries, key CampaignKey, changes []FieldChange) error {
+	for _, change := range changes {
+		err := q.createCampaignHistory(ctx, createCampaignHistoryParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			FieldChanged: change.Field,
+			OldValue:     change.From,
+			NewValue:     change.To,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create history for %s: %v", change.Field, err)
+		}
+	}
+	return nil
+}
+
+// createCreatedHistory starts the history of a campaign created in status.
+func createCreatedHistory(ctx context.Context, q *Queries, key CampaignKey, status StatusType) error {
+	return createChangesHistory(ctx, q, key, []FieldChange{{Field: createdHistoryField, To: string(status)}})
+}
+
+// createRowsHistory records rows removed from and added to a campaign under
+// field, unless there are none.
+func createRowsHistory[T any](ctx context.Context, q *Queries, key CampaignKey, field string, removed, added []T) error {
+	var values [2]string
+	for i, rows := range [][]T{removed, added} {
+		if len(rows) == 0 {
+			continue
+		}
+		value, err := json.Marshal(rows)
+		if err != nil {
+			return err
+		}
+		values[i] = string(value)
+	}
+	if values[0] == values[1] {
+		return nil
+	}
+
+	err := q.createCampaignHistory(ctx, createCampaignHistoryParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		FieldChanged: field,
+		OldValue:     values[0],
+		NewValue:     values[1],
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create history for %s: %v", field, err)
+	}
+	return nil
+}
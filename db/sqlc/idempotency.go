@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent
+	// again with a different request.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+	// ErrIdempotencyKeyInUse is returned while the first request sent with an
+	// idempotency key is still being served.
+	ErrIdempotencyKeyInUse = errors.New("a request with this idempotency key is still in progress")
+)
+
+const (
+	// idempotencyTTL is how long responses are kept for retries.
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyLockTTL bounds how long a request holds its key before its
+	// response is saved, so that a crashed server does not block the key.
+	// Requests running longer refresh it.
+	idempotencyLockTTL = time.Minute
+	// IdempotencyRefreshInterval is how often a request still being served
+	// refreshes the key it holds, well before the key would expire.
+	IdempotencyRefreshInterval = idempotencyLockTTL / 3
+)
+
+// IdempotentResponse is the response to a request sent with an idempotency
+// key, kept in Redis to be replayed to retries of the request. Fingerprint
+// identifies the request; a response without a status is still being served.
+type IdempotentResponse struct {
+	Fingerprint string            `json:"fingerprint"`
+	Status      int               `json:"status"`
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// idempotencyKey scopes keys to their caller, so that callers choosing the
+// same key do not see each other's responses.
+func idempotencyKey(scope string, key string) string {
+	sum := sha256.Sum256([]byte(scope + "\x00" + key))
+	return "idempotency:" + hex.EncodeToString(sum[:])
+}
+
+// ReserveIdempotencyKey claims an idempotency key for the request with the
+// given fingerprint. When a request with the key has already been served, its
+// response is returned to be replayed. Otherwise it returns nil and the key
+// is held until SaveIdempotentResponse or ReleaseIdempotencyKey.
+func (store *SQLStore) ReserveIdempotencyKey(ctx context.Context, scope string, key string, fingerprint string) (*IdempotentResponse, error) {
+	redisKey := idempotencyKey(scope, key)
+	data, err := json.Marshal(IdempotentResponse{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	reserved, err := store.rClient.SetNX(ctx, redisKey, data, idempotencyLockTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	data, err = store.rClient.Get(ctx, redisKey).Bytes()
+	if err == redis.Nil {
+		// The other request released the key in between.
+		return nil, ErrIdempotencyKeyInUse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response IdempotentResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	if response.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if response.Status == 0 {
+		return nil, ErrIdempotencyKeyInUse
+	}
+	return &response, nil
+}
+
+// SaveIdempotentResponse stores the response to a request holding key, to be
+// replayed for 24 hours.
+func (store *SQLStore) SaveIdempotentResponse(ctx context.Context, scope string, key string, response IdempotentResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return store.rClient.Set(ctx, idempotencyKey(scope, key), data, idempotencyTTL).Err()
+}
+
+// RefreshIdempotencyKey holds a reserved key for another idempotencyLockTTL,
+// for requests that outlive it, such as large imports. Retries sent meanwhile
+// keep getting ErrIdempotencyKeyInUse instead of running again.
+func (store *SQLStore) RefreshIdempotencyKey(ctx context.Context, scope string, key string) error {
+	return store.rClient.Expire(ctx, idempotencyKey(scope, key), idempotencyLockTTL).Err()
+}
+
+// ReleaseIdempotencyKey frees a key without storing a response, so that the
+// request can be retried.
+func (store *SQLStore) ReleaseIdempotencyKey(ctx context.Context, scope string, key string) error {
+	return store.rClient.Del(ctx, idempotencyKey(scope, key)).Err()
+}
@@ -0,0 +1,51 @@
+package db_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	scope := "api_key:" + util.RandomString(6)
+	key := util.RandomString(16)
+
+	response, err := testStore.ReserveIdempotencyKey(context.Background(), scope, key, "first")
+	require.NoError(t, err)
+	require.Nil(t, response)
+
+	// The key is held until the first request is answered.
+	_, err = testStore.ReserveIdempotencyKey(context.Background(), scope, key, "first")
+	require.ErrorIs(t, err, db.ErrIdempotencyKeyInUse)
+
+	saved := db.IdempotentResponse{
+		Fingerprint: "first",
+		Status:      http.StatusCreated,
+		Header:      map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:        []byte(`{"cid":"spotify"}`),
+	}
+	err = testStore.SaveIdempotentResponse(context.Background(), scope, key, saved)
+	require.NoError(t, err)
+
+	response, err = testStore.ReserveIdempotencyKey(context.Background(), scope, key, "first")
+	require.NoError(t, err)
+	require.Equal(t, saved, *response)
+
+	_, err = testStore.ReserveIdempotencyKey(context.Background(), scope, key, "second")
+	require.ErrorIs(t, err, db.ErrIdempotencyKeyReused)
+
+	// Keys are scoped to their caller.
+	response, err = testStore.ReserveIdempotencyKey(context.Background(), scope+"0", key, "second")
+	require.NoError(t, err)
+	require.Nil(t, response)
+
+	err = testStore.ReleaseIdempotencyKey(context.Background(), scope+"0", key)
+	require.NoError(t, err)
+	response, err = testStore.ReserveIdempotencyKey(context.Background(), scope+"0", key, "third")
+	require.NoError(t, err)
+	require.Nil(t, response)
+}
@@ -6,7 +6,6 @@ import (
 	"os"
 	"testing"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 	db "github.com/vivek-344/AdRouter/db/sqlc"
 	"github.com/vivek-344/AdRouter/util"
@@ -14,13 +13,16 @@ import (
 
 var testStore db.Store
 
+// testAdvertiser owns the campaigns created by the tests.
+var testAdvertiser db.Advertiser
+
 func TestMain(m *testing.M) {
 	config, err := util.LoadConfig("../..")
 	if err != nil {
 		log.Fatal("cannot load config: ", err)
 	}
 
-	testDB, err := pgxpool.New(context.Background(), config.DBSource)
+	testDB, err := db.NewPool(context.Background(), config.DBSource)
 	if err != nil {
 		log.Fatal("cannot connect to the database", err)
 	}
@@ -33,5 +35,10 @@ func TestMain(m *testing.M) {
 	client := redis.NewClient(opt)
 	testStore = db.NewStore(testDB, client)
 
+	testAdvertiser, err = testStore.GetAdvertiserByName(context.Background(), db.DefaultAdvertiserName)
+	if err != nil {
+		log.Fatal("cannot load the default advertiser: ", err)
+	}
+
 	os.Exit(m.Run())
 }
@@ -8,8 +8,187 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type AuditOperation string
+
+const (
+	AuditOperationCreate  AuditOperation = "create"
+	AuditOperationUpdate  AuditOperation = "update"
+	AuditOperationDelete  AuditOperation = "delete"
+	AuditOperationRestore AuditOperation = "restore"
+)
+
+func (e *AuditOperation) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AuditOperation(s)
+	case string:
+		*e = AuditOperation(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AuditOperation: %T", src)
+	}
+	return nil
+}
+
+type NullAuditOperation struct {
+	AuditOperation AuditOperation `json:"audit_operation"`
+	Valid          bool           `json:"valid"` // Valid is true if AuditOperation is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAuditOperation) Scan(value interface{}) error {
+	if value == nil {
+		ns.AuditOperation, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AuditOperation.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAuditOperation) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AuditOperation), nil
+}
+
+type KeyRole string
+
+const (
+	KeyRoleAdmin       KeyRole = "admin"
+	KeyRoleEditor      KeyRole = "editor"
+	KeyRoleReadOnly    KeyRole = "read_only"
+	KeyRoleDelivery    KeyRole = "delivery"
+	KeyRoleGlobalAdmin KeyRole = "global_admin"
+)
+
+func (e *KeyRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = KeyRole(s)
+	case string:
+		*e = KeyRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for KeyRole: %T", src)
+	}
+	return nil
+}
+
+type NullKeyRole struct {
+	KeyRole KeyRole `json:"key_role"`
+	Valid   bool    `json:"valid"` // Valid is true if KeyRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullKeyRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.KeyRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.KeyRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullKeyRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.KeyRole), nil
+}
+
+type ListType string
+
+const (
+	ListTypeApp     ListType = "app"
+	ListTypeCountry ListType = "country"
+	ListTypeOs      ListType = "os"
 )
 
+func (e *ListType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ListType(s)
+	case string:
+		*e = ListType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ListType: %T", src)
+	}
+	return nil
+}
+
+type NullListType struct {
+	ListType ListType `json:"list_type"`
+	Valid    bool     `json:"valid"` // Valid is true if ListType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullListType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ListType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ListType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullListType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ListType), nil
+}
+
+type RevisionStatus string
+
+const (
+	RevisionStatusPending    RevisionStatus = "pending"
+	RevisionStatusApproved   RevisionStatus = "approved"
+	RevisionStatusRejected   RevisionStatus = "rejected"
+	RevisionStatusSuperseded RevisionStatus = "superseded"
+	RevisionStatusWithdrawn  RevisionStatus = "withdrawn"
+)
+
+func (e *RevisionStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RevisionStatus(s)
+	case string:
+		*e = RevisionStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RevisionStatus: %T", src)
+	}
+	return nil
+}
+
+type NullRevisionStatus struct {
+	RevisionStatus RevisionStatus `json:"revision_status"`
+	Valid          bool           `json:"valid"` // Valid is true if RevisionStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRevisionStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.RevisionStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RevisionStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRevisionStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.RevisionStatus), nil
+}
+
 type RuleType string
 
 const (
@@ -52,11 +231,57 @@ func (ns NullRuleType) Value() (driver.Value, error) {
 	return string(ns.RuleType), nil
 }
 
+type SegmentStorage string
+
+const (
+	SegmentStorageSet   SegmentStorage = "set"
+	SegmentStorageBloom SegmentStorage = "bloom"
+)
+
+func (e *SegmentStorage) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SegmentStorage(s)
+	case string:
+		*e = SegmentStorage(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SegmentStorage: %T", src)
+	}
+	return nil
+}
+
+type NullSegmentStorage struct {
+	SegmentStorage SegmentStorage `json:"segment_storage"`
+	Valid          bool           `json:"valid"` // Valid is true if SegmentStorage is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSegmentStorage) Scan(value interface{}) error {
+	if value == nil {
+		ns.SegmentStorage, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SegmentStorage.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSegmentStorage) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SegmentStorage), nil
+}
+
 type StatusType string
 
 const (
-	StatusTypeActive   StatusType = "active"
-	StatusTypeInactive StatusType = "inactive"
+	StatusTypeDraft         StatusType = "draft"
+	StatusTypePendingReview StatusType = "pending_review"
+	StatusTypeActive        StatusType = "active"
+	StatusTypePaused        StatusType = "paused"
+	StatusTypeCompleted     StatusType = "completed"
+	StatusTypeArchived      StatusType = "archived"
 )
 
 func (e *StatusType) Scan(src interface{}) error {
@@ -94,38 +319,264 @@ func (ns NullStatusType) Value() (driver.Value, error) {
 	return string(ns.StatusType), nil
 }
 
+type Advertiser struct {
+	ID        int32     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ApiKey struct {
+	ID           int32              `json:"id"`
+	Name         string             `json:"name"`
+	Prefix       string             `json:"prefix"`
+	KeyHash      string             `json:"key_hash"`
+	Role         KeyRole            `json:"role"`
+	CreatedAt    time.Time          `json:"created_at"`
+	RevokedAt    pgtype.Timestamptz `json:"revoked_at"`
+	AdvertiserID int32              `json:"advertiser_id"`
+}
+
+type AppMetadata struct {
+	AppID      string    `json:"app_id"`
+	Categories string    `json:"categories"`
+	Tags       string    `json:"tags"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type AudienceSegment struct {
+	ID           int32          `json:"id"`
+	Name         string         `json:"name"`
+	Storage      SegmentStorage `json:"storage"`
+	MemberCount  int64          `json:"member_count"`
+	BloomBits    int64          `json:"bloom_bits"`
+	BloomHashes  int32          `json:"bloom_hashes"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	AdvertiserID int32          `json:"advertiser_id"`
+}
+
+type AuditLog struct {
+	ID           int64          `json:"id"`
+	Actor        string         `json:"actor"`
+	SourceIp     string         `json:"source_ip"`
+	RequestID    string         `json:"request_id"`
+	Operation    AuditOperation `json:"operation"`
+	Entity       string         `json:"entity"`
+	AdvertiserID pgtype.Int4    `json:"advertiser_id"`
+	EntityID     string         `json:"entity_id"`
+	Before       []byte         `json:"before"`
+	After        []byte         `json:"after"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
 type Campaign struct {
-	Cid       string     `json:"cid"`
-	Name      string     `json:"name"`
-	Img       string     `json:"img"`
-	Cta       string     `json:"cta"`
-	Status    StatusType `json:"status"`
-	CreatedAt time.Time  `json:"created_at"`
+	Cid          string             `json:"cid"`
+	Name         string             `json:"name"`
+	Img          string             `json:"img"`
+	Cta          string             `json:"cta"`
+	Status       StatusType         `json:"status"`
+	CreatedAt    time.Time          `json:"created_at"`
+	AdvertiserID int32              `json:"advertiser_id"`
+	Version      int32              `json:"version"`
+	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
 }
 
 type CampaignHistory struct {
+	ID           int32       `json:"id"`
+	Cid          string      `json:"cid"`
+	FieldChanged string      `json:"field_changed"`
+	OldValue     string      `json:"old_value"`
+	NewValue     string      `json:"new_value"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+	AdvertiserID int32       `json:"advertiser_id"`
+	RevertOf     pgtype.Int4 `json:"revert_of"`
+	Reason       string      `json:"reason"`
+}
+
+type CampaignLabel struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Label        string `json:"label"`
+}
+
+type CampaignLocale struct {
+	Cid          string `json:"cid"`
+	Locale       string `json:"locale"`
+	Img          string `json:"img"`
+	Cta          string `json:"cta"`
+	AdvertiserID int32  `json:"advertiser_id"`
+}
+
+type CampaignSegment struct {
+	Cid          string   `json:"cid"`
+	SegmentID    int32    `json:"segment_id"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type CampaignTargetList struct {
+	Cid          string   `json:"cid"`
+	ListID       int32    `json:"list_id"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type CreativeRevision struct {
+	ID           int32              `json:"id"`
+	AdvertiserID int32              `json:"advertiser_id"`
+	Cid          string             `json:"cid"`
+	Img          string             `json:"img"`
+	Cta          string             `json:"cta"`
+	Status       RevisionStatus     `json:"status"`
+	Reason       string             `json:"reason"`
+	ReviewedBy   string             `json:"reviewed_by"`
+	CreatedAt    time.Time          `json:"created_at"`
+	ReviewedAt   pgtype.Timestamptz `json:"reviewed_at"`
+	Locale       string             `json:"locale"`
+}
+
+type PublisherAdvertiser struct {
+	AppID        string `json:"app_id"`
+	AdvertiserID int32  `json:"advertiser_id"`
+}
+
+type TargetApp struct {
+	Cid          string   `json:"cid"`
+	AppID        string   `json:"app_id"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type TargetAppVersion struct {
+	Cid          string   `json:"cid"`
+	AppVersion   string   `json:"app_version"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type TargetCategory struct {
+	Cid          string   `json:"cid"`
+	Category     string   `json:"category"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type TargetCity struct {
+	Cid          string   `json:"cid"`
+	City         string   `json:"city"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type TargetCountry struct {
+	Cid          string   `json:"cid"`
+	Country      string   `json:"country"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type TargetKeyword struct {
+	Cid          string   `json:"cid"`
+	Keyword      string   `json:"keyword"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type TargetLanguage struct {
+	Cid          string   `json:"cid"`
+	Language     string   `json:"language"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type TargetList struct {
+	ID           int32     `json:"id"`
+	Name         string    `json:"name"`
+	ListType     ListType  `json:"list_type"`
+	Items        string    `json:"items"`
+	Version      int32     `json:"version"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	AdvertiserID int32     `json:"advertiser_id"`
+}
+
+type TargetListHistory struct {
 	ID           int32     `json:"id"`
-	Cid          string    `json:"cid"`
+	ListID       int32     `json:"list_id"`
+	Version      int32     `json:"version"`
 	FieldChanged string    `json:"field_changed"`
 	OldValue     string    `json:"old_value"`
 	NewValue     string    `json:"new_value"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
-type TargetApp struct {
-	Cid   string   `json:"cid"`
-	AppID string   `json:"app_id"`
-	Rule  RuleType `json:"rule"`
+type TargetOs struct {
+	Cid          string   `json:"cid"`
+	Os           string   `json:"os"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
 }
 
-type TargetCountry struct {
-	Cid     string   `json:"cid"`
-	Country string   `json:"country"`
-	Rule    RuleType `json:"rule"`
+type TargetOsVersion struct {
+	Cid          string   `json:"cid"`
+	OsVersion    string   `json:"os_version"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
 }
 
-type TargetOs struct {
-	Cid  string   `json:"cid"`
-	Os   string   `json:"os"`
-	Rule RuleType `json:"rule"`
+type TargetRadius struct {
+	ID           int32    `json:"id"`
+	Cid          string   `json:"cid"`
+	Lat          float64  `json:"lat"`
+	Lon          float64  `json:"lon"`
+	RadiusKm     float64  `json:"radius_km"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type TargetRegion struct {
+	Cid          string   `json:"cid"`
+	Region       string   `json:"region"`
+	Rule         RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+}
+
+type UnknownApp struct {
+	AppID        string    `json:"app_id"`
+	RequestCount int64     `json:"request_count"`
+	FirstSeenAt  time.Time `json:"first_seen_at"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
+}
+
+type WebhookDeadLetter struct {
+	ID             int64     `json:"id"`
+	SubscriptionID int32     `json:"subscription_id"`
+	AdvertiserID   int32     `json:"advertiser_id"`
+	EventType      string    `json:"event_type"`
+	Payload        []byte    `json:"payload"`
+	Attempts       int32     `json:"attempts"`
+	LastError      string    `json:"last_error"`
+	CreatedAt      time.Time `json:"created_at"`
+	FailedAt       time.Time `json:"failed_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64     `json:"id"`
+	SubscriptionID int32     `json:"subscription_id"`
+	AdvertiserID   int32     `json:"advertiser_id"`
+	EventType      string    `json:"event_type"`
+	Payload        []byte    `json:"payload"`
+	Attempts       int32     `json:"attempts"`
+	NextAttemptAt  time.Time `json:"next_attempt_at"`
+	LastError      string    `json:"last_error"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type WebhookSubscription struct {
+	ID           int32     `json:"id"`
+	AdvertiserID int32     `json:"advertiser_id"`
+	Url          string    `json:"url"`
+	Secret       string    `json:"secret"`
+	EventTypes   []string  `json:"event_types"`
+	CreatedAt    time.Time `json:"created_at"`
 }
@@ -0,0 +1,58 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomPublisherAdvertiser(t *testing.T, advertiserID int32) db.PublisherAdvertiser {
+	arg := db.AddPublisherAdvertiserParams{
+		AppID:        util.RandomAppID(),
+		AdvertiserID: advertiserID,
+	}
+
+	publisher_advertiser, err := testStore.AddPublisherAdvertiser(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.AppID, publisher_advertiser.AppID)
+	require.Equal(t, arg.AdvertiserID, publisher_advertiser.AdvertiserID)
+
+	return publisher_advertiser
+}
+
+func TestAddPublisherAdvertiser(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+	addRandomPublisherAdvertiser(t, advertiser.ID)
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
+
+func TestListPublisherAdvertisers(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+	publisher_advertiser := addRandomPublisherAdvertiser(t, advertiser.ID)
+
+	publisher_advertisers, err := testStore.ListPublisherAdvertisers(context.Background(), publisher_advertiser.AppID)
+	require.NoError(t, err)
+	require.Equal(t, []db.PublisherAdvertiser{publisher_advertiser}, publisher_advertisers)
+
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
+
+func TestDeletePublisherAdvertiser(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+	publisher_advertiser := addRandomPublisherAdvertiser(t, advertiser.ID)
+
+	err := testStore.DeletePublisherAdvertiser(context.Background(), db.DeletePublisherAdvertiserParams{
+		AppID:        publisher_advertiser.AppID,
+		AdvertiserID: publisher_advertiser.AdvertiserID,
+	})
+	require.NoError(t, err)
+
+	publisher_advertisers, err := testStore.ListPublisherAdvertisers(context.Background(), publisher_advertiser.AppID)
+	require.NoError(t, err)
+	require.Empty(t, publisher_advertisers)
+
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
@@ -6,34 +6,155 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
 	AddCampaign(ctx context.Context, arg AddCampaignParams) (Campaign, error)
-	AddTargetApp(ctx context.Context, arg AddTargetAppParams) (TargetApp, error)
-	AddTargetCountry(ctx context.Context, arg AddTargetCountryParams) (TargetCountry, error)
-	AddTargetOs(ctx context.Context, arg AddTargetOsParams) (TargetOs, error)
-	DeleteCampaign(ctx context.Context, cid string) error
-	DeleteTargetApp(ctx context.Context, cid string) error
-	DeleteTargetCountry(ctx context.Context, cid string) error
-	DeleteTargetOs(ctx context.Context, cid string) error
-	GetCampaign(ctx context.Context, cid string) (Campaign, error)
-	GetCampaignHistory(ctx context.Context, cid string) (CampaignHistory, error)
-	GetLastTwoCampaignHistory(ctx context.Context, cid string) ([]CampaignHistory, error)
-	GetTargetApp(ctx context.Context, cid string) (TargetApp, error)
-	GetTargetCountry(ctx context.Context, cid string) (TargetCountry, error)
-	GetTargetOs(ctx context.Context, cid string) (TargetOs, error)
+	AddPublisherAdvertiser(ctx context.Context, arg AddPublisherAdvertiserParams) (PublisherAdvertiser, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	CountActiveApiKeys(ctx context.Context, role KeyRole) (int64, error)
+	CountCampaigns(ctx context.Context, arg CountCampaignsParams) (int64, error)
+	CreateAdvertiser(ctx context.Context, name string) (Advertiser, error)
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
+	CreateAudienceSegment(ctx context.Context, arg CreateAudienceSegmentParams) (AudienceSegment, error)
+	CreateTargetList(ctx context.Context, arg CreateTargetListParams) (TargetList, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	DeleteAdvertiser(ctx context.Context, id int32) error
+	DeleteAppMetadata(ctx context.Context, appID string) error
+	DeleteCampaign(ctx context.Context, arg DeleteCampaignParams) error
+	DeletePublisherAdvertiser(ctx context.Context, arg DeletePublisherAdvertiserParams) error
+	DeleteUnknownApp(ctx context.Context, appID string) error
+	DeleteWebhookDelivery(ctx context.Context, id int64) error
+	DeleteWebhookSubscription(ctx context.Context, arg DeleteWebhookSubscriptionParams) (WebhookSubscription, error)
+	GetAdvertiser(ctx context.Context, id int32) (Advertiser, error)
+	GetAdvertiserByName(ctx context.Context, name string) (Advertiser, error)
+	GetApiKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetAppMetadata(ctx context.Context, appID string) (AppMetadata, error)
+	GetAudienceSegment(ctx context.Context, arg GetAudienceSegmentParams) (AudienceSegment, error)
+	GetCampaign(ctx context.Context, arg GetCampaignParams) (Campaign, error)
+	GetCampaignHistory(ctx context.Context, arg GetCampaignHistoryParams) (CampaignHistory, error)
+	GetCampaignHistoryEntry(ctx context.Context, arg GetCampaignHistoryEntryParams) (CampaignHistory, error)
+	GetCampaignLocale(ctx context.Context, arg GetCampaignLocaleParams) (CampaignLocale, error)
+	GetCreativeRevision(ctx context.Context, arg GetCreativeRevisionParams) (CreativeRevision, error)
+	GetLastTwoCampaignHistory(ctx context.Context, arg GetLastTwoCampaignHistoryParams) ([]CampaignHistory, error)
+	GetTargetApp(ctx context.Context, arg GetTargetAppParams) (TargetApp, error)
+	GetTargetAppVersion(ctx context.Context, arg GetTargetAppVersionParams) (TargetAppVersion, error)
+	GetTargetCategory(ctx context.Context, arg GetTargetCategoryParams) (TargetCategory, error)
+	GetTargetCity(ctx context.Context, arg GetTargetCityParams) (TargetCity, error)
+	GetTargetCountry(ctx context.Context, arg GetTargetCountryParams) (TargetCountry, error)
+	GetTargetKeyword(ctx context.Context, arg GetTargetKeywordParams) (TargetKeyword, error)
+	GetTargetLanguage(ctx context.Context, arg GetTargetLanguageParams) (TargetLanguage, error)
+	GetTargetList(ctx context.Context, arg GetTargetListParams) (TargetList, error)
+	GetTargetOs(ctx context.Context, arg GetTargetOsParams) (TargetOs, error)
+	GetTargetOsVersion(ctx context.Context, arg GetTargetOsVersionParams) (TargetOsVersion, error)
+	GetTargetRegion(ctx context.Context, arg GetTargetRegionParams) (TargetRegion, error)
+	GetWebhookSubscription(ctx context.Context, id int32) (WebhookSubscription, error)
 	ListActiveCampaigns(ctx context.Context) ([]Campaign, error)
-	ListCampaignHistory(ctx context.Context, cid string) ([]CampaignHistory, error)
-	ListCampaigns(ctx context.Context) ([]Campaign, error)
+	ListAdvertisers(ctx context.Context) ([]Advertiser, error)
+	ListApiKeys(ctx context.Context, advertiserID pgtype.Int4) ([]ApiKey, error)
+	ListAppMetadata(ctx context.Context) ([]AppMetadata, error)
+	ListAudienceSegments(ctx context.Context, advertiserID int32) ([]AudienceSegment, error)
+	ListAuditLog(ctx context.Context, arg ListAuditLogParams) ([]AuditLog, error)
+	ListCampaignHistory(ctx context.Context, arg ListCampaignHistoryParams) ([]CampaignHistory, error)
+	ListCampaignHistoryFrom(ctx context.Context, arg ListCampaignHistoryFromParams) ([]CampaignHistory, error)
+	ListCampaignHistoryPage(ctx context.Context, arg ListCampaignHistoryPageParams) ([]CampaignHistory, error)
+	ListCampaignHistorySince(ctx context.Context, arg ListCampaignHistorySinceParams) ([]CampaignHistory, error)
+	ListCampaignLabels(ctx context.Context, arg ListCampaignLabelsParams) ([]string, error)
+	ListCampaignLocales(ctx context.Context, arg ListCampaignLocalesParams) ([]CampaignLocale, error)
+	ListCampaignSegments(ctx context.Context, arg ListCampaignSegmentsParams) ([]CampaignSegment, error)
+	ListCampaignTargetLists(ctx context.Context, arg ListCampaignTargetListsParams) ([]CampaignTargetList, error)
+	ListCampaigns(ctx context.Context, advertiserID int32) ([]Campaign, error)
+	ListCreativeRevisions(ctx context.Context, arg ListCreativeRevisionsParams) ([]CreativeRevision, error)
+	ListPublisherAdvertisers(ctx context.Context, appID string) ([]PublisherAdvertiser, error)
+	ListTargetListHistory(ctx context.Context, listID int32) ([]TargetListHistory, error)
+	ListTargetLists(ctx context.Context, advertiserID int32) ([]TargetList, error)
+	ListTargetRadius(ctx context.Context, arg ListTargetRadiusParams) ([]TargetRadius, error)
+	ListUnknownApps(ctx context.Context) ([]UnknownApp, error)
+	ListWebhookDeadLetters(ctx context.Context, advertiserID int32) ([]WebhookDeadLetter, error)
+	ListWebhookDeliveries(ctx context.Context, advertiserID int32) ([]WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context, advertiserID int32) ([]WebhookSubscription, error)
+	PurgeDeletedCampaigns(ctx context.Context, before time.Time) (int64, error)
+	RecordUnknownApp(ctx context.Context, appID string) error
+	RetryWebhookDelivery(ctx context.Context, arg RetryWebhookDeliveryParams) error
+	RevokeApiKey(ctx context.Context, arg RevokeApiKeyParams) (ApiKey, error)
+	SearchCampaignsByName(ctx context.Context, arg SearchCampaignsByNameParams) ([]Campaign, error)
+	SearchCampaignsByNameDesc(ctx context.Context, arg SearchCampaignsByNameDescParams) ([]Campaign, error)
+	SearchCampaignsByNewest(ctx context.Context, arg SearchCampaignsByNewestParams) ([]Campaign, error)
+	SearchCampaignsByOldest(ctx context.Context, arg SearchCampaignsByOldestParams) ([]Campaign, error)
+	UpsertAppMetadata(ctx context.Context, arg UpsertAppMetadataParams) (AppMetadata, error)
+	addCampaignLabel(ctx context.Context, arg addCampaignLabelParams) error
+	addCampaignLocale(ctx context.Context, arg addCampaignLocaleParams) (CampaignLocale, error)
+	addCampaignSegment(ctx context.Context, arg addCampaignSegmentParams) (CampaignSegment, error)
+	addCampaignTargetList(ctx context.Context, arg addCampaignTargetListParams) (CampaignTargetList, error)
+	addTargetApp(ctx context.Context, arg addTargetAppParams) (TargetApp, error)
+	addTargetAppVersion(ctx context.Context, arg addTargetAppVersionParams) (TargetAppVersion, error)
+	addTargetCategory(ctx context.Context, arg addTargetCategoryParams) (TargetCategory, error)
+	addTargetCity(ctx context.Context, arg addTargetCityParams) (TargetCity, error)
+	addTargetCountry(ctx context.Context, arg addTargetCountryParams) (TargetCountry, error)
+	addTargetKeyword(ctx context.Context, arg addTargetKeywordParams) (TargetKeyword, error)
+	addTargetLanguage(ctx context.Context, arg addTargetLanguageParams) (TargetLanguage, error)
+	addTargetOs(ctx context.Context, arg addTargetOsParams) (TargetOs, error)
+	addTargetOsVersion(ctx context.Context, arg addTargetOsVersionParams) (TargetOsVersion, error)
+	addTargetRadius(ctx context.Context, arg addTargetRadiusParams) (TargetRadius, error)
+	addTargetRegion(ctx context.Context, arg addTargetRegionParams) (TargetRegion, error)
+	bumpCampaignVersion(ctx context.Context, arg bumpCampaignVersionParams) (int32, error)
+	closeCreativeRevision(ctx context.Context, arg closeCreativeRevisionParams) error
 	createCampaignHistory(ctx context.Context, arg createCampaignHistoryParams) error
-	toggleStatus(ctx context.Context, cid string) (StatusType, error)
+	createCampaignRevertHistory(ctx context.Context, arg createCampaignRevertHistoryParams) error
+	createCreativeRevision(ctx context.Context, arg createCreativeRevisionParams) (CreativeRevision, error)
+	createTargetListHistory(ctx context.Context, arg createTargetListHistoryParams) error
+	createWebhookDeadLetter(ctx context.Context, arg createWebhookDeadLetterParams) (WebhookDeadLetter, error)
+	createWebhookDelivery(ctx context.Context, arg createWebhookDeliveryParams) (WebhookDelivery, error)
+	deleteAudienceSegment(ctx context.Context, arg deleteAudienceSegmentParams) (int64, error)
+	deleteCampaignLabel(ctx context.Context, arg deleteCampaignLabelParams) error
+	deleteCampaignLocale(ctx context.Context, arg deleteCampaignLocaleParams) error
+	deleteCampaignSegment(ctx context.Context, arg deleteCampaignSegmentParams) ([]CampaignSegment, error)
+	deleteCampaignTargetList(ctx context.Context, arg deleteCampaignTargetListParams) ([]CampaignTargetList, error)
+	deleteTargetApp(ctx context.Context, arg deleteTargetAppParams) error
+	deleteTargetAppVersion(ctx context.Context, arg deleteTargetAppVersionParams) error
+	deleteTargetCategory(ctx context.Context, arg deleteTargetCategoryParams) error
+	deleteTargetCity(ctx context.Context, arg deleteTargetCityParams) error
+	deleteTargetCountry(ctx context.Context, arg deleteTargetCountryParams) error
+	deleteTargetKeyword(ctx context.Context, arg deleteTargetKeywordParams) error
+	deleteTargetLanguage(ctx context.Context, arg deleteTargetLanguageParams) error
+	deleteTargetList(ctx context.Context, arg deleteTargetListParams) (int64, error)
+	deleteTargetOs(ctx context.Context, arg deleteTargetOsParams) error
+	deleteTargetOsVersion(ctx context.Context, arg deleteTargetOsVersionParams) error
+	deleteTargetRadius(ctx context.Context, arg deleteTargetRadiusParams) ([]TargetRadius, error)
+	deleteTargetRadiusByID(ctx context.Context, arg deleteTargetRadiusByIDParams) ([]TargetRadius, error)
+	deleteTargetRegion(ctx context.Context, arg deleteTargetRegionParams) error
+	getAudienceSegmentByID(ctx context.Context, id int32) (AudienceSegment, error)
+	getCampaignHistoryByID(ctx context.Context, id int32) (CampaignHistory, error)
+	getPendingCreativeRevision(ctx context.Context, arg getPendingCreativeRevisionParams) (CreativeRevision, error)
+	getTargetListByID(ctx context.Context, id int32) (TargetList, error)
+	listCampaignAuditSince(ctx context.Context, arg listCampaignAuditSinceParams) ([]AuditLog, error)
+	listCampaignHistoryAfter(ctx context.Context, arg listCampaignHistoryAfterParams) ([]CampaignHistory, error)
+	purgeAdvertiserDeletedCampaigns(ctx context.Context, advertiserID int32) error
+	restoreCampaign(ctx context.Context, arg restoreCampaignParams) (Campaign, error)
+	reviewCreativeRevision(ctx context.Context, arg reviewCreativeRevisionParams) (CreativeRevision, error)
+	selectBulkCampaigns(ctx context.Context, arg selectBulkCampaignsParams) ([]selectBulkCampaignsRow, error)
+	setCampaignStatus(ctx context.Context, arg setCampaignStatusParams) (StatusType, error)
+	takeWebhookDeadLetter(ctx context.Context, arg takeWebhookDeadLetterParams) (WebhookDeadLetter, error)
+	takeWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	updateAudienceSegmentMembers(ctx context.Context, arg updateAudienceSegmentMembersParams) (AudienceSegment, error)
 	updateCampaignCta(ctx context.Context, arg updateCampaignCtaParams) (Campaign, error)
 	updateCampaignImage(ctx context.Context, arg updateCampaignImageParams) (Campaign, error)
+	updateCampaignLocale(ctx context.Context, arg updateCampaignLocaleParams) (CampaignLocale, error)
 	updateCampaignName(ctx context.Context, arg updateCampaignNameParams) (Campaign, error)
 	updateTargetApp(ctx context.Context, arg updateTargetAppParams) (TargetApp, error)
+	updateTargetAppVersion(ctx context.Context, arg updateTargetAppVersionParams) (TargetAppVersion, error)
+	updateTargetCategory(ctx context.Context, arg updateTargetCategoryParams) (TargetCategory, error)
+	updateTargetCity(ctx context.Context, arg updateTargetCityParams) (TargetCity, error)
 	updateTargetCountry(ctx context.Context, arg updateTargetCountryParams) (TargetCountry, error)
+	updateTargetKeyword(ctx context.Context, arg updateTargetKeywordParams) (TargetKeyword, error)
+	updateTargetLanguage(ctx context.Context, arg updateTargetLanguageParams) (TargetLanguage, error)
+	updateTargetList(ctx context.Context, arg updateTargetListParams) (TargetList, error)
 	updateTargetOs(ctx context.Context, arg updateTargetOsParams) (TargetOs, error)
+	updateTargetOsVersion(ctx context.Context, arg updateTargetOsVersionParams) (TargetOsVersion, error)
+	updateTargetRegion(ctx context.Context, arg updateTargetRegionParams) (TargetRegion, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,336 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+const (
+	segmentBatchSize = 1000
+	// bloomChunkSize keeps each Redis write of a bloom bitmap to a few megabytes.
+	bloomChunkSize                = 4 << 20
+	defaultBloomFalsePositiveRate = 0.001
+)
+
+func segmentMembersKey(id int32) string {
+	return fmt.Sprintf("segment:%d:members", id)
+}
+
+// SegmentMemberError reports an invalid line in an uploaded segment file.
+type SegmentMemberError struct {
+	Line int
+	Err  error
+}
+
+func (e *SegmentMemberError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *SegmentMemberError) Unwrap() error {
+	return e.Err
+}
+
+type UploadSegmentParams struct {
+	AdvertiserID    int32          `json:"advertiser_id"`
+	ID              int32          `json:"id"`
+	Storage         SegmentStorage `json:"storage"`
+	ExpectedMembers uint64         `json:"expected_members"`
+	Members         io.Reader      `json:"-"`
+}
+
+// UploadSegment replaces the members of a segment with the hashed IDs read
+// line by line from arg.Members. The new members are written under a temporary
+// key and renamed over the live one, so deliveries never see a partial upload.
+func (store *SQLStore) UploadSegment(ctx context.Context, arg UploadSegmentParams) (AudienceSegment, error) {
+	if _, err := store.GetAudienceSegment(ctx, GetAudienceSegmentParams{AdvertiserID: arg.AdvertiserID, ID: arg.ID}); err != nil {
+		return AudienceSegment{}, err
+	}
+
+	liveKey := segmentMembersKey(arg.ID)
+	tmpKey := fmt.Sprintf("%s:upload:%s", liveKey, util.RandomString(8))
+	defer store.rClient.Del(ctx, tmpKey)
+
+	update := updateAudienceSegmentMembersParams{
+		ID:      arg.ID,
+		Storage: arg.Storage,
+	}
+
+	var err error
+	if arg.Storage == SegmentStorageBloom {
+		var filter util.BloomFilter
+		filter, update.MemberCount, err = store.uploadBloom(ctx, tmpKey, arg)
+		update.BloomBits = int64(filter.Bits)
+		update.BloomHashes = int32(filter.Hashes)
+	} else {
+		update.Storage = SegmentStorageSet
+		update.MemberCount, err = store.uploadSet(ctx, tmpKey, arg.Members)
+	}
+	if err != nil {
+		return AudienceSegment{}, err
+	}
+
+	if update.MemberCount == 0 {
+		err = store.rClient.Del(ctx, liveKey).Err()
+	} else {
+		err = store.rClient.Rename(ctx, tmpKey, liveKey).Err()
+	}
+	if err != nil {
+		return AudienceSegment{}, err
+	}
+
+	segment, err := store.updateAudienceSegmentMembers(ctx, update)
+	if err != nil {
+		return AudienceSegment{}, err
+	}
+	store.invalidateAudienceSegment(ctx, arg.ID)
+	return segment, nil
+}
+
+// scanMembers calls fn with batches of normalized IDs, skipping blank lines.
+func scanMembers(r io.Reader, fn func(batch []string) error) error {
+	scanner := bufio.NewScanner(r)
+	batch := make([]string, 0, segmentBatchSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		id, err := util.NormalizeHashedID(scanner.Text())
+		if err != nil {
+			return &SegmentMemberError{Line: line, Err: err}
+		}
+
+		batch = append(batch, id)
+		if len(batch) == segmentBatchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+func (store *SQLStore) uploadSet(ctx context.Context, key string, members io.Reader) (int64, error) {
+	err := scanMembers(members, func(batch []string) error {
+		values := make([]interface{}, len(batch))
+		for i, id := range batch {
+			values[i] = id
+		}
+		return store.rClient.SAdd(ctx, key, values...).Err()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return store.rClient.SCard(ctx, key).Result()
+}
+
+func (store *SQLStore) uploadBloom(ctx context.Context, key string, arg UploadSegmentParams) (util.BloomFilter, int64, error) {
+	filter := util.NewBloomFilter(arg.ExpectedMembers, defaultBloomFalsePositiveRate)
+	bitmap := filter.NewBitmap()
+
+	var count int64
+	err := scanMembers(arg.Members, func(batch []string) error {
+		for _, id := range batch {
+			filter.Add(bitmap, id)
+		}
+		count += int64(len(batch))
+		return nil
+	})
+	if err != nil {
+		return util.BloomFilter{}, 0, err
+	}
+
+	for offset := 0; offset < len(bitmap); offset += bloomChunkSize {
+		end := min(offset+bloomChunkSize, len(bitmap))
+		if err := store.rClient.SetRange(ctx, key, int64(offset), string(bitmap[offset:end])).Err(); err != nil {
+			return util.BloomFilter{}, 0, err
+		}
+	}
+	return filter, count, nil
+}
+
+func (store *SQLStore) isSegmentMember(ctx context.Context, segment *AudienceSegment, userID string) (bool, error) {
+	key := segmentMembersKey(segment.ID)
+
+	if segment.Storage != SegmentStorageBloom {
+		return store.rClient.SIsMember(ctx, key, userID).Result()
+	}
+
+	if segment.BloomBits == 0 {
+		return false, nil
+	}
+	filter := util.BloomFilter{Bits: uint64(segment.BloomBits), Hashes: uint32(segment.BloomHashes)}
+
+	pipe := store.rClient.Pipeline()
+	bits := make([]*redis.IntCmd, 0, filter.Hashes)
+	for _, offset := range filter.Locations(userID) {
+		bits = append(bits, pipe.GetBit(ctx, key, int64(offset)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	for _, bit := range bits {
+		if bit.Val() == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (store *SQLStore) getCachedCampaignSegments(ctx context.Context, key CampaignKey) (*[]CampaignSegment, error) {
+	return getCached(ctx, store, fmt.Sprintf("campaign_segment:%s", key), func(ctx context.Context) ([]CampaignSegment, error) {
+		return store.ListCampaignSegments(ctx, ListCampaignSegmentsParams(key))
+	})
+}
+
+func (store *SQLStore) getCachedAudienceSegment(ctx context.Context, id int32) (*AudienceSegment, error) {
+	return getCached(ctx, store, fmt.Sprintf("audience_segment:%d", id), func(ctx context.Context) (AudienceSegment, error) {
+		return store.getAudienceSegmentByID(ctx, id)
+	})
+}
+
+// filterBySegments drops campaigns whose segment rules reject userID. Without
+// a user ID, campaigns that include a segment are never delivered.
+func (store *SQLStore) filterBySegments(ctx context.Context, results []DeliveryResult, userID string) ([]DeliveryResult, error) {
+	userID = strings.ToLower(strings.TrimSpace(userID))
+
+	filtered := []DeliveryResult{}
+	for _, result := range results {
+		refs, err := store.getCachedCampaignSegments(ctx, result.Key())
+		if err != nil {
+			return []DeliveryResult{}, err
+		}
+
+		matches := true
+		for _, ref := range *refs {
+			member := false
+			if userID != "" {
+				segment, err := store.getCachedAudienceSegment(ctx, ref.SegmentID)
+				if err != nil {
+					return []DeliveryResult{}, err
+				}
+				member, err = store.isSegmentMember(ctx, segment, userID)
+				if err != nil {
+					return []DeliveryResult{}, err
+				}
+			}
+
+			if (ref.Rule == RuleTypeInclude && !member) || (ref.Rule == RuleTypeExclude && member) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered, nil
+}
+
+func (store *SQLStore) invalidateAudienceSegment(ctx context.Context, id int32) {
+	cacheKey := fmt.Sprintf("audience_segment:%d", id)
+	if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+	}
+}
+
+type DeleteAudienceSegmentParams struct {
+	AdvertiserID int32 `json:"advertiser_id"`
+	ID           int32 `json:"id"`
+}
+
+// DeleteAudienceSegment deletes a segment of the advertiser with its members.
+func (store *SQLStore) DeleteAudienceSegment(ctx context.Context, arg DeleteAudienceSegmentParams) error {
+	deleted, err := store.deleteAudienceSegment(ctx, deleteAudienceSegmentParams(arg))
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if err := store.rClient.Del(ctx, segmentMembersKey(arg.ID)).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", segmentMembersKey(arg.ID), err)
+	}
+	store.invalidateAudienceSegment(ctx, arg.ID)
+	return nil
+}
+
+type AddCampaignSegmentParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	SegmentID    int32    `json:"segment_id"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddCampaignSegment(ctx context.Context, arg AddCampaignSegmentParams) (CampaignSegment, error) {
+	var ref CampaignSegment
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	err := store.updateCampaignTx(ctx, key, arg.Version, func(q *Queries) error {
+		var err error
+		ref, err = q.addCampaignSegment(ctx, addCampaignSegmentParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			SegmentID:    arg.SegmentID,
+			Rule:         arg.Rule,
+		})
+		if err != nil {
+			return err
+		}
+		return createRowsHistory(ctx, q, key, segmentsHistoryField, nil, []CampaignSegment{ref})
+	})
+	if err == nil {
+		store.invalidateCampaignSegments(ctx, key)
+	}
+	return ref, err
+}
+
+type DeleteCampaignSegmentParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	SegmentID    int32  `json:"segment_id"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteCampaignSegment(ctx context.Context, arg DeleteCampaignSegmentParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	err := store.updateCampaignTx(ctx, key, arg.Version, func(q *Queries) error {
+		deleted, err := q.deleteCampaignSegment(ctx, deleteCampaignSegmentParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			SegmentID:    arg.SegmentID,
+		})
+		if err != nil {
+			return err
+		}
+		return createRowsHistory(ctx, q, key, segmentsHistoryField, deleted, nil)
+	})
+	if err == nil {
+		store.invalidateCampaignSegments(ctx, key)
+	}
+	return err
+}
+
+func (store *SQLStore) invalidateCampaignSegments(ctx context.Context, key CampaignKey) {
+	cacheKey := fmt.Sprintf("campaign_segment:%s", key)
+	if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+	}
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+// ErrSessionNotFound is returned for unknown and expired session tokens.
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session is a short-lived login to the management API. Sessions live only in
+// Redis and cannot be refreshed; they expire with their Redis key.
+type Session struct {
+	Subject      string    `json:"subject"`
+	Role         KeyRole   `json:"role"`
+	AdvertiserID int32     `json:"advertiser_id"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// CreateSessionResult carries the plaintext session token, which is stored
+// only as a hash.
+type CreateSessionResult struct {
+	Session Session `json:"session"`
+	Token   string  `json:"token"`
+}
+
+func sessionKey(token string) string {
+	return fmt.Sprintf("session:%s", util.HashAPIKey(token))
+}
+
+// CreateSession issues a session token valid until arg.ExpiresAt.
+func (store *SQLStore) CreateSession(ctx context.Context, arg Session) (CreateSessionResult, error) {
+	ttl := time.Until(arg.ExpiresAt)
+	if ttl <= 0 {
+		return CreateSessionResult{}, errors.New("session expires in the past")
+	}
+
+	token, err := util.NewSessionToken()
+	if err != nil {
+		return CreateSessionResult{}, err
+	}
+
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return CreateSessionResult{}, err
+	}
+	if err := store.rClient.Set(ctx, sessionKey(token), data, ttl).Err(); err != nil {
+		return CreateSessionResult{}, err
+	}
+	return CreateSessionResult{Session: arg, Token: token}, nil
+}
+
+// AuthenticateSession resolves a session token. Unknown and expired tokens
+// return ErrSessionNotFound.
+func (store *SQLStore) AuthenticateSession(ctx context.Context, token string) (Session, error) {
+	data, err := store.rClient.Get(ctx, sessionKey(token)).Bytes()
+	if err == redis.Nil {
+		return Session{}, ErrSessionNotFound
+	}
+	if err != nil {
+		return Session{}, err
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return Session{}, err
+	}
+	return session, nil
+}
+
+// DeleteSession ends a session before it expires.
+func (store *SQLStore) DeleteSession(ctx context.Context, token string) error {
+	return store.rClient.Del(ctx, sessionKey(token)).Err()
+}
@@ -0,0 +1,63 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func createRandomSession(t *testing.T, duration time.Duration) db.CreateSessionResult {
+	arg := db.Session{
+		Subject:      util.RandomName(),
+		Role:         db.KeyRoleEditor,
+		AdvertiserID: testAdvertiser.ID,
+		ExpiresAt:    time.Now().Add(duration),
+	}
+
+	result, err := testStore.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(result.Token, util.SessionTokenPrefix))
+	require.Equal(t, arg, result.Session)
+
+	return result
+}
+
+func TestAuthenticateSession(t *testing.T) {
+	result := createRandomSession(t, time.Minute)
+
+	session, err := testStore.AuthenticateSession(context.Background(), result.Token)
+	require.NoError(t, err)
+	require.Equal(t, result.Session.Subject, session.Subject)
+	require.Equal(t, result.Session.Role, session.Role)
+	require.Equal(t, result.Session.AdvertiserID, session.AdvertiserID)
+	require.WithinDuration(t, result.Session.ExpiresAt, session.ExpiresAt, time.Second)
+
+	_, err = testStore.AuthenticateSession(context.Background(), result.Token+"0")
+	require.ErrorIs(t, err, db.ErrSessionNotFound)
+
+	testStore.DeleteSession(context.Background(), result.Token)
+}
+
+func TestSessionExpires(t *testing.T) {
+	result := createRandomSession(t, time.Second)
+
+	time.Sleep(1100 * time.Millisecond)
+
+	_, err := testStore.AuthenticateSession(context.Background(), result.Token)
+	require.ErrorIs(t, err, db.ErrSessionNotFound)
+}
+
+func TestDeleteSession(t *testing.T) {
+	result := createRandomSession(t, time.Minute)
+
+	err := testStore.DeleteSession(context.Background(), result.Token)
+	require.NoError(t, err)
+
+	_, err = testStore.AuthenticateSession(context.Background(), result.Token)
+	require.ErrorIs(t, err, db.ErrSessionNotFound)
+}
@@ -3,40 +3,120 @@ package db
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
+	"github.com/vivek-344/AdRouter/util"
 )
 
+// ErrVersionMismatch is returned by updates that expect a campaign to be at a
+// version it is no longer at.
+var ErrVersionMismatch = errors.New("campaign has been modified since that version")
+
 type Store interface {
 	Querier
 	Delivery(ctx context.Context, arg DeliveryParams) ([]DeliveryResult, error)
 	CreateCampaign(tx context.Context, arg CreateCampaignParams) (CreateCampaignResult, error)
-	ReadCampaign(ctx context.Context, cid string) (CompleteCampaign, error)
-	ToggleStatus(ctx context.Context, cid string) error
+	ReadCampaign(ctx context.Context, key CampaignKey) (CompleteCampaign, error)
+	ReadCampaignAsOf(ctx context.Context, key CampaignKey, asOf time.Time) (CompleteCampaign, error)
+	SearchCampaigns(ctx context.Context, arg SearchCampaignsParams) (SearchCampaignsResult, error)
+	RevertCampaign(ctx context.Context, arg RevertCampaignParams) (RevertCampaignResult, error)
+	PatchCampaign(ctx context.Context, arg PatchCampaignParams) (CompleteCampaign, error)
+	ImportCampaigns(ctx context.Context, arg ImportCampaignsParams) (ImportCampaignsResult, error)
+	CloneCampaign(ctx context.Context, arg CloneCampaignParams) (CompleteCampaign, error)
+	ExportCampaigns(ctx context.Context, advertiserID int32, fn func(CompleteCampaign) error) error
+	UpdateCampaignLabels(ctx context.Context, arg UpdateCampaignLabelsParams) ([]string, error)
+	BulkUpdateCampaigns(ctx context.Context, arg BulkUpdateCampaignsParams) (BulkUpdateCampaignsResult, error)
+	ToggleStatus(ctx context.Context, key CampaignKey, version int32) error
+	TransitionCampaign(ctx context.Context, arg TransitionCampaignParams) (Campaign, error)
+	DeleteVersionedCampaign(ctx context.Context, key CampaignKey, version int32) error
+	RestoreCampaign(ctx context.Context, key CampaignKey) (Campaign, error)
 	UpdateCampaignName(ctx context.Context, arg UpdateCampaignNameParams) (Campaign, error)
-	UpdateCampaignCta(ctx context.Context, arg UpdateCampaignCtaParams) (Campaign, error)
-	UpdateCampaignImage(ctx context.Context, arg UpdateCampaignImageParams) (Campaign, error)
+	UpdateCampaignCta(ctx context.Context, arg UpdateCampaignCtaParams) (CreativeUpdateResult, error)
+	UpdateCampaignImage(ctx context.Context, arg UpdateCampaignImageParams) (CreativeUpdateResult, error)
+	ReviewCreativeRevision(ctx context.Context, arg ReviewCreativeRevisionParams) (CreativeRevision, error)
+	AddTargetApp(ctx context.Context, arg AddTargetAppParams) (TargetApp, error)
 	UpdateTargetApp(ctx context.Context, arg UpdateTargetAppParams) (TargetApp, error)
+	DeleteTargetApp(ctx context.Context, arg DeleteTargetAppParams) error
+	AddTargetCountry(ctx context.Context, arg AddTargetCountryParams) (TargetCountry, error)
 	UpdateTargetCountry(ctx context.Context, arg UpdateTargetCountryParams) (TargetCountry, error)
+	DeleteTargetCountry(ctx context.Context, arg DeleteTargetCountryParams) error
+	AddTargetOs(ctx context.Context, arg AddTargetOsParams) (TargetOs, error)
 	UpdateTargetOs(ctx context.Context, arg UpdateTargetOsParams) (TargetOs, error)
+	DeleteTargetOs(ctx context.Context, arg DeleteTargetOsParams) error
+	AddTargetOsVersion(ctx context.Context, arg AddTargetOsVersionParams) (TargetOsVersion, error)
+	UpdateTargetOsVersion(ctx context.Context, arg UpdateTargetOsVersionParams) (TargetOsVersion, error)
+	DeleteTargetOsVersion(ctx context.Context, arg DeleteTargetOsVersionParams) error
+	AddTargetAppVersion(ctx context.Context, arg AddTargetAppVersionParams) (TargetAppVersion, error)
+	UpdateTargetAppVersion(ctx context.Context, arg UpdateTargetAppVersionParams) (TargetAppVersion, error)
+	DeleteTargetAppVersion(ctx context.Context, arg DeleteTargetAppVersionParams) error
+	AddTargetLanguage(ctx context.Context, arg AddTargetLanguageParams) (TargetLanguage, error)
+	UpdateTargetLanguage(ctx context.Context, arg UpdateTargetLanguageParams) (TargetLanguage, error)
+	DeleteTargetLanguage(ctx context.Context, arg DeleteTargetLanguageParams) error
+	AddCampaignLocale(ctx context.Context, arg AddCampaignLocaleParams) (LocaleUpdateResult, error)
+	UpdateCampaignLocale(ctx context.Context, arg UpdateCampaignLocaleParams) (LocaleUpdateResult, error)
+	DeleteCampaignLocale(ctx context.Context, arg DeleteCampaignLocaleParams) error
+	AddTargetRegion(ctx context.Context, arg AddTargetRegionParams) (TargetRegion, error)
+	UpdateTargetRegion(ctx context.Context, arg UpdateTargetRegionParams) (TargetRegion, error)
+	DeleteTargetRegion(ctx context.Context, arg DeleteTargetRegionParams) error
+	AddTargetCity(ctx context.Context, arg AddTargetCityParams) (TargetCity, error)
+	UpdateTargetCity(ctx context.Context, arg UpdateTargetCityParams) (TargetCity, error)
+	DeleteTargetCity(ctx context.Context, arg DeleteTargetCityParams) error
+	AddTargetRadius(ctx context.Context, arg AddTargetRadiusParams) (TargetRadius, error)
+	AddTargetRadiusCircles(ctx context.Context, arg AddTargetRadiusCirclesParams) ([]TargetRadius, error)
+	DeleteTargetRadius(ctx context.Context, arg DeleteTargetRadiusParams) error
+	DeleteTargetRadiusByID(ctx context.Context, arg DeleteTargetRadiusByIDParams) error
+	AddCampaignTargetList(ctx context.Context, arg AddCampaignTargetListParams) (CampaignTargetList, error)
+	DeleteCampaignTargetList(ctx context.Context, arg DeleteCampaignTargetListParams) error
+	UpdateTargetList(ctx context.Context, arg UpdateTargetListParams) (TargetList, error)
+	DeleteTargetList(ctx context.Context, arg DeleteTargetListParams) error
+	UploadSegment(ctx context.Context, arg UploadSegmentParams) (AudienceSegment, error)
+	DeleteAudienceSegment(ctx context.Context, arg DeleteAudienceSegmentParams) error
+	AddCampaignSegment(ctx context.Context, arg AddCampaignSegmentParams) (CampaignSegment, error)
+	DeleteCampaignSegment(ctx context.Context, arg DeleteCampaignSegmentParams) error
+	AddTargetCategory(ctx context.Context, arg AddTargetCategoryParams) (TargetCategory, error)
+	UpdateTargetCategory(ctx context.Context, arg UpdateTargetCategoryParams) (TargetCategory, error)
+	DeleteTargetCategory(ctx context.Context, arg DeleteTargetCategoryParams) error
+	AddTargetKeyword(ctx context.Context, arg AddTargetKeywordParams) (TargetKeyword, error)
+	UpdateTargetKeyword(ctx context.Context, arg UpdateTargetKeywordParams) (TargetKeyword, error)
+	DeleteTargetKeyword(ctx context.Context, arg DeleteTargetKeywordParams) error
+	ImportAppMetadata(ctx context.Context, args []UpsertAppMetadataParams) ([]AppMetadata, error)
+	MintApiKey(ctx context.Context, arg MintApiKeyParams) (MintApiKeyResult, error)
+	BootstrapAdminKey(ctx context.Context, name string) (MintApiKeyResult, error)
+	AuthenticateApiKey(ctx context.Context, key string) (ApiKey, error)
+	CreateSession(ctx context.Context, arg Session) (CreateSessionResult, error)
+	AuthenticateSession(ctx context.Context, token string) (Session, error)
+	DeleteSession(ctx context.Context, token string) error
+	ReserveIdempotencyKey(ctx context.Context, scope string, key string, fingerprint string) (*IdempotentResponse, error)
+	SaveIdempotentResponse(ctx context.Context, scope string, key string, response IdempotentResponse) error
+	ReleaseIdempotencyKey(ctx context.Context, scope string, key string) error
+	RefreshIdempotencyKey(ctx context.Context, scope string, key string) error
+	DeadLetterWebhookDelivery(ctx context.Context, id int64, lastError string) error
+	RedeliverWebhook(ctx context.Context, arg RedeliverWebhookParams) (WebhookDelivery, error)
+	SubscribeChanges(ctx context.Context, arg SubscribeChangesParams) (ChangeSubscription, error)
 }
 
 type SQLStore struct {
 	*Queries
-	db      *pgxpool.Pool
-	rClient *redis.Client
+	db       *pgxpool.Pool
+	rClient  *redis.Client
+	geoGrids *geoGridCache
+	changes  *changeFeed
 }
 
 func NewStore(db *pgxpool.Pool, rClient *redis.Client) Store {
 	return &SQLStore{
-		db:      db,
-		Queries: New(db),
-		rClient: rClient,
+		db:       db,
+		Queries:  New(db),
+		rClient:  rClient,
+		geoGrids: newGeoGridCache(),
+		changes:  newChangeFeed(),
 	}
 }
 
@@ -86,16 +166,136 @@ func shouldInclude(csv string, arg string, rule string) bool {
 	return true
 }
 
+// shouldIncludeVersion applies an include/exclude rule to a version range
+// expression, such as ">=10" or "15.0-17.x".
+func shouldIncludeVersion(expr string, version string, rule string) bool {
+	matches := util.MatchVersion(expr, version)
+
+	if (rule == "include" && !matches) || (rule == "exclude" && matches) {
+		return false
+	}
+	return true
+}
+
+// shouldIncludeLanguage matches a language csv such as "en, pt" against lang
+// and its fallback chain, so a "pt" target also covers "pt-BR".
+func shouldIncludeLanguage(csv string, lang string, rule string) bool {
+	list := csvToSlice(csv)
+	matches := false
+	for _, tag := range util.LocaleFallbackChain(lang) {
+		if contains(list, tag) {
+			matches = true
+			break
+		}
+	}
+
+	if (rule == "include" && !matches) || (rule == "exclude" && matches) {
+		return false
+	}
+	return true
+}
+
+// shouldIncludeCategory matches a category csv against the categories of the
+// requesting app. Parent categories cover their children, so IAB1 matches IAB1-2.
+func shouldIncludeCategory(csv string, categories string, rule string) bool {
+	matches := false
+	for _, target := range csvToSlice(csv) {
+		for _, category := range csvToSlice(categories) {
+			if util.MatchCategory(target, category) {
+				matches = true
+			}
+		}
+	}
+
+	if (rule == "include" && !matches) || (rule == "exclude" && matches) {
+		return false
+	}
+	return true
+}
+
+// shouldIncludeKeyword matches a keyword csv against the tags of the requesting app.
+func shouldIncludeKeyword(csv string, tags string, rule string) bool {
+	list := csvToSlice(csv)
+	matches := false
+	for _, tag := range csvToSlice(tags) {
+		if tag != "" && contains(list, tag) {
+			matches = true
+			break
+		}
+	}
+
+	if (rule == "include" && !matches) || (rule == "exclude" && matches) {
+		return false
+	}
+	return true
+}
+
+// localize swaps in the campaign's most specific locale override for lang,
+// following the fallback chain (pt-BR -> pt -> default). Img and cta fall back
+// independently, so a pt-BR cta can be served with a pt image.
+func localize(result *DeliveryResult, locales []CampaignLocale, lang string) {
+	var img, cta string
+	for _, tag := range util.LocaleFallbackChain(lang) {
+		for _, locale := range locales {
+			if !strings.EqualFold(locale.Locale, tag) {
+				continue
+			}
+			if img == "" {
+				img = locale.Img
+			}
+			if cta == "" {
+				cta = locale.Cta
+			}
+		}
+	}
+
+	if img != "" {
+		result.Img = img
+	}
+	if cta != "" {
+		result.Cta = cta
+	}
+}
+
 type DeliveryParams struct {
-	AppID   string `json:"app_id"`
-	Country string `json:"country"`
-	Os      string `json:"os"`
+	AppID      string   `json:"app_id"`
+	Country    string   `json:"country"`
+	Os         string   `json:"os"`
+	OsVersion  string   `json:"os_version"`
+	AppVersion string   `json:"app_version"`
+	Lang       string   `json:"lang"`
+	Region     string   `json:"region"`
+	City       string   `json:"city"`
+	Lat        *float64 `json:"lat"`
+	Lon        *float64 `json:"lon"`
+	UserID     string   `json:"user_id"`
 }
 
 type DeliveryResult struct {
-	Cid string `json:"cid"`
-	Img string `json:"img"`
-	Cta string `json:"cta"`
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Img          string `json:"img"`
+	Cta          string `json:"cta"`
+}
+
+func (result DeliveryResult) Key() CampaignKey {
+	return CampaignKey{AdvertiserID: result.AdvertiserID, Cid: result.Cid}
+}
+
+// CampaignKey identifies a campaign. Cids are chosen by advertisers and are
+// only unique within one, so every campaign lookup carries both. It converts
+// directly to the generated params of queries keyed on the pair.
+type CampaignKey struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+}
+
+func (key CampaignKey) String() string {
+	return fmt.Sprintf("%d:%s", key.AdvertiserID, key.Cid)
+}
+
+func (campaign Campaign) Key() CampaignKey {
+	return CampaignKey{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid}
 }
 
 // Helper functions for caching query results
@@ -135,22 +335,23 @@ func (store *SQLStore) getCachedActiveCampaigns(ctx context.Context) ([]Campaign
 	return campaigns, nil
 }
 
-func (store *SQLStore) getCachedTargetApp(ctx context.Context, cid string) (*TargetApp, error) {
-	cacheKey := fmt.Sprintf("target_app:%s", cid)
-	var targetApp TargetApp
+// getCached reads a campaign's targeting data through the Redis cache.
+// Missing rows are cached as "null" so repeated lookups skip the database.
+func getCached[T any](ctx context.Context, store *SQLStore, cacheKey string, fetch func(context.Context) (T, error)) (*T, error) {
+	var target T
 
 	cachedData, err := store.rClient.Get(ctx, cacheKey).Bytes()
 	if err == nil {
-		err = json.Unmarshal(cachedData, &targetApp)
+		err = json.Unmarshal(cachedData, &target)
 		if err == nil {
-			return &targetApp, nil
+			return &target, nil
 		}
-		fmt.Printf("Redis JSON unmarshal error for target app: %v\n", err)
+		fmt.Printf("Redis JSON unmarshal error for %s: %v\n", cacheKey, err)
 	} else if err != redis.Nil {
-		fmt.Printf("Redis Get error for target app: %v\n", err)
+		fmt.Printf("Redis Get error for %s: %v\n", cacheKey, err)
 	}
 
-	result, err := store.GetTargetApp(ctx, cid)
+	result, err := fetch(ctx)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			store.rClient.Set(ctx, cacheKey, "null", 15*time.Minute)
@@ -161,93 +362,100 @@ func (store *SQLStore) getCachedTargetApp(ctx context.Context, cid string) (*Tar
 
 	jsonData, err := json.Marshal(result)
 	if err != nil {
-		fmt.Printf("JSON marshal error for target app: %v\n", err)
+		fmt.Printf("JSON marshal error for %s: %v\n", cacheKey, err)
 	} else {
 		err = store.rClient.Set(ctx, cacheKey, jsonData, 15*time.Minute).Err()
 		if err != nil {
-			fmt.Printf("Redis Set error for target app: %v\n", err)
+			fmt.Printf("Redis Set error for %s: %v\n", cacheKey, err)
 		}
 	}
 
 	return &result, nil
 }
 
-func (store *SQLStore) getCachedTargetCountry(ctx context.Context, cid string) (*TargetCountry, error) {
-	cacheKey := fmt.Sprintf("target_country:%s", cid)
-	var targetCountry TargetCountry
+func (store *SQLStore) getCachedTargetApp(ctx context.Context, key CampaignKey) (*TargetApp, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_app:%s", key), func(ctx context.Context) (TargetApp, error) {
+		return store.GetTargetApp(ctx, GetTargetAppParams(key))
+	})
+}
 
-	cachedData, err := store.rClient.Get(ctx, cacheKey).Bytes()
-	if err == nil {
-		err = json.Unmarshal(cachedData, &targetCountry)
-		if err == nil {
-			return &targetCountry, nil
-		}
-		fmt.Printf("Redis JSON unmarshal error for target country: %v\n", err)
-	} else if err != redis.Nil {
-		fmt.Printf("Redis Get error for target country: %v\n", err)
-	}
+func (store *SQLStore) getCachedTargetCountry(ctx context.Context, key CampaignKey) (*TargetCountry, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_country:%s", key), func(ctx context.Context) (TargetCountry, error) {
+		return store.GetTargetCountry(ctx, GetTargetCountryParams(key))
+	})
+}
 
-	result, err := store.GetTargetCountry(ctx, cid)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			store.rClient.Set(ctx, cacheKey, "null", 15*time.Minute)
-			return nil, err
-		}
-		return nil, err
-	}
+func (store *SQLStore) getCachedTargetOs(ctx context.Context, key CampaignKey) (*TargetOs, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_os:%s", key), func(ctx context.Context) (TargetOs, error) {
+		return store.GetTargetOs(ctx, GetTargetOsParams(key))
+	})
+}
 
-	jsonData, err := json.Marshal(result)
-	if err != nil {
-		fmt.Printf("JSON marshal error for target country: %v\n", err)
-	} else {
-		err = store.rClient.Set(ctx, cacheKey, jsonData, 15*time.Minute).Err()
-		if err != nil {
-			fmt.Printf("Redis Set error for target country: %v\n", err)
-		}
-	}
+func (store *SQLStore) getCachedTargetOsVersion(ctx context.Context, key CampaignKey) (*TargetOsVersion, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_os_version:%s", key), func(ctx context.Context) (TargetOsVersion, error) {
+		return store.GetTargetOsVersion(ctx, GetTargetOsVersionParams(key))
+	})
+}
 
-	return &result, nil
+func (store *SQLStore) getCachedTargetAppVersion(ctx context.Context, key CampaignKey) (*TargetAppVersion, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_app_version:%s", key), func(ctx context.Context) (TargetAppVersion, error) {
+		return store.GetTargetAppVersion(ctx, GetTargetAppVersionParams(key))
+	})
 }
 
-func (store *SQLStore) getCachedTargetOs(ctx context.Context, cid string) (*TargetOs, error) {
-	cacheKey := fmt.Sprintf("target_os:%s", cid)
-	var targetOs TargetOs
+func (store *SQLStore) getCachedTargetLanguage(ctx context.Context, key CampaignKey) (*TargetLanguage, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_language:%s", key), func(ctx context.Context) (TargetLanguage, error) {
+		return store.GetTargetLanguage(ctx, GetTargetLanguageParams(key))
+	})
+}
 
-	cachedData, err := store.rClient.Get(ctx, cacheKey).Bytes()
-	if err == nil {
-		err = json.Unmarshal(cachedData, &targetOs)
-		if err == nil {
-			return &targetOs, nil
-		}
-		fmt.Printf("Redis JSON unmarshal error for target os: %v\n", err)
-	} else if err != redis.Nil {
-		fmt.Printf("Redis Get error for target os: %v\n", err)
-	}
+func (store *SQLStore) getCachedCampaignLocales(ctx context.Context, key CampaignKey) (*[]CampaignLocale, error) {
+	return getCached(ctx, store, fmt.Sprintf("campaign_locale:%s", key), func(ctx context.Context) ([]CampaignLocale, error) {
+		return store.ListCampaignLocales(ctx, ListCampaignLocalesParams(key))
+	})
+}
 
-	result, err := store.GetTargetOs(ctx, cid)
+func (store *SQLStore) getCachedTargetRegion(ctx context.Context, key CampaignKey) (*TargetRegion, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_region:%s", key), func(ctx context.Context) (TargetRegion, error) {
+		return store.GetTargetRegion(ctx, GetTargetRegionParams(key))
+	})
+}
+
+func (store *SQLStore) getCachedTargetCity(ctx context.Context, key CampaignKey) (*TargetCity, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_city:%s", key), func(ctx context.Context) (TargetCity, error) {
+		return store.GetTargetCity(ctx, GetTargetCityParams(key))
+	})
+}
+
+func (store *SQLStore) getCachedTargetCategory(ctx context.Context, key CampaignKey) (*TargetCategory, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_category:%s", key), func(ctx context.Context) (TargetCategory, error) {
+		return store.GetTargetCategory(ctx, GetTargetCategoryParams(key))
+	})
+}
+
+func (store *SQLStore) getCachedTargetKeyword(ctx context.Context, key CampaignKey) (*TargetKeyword, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_keyword:%s", key), func(ctx context.Context) (TargetKeyword, error) {
+		return store.GetTargetKeyword(ctx, GetTargetKeywordParams(key))
+	})
+}
+
+func (store *SQLStore) Delivery(ctx context.Context, arg DeliveryParams) ([]DeliveryResult, error) {
+	results, err := store.cachedDelivery(ctx, arg)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			store.rClient.Set(ctx, cacheKey, "null", 15*time.Minute)
-			return nil, err
-		}
-		return nil, err
+		return []DeliveryResult{}, err
 	}
 
-	jsonData, err := json.Marshal(result)
+	// Radius targets and segments depend on per-user values, so rather than
+	// being part of the cache key they are checked after the cached lookup.
+	results, err = store.filterByRadius(ctx, results, arg.Lat, arg.Lon)
 	if err != nil {
-		fmt.Printf("JSON marshal error for target os: %v\n", err)
-	} else {
-		err = store.rClient.Set(ctx, cacheKey, jsonData, 15*time.Minute).Err()
-		if err != nil {
-			fmt.Printf("Redis Set error for target os: %v\n", err)
-		}
+		return []DeliveryResult{}, err
 	}
-
-	return &result, nil
+	return store.filterBySegments(ctx, results, arg.UserID)
 }
 
-func (store *SQLStore) Delivery(ctx context.Context, arg DeliveryParams) ([]DeliveryResult, error) {
-	cacheKey := fmt.Sprintf("delivery:%s:%s:%s", arg.AppID, arg.Country, arg.Os)
+func (store *SQLStore) cachedDelivery(ctx context.Context, arg DeliveryParams) ([]DeliveryResult, error) {
+	cacheKey := fmt.Sprintf("delivery:%s:%s:%s:%s:%s:%s:%s:%s", arg.AppID, arg.Country, arg.Os, arg.OsVersion, arg.AppVersion, arg.Lang, arg.Region, arg.City)
 	var results []DeliveryResult
 
 	cachedData, err := store.rClient.Get(ctx, cacheKey).Bytes()
@@ -266,9 +474,25 @@ func (store *SQLStore) Delivery(ctx context.Context, arg DeliveryParams) ([]Deli
 		return []DeliveryResult{}, err
 	}
 
+	app, err := store.resolveApp(ctx, arg.AppID)
+	if err != nil {
+		return []DeliveryResult{}, err
+	}
+
+	advertisers, err := store.getCachedPublisherAdvertisers(ctx, arg.AppID)
+	if err != nil {
+		return []DeliveryResult{}, err
+	}
+
 	var campaigns []Campaign
 	for _, campaign := range active_campaigns {
-		target_app, err := store.getCachedTargetApp(ctx, campaign.Cid)
+		if !allowsAdvertiser(*advertisers, campaign.AdvertiserID) {
+			continue
+		}
+
+		key := CampaignKey{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid}
+
+		target_app, err := store.getCachedTargetApp(ctx, key)
 		if err != nil && err != pgx.ErrNoRows {
 			return []DeliveryResult{}, err
 		}
@@ -278,7 +502,7 @@ func (store *SQLStore) Delivery(ctx context.Context, arg DeliveryParams) ([]Deli
 			}
 		}
 
-		target_country, err := store.getCachedTargetCountry(ctx, campaign.Cid)
+		target_country, err := store.getCachedTargetCountry(ctx, key)
 		if err != nil && err != pgx.ErrNoRows {
 			return []DeliveryResult{}, err
 		}
@@ -288,7 +512,7 @@ func (store *SQLStore) Delivery(ctx context.Context, arg DeliveryParams) ([]Deli
 			}
 		}
 
-		target_os, err := store.getCachedTargetOs(ctx, campaign.Cid)
+		target_os, err := store.getCachedTargetOs(ctx, key)
 		if err != nil && err != pgx.ErrNoRows {
 			return []DeliveryResult{}, err
 		}
@@ -298,16 +522,105 @@ func (store *SQLStore) Delivery(ctx context.Context, arg DeliveryParams) ([]Deli
 			}
 		}
 
+		target_os_version, err := store.getCachedTargetOsVersion(ctx, key)
+		if err != nil && err != pgx.ErrNoRows {
+			return []DeliveryResult{}, err
+		}
+		if err != pgx.ErrNoRows {
+			if !shouldIncludeVersion(target_os_version.OsVersion, arg.OsVersion, string(target_os_version.Rule)) {
+				continue
+			}
+		}
+
+		target_app_version, err := store.getCachedTargetAppVersion(ctx, key)
+		if err != nil && err != pgx.ErrNoRows {
+			return []DeliveryResult{}, err
+		}
+		if err != pgx.ErrNoRows {
+			if !shouldIncludeVersion(target_app_version.AppVersion, arg.AppVersion, string(target_app_version.Rule)) {
+				continue
+			}
+		}
+
+		target_language, err := store.getCachedTargetLanguage(ctx, key)
+		if err != nil && err != pgx.ErrNoRows {
+			return []DeliveryResult{}, err
+		}
+		if err != pgx.ErrNoRows {
+			if !shouldIncludeLanguage(target_language.Language, arg.Lang, string(target_language.Rule)) {
+				continue
+			}
+		}
+
+		target_region, err := store.getCachedTargetRegion(ctx, key)
+		if err != nil && err != pgx.ErrNoRows {
+			return []DeliveryResult{}, err
+		}
+		if err != pgx.ErrNoRows {
+			if !shouldInclude(target_region.Region, arg.Region, string(target_region.Rule)) {
+				continue
+			}
+		}
+
+		target_city, err := store.getCachedTargetCity(ctx, key)
+		if err != nil && err != pgx.ErrNoRows {
+			return []DeliveryResult{}, err
+		}
+		if err != pgx.ErrNoRows {
+			if !shouldInclude(target_city.City, arg.City, string(target_city.Rule)) {
+				continue
+			}
+		}
+
+		target_category, err := store.getCachedTargetCategory(ctx, key)
+		if err != nil && err != pgx.ErrNoRows {
+			return []DeliveryResult{}, err
+		}
+		if err != pgx.ErrNoRows {
+			if !shouldIncludeCategory(target_category.Category, app.Categories, string(target_category.Rule)) {
+				continue
+			}
+		}
+
+		target_keyword, err := store.getCachedTargetKeyword(ctx, key)
+		if err != nil && err != pgx.ErrNoRows {
+			return []DeliveryResult{}, err
+		}
+		if err != pgx.ErrNoRows {
+			if !shouldIncludeKeyword(target_keyword.Keyword, app.Tags, string(target_keyword.Rule)) {
+				continue
+			}
+		}
+
+		matches, err := store.matchTargetLists(ctx, key, arg)
+		if err != nil {
+			return []DeliveryResult{}, err
+		}
+		if !matches {
+			continue
+		}
+
 		campaigns = append(campaigns, campaign)
 	}
 
 	var result []DeliveryResult
 	for _, campaign := range campaigns {
-		result = append(result, DeliveryResult{
-			Cid: campaign.Cid,
-			Img: campaign.Img,
-			Cta: campaign.Cta,
-		})
+		delivery := DeliveryResult{
+			AdvertiserID: campaign.AdvertiserID,
+			Cid:          campaign.Cid,
+			Img:          campaign.Img,
+			Cta:          campaign.Cta,
+		}
+
+		if arg.Lang != "" {
+			locales, err := store.getCachedCampaignLocales(ctx, delivery.Key())
+			if err != nil {
+				return []DeliveryResult{}, err
+			}
+			localize(&delivery, *locales, arg.Lang)
+		}
+
+		result = append(result, delivery)
 	}
 
 	if len(result) > 0 {
@@ -326,61 +639,111 @@ func (store *SQLStore) Delivery(ctx context.Context, arg DeliveryParams) ([]Deli
 }
 
 type CreateCampaignParams struct {
-	Cid         string   `json:"cid"`
-	Name        string   `json:"name"`
-	Img         string   `json:"img"`
-	Cta         string   `json:"cta"`
-	AppID       string   `json:"app_id"`
-	AppRule     RuleType `json:"app_rule"`
-	Country     string   `json:"country"`
-	CountryRule RuleType `json:"country_rule"`
-	Os          string   `json:"os"`
-	OsRule      RuleType `json:"os_rule"`
+	AdvertiserID   int32    `json:"advertiser_id"`
+	Cid            string   `json:"cid"`
+	Name           string   `json:"name"`
+	Img            string   `json:"img"`
+	Cta            string   `json:"cta"`
+	AppID          string   `json:"app_id"`
+	AppRule        RuleType `json:"app_rule"`
+	Country        string   `json:"country"`
+	CountryRule    RuleType `json:"country_rule"`
+	Os             string   `json:"os"`
+	OsRule         RuleType `json:"os_rule"`
+	OsVersion      string   `json:"os_version"`
+	OsVersionRule  RuleType `json:"os_version_rule"`
+	AppVersion     string   `json:"app_version"`
+	AppVersionRule RuleType `json:"app_version_rule"`
+	Language       string   `json:"language"`
+	LanguageRule   RuleType `json:"language_rule"`
+	Region         string   `json:"region"`
+	RegionRule     RuleType `json:"region_rule"`
+	City           string   `json:"city"`
+	CityRule       RuleType `json:"city_rule"`
+	Category       string   `json:"category"`
+	CategoryRule   RuleType `json:"category_rule"`
+	Keyword        string   `json:"keyword"`
+	KeywordRule    RuleType `json:"keyword_rule"`
+	// Status may only be draft, the default: campaigns go live once approved.
+	Status StatusType `json:"status"`
 }
 
 type CreateCampaignResult struct {
-	Cid         string     `json:"cid"`
-	Name        string     `json:"name"`
-	Img         string     `json:"img"`
-	Cta         string     `json:"cta"`
-	AppID       string     `json:"app_id"`
-	AppRule     RuleType   `json:"app_rule"`
-	Country     string     `json:"country"`
-	CountryRule RuleType   `json:"country_rule"`
-	Os          string     `json:"os"`
-	OsRule      RuleType   `json:"os_rule"`
-	Status      StatusType `json:"status"`
-	CreatedAt   time.Time  `json:"created_at"`
+	AdvertiserID   int32      `json:"advertiser_id"`
+	Cid            string     `json:"cid"`
+	Name           string     `json:"name"`
+	Img            string     `json:"img"`
+	Cta            string     `json:"cta"`
+	AppID          string     `json:"app_id"`
+	AppRule        RuleType   `json:"app_rule"`
+	Country        string     `json:"country"`
+	CountryRule    RuleType   `json:"country_rule"`
+	Os             string     `json:"os"`
+	OsRule         RuleType   `json:"os_rule"`
+	OsVersion      string     `json:"os_version"`
+	OsVersionRule  RuleType   `json:"os_version_rule"`
+	AppVersion     string     `json:"app_version"`
+	AppVersionRule RuleType   `json:"app_version_rule"`
+	Language       string     `json:"language"`
+	LanguageRule   RuleType   `json:"language_rule"`
+	Region         string     `json:"region"`
+	RegionRule     RuleType   `json:"region_rule"`
+	City           string     `json:"city"`
+	CityRule       RuleType   `json:"city_rule"`
+	Category       string     `json:"category"`
+	CategoryRule   RuleType   `json:"category_rule"`
+	Keyword        string     `json:"keyword"`
+	KeywordRule    RuleType   `json:"keyword_rule"`
+	Status         StatusType `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func (campaign CreateCampaignResult) Key() CampaignKey {
+	return CampaignKey{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid}
 }
 
 func (store *SQLStore) CreateCampaign(ctx context.Context, arg CreateCampaignParams) (CreateCampaignResult, error) {
 	var result CreateCampaignResult
 
+	switch arg.Status {
+	case "", StatusTypeDraft:
+	default:
+		return CreateCampaignResult{}, &Error{Kind: ErrValidation, Message: "campaigns are created as drafts and go live once approved"}
+	}
+
 	err := store.execTx(ctx, func(q *Queries) error {
 		campaign, err := q.AddCampaign(ctx, AddCampaignParams{
-			Cid:  arg.Cid,
-			Name: arg.Name,
-			Img:  arg.Img,
-			Cta:  arg.Cta,
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Name:         arg.Name,
+			Img:          arg.Img,
+			Cta:          arg.Cta,
+			Status:       NullStatusType{StatusType: StatusTypeDraft, Valid: true},
 		})
 		if err != nil {
 			return err
 		}
 
 		result = CreateCampaignResult{
-			Cid:       campaign.Cid,
-			Name:      campaign.Name,
-			Img:       campaign.Img,
-			Cta:       campaign.Cta,
-			Status:    campaign.Status,
-			CreatedAt: campaign.CreatedAt,
+			AdvertiserID: campaign.AdvertiserID,
+			Cid:          campaign.Cid,
+			Name:         campaign.Name,
+			Img:          campaign.Img,
+			Cta:          campaign.Cta,
+			Status:       campaign.Status,
+			CreatedAt:    campaign.CreatedAt,
+		}
+		err = createCreatedHistory(ctx, q, campaign.Key(), campaign.Status)
+		if err != nil {
+			return err
 		}
 
 		if arg.AppID != "" {
-			targetApp, err := q.AddTargetApp(ctx, AddTargetAppParams{
-				Cid:   arg.Cid,
-				AppID: arg.AppID,
-				Rule:  arg.AppRule,
+			targetApp, err := q.addTargetApp(ctx, addTargetAppParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				AppID:        arg.AppID,
+				Rule:         arg.AppRule,
 			})
 			if err != nil {
 				return err
@@ -390,10 +753,11 @@ func (store *SQLStore) CreateCampaign(ctx context.Context, arg CreateCampaignPar
 		}
 
 		if arg.Country != "" {
-			targetCountry, err := q.AddTargetCountry(ctx, AddTargetCountryParams{
-				Cid:     arg.Cid,
-				Country: arg.Country,
-				Rule:    arg.CountryRule,
+			targetCountry, err := q.addTargetCountry(ctx, addTargetCountryParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				Country:      arg.Country,
+				Rule:         arg.CountryRule,
 			})
 			if err != nil {
 				return err
@@ -403,10 +767,11 @@ func (store *SQLStore) CreateCampaign(ctx context.Context, arg CreateCampaignPar
 		}
 
 		if arg.Os != "" {
-			targetOs, err := q.AddTargetOs(ctx, AddTargetOsParams{
-				Cid:  arg.Cid,
-				Os:   arg.Os,
-				Rule: arg.OsRule,
+			targetOs, err := q.addTargetOs(ctx, addTargetOsParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				Os:           arg.Os,
+				Rule:         arg.OsRule,
 			})
 			if err != nil {
 				return err
@@ -415,173 +780,436 @@ func (store *SQLStore) CreateCampaign(ctx context.Context, arg CreateCampaignPar
 			result.OsRule = targetOs.Rule
 		}
 
-		return nil
-	})
-
-	return result, err
-}
-
-type CompleteCampaign struct {
-	Cid         string     `json:"cid"`
-	Name        string     `json:"name"`
-	Img         string     `json:"img"`
-	Cta         string     `json:"cta"`
-	AppID       string     `json:"app_id"`
-	AppRule     RuleType   `json:"app_rule"`
-	Country     string     `json:"country"`
-	CountryRule RuleType   `json:"country_rule"`
-	Os          string     `json:"os"`
-	OsRule      RuleType   `json:"os_rule"`
-	Status      StatusType `json:"status"`
-	CreatedAt   time.Time  `json:"created_at"`
-}
-
-func (store *SQLStore) ReadCampaign(ctx context.Context, cid string) (CompleteCampaign, error) {
-	campaign, err := store.GetCampaign(ctx, cid)
-	if err != nil {
-		return CompleteCampaign{}, err
-	}
-
-	TargetApp, _ := store.GetTargetApp(ctx, cid)
-	TargetCountry, _ := store.GetTargetCountry(ctx, cid)
-	TargetOs, _ := store.GetTargetOs(ctx, cid)
-
-	return CompleteCampaign{
-		Cid:         cid,
-		Name:        campaign.Name,
-		Img:         campaign.Img,
-		Cta:         campaign.Cta,
-		AppID:       TargetApp.AppID,
-		AppRule:     TargetApp.Rule,
-		Country:     TargetCountry.Country,
-		CountryRule: TargetCountry.Rule,
-		Os:          TargetOs.Os,
-		OsRule:      TargetOs.Rule,
-		Status:      campaign.Status,
-		CreatedAt:   campaign.CreatedAt,
-	}, nil
-}
-
-func (store *SQLStore) createHistory(ctx context.Context, q *Queries, args []createCampaignHistoryParams) error {
-	for _, arg := range args {
-		if arg.OldValue != arg.NewValue {
-			err := q.createCampaignHistory(ctx, arg)
+		if arg.OsVersion != "" {
+			targetOsVersion, err := q.addTargetOsVersion(ctx, addTargetOsVersionParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				OsVersion:    arg.OsVersion,
+				Rule:         arg.OsVersionRule,
+			})
 			if err != nil {
-				return fmt.Errorf("failed to create history for %s: %v", arg.FieldChanged, err)
+				return err
 			}
+			result.OsVersion = targetOsVersion.OsVersion
+			result.OsVersionRule = targetOsVersion.Rule
 		}
-	}
-	return nil
-}
 
-func (store *SQLStore) ToggleStatus(ctx context.Context, cid string) error {
-	return store.execTx(ctx, func(q *Queries) error {
-		campaign, err := q.GetCampaign(ctx, cid)
-		if err != nil {
-			return err
+		if arg.AppVersion != "" {
+			targetAppVersion, err := q.addTargetAppVersion(ctx, addTargetAppVersionParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				AppVersion:   arg.AppVersion,
+				Rule:         arg.AppVersionRule,
+			})
+			if err != nil {
+				return err
+			}
+			result.AppVersion = targetAppVersion.AppVersion
+			result.AppVersionRule = targetAppVersion.Rule
 		}
 
-		status, err := q.toggleStatus(ctx, cid)
-		if err != nil {
-			return err
+		if arg.Language != "" {
+			targetLanguage, err := q.addTargetLanguage(ctx, addTargetLanguageParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				Language:     arg.Language,
+				Rule:         arg.LanguageRule,
+			})
+			if err != nil {
+				return err
+			}
+			result.Language = targetLanguage.Language
+			result.LanguageRule = targetLanguage.Rule
 		}
 
-		return store.createHistory(ctx, q, []createCampaignHistoryParams{
-			{
-				Cid:          cid,
-				FieldChanged: "status",
-				OldValue:     string(campaign.Status),
-				NewValue:     string(status),
-			},
-		})
+		if arg.Region != "" {
+			targetRegion, err := q.addTargetRegion(ctx, addTargetRegionParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				Region:       arg.Region,
+				Rule:         arg.RegionRule,
+			})
+			if err != nil {
+				return err
+			}
+			result.Region = targetRegion.Region
+			result.RegionRule = targetRegion.Rule
+		}
+
+		if arg.City != "" {
+			targetCity, err := q.addTargetCity(ctx, addTargetCityParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				City:         arg.City,
+				Rule:         arg.CityRule,
+			})
+			if err != nil {
+				return err
+			}
+			result.City = targetCity.City
+			result.CityRule = targetCity.Rule
+		}
+
+		if arg.Category != "" {
+			targetCategory, err := q.addTargetCategory(ctx, addTargetCategoryParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				Category:     arg.Category,
+				Rule:         arg.CategoryRule,
+			})
+			if err != nil {
+				return err
+			}
+			result.Category = targetCategory.Category
+			result.CategoryRule = targetCategory.Rule
+		}
+
+		if arg.Keyword != "" {
+			targetKeyword, err := q.addTargetKeyword(ctx, addTargetKeywordParams{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				Keyword:      arg.Keyword,
+				Rule:         arg.KeywordRule,
+			})
+			if err != nil {
+				return err
+			}
+			result.Keyword = targetKeyword.Keyword
+			result.KeywordRule = targetKeyword.Rule
+		}
+
+		return nil
 	})
+
+	return result, err
 }
 
-type UpdateCampaignNameParams struct {
-	Cid  string `json:"cid"`
-	Name string `json:"name"`
+type CompleteCampaign struct {
+	AdvertiserID   int32                `json:"advertiser_id"`
+	Cid            string               `json:"cid"`
+	Name           string               `json:"name"`
+	Img            string               `json:"img"`
+	Cta            string               `json:"cta"`
+	AppID          string               `json:"app_id"`
+	AppRule        RuleType             `json:"app_rule"`
+	Country        string               `json:"country"`
+	CountryRule    RuleType             `json:"country_rule"`
+	Os             string               `json:"os"`
+	OsRule         RuleType             `json:"os_rule"`
+	OsVersion      string               `json:"os_version"`
+	OsVersionRule  RuleType             `json:"os_version_rule"`
+	AppVersion     string               `json:"app_version"`
+	AppVersionRule RuleType             `json:"app_version_rule"`
+	Language       string               `json:"language"`
+	LanguageRule   RuleType             `json:"language_rule"`
+	Region         string               `json:"region"`
+	RegionRule     RuleType             `json:"region_rule"`
+	City           string               `json:"city"`
+	CityRule       RuleType             `json:"city_rule"`
+	Category       string               `json:"category"`
+	CategoryRule   RuleType             `json:"category_rule"`
+	Keyword        string               `json:"keyword"`
+	KeywordRule    RuleType             `json:"keyword_rule"`
+	Radius         []TargetRadius       `json:"radius"`
+	TargetLists    []CampaignTargetList `json:"target_lists"`
+	Segments       []CampaignSegment    `json:"segments"`
+	Locales        []CampaignLocale     `json:"locales"`
+	Labels         []string             `json:"labels"`
+	Status         StatusType           `json:"status"`
+	CreatedAt      time.Time            `json:"created_at"`
+	Version        int32                `json:"version"`
+	// PendingCreative is the creative revision waiting for review. Img and
+	// Cta are the approved creative, which the campaign serves until then.
+	PendingCreative *CreativeRevision `json:"pending_creative,omitempty"`
 }
 
-func (store *SQLStore) UpdateCampaignName(ctx context.Context, arg UpdateCampaignNameParams) (Campaign, error) {
+func (store *SQLStore) ReadCampaign(ctx context.Context, key CampaignKey) (CompleteCampaign, error) {
+	return readCampaign(ctx, store.Queries, key)
+}
+
+// readCampaign loads a campaign with its targeting through q, so that it can
+// be read inside a transaction.
+func readCampaign(ctx context.Context, q *Queries, key CampaignKey) (CompleteCampaign, error) {
+	campaign, err := q.GetCampaign(ctx, GetCampaignParams(key))
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+
+	TargetApp, _ := q.GetTargetApp(ctx, GetTargetAppParams(key))
+	TargetCountry, _ := q.GetTargetCountry(ctx, GetTargetCountryParams(key))
+	TargetOs, _ := q.GetTargetOs(ctx, GetTargetOsParams(key))
+	TargetOsVersion, _ := q.GetTargetOsVersion(ctx, GetTargetOsVersionParams(key))
+	TargetAppVersion, _ := q.GetTargetAppVersion(ctx, GetTargetAppVersionParams(key))
+	TargetLanguage, _ := q.GetTargetLanguage(ctx, GetTargetLanguageParams(key))
+	TargetRegion, _ := q.GetTargetRegion(ctx, GetTargetRegionParams(key))
+	TargetCity, _ := q.GetTargetCity(ctx, GetTargetCityParams(key))
+	TargetCategory, _ := q.GetTargetCategory(ctx, GetTargetCategoryParams(key))
+	TargetKeyword, _ := q.GetTargetKeyword(ctx, GetTargetKeywordParams(key))
+
+	radius, err := q.ListTargetRadius(ctx, ListTargetRadiusParams(key))
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+
+	targetLists, err := q.ListCampaignTargetLists(ctx, ListCampaignTargetListsParams(key))
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+
+	segments, err := q.ListCampaignSegments(ctx, ListCampaignSegmentsParams(key))
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+
+	locales, err := q.ListCampaignLocales(ctx, ListCampaignLocalesParams(key))
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+
+	labels, err := q.ListCampaignLabels(ctx, ListCampaignLabelsParams(key))
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+
+	pending, err := pendingCreative(ctx, q, key, "")
+	if err != nil {
+		return CompleteCampaign{}, err
+	}
+
+	return CompleteCampaign{
+		AdvertiserID:    key.AdvertiserID,
+		Cid:             key.Cid,
+		Name:            campaign.Name,
+		Img:             campaign.Img,
+		Cta:             campaign.Cta,
+		AppID:           TargetApp.AppID,
+		AppRule:         TargetApp.Rule,
+		Country:         TargetCountry.Country,
+		CountryRule:     TargetCountry.Rule,
+		Os:              TargetOs.Os,
+		OsRule:          TargetOs.Rule,
+		OsVersion:       TargetOsVersion.OsVersion,
+		OsVersionRule:   TargetOsVersion.Rule,
+		AppVersion:      TargetAppVersion.AppVersion,
+		AppVersionRule:  TargetAppVersion.Rule,
+		Language:        TargetLanguage.Language,
+		LanguageRule:    TargetLanguage.Rule,
+		Region:          TargetRegion.Region,
+		RegionRule:      TargetRegion.Rule,
+		City:            TargetCity.City,
+		CityRule:        TargetCity.Rule,
+		Category:        TargetCategory.Category,
+		CategoryRule:    TargetCategory.Rule,
+		Keyword:         TargetKeyword.Keyword,
+		KeywordRule:     TargetKeyword.Rule,
+		Radius:          radius,
+		TargetLists:     targetLists,
+		Segments:        segments,
+		Locales:         locales,
+		Labels:          labels,
+		Status:          campaign.Status,
+		CreatedAt:       campaign.CreatedAt,
+		Version:         campaign.Version,
+		PendingCreative: pending,
+	}, nil
+}
+
+// bumpVersion moves a campaign to its next version as part of an update.
+// Unless version is zero, the campaign must still be at that version. The
+// campaign row stays locked until the transaction ends, so concurrent updates
+// of a campaign run one after the other and all but the first fail the check.
+func bumpVersion(ctx context.Context, q *Queries, key CampaignKey, version int32) error {
+	_, err := q.bumpCampaignVersion(ctx, bumpCampaignVersionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Version:      pgtype.Int4{Int32: version, Valid: version != 0},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		if version != 0 {
+			if _, err := q.GetCampaign(ctx, GetCampaignParams(key)); err == nil {
+				return ErrVersionMismatch
+			}
+		}
+		return &Error{Kind: ErrNotFound, Message: "campaign not found", Err: err}
+	}
+	return err
+}
+
+// DeleteVersionedCampaign deletes a campaign if it is still at version. The
+// campaign is only marked deleted: it keeps its targeting and history until
+// PurgeDeletedCampaigns removes it, and RestoreCampaign brings it back.
+func (store *SQLStore) DeleteVersionedCampaign(ctx context.Context, key CampaignKey, version int32) error {
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := bumpVersion(ctx, q, key, version); err != nil {
+			return err
+		}
+		return softDeleteCampaign(ctx, q, key)
+	})
+	if err == nil {
+		store.invalidateCampaigns(ctx)
+	}
+	return err
+}
+
+// softDeleteCampaign marks a campaign deleted and records it in the history.
+func softDeleteCampaign(ctx context.Context, q *Queries, key CampaignKey) error {
+	if err := q.DeleteCampaign(ctx, DeleteCampaignParams(key)); err != nil {
+		return err
+	}
+	return createChangesHistory(ctx, q, key, []FieldChange{{Field: deletedHistoryField, From: "false", To: "true"}})
+}
+
+// RestoreCampaign undoes the deletion of a campaign that was not purged yet.
+// The campaign comes back in the status it was deleted in, at a new version.
+func (store *SQLStore) RestoreCampaign(ctx context.Context, key CampaignKey) (Campaign, error) {
 	var campaign Campaign
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
-		oldCampaign, err := q.GetCampaign(ctx, arg.Cid)
+		campaign, err = q.restoreCampaign(ctx, restoreCampaignParams(key))
 		if err != nil {
 			return err
 		}
+		return createChangesHistory(ctx, q, key, []FieldChange{{Field: deletedHistoryField, From: "true", To: "false"}})
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Campaign{}, &Error{Kind: ErrNotFound, Message: fmt.Sprintf("no deleted campaign %s", key.Cid)}
+		}
+		return Campaign{}, err
+	}
+	store.invalidateCampaigns(ctx)
+	return campaign, nil
+}
 
-		campaign, err = q.updateCampaignName(ctx, updateCampaignNameParams{
-			Cid:  arg.Cid,
-			Name: arg.Name,
-		})
-		if err != nil {
-			return err
+// invalidateCampaigns drops the cached active campaigns and delivery results
+// after campaigns appear or disappear.
+func (store *SQLStore) invalidateCampaigns(ctx context.Context) {
+	if err := store.rClient.Del(ctx, "active_campaigns").Err(); err != nil {
+		fmt.Printf("Redis Del error for active campaigns: %v\n", err)
+	}
+	store.invalidateDelivery(ctx)
+}
+
+// invalidateChanges drops the cached targeting, campaigns and locales holding
+// the fields changed of a campaign, and the delivery results built from them.
+func (store *SQLStore) invalidateChanges(ctx context.Context, key CampaignKey, changes []FieldChange) {
+	if len(changes) == 0 {
+		return
+	}
+	var campaigns, locales bool
+	for _, change := range changes {
+		if strings.Contains(change.Field, ":") {
+			locales = true
+			continue
+		}
+		field := strings.TrimSuffix(change.Field, "_rule")
+		targeting := false
+		for entity, name := range targetingEntities {
+			if name == field {
+				cacheKey := fmt.Sprintf("%s:%s", entity, key)
+				if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+					fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+				}
+				targeting = true
+			}
 		}
+		if !targeting {
+			campaigns = true
+		}
+	}
+	if campaigns {
+		if err := store.rClient.Del(ctx, "active_campaigns").Err(); err != nil {
+			fmt.Printf("Redis Del error for active campaigns: %v\n", err)
+		}
+	}
+	if locales {
+		cacheKey := fmt.Sprintf("campaign_locale:%s", key)
+		if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+			fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+		}
+	}
+	store.invalidateDelivery(ctx)
+}
 
-		return store.createHistory(ctx, q, []createCampaignHistoryParams{
-			{
-				Cid:          arg.Cid,
-				FieldChanged: "name",
-				OldValue:     oldCampaign.Name,
-				NewValue:     campaign.Name,
-			},
-		})
-	})
-	return campaign, err
+func (store *SQLStore) invalidateCampaignLocales(ctx context.Context, key CampaignKey) {
+	cacheKey := fmt.Sprintf("campaign_locale:%s", key)
+	if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+	}
+	store.invalidateDelivery(ctx)
 }
 
-type UpdateCampaignCtaParams struct {
-	Cid string `json:"cid"`
-	Cta string `json:"cta"`
+func (store *SQLStore) createHistory(ctx context.Context, q *Queries, args []createCampaignHistoryParams) error {
+	for _, arg := range args {
+		if arg.OldValue != arg.NewValue {
+			err := q.createCampaignHistory(ctx, arg)
+			if err != nil {
+				return fmt.Errorf("failed to create history for %s: %v", arg.FieldChanged, err)
+			}
+		}
+	}
+	return nil
 }
 
-func (store *SQLStore) UpdateCampaignCta(ctx context.Context, arg UpdateCampaignCtaParams) (Campaign, error) {
-	var campaign Campaign
+// ToggleStatus pauses an active campaign and resumes a paused one. Campaigns
+// in other states fail with ErrInvalidTransition.
+func (store *SQLStore) ToggleStatus(ctx context.Context, key CampaignKey, version int32) error {
+	var changed bool
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
-		oldCampaign, err := q.GetCampaign(ctx, arg.Cid)
+		err := bumpVersion(ctx, q, key, version)
 		if err != nil {
 			return err
 		}
 
-		campaign, err = q.updateCampaignCta(ctx, updateCampaignCtaParams{
-			Cid: arg.Cid,
-			Cta: arg.Cta,
-		})
+		campaign, err := q.GetCampaign(ctx, GetCampaignParams(key))
 		if err != nil {
 			return err
 		}
 
-		return store.createHistory(ctx, q, []createCampaignHistoryParams{
-			{
-				Cid:          arg.Cid,
-				FieldChanged: "cta",
-				OldValue:     oldCampaign.Cta,
-				NewValue:     campaign.Cta,
-			},
-		})
+		transition := TransitionPause
+		if campaign.Status != StatusTypeActive {
+			transition = TransitionResume
+		}
+		status, err := nextStatus(transition, campaign.Status)
+		if err != nil {
+			return err
+		}
+
+		changed, err = store.setStatus(ctx, q, key, campaign.Status, status, "")
+		return err
 	})
-	return campaign, err
+	if err == nil && changed {
+		store.invalidateCampaigns(ctx)
+	}
+	return err
 }
 
-type UpdateCampaignImageParams struct {
-	Cid string `json:"cid"`
-	Img string `json:"img"`
+type UpdateCampaignNameParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Name         string `json:"name"`
+	Version      int32  `json:"version"`
 }
 
-func (store *SQLStore) UpdateCampaignImage(ctx context.Context, arg UpdateCampaignImageParams) (Campaign, error) {
+func (store *SQLStore) UpdateCampaignName(ctx context.Context, arg UpdateCampaignNameParams) (Campaign, error) {
 	var campaign Campaign
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
-		oldCampaign, err := q.GetCampaign(ctx, arg.Cid)
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldCampaign, err := q.GetCampaign(ctx, GetCampaignParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
 		if err != nil {
 			return err
 		}
 
-		campaign, err = q.updateCampaignImage(ctx, updateCampaignImageParams{
-			Cid: arg.Cid,
-			Img: arg.Img,
+		campaign, err = q.updateCampaignName(ctx, updateCampaignNameParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Name:         arg.Name,
 		})
 		if err != nil {
 			return err
@@ -589,35 +1217,73 @@ func (store *SQLStore) UpdateCampaignImage(ctx context.Context, arg UpdateCampai
 
 		return store.createHistory(ctx, q, []createCampaignHistoryParams{
 			{
+				AdvertiserID: arg.AdvertiserID,
 				Cid:          arg.Cid,
-				FieldChanged: "img",
-				OldValue:     oldCampaign.Img,
-				NewValue:     campaign.Img,
+				FieldChanged: "name",
+				OldValue:     oldCampaign.Name,
+				NewValue:     campaign.Name,
 			},
 		})
 	})
 	return campaign, err
 }
 
+type UpdateCampaignCtaParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Cta          string `json:"cta"`
+	Version      int32  `json:"version"`
+}
+
+// UpdateCampaignCta changes the cta of a campaign. The cta of a live campaign
+// only changes once its creative revision is approved.
+func (store *SQLStore) UpdateCampaignCta(ctx context.Context, arg UpdateCampaignCtaParams) (CreativeUpdateResult, error) {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.updateCreative(ctx, key, creative{cta: arg.Cta}, arg.Version)
+}
+
+type UpdateCampaignImageParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Img          string `json:"img"`
+	Version      int32  `json:"version"`
+}
+
+// UpdateCampaignImage changes the img of a campaign. The img of a live
+// campaign only changes once its creative revision is approved.
+func (store *SQLStore) UpdateCampaignImage(ctx context.Context, arg UpdateCampaignImageParams) (CreativeUpdateResult, error) {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	return store.updateCreative(ctx, key, creative{img: arg.Img}, arg.Version)
+}
+
 type UpdateTargetAppParams struct {
-	Cid   string   `json:"cid"`
-	AppID string   `json:"app_id"`
-	Rule  RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	AppID        string   `json:"app_id"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
 }
 
 func (store *SQLStore) UpdateTargetApp(ctx context.Context, arg UpdateTargetAppParams) (TargetApp, error) {
 	var targetApp TargetApp
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
-		oldTarget, err := q.GetTargetApp(ctx, arg.Cid)
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldTarget, err := q.GetTargetApp(ctx, GetTargetAppParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
 		if err != nil {
 			return err
 		}
 
 		targetApp, err = q.updateTargetApp(ctx, updateTargetAppParams{
-			Cid:   arg.Cid,
-			AppID: arg.AppID,
-			Rule:  arg.Rule,
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			AppID:        arg.AppID,
+			Rule:         arg.Rule,
 		})
 		if err != nil {
 			return err
@@ -625,12 +1291,14 @@ func (store *SQLStore) UpdateTargetApp(ctx context.Context, arg UpdateTargetAppP
 
 		return store.createHistory(ctx, q, []createCampaignHistoryParams{
 			{
+				AdvertiserID: arg.AdvertiserID,
 				Cid:          arg.Cid,
 				FieldChanged: "app_id",
 				OldValue:     oldTarget.AppID,
 				NewValue:     targetApp.AppID,
 			},
 			{
+				AdvertiserID: arg.AdvertiserID,
 				Cid:          arg.Cid,
 				FieldChanged: "app_rule",
 				OldValue:     string(oldTarget.Rule),
@@ -642,24 +1310,33 @@ func (store *SQLStore) UpdateTargetApp(ctx context.Context, arg UpdateTargetAppP
 }
 
 type UpdateTargetOsParams struct {
-	Cid  string   `json:"cid"`
-	Os   string   `json:"os"`
-	Rule RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Os           string   `json:"os"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
 }
 
 func (store *SQLStore) UpdateTargetOs(ctx context.Context, arg UpdateTargetOsParams) (TargetOs, error) {
 	var targetOs TargetOs
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
-		oldTarget, err := q.GetTargetOs(ctx, arg.Cid)
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldTarget, err := q.GetTargetOs(ctx, GetTargetOsParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
 		if err != nil {
 			return err
 		}
 
 		targetOs, err = q.updateTargetOs(ctx, updateTargetOsParams{
-			Cid:  arg.Cid,
-			Os:   arg.Os,
-			Rule: arg.Rule,
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Os:           arg.Os,
+			Rule:         arg.Rule,
 		})
 		if err != nil {
 			return err
@@ -667,12 +1344,14 @@ func (store *SQLStore) UpdateTargetOs(ctx context.Context, arg UpdateTargetOsPar
 
 		return store.createHistory(ctx, q, []createCampaignHistoryParams{
 			{
+				AdvertiserID: arg.AdvertiserID,
 				Cid:          arg.Cid,
 				FieldChanged: "os",
 				OldValue:     oldTarget.Os,
 				NewValue:     targetOs.Os,
 			},
 			{
+				AdvertiserID: arg.AdvertiserID,
 				Cid:          arg.Cid,
 				FieldChanged: "os_rule",
 				OldValue:     string(oldTarget.Rule),
@@ -684,24 +1363,33 @@ func (store *SQLStore) UpdateTargetOs(ctx context.Context, arg UpdateTargetOsPar
 }
 
 type UpdateTargetCountryParams struct {
-	Cid     string   `json:"cid"`
-	Country string   `json:"country"`
-	Rule    RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Country      string   `json:"country"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
 }
 
 func (store *SQLStore) UpdateTargetCountry(ctx context.Context, arg UpdateTargetCountryParams) (TargetCountry, error) {
 	var targetCountry TargetCountry
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
-		oldTarget, err := q.GetTargetCountry(ctx, arg.Cid)
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldTarget, err := q.GetTargetCountry(ctx, GetTargetCountryParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
 		if err != nil {
 			return err
 		}
 
 		targetCountry, err = q.updateTargetCountry(ctx, updateTargetCountryParams{
-			Cid:     arg.Cid,
-			Country: arg.Country,
-			Rule:    arg.Rule,
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Country:      arg.Country,
+			Rule:         arg.Rule,
 		})
 		if err != nil {
 			return err
@@ -709,12 +1397,14 @@ func (store *SQLStore) UpdateTargetCountry(ctx context.Context, arg UpdateTarget
 
 		return store.createHistory(ctx, q, []createCampaignHistoryParams{
 			{
+				AdvertiserID: arg.AdvertiserID,
 				Cid:          arg.Cid,
 				FieldChanged: "country",
 				OldValue:     oldTarget.Country,
 				NewValue:     targetCountry.Country,
 			},
 			{
+				AdvertiserID: arg.AdvertiserID,
 				Cid:          arg.Cid,
 				FieldChanged: "country_rule",
 				OldValue:     string(oldTarget.Rule),
@@ -724,3 +1414,429 @@ func (store *SQLStore) UpdateTargetCountry(ctx context.Context, arg UpdateTarget
 	})
 	return targetCountry, err
 }
+
+type UpdateTargetOsVersionParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	OsVersion    string   `json:"os_version"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) UpdateTargetOsVersion(ctx context.Context, arg UpdateTargetOsVersionParams) (TargetOsVersion, error) {
+	var targetOsVersion TargetOsVersion
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldTarget, err := q.GetTargetOsVersion(ctx, GetTargetOsVersionParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
+		if err != nil {
+			return err
+		}
+
+		targetOsVersion, err = q.updateTargetOsVersion(ctx, updateTargetOsVersionParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			OsVersion:    arg.OsVersion,
+			Rule:         arg.Rule,
+		})
+		if err != nil {
+			return err
+		}
+
+		return store.createHistory(ctx, q, []createCampaignHistoryParams{
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "os_version",
+				OldValue:     oldTarget.OsVersion,
+				NewValue:     targetOsVersion.OsVersion,
+			},
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "os_version_rule",
+				OldValue:     string(oldTarget.Rule),
+				NewValue:     string(targetOsVersion.Rule),
+			},
+		})
+	})
+	return targetOsVersion, err
+}
+
+type UpdateTargetAppVersionParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	AppVersion   string   `json:"app_version"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) UpdateTargetAppVersion(ctx context.Context, arg UpdateTargetAppVersionParams) (TargetAppVersion, error) {
+	var targetAppVersion TargetAppVersion
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldTarget, err := q.GetTargetAppVersion(ctx, GetTargetAppVersionParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
+		if err != nil {
+			return err
+		}
+
+		targetAppVersion, err = q.updateTargetAppVersion(ctx, updateTargetAppVersionParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			AppVersion:   arg.AppVersion,
+			Rule:         arg.Rule,
+		})
+		if err != nil {
+			return err
+		}
+
+		return store.createHistory(ctx, q, []createCampaignHistoryParams{
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "app_version",
+				OldValue:     oldTarget.AppVersion,
+				NewValue:     targetAppVersion.AppVersion,
+			},
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "app_version_rule",
+				OldValue:     string(oldTarget.Rule),
+				NewValue:     string(targetAppVersion.Rule),
+			},
+		})
+	})
+	return targetAppVersion, err
+}
+
+type UpdateTargetLanguageParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Language     string   `json:"language"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) UpdateTargetLanguage(ctx context.Context, arg UpdateTargetLanguageParams) (TargetLanguage, error) {
+	var targetLanguage TargetLanguage
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldTarget, err := q.GetTargetLanguage(ctx, GetTargetLanguageParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
+		if err != nil {
+			return err
+		}
+
+		targetLanguage, err = q.updateTargetLanguage(ctx, updateTargetLanguageParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Language:     arg.Language,
+			Rule:         arg.Rule,
+		})
+		if err != nil {
+			return err
+		}
+
+		return store.createHistory(ctx, q, []createCampaignHistoryParams{
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "language",
+				OldValue:     oldTarget.Language,
+				NewValue:     targetLanguage.Language,
+			},
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "language_rule",
+				OldValue:     string(oldTarget.Rule),
+				NewValue:     string(targetLanguage.Rule),
+			},
+		})
+	})
+	return targetLanguage, err
+}
+
+type UpdateCampaignLocaleParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	Locale       string `json:"locale"`
+	Img          string `json:"img"`
+	Cta          string `json:"cta"`
+	Version      int32  `json:"version"`
+}
+
+// UpdateCampaignLocale replaces a locale's creative overrides. On a live
+// campaign new overrides go to a creative revision, which applies them once
+// approved, while cleared ones apply at once, as the locale then serves the
+// approved creative of the campaign.
+func (store *SQLStore) UpdateCampaignLocale(ctx context.Context, arg UpdateCampaignLocaleParams) (LocaleUpdateResult, error) {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+
+	var result LocaleUpdateResult
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := bumpVersion(ctx, q, key, arg.Version)
+		if err != nil {
+			return err
+		}
+		campaign, err := q.GetCampaign(ctx, GetCampaignParams(key))
+		if err != nil {
+			return err
+		}
+		oldLocale, err := q.GetCampaignLocale(ctx, GetCampaignLocaleParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Locale:       arg.Locale,
+		})
+		if err != nil {
+			return err
+		}
+
+		to := creative{img: arg.Img, cta: arg.Cta}
+		if !liveStatus(campaign.Status) {
+			result.Locale, err = store.applyLocale(ctx, q, oldLocale, to, "")
+			return err
+		}
+
+		applied, change := holdLocale(creative{img: oldLocale.Img, cta: oldLocale.Cta}, to)
+		result.Locale, err = store.applyLocale(ctx, q, oldLocale, applied, "")
+		if err != nil {
+			return err
+		}
+		result.Revision, err = proposeCreative(ctx, q, key, arg.Locale, applied, change)
+		return err
+	})
+	if err == nil {
+		store.invalidateCampaignLocales(ctx, key)
+	}
+	return result, err
+}
+
+type UpdateTargetRegionParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Region       string   `json:"region"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) UpdateTargetRegion(ctx context.Context, arg UpdateTargetRegionParams) (TargetRegion, error) {
+	var targetRegion TargetRegion
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldTarget, err := q.GetTargetRegion(ctx, GetTargetRegionParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
+		if err != nil {
+			return err
+		}
+
+		targetRegion, err = q.updateTargetRegion(ctx, updateTargetRegionParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Region:       arg.Region,
+			Rule:         arg.Rule,
+		})
+		if err != nil {
+			return err
+		}
+
+		return store.createHistory(ctx, q, []createCampaignHistoryParams{
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "region",
+				OldValue:     oldTarget.Region,
+				NewValue:     targetRegion.Region,
+			},
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "region_rule",
+				OldValue:     string(oldTarget.Rule),
+				NewValue:     string(targetRegion.Rule),
+			},
+		})
+	})
+	return targetRegion, err
+}
+
+type UpdateTargetCityParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	City         string   `json:"city"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) UpdateTargetCity(ctx context.Context, arg UpdateTargetCityParams) (TargetCity, error) {
+	var targetCity TargetCity
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldTarget, err := q.GetTargetCity(ctx, GetTargetCityParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
+		if err != nil {
+			return err
+		}
+
+		targetCity, err = q.updateTargetCity(ctx, updateTargetCityParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			City:         arg.City,
+			Rule:         arg.Rule,
+		})
+		if err != nil {
+			return err
+		}
+
+		return store.createHistory(ctx, q, []createCampaignHistoryParams{
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "city",
+				OldValue:     oldTarget.City,
+				NewValue:     targetCity.City,
+			},
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "city_rule",
+				OldValue:     string(oldTarget.Rule),
+				NewValue:     string(targetCity.Rule),
+			},
+		})
+	})
+	return targetCity, err
+}
+
+type UpdateTargetCategoryParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Category     string   `json:"category"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) UpdateTargetCategory(ctx context.Context, arg UpdateTargetCategoryParams) (TargetCategory, error) {
+	var targetCategory TargetCategory
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldTarget, err := q.GetTargetCategory(ctx, GetTargetCategoryParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
+		if err != nil {
+			return err
+		}
+
+		targetCategory, err = q.updateTargetCategory(ctx, updateTargetCategoryParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Category:     arg.Category,
+			Rule:         arg.Rule,
+		})
+		if err != nil {
+			return err
+		}
+
+		return store.createHistory(ctx, q, []createCampaignHistoryParams{
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "category",
+				OldValue:     oldTarget.Category,
+				NewValue:     targetCategory.Category,
+			},
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "category_rule",
+				OldValue:     string(oldTarget.Rule),
+				NewValue:     string(targetCategory.Rule),
+			},
+		})
+	})
+	return targetCategory, err
+}
+
+type UpdateTargetKeywordParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Keyword      string   `json:"keyword"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) UpdateTargetKeyword(ctx context.Context, arg UpdateTargetKeywordParams) (TargetKeyword, error) {
+	var targetKeyword TargetKeyword
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := bumpVersion(ctx, q, CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}, arg.Version)
+		if err != nil {
+			return err
+		}
+		oldTarget, err := q.GetTargetKeyword(ctx, GetTargetKeywordParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+		})
+		if err != nil {
+			return err
+		}
+
+		targetKeyword, err = q.updateTargetKeyword(ctx, updateTargetKeywordParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			Keyword:      arg.Keyword,
+			Rule:         arg.Rule,
+		})
+		if err != nil {
+			return err
+		}
+
+		return store.createHistory(ctx, q, []createCampaignHistoryParams{
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "keyword",
+				OldValue:     oldTarget.Keyword,
+				NewValue:     targetKeyword.Keyword,
+			},
+			{
+				AdvertiserID: arg.AdvertiserID,
+				Cid:          arg.Cid,
+				FieldChanged: "keyword_rule",
+				OldValue:     string(oldTarget.Rule),
+				NewValue:     string(targetKeyword.Rule),
+			},
+		})
+	})
+	return targetKeyword, err
+}
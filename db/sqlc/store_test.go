@@ -2,6 +2,8 @@ package db_test
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -10,35 +12,178 @@ import (
 )
 
 func TestDelivery(t *testing.T) {
-	campaigns := make([]db.Campaign, 3)
+	campaigns := make([]db.Campaign, 13)
 
 	campaigns[0] = addRandomCampaign(t)
 	arg1 := db.AddTargetAppParams{
-		Cid:   campaigns[0].Cid,
-		AppID: "app1,app2,app3",
-		Rule:  "include",
+		AdvertiserID: campaigns[0].AdvertiserID,
+		Cid:          campaigns[0].Cid,
+		AppID:        "app1,app2,app3",
+		Rule:         "include",
 	}
 	_, err := testStore.AddTargetApp(context.Background(), arg1)
 	require.NoError(t, err)
 
 	campaigns[1] = addRandomCampaign(t)
 	arg2 := db.AddTargetCountryParams{
-		Cid:     campaigns[1].Cid,
-		Country: "US,UK,CA",
-		Rule:    "exclude",
+		AdvertiserID: campaigns[1].AdvertiserID,
+		Cid:          campaigns[1].Cid,
+		Country:      "US,UK,CA",
+		Rule:         "exclude",
 	}
 	_, err = testStore.AddTargetCountry(context.Background(), arg2)
 	require.NoError(t, err)
 
 	campaigns[2] = addRandomCampaign(t)
 	arg3 := db.AddTargetOsParams{
-		Cid:  campaigns[2].Cid,
-		Os:   "android,ios",
-		Rule: "include",
+		AdvertiserID: campaigns[2].AdvertiserID,
+		Cid:          campaigns[2].Cid,
+		Os:           "android,ios",
+		Rule:         "include",
 	}
 	_, err = testStore.AddTargetOs(context.Background(), arg3)
 	require.NoError(t, err)
 
+	campaigns[3] = addRandomCampaign(t)
+	arg4 := db.AddTargetOsVersionParams{
+		AdvertiserID: campaigns[3].AdvertiserID,
+		Cid:          campaigns[3].Cid,
+		OsVersion:    "15.0-17.x",
+		Rule:         "include",
+	}
+	_, err = testStore.AddTargetOsVersion(context.Background(), arg4)
+	require.NoError(t, err)
+
+	campaigns[4] = addRandomCampaign(t)
+	arg5 := db.AddTargetAppVersionParams{
+		AdvertiserID: campaigns[4].AdvertiserID,
+		Cid:          campaigns[4].Cid,
+		AppVersion:   "<3.2",
+		Rule:         "exclude",
+	}
+	_, err = testStore.AddTargetAppVersion(context.Background(), arg5)
+	require.NoError(t, err)
+
+	campaigns[5] = addRandomCampaign(t)
+	arg6 := db.AddTargetLanguageParams{
+		AdvertiserID: campaigns[5].AdvertiserID,
+		Cid:          campaigns[5].Cid,
+		Language:     "pt, es",
+		Rule:         "include",
+	}
+	_, err = testStore.AddTargetLanguage(context.Background(), arg6)
+	require.NoError(t, err)
+
+	// Locale overrides of live campaigns wait for review, so these are
+	// added before the campaign goes live.
+	campaigns[6] = addRandomDraftCampaign(t)
+	_, err = testStore.AddCampaignLocale(context.Background(), db.AddCampaignLocaleParams{
+		AdvertiserID: campaigns[6].AdvertiserID,
+		Cid:          campaigns[6].Cid,
+		Locale:       "pt",
+		Img:          "https://example.com/pt.png",
+		Cta:          "Baixar",
+	})
+	require.NoError(t, err)
+	_, err = testStore.AddCampaignLocale(context.Background(), db.AddCampaignLocaleParams{
+		AdvertiserID: campaigns[6].AdvertiserID,
+		Cid:          campaigns[6].Cid,
+		Locale:       "pt-BR",
+		Cta:          "Instalar",
+	})
+	require.NoError(t, err)
+	campaigns[6] = launchCampaign(t, campaigns[6].Key())
+
+	campaigns[7] = addRandomCampaign(t)
+	arg8 := db.AddTargetRegionParams{
+		AdvertiserID: campaigns[7].AdvertiserID,
+		Cid:          campaigns[7].Cid,
+		Region:       "TX, CA",
+		Rule:         "include",
+	}
+	_, err = testStore.AddTargetRegion(context.Background(), arg8)
+	require.NoError(t, err)
+
+	campaigns[8] = addRandomCampaign(t)
+	_, err = testStore.AddTargetRadiusCircles(context.Background(), db.AddTargetRadiusCirclesParams{
+		AdvertiserID: campaigns[8].AdvertiserID,
+		Cid:          campaigns[8].Cid,
+		Rule:         "include",
+		Circles: []util.Circle{
+			{Lat: 30.2672, Lon: -97.7431, RadiusKm: 25},
+			{Lat: 32.7767, Lon: -96.7970, RadiusKm: 25},
+		},
+	})
+	require.NoError(t, err)
+	_, err = testStore.AddTargetRadiusCircles(context.Background(), db.AddTargetRadiusCirclesParams{
+		AdvertiserID: campaigns[8].AdvertiserID,
+		Cid:          campaigns[8].Cid,
+		Rule:         "exclude",
+		Circles:      []util.Circle{{Lat: 30.2500, Lon: -97.7500, RadiusKm: 2}},
+	})
+	require.NoError(t, err)
+
+	blocklist, err := testStore.CreateTargetList(context.Background(), db.CreateTargetListParams{
+		AdvertiserID: testAdvertiser.ID,
+		Name:         util.RandomName(),
+		ListType:     db.ListTypeApp,
+		Items:        "app5, app6",
+	})
+	require.NoError(t, err)
+
+	campaigns[9] = addRandomCampaign(t)
+	_, err = testStore.AddCampaignTargetList(context.Background(), db.AddCampaignTargetListParams{
+		AdvertiserID: campaigns[9].AdvertiserID,
+		Cid:          campaigns[9].Cid,
+		ListID:       blocklist.ID,
+		Rule:         "exclude",
+	})
+	require.NoError(t, err)
+
+	segment := createRandomAudienceSegment(t)
+	_, err = testStore.UploadSegment(context.Background(), db.UploadSegmentParams{
+		AdvertiserID: testAdvertiser.ID,
+		ID:           segment.ID,
+		Storage:      db.SegmentStorageSet,
+		Members:      strings.NewReader(segmentMember + "\n"),
+	})
+	require.NoError(t, err)
+
+	puzzleApp := util.RandomString(12)
+	_, err = testStore.UpsertAppMetadata(context.Background(), db.UpsertAppMetadataParams{
+		AppID:      puzzleApp,
+		Categories: "IAB9-30",
+		Tags:       "puzzle, casual",
+	})
+	require.NoError(t, err)
+
+	campaigns[11] = addRandomCampaign(t)
+	_, err = testStore.AddTargetCategory(context.Background(), db.AddTargetCategoryParams{
+		AdvertiserID: campaigns[11].AdvertiserID,
+		Cid:          campaigns[11].Cid,
+		Category:     "IAB9",
+		Rule:         "include",
+	})
+	require.NoError(t, err)
+
+	campaigns[12] = addRandomCampaign(t)
+	_, err = testStore.AddTargetKeyword(context.Background(), db.AddTargetKeywordParams{
+		AdvertiserID: campaigns[12].AdvertiserID,
+		Cid:          campaigns[12].Cid,
+		Keyword:      "casual",
+		Rule:         "exclude",
+	})
+	require.NoError(t, err)
+
+	campaigns[10] = addRandomCampaign(t)
+	_, err = testStore.AddCampaignSegment(context.Background(), db.AddCampaignSegmentParams{
+		AdvertiserID: campaigns[10].AdvertiserID,
+		Cid:          campaigns[10].Cid,
+		SegmentID:    segment.ID,
+		Rule:         "include",
+	})
+	require.NoError(t, err)
+
 	testCases := []struct {
 		name         string
 		deliveryArgs db.DeliveryParams
@@ -110,6 +255,292 @@ func TestDelivery(t *testing.T) {
 				require.NotContains(t, extractCids(results), campaigns[2].Cid)
 			},
 		},
+		{
+			name: "Match OS version range",
+			deliveryArgs: db.DeliveryParams{
+				AppID:     "app1",
+				Country:   "IN",
+				Os:        "ios",
+				OsVersion: "17.4.1 (21E236)",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.Contains(t, extractCids(results), campaigns[3].Cid)
+			},
+		},
+		{
+			name: "No match for OS version range",
+			deliveryArgs: db.DeliveryParams{
+				AppID:     "app1",
+				Country:   "IN",
+				Os:        "ios",
+				OsVersion: "18.0",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[3].Cid)
+			},
+		},
+		{
+			name: "Missing OS version fails inclusion range",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "IN",
+				Os:      "ios",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[3].Cid)
+			},
+		},
+		{
+			name: "Match app version exclusion range",
+			deliveryArgs: db.DeliveryParams{
+				AppID:      "app1",
+				Country:    "IN",
+				Os:         "android",
+				AppVersion: "3.2.0",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.Contains(t, extractCids(results), campaigns[4].Cid)
+			},
+		},
+		{
+			name: "No match for app version exclusion range",
+			deliveryArgs: db.DeliveryParams{
+				AppID:      "app1",
+				Country:    "IN",
+				Os:         "android",
+				AppVersion: "3.1.9",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[4].Cid)
+			},
+		},
+		{
+			name: "Match language by fallback",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "BR",
+				Os:      "android",
+				Lang:    "pt-BR",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.Contains(t, extractCids(results), campaigns[5].Cid)
+			},
+		},
+		{
+			name: "No match for language inclusion rule",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "US",
+				Os:      "android",
+				Lang:    "en-US",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[5].Cid)
+			},
+		},
+		{
+			name: "Localized creative follows locale fallback chain",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "BR",
+				Os:      "ios",
+				Lang:    "pt-BR",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				result := findResult(t, results, campaigns[6].Cid)
+				require.Equal(t, "https://example.com/pt.png", result.Img)
+				require.Equal(t, "Instalar", result.Cta)
+			},
+		},
+		{
+			name: "Default creative without a matching locale",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "FR",
+				Os:      "ios",
+				Lang:    "fr",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				result := findResult(t, results, campaigns[6].Cid)
+				require.Equal(t, campaigns[6].Img, result.Img)
+				require.Equal(t, campaigns[6].Cta, result.Cta)
+			},
+		},
+		{
+			name: "Match parent category of the requesting app",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   puzzleApp,
+				Country: "IN",
+				Os:      "android",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.Contains(t, extractCids(results), campaigns[11].Cid)
+			},
+		},
+		{
+			name: "Unknown app fails category inclusion",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "IN",
+				Os:      "android",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[11].Cid)
+			},
+		},
+		{
+			name: "No match for keyword exclusion rule",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   puzzleApp,
+				Country: "IN",
+				Os:      "android",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[12].Cid)
+			},
+		},
+		{
+			name: "Unknown app passes keyword exclusion",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "IN",
+				Os:      "android",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.Contains(t, extractCids(results), campaigns[12].Cid)
+			},
+		},
+		{
+			name: "Match segment inclusion rule",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "IN",
+				Os:      "android",
+				UserID:  segmentMember,
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.Contains(t, extractCids(results), campaigns[10].Cid)
+			},
+		},
+		{
+			name: "No match for segment inclusion rule",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "IN",
+				Os:      "android",
+				UserID:  strings.Repeat("0", 32),
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[10].Cid)
+			},
+		},
+		{
+			name: "Missing user id fails segment inclusion",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "IN",
+				Os:      "android",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[10].Cid)
+			},
+		},
+		{
+			name: "Match shared list exclusion rule",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "IN",
+				Os:      "android",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.Contains(t, extractCids(results), campaigns[9].Cid)
+			},
+		},
+		{
+			name: "No match for shared list exclusion rule",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app5",
+				Country: "IN",
+				Os:      "android",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[9].Cid)
+			},
+		},
+		{
+			name: "Match region inclusion rule",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "US",
+				Os:      "android",
+				Region:  "TX",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.Contains(t, extractCids(results), campaigns[7].Cid)
+			},
+		},
+		{
+			name: "No match for region inclusion rule",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "US",
+				Os:      "android",
+				Region:  "NY",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[7].Cid)
+			},
+		},
+		{
+			name: "Match radius inclusion circle",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "US",
+				Os:      "android",
+				Lat:     floatPtr(32.80),
+				Lon:     floatPtr(-96.80),
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.Contains(t, extractCids(results), campaigns[8].Cid)
+			},
+		},
+		{
+			name: "No match outside radius circles",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "US",
+				Os:      "android",
+				Lat:     floatPtr(29.7604),
+				Lon:     floatPtr(-95.3698),
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[8].Cid)
+			},
+		},
+		{
+			name: "No match inside radius exclusion circle",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "US",
+				Os:      "android",
+				Lat:     floatPtr(30.2510),
+				Lon:     floatPtr(-97.7490),
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[8].Cid)
+			},
+		},
+		{
+			name: "Missing coordinates fail radius inclusion",
+			deliveryArgs: db.DeliveryParams{
+				AppID:   "app1",
+				Country: "US",
+				Os:      "android",
+			},
+			checkResults: func(t *testing.T, results []db.DeliveryResult) {
+				require.NotContains(t, extractCids(results), campaigns[8].Cid)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -120,12 +551,38 @@ func TestDelivery(t *testing.T) {
 		})
 	}
 
+	t.Run("Shared list edits reach cached delivery", func(t *testing.T) {
+		_, err := testStore.UpdateTargetList(context.Background(), db.UpdateTargetListParams{
+			AdvertiserID: testAdvertiser.ID,
+			ID:           blocklist.ID,
+			Name:         blocklist.Name,
+			Items:        "app1, app5, app6",
+		})
+		require.NoError(t, err)
+
+		results, err := testStore.Delivery(context.Background(), db.DeliveryParams{
+			AppID:   "app1",
+			Country: "IN",
+			Os:      "android",
+		})
+		require.NoError(t, err)
+		require.NotContains(t, extractCids(results), campaigns[9].Cid)
+	})
+
 	for _, campaign := range campaigns {
-		err := testStore.DeleteCampaign(context.Background(), campaign.Cid)
+		err := testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 		require.NoError(t, err)
 	}
+	err = testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: blocklist.ID})
+	require.NoError(t, err)
+	err = testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment.ID})
+	require.NoError(t, err)
+	err = testStore.DeleteAppMetadata(context.Background(), puzzleApp)
+	require.NoError(t, err)
 }
 
+const segmentMember = "9e107d9d372bb6826bd81d3542a419d6"
+
 func extractCids(results []db.DeliveryResult) []string {
 	cids := make([]string, len(results))
 	for i, result := range results {
@@ -134,12 +591,28 @@ func extractCids(results []db.DeliveryResult) []string {
 	return cids
 }
 
+func findResult(t *testing.T, results []db.DeliveryResult, cid string) db.DeliveryResult {
+	for _, result := range results {
+		if result.Cid == cid {
+			return result
+		}
+	}
+	require.FailNow(t, "campaign not delivered", cid)
+	return db.DeliveryResult{}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
 func addRandomCampaign(t *testing.T) db.Campaign {
 	arg := db.AddCampaignParams{
-		Cid:  util.RandomCid(),
-		Name: util.RandomName(),
-		Img:  util.RandomImg(),
-		Cta:  util.RandomCta(),
+		AdvertiserID: testAdvertiser.ID,
+		Cid:          util.RandomCid(),
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+		Status:       db.NullStatusType{StatusType: db.StatusTypeActive, Valid: true},
 	}
 
 	campaign, err := testStore.AddCampaign(context.Background(), arg)
@@ -154,12 +627,47 @@ func addRandomCampaign(t *testing.T) db.Campaign {
 	return campaign
 }
 
+// addRandomDraftCampaign adds a campaign that is not live yet, whose creative
+// changes apply without review.
+func addRandomDraftCampaign(t *testing.T) db.Campaign {
+	campaign, err := testStore.AddCampaign(context.Background(), db.AddCampaignParams{
+		AdvertiserID: testAdvertiser.ID,
+		Cid:          util.RandomCid(),
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+		Status:       db.NullStatusType{StatusType: db.StatusTypeDraft, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, db.StatusTypeDraft, campaign.Status)
+
+	return campaign
+}
+
+// launchCampaign submits a draft campaign and approves it.
+func launchCampaign(t *testing.T, key db.CampaignKey) db.Campaign {
+	var campaign db.Campaign
+	for _, transition := range []string{db.TransitionSubmit, db.TransitionApprove} {
+		var err error
+		campaign, err = testStore.TransitionCampaign(context.Background(), db.TransitionCampaignParams{
+			AdvertiserID: key.AdvertiserID,
+			Cid:          key.Cid,
+			Transition:   transition,
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, db.StatusTypeActive, campaign.Status)
+
+	return campaign
+}
+
 func createRandomCampaign(t *testing.T) db.CreateCampaignResult {
 	arg := db.CreateCampaignParams{
-		Cid:  util.RandomCid(),
-		Name: util.RandomName(),
-		Img:  util.RandomImg(),
-		Cta:  util.RandomCta(),
+		AdvertiserID: testAdvertiser.ID,
+		Cid:          util.RandomCid(),
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
 	}
 	if util.RandomBool() {
 		arg.AppID = util.RandomAppID()
@@ -173,6 +681,34 @@ func createRandomCampaign(t *testing.T) db.CreateCampaignResult {
 		arg.Os = util.RandomOs()
 		arg.OsRule = db.RuleType(util.RandomRule())
 	}
+	if util.RandomBool() {
+		arg.OsVersion = util.RandomVersionRange()
+		arg.OsVersionRule = db.RuleType(util.RandomRule())
+	}
+	if util.RandomBool() {
+		arg.AppVersion = util.RandomVersionRange()
+		arg.AppVersionRule = db.RuleType(util.RandomRule())
+	}
+	if util.RandomBool() {
+		arg.Language = util.RandomLanguage()
+		arg.LanguageRule = db.RuleType(util.RandomRule())
+	}
+	if util.RandomBool() {
+		arg.Region = util.RandomRegion()
+		arg.RegionRule = db.RuleType(util.RandomRule())
+	}
+	if util.RandomBool() {
+		arg.City = util.RandomCity()
+		arg.CityRule = db.RuleType(util.RandomRule())
+	}
+	if util.RandomBool() {
+		arg.Category = util.RandomCategory()
+		arg.CategoryRule = db.RuleType(util.RandomRule())
+	}
+	if util.RandomBool() {
+		arg.Keyword = util.RandomKeyword()
+		arg.KeywordRule = db.RuleType(util.RandomRule())
+	}
 
 	campaign, err := testStore.CreateCampaign(context.Background(), arg)
 	require.NoError(t, err)
@@ -186,7 +722,21 @@ func createRandomCampaign(t *testing.T) db.CreateCampaignResult {
 	require.Equal(t, arg.CountryRule, campaign.CountryRule)
 	require.Equal(t, arg.Os, campaign.Os)
 	require.Equal(t, arg.OsRule, campaign.OsRule)
-	require.Equal(t, db.StatusType("active"), campaign.Status)
+	require.Equal(t, arg.OsVersion, campaign.OsVersion)
+	require.Equal(t, arg.OsVersionRule, campaign.OsVersionRule)
+	require.Equal(t, arg.AppVersion, campaign.AppVersion)
+	require.Equal(t, arg.AppVersionRule, campaign.AppVersionRule)
+	require.Equal(t, arg.Language, campaign.Language)
+	require.Equal(t, arg.LanguageRule, campaign.LanguageRule)
+	require.Equal(t, arg.Region, campaign.Region)
+	require.Equal(t, arg.RegionRule, campaign.RegionRule)
+	require.Equal(t, arg.City, campaign.City)
+	require.Equal(t, arg.CityRule, campaign.CityRule)
+	require.Equal(t, arg.Category, campaign.Category)
+	require.Equal(t, arg.CategoryRule, campaign.CategoryRule)
+	require.Equal(t, arg.Keyword, campaign.Keyword)
+	require.Equal(t, arg.KeywordRule, campaign.KeywordRule)
+	require.Equal(t, db.StatusTypeDraft, campaign.Status)
 	require.NotEmpty(t, campaign.CreatedAt)
 
 	return campaign
@@ -194,12 +744,23 @@ func createRandomCampaign(t *testing.T) db.CreateCampaignResult {
 
 func TestCreateCampaign(t *testing.T) {
 	campaign := createRandomCampaign(t)
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+
+	// Campaigns go live only through review.
+	_, err := testStore.CreateCampaign(context.Background(), db.CreateCampaignParams{
+		AdvertiserID: testAdvertiser.ID,
+		Cid:          util.RandomCid(),
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+		Status:       db.StatusTypeActive,
+	})
+	require.ErrorIs(t, err, db.ErrValidation)
 }
 
 func TestReadCampaign(t *testing.T) {
 	campaign := createRandomCampaign(t)
-	read_campaign, err := testStore.ReadCampaign(context.Background(), campaign.Cid)
+	read_campaign, err := testStore.ReadCampaign(context.Background(), campaign.Key())
 	require.NoError(t, err)
 
 	require.Equal(t, campaign.Cid, read_campaign.Cid)
@@ -212,28 +773,46 @@ func TestReadCampaign(t *testing.T) {
 	require.Equal(t, campaign.CountryRule, read_campaign.CountryRule)
 	require.Equal(t, campaign.Os, read_campaign.Os)
 	require.Equal(t, campaign.OsRule, read_campaign.OsRule)
+	require.Equal(t, campaign.OsVersion, read_campaign.OsVersion)
+	require.Equal(t, campaign.OsVersionRule, read_campaign.OsVersionRule)
+	require.Equal(t, campaign.AppVersion, read_campaign.AppVersion)
+	require.Equal(t, campaign.AppVersionRule, read_campaign.AppVersionRule)
+	require.Equal(t, campaign.Language, read_campaign.Language)
+	require.Equal(t, campaign.LanguageRule, read_campaign.LanguageRule)
+	require.Equal(t, campaign.Region, read_campaign.Region)
+	require.Equal(t, campaign.RegionRule, read_campaign.RegionRule)
+	require.Equal(t, campaign.City, read_campaign.City)
+	require.Equal(t, campaign.CityRule, read_campaign.CityRule)
+	require.Equal(t, campaign.Category, read_campaign.Category)
+	require.Equal(t, campaign.CategoryRule, read_campaign.CategoryRule)
+	require.Equal(t, campaign.Keyword, read_campaign.Keyword)
+	require.Equal(t, campaign.KeywordRule, read_campaign.KeywordRule)
+	require.Empty(t, read_campaign.Radius)
+	require.Empty(t, read_campaign.TargetLists)
+	require.Empty(t, read_campaign.Segments)
+	require.Empty(t, read_campaign.Locales)
 	require.Equal(t, campaign.Status, read_campaign.Status)
 	require.Equal(t, campaign.CreatedAt, read_campaign.CreatedAt)
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
 
 func TestToggleStatus(t *testing.T) {
 	old_campaign := addRandomCampaign(t)
 
-	err := testStore.ToggleStatus(context.Background(), old_campaign.Cid)
+	err := testStore.ToggleStatus(context.Background(), old_campaign.Key(), 0)
 	require.NoError(t, err)
 
-	updated_campaign, err := testStore.GetCampaign(context.Background(), old_campaign.Cid)
+	updated_campaign, err := testStore.GetCampaign(context.Background(), db.GetCampaignParams(old_campaign.Key()))
 	require.NoError(t, err)
 	require.Equal(t, old_campaign.Cid, updated_campaign.Cid)
 	require.Equal(t, old_campaign.Name, updated_campaign.Name)
 	require.Equal(t, old_campaign.Img, updated_campaign.Img)
 	require.Equal(t, old_campaign.Cta, updated_campaign.Cta)
-	require.Equal(t, db.StatusType("inactive"), updated_campaign.Status)
+	require.Equal(t, db.StatusType("paused"), updated_campaign.Status)
 	require.Equal(t, old_campaign.CreatedAt, updated_campaign.CreatedAt)
 
-	campaignHistory, err := testStore.GetCampaignHistory(context.Background(), old_campaign.Cid)
+	campaignHistory, err := testStore.GetCampaignHistory(context.Background(), db.GetCampaignHistoryParams(old_campaign.Key()))
 	require.NoError(t, err)
 	require.NotEmpty(t, campaignHistory.ID)
 	require.Equal(t, updated_campaign.Cid, campaignHistory.Cid)
@@ -243,10 +822,10 @@ func TestToggleStatus(t *testing.T) {
 	require.NotEmpty(t, campaignHistory.UpdatedAt)
 
 	old_campaign = updated_campaign
-	err = testStore.ToggleStatus(context.Background(), old_campaign.Cid)
+	err = testStore.ToggleStatus(context.Background(), old_campaign.Key(), 0)
 	require.NoError(t, err)
 
-	updated_campaign, err = testStore.GetCampaign(context.Background(), old_campaign.Cid)
+	updated_campaign, err = testStore.GetCampaign(context.Background(), db.GetCampaignParams(old_campaign.Key()))
 	require.NoError(t, err)
 	require.Equal(t, old_campaign.Cid, updated_campaign.Cid)
 	require.Equal(t, old_campaign.Name, updated_campaign.Name)
@@ -255,16 +834,16 @@ func TestToggleStatus(t *testing.T) {
 	require.Equal(t, db.StatusType("active"), updated_campaign.Status)
 	require.Equal(t, old_campaign.CreatedAt, updated_campaign.CreatedAt)
 
-	campaignHistory, err = testStore.GetCampaignHistory(context.Background(), updated_campaign.Cid)
+	campaignHistory, err = testStore.GetCampaignHistory(context.Background(), db.GetCampaignHistoryParams(updated_campaign.Key()))
 	require.NoError(t, err)
 	require.NotEmpty(t, campaignHistory.ID)
 	require.Equal(t, updated_campaign.Cid, campaignHistory.Cid)
-	require.Equal(t, "inactive", campaignHistory.OldValue)
+	require.Equal(t, "paused", campaignHistory.OldValue)
 	require.Equal(t, string(updated_campaign.Status), campaignHistory.NewValue)
 	require.Equal(t, "status", campaignHistory.FieldChanged)
 	require.NotEmpty(t, campaignHistory.UpdatedAt)
 
-	testStore.DeleteCampaign(context.Background(), updated_campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(updated_campaign.Key()))
 }
 
 func TestUpdateCampaignName(t *testing.T) {
@@ -279,8 +858,9 @@ func TestUpdateCampaignName(t *testing.T) {
 	}
 
 	arg := db.UpdateCampaignNameParams{
-		Cid:  old_campaign.Cid,
-		Name: newName,
+		AdvertiserID: old_campaign.AdvertiserID,
+		Cid:          old_campaign.Cid,
+		Name:         newName,
 	}
 
 	updated_campaign, err := testStore.UpdateCampaignName(context.Background(), arg)
@@ -292,7 +872,7 @@ func TestUpdateCampaignName(t *testing.T) {
 	require.Equal(t, old_campaign.Status, updated_campaign.Status)
 	require.Equal(t, old_campaign.CreatedAt, updated_campaign.CreatedAt)
 
-	campaignHistory, err := testStore.GetCampaignHistory(context.Background(), arg.Cid)
+	campaignHistory, err := testStore.GetCampaignHistory(context.Background(), db.GetCampaignHistoryParams(old_campaign.Key()))
 	require.NoError(t, err)
 	require.NotEmpty(t, campaignHistory.ID)
 	require.Equal(t, arg.Cid, campaignHistory.Cid)
@@ -301,11 +881,11 @@ func TestUpdateCampaignName(t *testing.T) {
 	require.Equal(t, "name", campaignHistory.FieldChanged)
 	require.NotEmpty(t, campaignHistory.UpdatedAt)
 
-	testStore.DeleteCampaign(context.Background(), arg.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(old_campaign.Key()))
 }
 
 func TestUpdateCampaignCta(t *testing.T) {
-	old_campaign := addRandomCampaign(t)
+	old_campaign := addRandomDraftCampaign(t)
 
 	var newCta string
 	for {
@@ -316,12 +896,15 @@ func TestUpdateCampaignCta(t *testing.T) {
 	}
 
 	arg := db.UpdateCampaignCtaParams{
-		Cid: old_campaign.Cid,
-		Cta: newCta,
+		AdvertiserID: old_campaign.AdvertiserID,
+		Cid:          old_campaign.Cid,
+		Cta:          newCta,
 	}
 
-	updated_campaign, err := testStore.UpdateCampaignCta(context.Background(), arg)
+	result, err := testStore.UpdateCampaignCta(context.Background(), arg)
 	require.NoError(t, err)
+	require.Nil(t, result.Revision)
+	updated_campaign := result.Campaign
 	require.Equal(t, old_campaign.Cid, updated_campaign.Cid)
 	require.Equal(t, old_campaign.Name, updated_campaign.Name)
 	require.Equal(t, old_campaign.Img, updated_campaign.Img)
@@ -329,7 +912,7 @@ func TestUpdateCampaignCta(t *testing.T) {
 	require.Equal(t, old_campaign.Status, updated_campaign.Status)
 	require.Equal(t, old_campaign.CreatedAt, updated_campaign.CreatedAt)
 
-	campaignHistory, err := testStore.GetCampaignHistory(context.Background(), arg.Cid)
+	campaignHistory, err := testStore.GetCampaignHistory(context.Background(), db.GetCampaignHistoryParams(old_campaign.Key()))
 	require.NoError(t, err)
 	require.NotEmpty(t, campaignHistory.ID)
 	require.Equal(t, arg.Cid, campaignHistory.Cid)
@@ -338,11 +921,11 @@ func TestUpdateCampaignCta(t *testing.T) {
 	require.Equal(t, "cta", campaignHistory.FieldChanged)
 	require.NotEmpty(t, campaignHistory.UpdatedAt)
 
-	testStore.DeleteCampaign(context.Background(), arg.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(old_campaign.Key()))
 }
 
 func TestUpdateCampaignImage(t *testing.T) {
-	old_campaign := addRandomCampaign(t)
+	old_campaign := addRandomDraftCampaign(t)
 
 	var newImg string
 	for {
@@ -353,12 +936,15 @@ func TestUpdateCampaignImage(t *testing.T) {
 	}
 
 	arg := db.UpdateCampaignImageParams{
-		Cid: old_campaign.Cid,
-		Img: newImg,
+		AdvertiserID: old_campaign.AdvertiserID,
+		Cid:          old_campaign.Cid,
+		Img:          newImg,
 	}
 
-	updated_campaign, err := testStore.UpdateCampaignImage(context.Background(), arg)
+	result, err := testStore.UpdateCampaignImage(context.Background(), arg)
 	require.NoError(t, err)
+	require.Nil(t, result.Revision)
+	updated_campaign := result.Campaign
 	require.Equal(t, old_campaign.Cid, updated_campaign.Cid)
 	require.Equal(t, old_campaign.Name, updated_campaign.Name)
 	require.Equal(t, arg.Img, updated_campaign.Img)
@@ -366,7 +952,7 @@ func TestUpdateCampaignImage(t *testing.T) {
 	require.Equal(t, old_campaign.Status, updated_campaign.Status)
 	require.Equal(t, old_campaign.CreatedAt, updated_campaign.CreatedAt)
 
-	campaignHistory, err := testStore.GetCampaignHistory(context.Background(), arg.Cid)
+	campaignHistory, err := testStore.GetCampaignHistory(context.Background(), db.GetCampaignHistoryParams(old_campaign.Key()))
 	require.NoError(t, err)
 	require.NotEmpty(t, campaignHistory.ID)
 	require.Equal(t, arg.Cid, campaignHistory.Cid)
@@ -375,16 +961,17 @@ func TestUpdateCampaignImage(t *testing.T) {
 	require.Equal(t, "img", campaignHistory.FieldChanged)
 	require.NotEmpty(t, campaignHistory.UpdatedAt)
 
-	testStore.DeleteCampaign(context.Background(), arg.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(old_campaign.Key()))
 }
 
 func TestUpdateTargetApp(t *testing.T) {
 	campaign := addRandomCampaign(t)
 
 	new_arg := db.AddTargetAppParams{
-		Cid:   campaign.Cid,
-		AppID: util.RandomAppID(),
-		Rule:  db.RuleType(util.RandomRule()),
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		AppID:        util.RandomAppID(),
+		Rule:         db.RuleType(util.RandomRule()),
 	}
 
 	old_target_app, err := testStore.AddTargetApp(context.Background(), new_arg)
@@ -402,9 +989,10 @@ func TestUpdateTargetApp(t *testing.T) {
 	}
 
 	update_arg := db.UpdateTargetAppParams{
-		Cid:   campaign.Cid,
-		AppID: newAppID,
-		Rule:  db.RuleType(util.RandomRule()),
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		AppID:        newAppID,
+		Rule:         db.RuleType(util.RandomRule()),
 	}
 
 	updated_target_app, err := testStore.UpdateTargetApp(context.Background(), update_arg)
@@ -413,7 +1001,7 @@ func TestUpdateTargetApp(t *testing.T) {
 	require.Equal(t, update_arg.AppID, updated_target_app.AppID)
 	require.Equal(t, update_arg.Rule, updated_target_app.Rule)
 
-	campaignHistory, err := testStore.GetLastTwoCampaignHistory(context.Background(), campaign.Cid)
+	campaignHistory, err := testStore.GetLastTwoCampaignHistory(context.Background(), db.GetLastTwoCampaignHistoryParams(campaign.Key()))
 	require.NoError(t, err)
 
 	for _, history := range campaignHistory {
@@ -433,16 +1021,17 @@ func TestUpdateTargetApp(t *testing.T) {
 		}
 	}
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
 
 func TestUpdateTargetOs(t *testing.T) {
 	campaign := addRandomCampaign(t)
 
 	new_arg := db.AddTargetOsParams{
-		Cid:  campaign.Cid,
-		Os:   util.RandomOs(),
-		Rule: db.RuleType(util.RandomRule()),
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Os:           util.RandomOs(),
+		Rule:         db.RuleType(util.RandomRule()),
 	}
 
 	old_target_os, err := testStore.AddTargetOs(context.Background(), new_arg)
@@ -460,9 +1049,10 @@ func TestUpdateTargetOs(t *testing.T) {
 	}
 
 	update_arg := db.UpdateTargetOsParams{
-		Cid:  campaign.Cid,
-		Os:   newOs,
-		Rule: db.RuleType(util.RandomRule()),
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Os:           newOs,
+		Rule:         db.RuleType(util.RandomRule()),
 	}
 
 	updated_target_os, err := testStore.UpdateTargetOs(context.Background(), update_arg)
@@ -471,7 +1061,7 @@ func TestUpdateTargetOs(t *testing.T) {
 	require.Equal(t, update_arg.Os, updated_target_os.Os)
 	require.Equal(t, update_arg.Rule, updated_target_os.Rule)
 
-	campaignHistory, err := testStore.GetLastTwoCampaignHistory(context.Background(), campaign.Cid)
+	campaignHistory, err := testStore.GetLastTwoCampaignHistory(context.Background(), db.GetLastTwoCampaignHistoryParams(campaign.Key()))
 	require.NoError(t, err)
 
 	for _, history := range campaignHistory {
@@ -491,16 +1081,17 @@ func TestUpdateTargetOs(t *testing.T) {
 		}
 	}
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
 
 func TestUpdateTargetCountry(t *testing.T) {
 	campaign := addRandomCampaign(t)
 
 	new_arg := db.AddTargetCountryParams{
-		Cid:     campaign.Cid,
-		Country: util.RandomCountry(),
-		Rule:    db.RuleType(util.RandomRule()),
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Country:      util.RandomCountry(),
+		Rule:         db.RuleType(util.RandomRule()),
 	}
 
 	old_target_country, err := testStore.AddTargetCountry(context.Background(), new_arg)
@@ -518,9 +1109,10 @@ func TestUpdateTargetCountry(t *testing.T) {
 	}
 
 	update_arg := db.UpdateTargetCountryParams{
-		Cid:     campaign.Cid,
-		Country: newCountry,
-		Rule:    db.RuleType(util.RandomRule()),
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Country:      newCountry,
+		Rule:         db.RuleType(util.RandomRule()),
 	}
 
 	updated_target_country, err := testStore.UpdateTargetCountry(context.Background(), update_arg)
@@ -529,7 +1121,7 @@ func TestUpdateTargetCountry(t *testing.T) {
 	require.Equal(t, update_arg.Country, updated_target_country.Country)
 	require.Equal(t, update_arg.Rule, updated_target_country.Rule)
 
-	campaignHistory, err := testStore.GetLastTwoCampaignHistory(context.Background(), campaign.Cid)
+	campaignHistory, err := testStore.GetLastTwoCampaignHistory(context.Background(), db.GetLastTwoCampaignHistoryParams(campaign.Key()))
 	require.NoError(t, err)
 
 	for _, history := range campaignHistory {
@@ -549,5 +1141,291 @@ func TestUpdateTargetCountry(t *testing.T) {
 		}
 	}
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestUpdateTargetOsVersion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+
+	new_arg := db.AddTargetOsVersionParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		OsVersion:    util.RandomVersionRange(),
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	old_target_os_version, err := testStore.AddTargetOsVersion(context.Background(), new_arg)
+	require.NoError(t, err)
+	require.Equal(t, new_arg.Cid, old_target_os_version.Cid)
+	require.Equal(t, new_arg.OsVersion, old_target_os_version.OsVersion)
+	require.Equal(t, new_arg.Rule, old_target_os_version.Rule)
+
+	var newOsVersion string
+	for {
+		newOsVersion = util.RandomVersionRange()
+		if newOsVersion != old_target_os_version.OsVersion {
+			break
+		}
+	}
+
+	update_arg := db.UpdateTargetOsVersionParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		OsVersion:    newOsVersion,
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	updated_target_os_version, err := testStore.UpdateTargetOsVersion(context.Background(), update_arg)
+	require.NoError(t, err)
+	require.Equal(t, campaign.Cid, updated_target_os_version.Cid)
+	require.Equal(t, update_arg.OsVersion, updated_target_os_version.OsVersion)
+	require.Equal(t, update_arg.Rule, updated_target_os_version.Rule)
+
+	campaignHistory, err := testStore.GetLastTwoCampaignHistory(context.Background(), db.GetLastTwoCampaignHistoryParams(campaign.Key()))
+	require.NoError(t, err)
+
+	for _, history := range campaignHistory {
+		expected_changes := []string{"os_version", "os_version_rule"}
+		require.NotEmpty(t, history.ID)
+		require.Equal(t, campaign.Cid, history.Cid)
+		require.Contains(t, expected_changes, history.FieldChanged)
+		require.NotEmpty(t, history.UpdatedAt)
+		if history.FieldChanged == "os_version" {
+			require.Equal(t, "os_version", history.FieldChanged)
+			require.Equal(t, old_target_os_version.OsVersion, history.OldValue)
+			require.Equal(t, updated_target_os_version.OsVersion, history.NewValue)
+		} else {
+			require.Equal(t, "os_version_rule", history.FieldChanged)
+			require.Equal(t, string(old_target_os_version.Rule), history.OldValue)
+			require.Equal(t, string(updated_target_os_version.Rule), history.NewValue)
+		}
+	}
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestUpdateCampaignLocale(t *testing.T) {
+	campaign := addRandomDraftCampaign(t)
+	old_locale := addRandomCampaignLocale(t, campaign.Key(), "pt-BR")
+
+	var newCta string
+	for {
+		newCta = util.RandomCta()
+		if newCta != old_locale.Cta {
+			break
+		}
+	}
+
+	arg := db.UpdateCampaignLocaleParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		Locale:       old_locale.Locale,
+		Img:          old_locale.Img,
+		Cta:          newCta,
+	}
+
+	result, err := testStore.UpdateCampaignLocale(context.Background(), arg)
+	require.NoError(t, err)
+	require.Nil(t, result.Revision)
+	updated_locale := result.Locale
+	require.Equal(t, arg.Cid, updated_locale.Cid)
+	require.Equal(t, arg.Locale, updated_locale.Locale)
+	require.Equal(t, arg.Img, updated_locale.Img)
+	require.Equal(t, arg.Cta, updated_locale.Cta)
+
+	campaignHistory, err := testStore.GetCampaignHistory(context.Background(), db.GetCampaignHistoryParams(campaign.Key()))
+	require.NoError(t, err)
+	require.NotEmpty(t, campaignHistory.ID)
+	require.Equal(t, campaign.Cid, campaignHistory.Cid)
+	require.Equal(t, "cta:pt-BR", campaignHistory.FieldChanged)
+	require.Equal(t, old_locale.Cta, campaignHistory.OldValue)
+	require.Equal(t, updated_locale.Cta, campaignHistory.NewValue)
+	require.NotEmpty(t, campaignHistory.UpdatedAt)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestUpdateTargetList(t *testing.T) {
+	old_target_list := createRandomTargetList(t)
+
+	var newItems string
+	for {
+		newItems = util.RandomAppID()
+		if newItems != old_target_list.Items {
+			break
+		}
+	}
+
+	arg := db.UpdateTargetListParams{
+		AdvertiserID: testAdvertiser.ID,
+		ID:           old_target_list.ID,
+		Name:         old_target_list.Name,
+		Items:        newItems,
+	}
+
+	updated_target_list, err := testStore.UpdateTargetList(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, old_target_list.ID, updated_target_list.ID)
+	require.Equal(t, old_target_list.Name, updated_target_list.Name)
+	require.Equal(t, old_target_list.ListType, updated_target_list.ListType)
+	require.Equal(t, arg.Items, updated_target_list.Items)
+	require.Equal(t, old_target_list.Version+1, updated_target_list.Version)
+	require.Equal(t, old_target_list.CreatedAt, updated_target_list.CreatedAt)
+
+	history, err := testStore.ListTargetListHistory(context.Background(), old_target_list.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, old_target_list.ID, history[0].ListID)
+	require.Equal(t, updated_target_list.Version, history[0].Version)
+	require.Equal(t, "items", history[0].FieldChanged)
+	require.Equal(t, old_target_list.Items, history[0].OldValue)
+	require.Equal(t, updated_target_list.Items, history[0].NewValue)
+	require.NotEmpty(t, history[0].UpdatedAt)
+
+	testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: old_target_list.ID})
+}
+
+func TestUploadSegment(t *testing.T) {
+	members := "9E107D9D372BB6826BD81D3542A419D6\n\ne4d909c290d0fb1ca068ffaddf22cbd0\n9e107d9d372bb6826bd81d3542a419d6\n"
+
+	for _, storage := range []db.SegmentStorage{db.SegmentStorageSet, db.SegmentStorageBloom} {
+		t.Run(string(storage), func(t *testing.T) {
+			segment := createRandomAudienceSegment(t)
+
+			uploaded, err := testStore.UploadSegment(context.Background(), db.UploadSegmentParams{
+				AdvertiserID:    testAdvertiser.ID,
+				ID:              segment.ID,
+				Storage:         storage,
+				ExpectedMembers: 10,
+				Members:         strings.NewReader(members),
+			})
+			require.NoError(t, err)
+			require.Equal(t, segment.ID, uploaded.ID)
+			require.Equal(t, storage, uploaded.Storage)
+			if storage == db.SegmentStorageSet {
+				require.Equal(t, int64(2), uploaded.MemberCount)
+			} else {
+				require.Equal(t, int64(3), uploaded.MemberCount)
+				require.NotZero(t, uploaded.BloomBits)
+				require.NotZero(t, uploaded.BloomHashes)
+			}
+
+			_, err = testStore.UploadSegment(context.Background(), db.UploadSegmentParams{
+				AdvertiserID: testAdvertiser.ID,
+				ID:           segment.ID,
+				Storage:      storage,
+				Members:      strings.NewReader("9e107d9d372bb6826bd81d3542a419d6\nnot-a-hash\n"),
+			})
+			var memberErr *db.SegmentMemberError
+			require.True(t, errors.As(err, &memberErr))
+			require.Equal(t, 2, memberErr.Line)
+
+			testStore.DeleteAudienceSegment(context.Background(), db.DeleteAudienceSegmentParams{AdvertiserID: testAdvertiser.ID, ID: segment.ID})
+		})
+	}
+}
+
+func TestImportAppMetadataClassifiesUnknownApps(t *testing.T) {
+	appID := util.RandomString(12)
+
+	_, err := testStore.Delivery(context.Background(), db.DeliveryParams{
+		AppID:   appID,
+		Country: "IN",
+		Os:      "android",
+	})
+	require.NoError(t, err)
+
+	_, ok := findUnknownApp(t, appID)
+	require.True(t, ok)
+
+	apps, err := testStore.ImportAppMetadata(context.Background(), []db.UpsertAppMetadataParams{
+		{AppID: appID, Categories: util.RandomCategory(), Tags: util.RandomKeyword()},
+	})
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+
+	_, ok = findUnknownApp(t, appID)
+	require.False(t, ok)
+
+	testStore.DeleteAppMetadata(context.Background(), appID)
+}
+
+func extractKeys(results []db.DeliveryResult) []db.CampaignKey {
+	keys := make([]db.CampaignKey, len(results))
+	for i, result := range results {
+		keys[i] = result.Key()
+	}
+	return keys
+}
+
+func TestCampaignsIsolatedByAdvertiser(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+	campaign := addRandomCampaign(t)
+
+	other_campaign, err := testStore.AddCampaign(context.Background(), db.AddCampaignParams{
+		AdvertiserID: advertiser.ID,
+		Cid:          campaign.Cid,
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+		Status:       db.NullStatusType{StatusType: db.StatusTypeActive, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, campaign.Cid, other_campaign.Cid)
+
+	err = testStore.ToggleStatus(context.Background(), other_campaign.Key(), 0)
+	require.NoError(t, err)
+
+	get_campaign, err := testStore.GetCampaign(context.Background(), db.GetCampaignParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, db.StatusTypeActive, get_campaign.Status)
+
+	campaigns, err := testStore.ListCampaigns(context.Background(), advertiser.ID)
+	require.NoError(t, err)
+	require.Len(t, campaigns, 1)
+	require.Equal(t, db.StatusTypePaused, campaigns[0].Status)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(other_campaign.Key()))
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+}
+
+func TestDeliveryPublisherAdvertisers(t *testing.T) {
+	advertiser := createRandomAdvertiser(t)
+	campaign := addRandomCampaign(t)
+	other_campaign, err := testStore.AddCampaign(context.Background(), db.AddCampaignParams{
+		AdvertiserID: advertiser.ID,
+		Cid:          util.RandomCid(),
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+		Status:       db.NullStatusType{StatusType: db.StatusTypeActive, Valid: true},
+	})
+	require.NoError(t, err)
+
+	deliveryArgs := db.DeliveryParams{
+		AppID:   util.RandomAppID(),
+		Country: "IN",
+		Os:      "android",
+	}
+
+	results, err := testStore.Delivery(context.Background(), deliveryArgs)
+	require.NoError(t, err)
+	require.Contains(t, extractKeys(results), campaign.Key())
+	require.Contains(t, extractKeys(results), other_campaign.Key())
+
+	_, err = testStore.AddPublisherAdvertiser(context.Background(), db.AddPublisherAdvertiserParams{
+		AppID:        deliveryArgs.AppID,
+		AdvertiserID: advertiser.ID,
+	})
+	require.NoError(t, err)
+
+	results, err = testStore.Delivery(context.Background(), deliveryArgs)
+	require.NoError(t, err)
+	require.NotContains(t, extractKeys(results), campaign.Key())
+	require.Contains(t, extractKeys(results), other_campaign.Key())
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(other_campaign.Key()))
+	testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
 }
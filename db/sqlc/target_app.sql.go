@@ -9,69 +9,107 @@ import (
 	"context"
 )
 
-const addTargetApp = `-- name: AddTargetApp :one
+const getTargetApp = `-- name: GetTargetApp :one
+SELECT cid, app_id, rule, advertiser_id
+FROM target_app
+WHERE advertiser_id = $1 AND cid = $2
+`
+
+type GetTargetAppParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+}
+
+func (q *Queries) GetTargetApp(ctx context.Context, arg GetTargetAppParams) (TargetApp, error) {
+	row := q.db.QueryRow(ctx, getTargetApp, arg.AdvertiserID, arg.Cid)
+	var i TargetApp
+	err := row.Scan(
+		&i.Cid,
+		&i.AppID,
+		&i.Rule,
+		&i.AdvertiserID,
+	)
+	return i, err
+}
+
+const addTargetApp = `-- name: addTargetApp :one
 INSERT INTO target_app (
+    advertiser_id,
     cid,
     app_id,
     rule
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 )
-RETURNING cid, app_id, rule
+RETURNING cid, app_id, rule, advertiser_id
 `
 
-type AddTargetAppParams struct {
-	Cid   string   `json:"cid"`
-	AppID string   `json:"app_id"`
-	Rule  RuleType `json:"rule"`
+type addTargetAppParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	AppID        string   `json:"app_id"`
+	Rule         RuleType `json:"rule"`
 }
 
-func (q *Queries) AddTargetApp(ctx context.Context, arg AddTargetAppParams) (TargetApp, error) {
-	row := q.db.QueryRow(ctx, addTargetApp, arg.Cid, arg.AppID, arg.Rule)
+func (q *Queries) addTargetApp(ctx context.Context, arg addTargetAppParams) (TargetApp, error) {
+	row := q.db.QueryRow(ctx, addTargetApp,
+		arg.AdvertiserID,
+		arg.Cid,
+		arg.AppID,
+		arg.Rule,
+	)
 	var i TargetApp
-	err := row.Scan(&i.Cid, &i.AppID, &i.Rule)
+	err := row.Scan(
+		&i.Cid,
+		&i.AppID,
+		&i.Rule,
+		&i.AdvertiserID,
+	)
 	return i, err
 }
 
-const deleteTargetApp = `-- name: DeleteTargetApp :exec
+const deleteTargetApp = `-- name: deleteTargetApp :exec
 DELETE FROM target_app
-WHERE cid = $1
+WHERE advertiser_id = $1 AND cid = $2
 `
 
-func (q *Queries) DeleteTargetApp(ctx context.Context, cid string) error {
-	_, err := q.db.Exec(ctx, deleteTargetApp, cid)
-	return err
+type deleteTargetAppParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
 }
 
-const getTargetApp = `-- name: GetTargetApp :one
-SELECT cid, app_id, rule
-FROM target_app
-WHERE cid = $1
-`
-
-func (q *Queries) GetTargetApp(ctx context.Context, cid string) (TargetApp, error) {
-	row := q.db.QueryRow(ctx, getTargetApp, cid)
-	var i TargetApp
-	err := row.Scan(&i.Cid, &i.AppID, &i.Rule)
-	return i, err
+func (q *Queries) deleteTargetApp(ctx context.Context, arg deleteTargetAppParams) error {
+	_, err := q.db.Exec(ctx, deleteTargetApp, arg.AdvertiserID, arg.Cid)
+	return err
 }
 
 const updateTargetApp = `-- name: updateTargetApp :one
 UPDATE target_app
-SET app_id = $2, rule = $3
-WHERE cid = $1
-RETURNING cid, app_id, rule
+SET app_id = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING cid, app_id, rule, advertiser_id
 `
 
 type updateTargetAppParams struct {
-	Cid   string   `json:"cid"`
-	AppID string   `json:"app_id"`
-	Rule  RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	AppID        string   `json:"app_id"`
+	Rule         RuleType `json:"rule"`
 }
 
 func (q *Queries) updateTargetApp(ctx context.Context, arg updateTargetAppParams) (TargetApp, error) {
-	row := q.db.QueryRow(ctx, updateTargetApp, arg.Cid, arg.AppID, arg.Rule)
+	row := q.db.QueryRow(ctx, updateTargetApp,
+		arg.AdvertiserID,
+		arg.Cid,
+		arg.AppID,
+		arg.Rule,
+	)
 	var i TargetApp
-	err := row.Scan(&i.Cid, &i.AppID, &i.Rule)
+	err := row.Scan(
+		&i.Cid,
+		&i.AppID,
+		&i.Rule,
+		&i.AdvertiserID,
+	)
 	return i, err
 }
@@ -10,11 +10,12 @@ import (
 	"github.com/vivek-344/AdRouter/util"
 )
 
-func addRandomTargetApp(t *testing.T, cid string) db.TargetApp {
+func addRandomTargetApp(t *testing.T, key db.CampaignKey) db.TargetApp {
 	arg := db.AddTargetAppParams{
-		Cid:   cid,
-		AppID: util.RandomAppID(),
-		Rule:  db.RuleType(util.RandomRule()),
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		AppID:        util.RandomAppID(),
+		Rule:         db.RuleType(util.RandomRule()),
 	}
 
 	target_app, err := testStore.AddTargetApp(context.Background(), arg)
@@ -28,32 +29,32 @@ func addRandomTargetApp(t *testing.T, cid string) db.TargetApp {
 
 func TestAddTargetApp(t *testing.T) {
 	campaign := addRandomCampaign(t)
-	addRandomTargetApp(t, campaign.Cid)
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	addRandomTargetApp(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
 
 func TestGetTargetApp(t *testing.T) {
 	campaign := addRandomCampaign(t)
-	target_app := addRandomTargetApp(t, campaign.Cid)
+	target_app := addRandomTargetApp(t, campaign.Key())
 
-	get_target_app, err := testStore.GetTargetApp(context.Background(), campaign.Cid)
+	get_target_app, err := testStore.GetTargetApp(context.Background(), db.GetTargetAppParams(campaign.Key()))
 	require.NoError(t, err)
 	require.Equal(t, target_app, get_target_app)
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
 
 func TestDeleteTargetApp(t *testing.T) {
 	campaign := addRandomCampaign(t)
-	addRandomTargetApp(t, campaign.Cid)
+	addRandomTargetApp(t, campaign.Key())
 
-	err := testStore.DeleteTargetApp(context.Background(), campaign.Cid)
+	err := testStore.DeleteTargetApp(context.Background(), db.DeleteTargetAppParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
 	require.NoError(t, err)
 
-	target_app, err := testStore.GetTargetApp(context.Background(), campaign.Cid)
+	target_app, err := testStore.GetTargetApp(context.Background(), db.GetTargetAppParams(campaign.Key()))
 	require.Error(t, err)
 	require.EqualError(t, err, pgx.ErrNoRows.Error())
 	require.Empty(t, target_app)
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
@@ -0,0 +1,60 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomTargetAppVersion(t *testing.T, key db.CampaignKey) db.TargetAppVersion {
+	arg := db.AddTargetAppVersionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		AppVersion:   util.RandomVersionRange(),
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	target_app_version, err := testStore.AddTargetAppVersion(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Cid, target_app_version.Cid)
+	require.Equal(t, arg.AppVersion, target_app_version.AppVersion)
+	require.Equal(t, arg.Rule, target_app_version.Rule)
+
+	return target_app_version
+}
+
+func TestAddTargetAppVersion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetAppVersion(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestGetTargetAppVersion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_app_version := addRandomTargetAppVersion(t, campaign.Key())
+
+	get_target_app_version, err := testStore.GetTargetAppVersion(context.Background(), db.GetTargetAppVersionParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, target_app_version, get_target_app_version)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestDeleteTargetAppVersion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetAppVersion(t, campaign.Key())
+
+	err := testStore.DeleteTargetAppVersion(context.Background(), db.DeleteTargetAppVersionParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
+	require.NoError(t, err)
+
+	target_app_version, err := testStore.GetTargetAppVersion(context.Background(), db.GetTargetAppVersionParams(campaign.Key()))
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, target_app_version)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
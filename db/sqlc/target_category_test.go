@@ -0,0 +1,60 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomTargetCategory(t *testing.T, key db.CampaignKey) db.TargetCategory {
+	arg := db.AddTargetCategoryParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Category:     util.RandomCategory(),
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	target_category, err := testStore.AddTargetCategory(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Cid, target_category.Cid)
+	require.Equal(t, arg.Category, target_category.Category)
+	require.Equal(t, arg.Rule, target_category.Rule)
+
+	return target_category
+}
+
+func TestAddTargetCategory(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetCategory(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestGetTargetCategory(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_category := addRandomTargetCategory(t, campaign.Key())
+
+	get_target_category, err := testStore.GetTargetCategory(context.Background(), db.GetTargetCategoryParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, target_category, get_target_category)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestDeleteTargetCategory(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetCategory(t, campaign.Key())
+
+	err := testStore.DeleteTargetCategory(context.Background(), db.DeleteTargetCategoryParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
+	require.NoError(t, err)
+
+	target_category, err := testStore.GetTargetCategory(context.Background(), db.GetTargetCategoryParams(campaign.Key()))
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, target_category)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
@@ -0,0 +1,60 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomTargetCity(t *testing.T, key db.CampaignKey) db.TargetCity {
+	arg := db.AddTargetCityParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		City:         util.RandomCity(),
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	target_city, err := testStore.AddTargetCity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Cid, target_city.Cid)
+	require.Equal(t, arg.City, target_city.City)
+	require.Equal(t, arg.Rule, target_city.Rule)
+
+	return target_city
+}
+
+func TestAddTargetCity(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetCity(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestGetTargetCity(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_city := addRandomTargetCity(t, campaign.Key())
+
+	get_target_city, err := testStore.GetTargetCity(context.Background(), db.GetTargetCityParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, target_city, get_target_city)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestDeleteTargetCity(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetCity(t, campaign.Key())
+
+	err := testStore.DeleteTargetCity(context.Background(), db.DeleteTargetCityParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
+	require.NoError(t, err)
+
+	target_city, err := testStore.GetTargetCity(context.Background(), db.GetTargetCityParams(campaign.Key()))
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, target_city)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
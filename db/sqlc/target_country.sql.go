@@ -9,69 +9,107 @@ import (
 	"context"
 )
 
-const addTargetCountry = `-- name: AddTargetCountry :one
+const getTargetCountry = `-- name: GetTargetCountry :one
+SELECT cid, country, rule, advertiser_id
+FROM target_country
+WHERE advertiser_id = $1 AND cid = $2
+`
+
+type GetTargetCountryParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+}
+
+func (q *Queries) GetTargetCountry(ctx context.Context, arg GetTargetCountryParams) (TargetCountry, error) {
+	row := q.db.QueryRow(ctx, getTargetCountry, arg.AdvertiserID, arg.Cid)
+	var i TargetCountry
+	err := row.Scan(
+		&i.Cid,
+		&i.Country,
+		&i.Rule,
+		&i.AdvertiserID,
+	)
+	return i, err
+}
+
+const addTargetCountry = `-- name: addTargetCountry :one
 INSERT INTO target_country (
+    advertiser_id,
     cid,
     country,
     rule
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 )
-RETURNING cid, country, rule
+RETURNING cid, country, rule, advertiser_id
 `
 
-type AddTargetCountryParams struct {
-	Cid     string   `json:"cid"`
-	Country string   `json:"country"`
-	Rule    RuleType `json:"rule"`
+type addTargetCountryParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Country      string   `json:"country"`
+	Rule         RuleType `json:"rule"`
 }
 
-func (q *Queries) AddTargetCountry(ctx context.Context, arg AddTargetCountryParams) (TargetCountry, error) {
-	row := q.db.QueryRow(ctx, addTargetCountry, arg.Cid, arg.Country, arg.Rule)
+func (q *Queries) addTargetCountry(ctx context.Context, arg addTargetCountryParams) (TargetCountry, error) {
+	row := q.db.QueryRow(ctx, addTargetCountry,
+		arg.AdvertiserID,
+		arg.Cid,
+		arg.Country,
+		arg.Rule,
+	)
 	var i TargetCountry
-	err := row.Scan(&i.Cid, &i.Country, &i.Rule)
+	err := row.Scan(
+		&i.Cid,
+		&i.Country,
+		&i.Rule,
+		&i.AdvertiserID,
+	)
 	return i, err
 }
 
-const deleteTargetCountry = `-- name: DeleteTargetCountry :exec
+const deleteTargetCountry = `-- name: deleteTargetCountry :exec
 DELETE FROM target_country
-WHERE cid = $1
+WHERE advertiser_id = $1 AND cid = $2
 `
 
-func (q *Queries) DeleteTargetCountry(ctx context.Context, cid string) error {
-	_, err := q.db.Exec(ctx, deleteTargetCountry, cid)
-	return err
+type deleteTargetCountryParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
 }
 
-const getTargetCountry = `-- name: GetTargetCountry :one
-SELECT cid, country, rule
-FROM target_country
-WHERE cid = $1
-`
-
-func (q *Queries) GetTargetCountry(ctx context.Context, cid string) (TargetCountry, error) {
-	row := q.db.QueryRow(ctx, getTargetCountry, cid)
-	var i TargetCountry
-	err := row.Scan(&i.Cid, &i.Country, &i.Rule)
-	return i, err
+func (q *Queries) deleteTargetCountry(ctx context.Context, arg deleteTargetCountryParams) error {
+	_, err := q.db.Exec(ctx, deleteTargetCountry, arg.AdvertiserID, arg.Cid)
+	return err
 }
 
 const updateTargetCountry = `-- name: updateTargetCountry :one
 UPDATE target_country
-SET country = $2, rule = $3
-WHERE cid = $1
-RETURNING cid, country, rule
+SET country = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING cid, country, rule, advertiser_id
 `
 
 type updateTargetCountryParams struct {
-	Cid     string   `json:"cid"`
-	Country string   `json:"country"`
-	Rule    RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Country      string   `json:"country"`
+	Rule         RuleType `json:"rule"`
 }
 
 func (q *Queries) updateTargetCountry(ctx context.Context, arg updateTargetCountryParams) (TargetCountry, error) {
-	row := q.db.QueryRow(ctx, updateTargetCountry, arg.Cid, arg.Country, arg.Rule)
+	row := q.db.QueryRow(ctx, updateTargetCountry,
+		arg.AdvertiserID,
+		arg.Cid,
+		arg.Country,
+		arg.Rule,
+	)
 	var i TargetCountry
-	err := row.Scan(&i.Cid, &i.Country, &i.Rule)
+	err := row.Scan(
+		&i.Cid,
+		&i.Country,
+		&i.Rule,
+		&i.AdvertiserID,
+	)
 	return i, err
 }
@@ -10,11 +10,12 @@ import (
 	"github.com/vivek-344/AdRouter/util"
 )
 
-func addRandomTargetCountry(t *testing.T, cid string) db.TargetCountry {
+func addRandomTargetCountry(t *testing.T, key db.CampaignKey) db.TargetCountry {
 	arg := db.AddTargetCountryParams{
-		Cid:     cid,
-		Country: util.RandomCountry(),
-		Rule:    db.RuleType(util.RandomRule()),
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Country:      util.RandomCountry(),
+		Rule:         db.RuleType(util.RandomRule()),
 	}
 
 	target_country, err := testStore.AddTargetCountry(context.Background(), arg)
@@ -28,32 +29,32 @@ func addRandomTargetCountry(t *testing.T, cid string) db.TargetCountry {
 
 func TestAddTargetCountry(t *testing.T) {
 	campaign := addRandomCampaign(t)
-	addRandomTargetCountry(t, campaign.Cid)
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	addRandomTargetCountry(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
 
 func TestGetTargetCountry(t *testing.T) {
 	campaign := addRandomCampaign(t)
-	target_country := addRandomTargetCountry(t, campaign.Cid)
+	target_country := addRandomTargetCountry(t, campaign.Key())
 
-	get_target_country, err := testStore.GetTargetCountry(context.Background(), campaign.Cid)
+	get_target_country, err := testStore.GetTargetCountry(context.Background(), db.GetTargetCountryParams(campaign.Key()))
 	require.NoError(t, err)
 	require.Equal(t, target_country, get_target_country)
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
 
 func TestDeleteTargetCountry(t *testing.T) {
 	campaign := addRandomCampaign(t)
-	addRandomTargetCountry(t, campaign.Cid)
+	addRandomTargetCountry(t, campaign.Key())
 
-	err := testStore.DeleteTargetCountry(context.Background(), campaign.Cid)
+	err := testStore.DeleteTargetCountry(context.Background(), db.DeleteTargetCountryParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
 	require.NoError(t, err)
 
-	target_country, err := testStore.GetTargetCountry(context.Background(), campaign.Cid)
+	target_country, err := testStore.GetTargetCountry(context.Background(), db.GetTargetCountryParams(campaign.Key()))
 	require.Error(t, err)
 	require.EqualError(t, err, pgx.ErrNoRows.Error())
 	require.Empty(t, target_country)
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
@@ -0,0 +1,60 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomTargetKeyword(t *testing.T, key db.CampaignKey) db.TargetKeyword {
+	arg := db.AddTargetKeywordParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Keyword:      util.RandomKeyword(),
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	target_keyword, err := testStore.AddTargetKeyword(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Cid, target_keyword.Cid)
+	require.Equal(t, arg.Keyword, target_keyword.Keyword)
+	require.Equal(t, arg.Rule, target_keyword.Rule)
+
+	return target_keyword
+}
+
+func TestAddTargetKeyword(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetKeyword(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestGetTargetKeyword(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_keyword := addRandomTargetKeyword(t, campaign.Key())
+
+	get_target_keyword, err := testStore.GetTargetKeyword(context.Background(), db.GetTargetKeywordParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, target_keyword, get_target_keyword)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestDeleteTargetKeyword(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetKeyword(t, campaign.Key())
+
+	err := testStore.DeleteTargetKeyword(context.Background(), db.DeleteTargetKeywordParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
+	require.NoError(t, err)
+
+	target_keyword, err := testStore.GetTargetKeyword(context.Background(), db.GetTargetKeywordParams(campaign.Key()))
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, target_keyword)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
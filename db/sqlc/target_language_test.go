@@ -0,0 +1,60 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomTargetLanguage(t *testing.T, key db.CampaignKey) db.TargetLanguage {
+	arg := db.AddTargetLanguageParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Language:     util.RandomLanguage(),
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	target_language, err := testStore.AddTargetLanguage(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Cid, target_language.Cid)
+	require.Equal(t, arg.Language, target_language.Language)
+	require.Equal(t, arg.Rule, target_language.Rule)
+
+	return target_language
+}
+
+func TestAddTargetLanguage(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetLanguage(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestGetTargetLanguage(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_language := addRandomTargetLanguage(t, campaign.Key())
+
+	get_target_language, err := testStore.GetTargetLanguage(context.Background(), db.GetTargetLanguageParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, target_language, get_target_language)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestDeleteTargetLanguage(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetLanguage(t, campaign.Key())
+
+	err := testStore.DeleteTargetLanguage(context.Background(), db.DeleteTargetLanguageParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
+	require.NoError(t, err)
+
+	target_language, err := testStore.GetTargetLanguage(context.Background(), db.GetTargetLanguageParams(campaign.Key()))
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, target_language)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
@@ -0,0 +1,88 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func createRandomTargetList(t *testing.T) db.TargetList {
+	arg := db.CreateTargetListParams{
+		AdvertiserID: testAdvertiser.ID,
+		Name:         util.RandomName(),
+		ListType:     db.ListTypeApp,
+		Items:        util.RandomAppID(),
+	}
+
+	target_list, err := testStore.CreateTargetList(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, target_list.ID)
+	require.Equal(t, arg.Name, target_list.Name)
+	require.Equal(t, arg.ListType, target_list.ListType)
+	require.Equal(t, arg.Items, target_list.Items)
+	require.Equal(t, int32(1), target_list.Version)
+	require.NotEmpty(t, target_list.CreatedAt)
+	require.NotEmpty(t, target_list.UpdatedAt)
+
+	return target_list
+}
+
+func TestCreateTargetList(t *testing.T) {
+	target_list := createRandomTargetList(t)
+	testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list.ID})
+}
+
+func TestGetTargetList(t *testing.T) {
+	target_list := createRandomTargetList(t)
+
+	get_target_list, err := testStore.GetTargetList(context.Background(), db.GetTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list.ID})
+	require.NoError(t, err)
+	require.Equal(t, target_list, get_target_list)
+
+	testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list.ID})
+}
+
+func TestListTargetLists(t *testing.T) {
+	target_list := createRandomTargetList(t)
+
+	target_lists, err := testStore.ListTargetLists(context.Background(), testAdvertiser.ID)
+	require.NoError(t, err)
+	require.Contains(t, target_lists, target_list)
+
+	testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list.ID})
+}
+
+func TestDeleteTargetList(t *testing.T) {
+	target_list := createRandomTargetList(t)
+
+	err := testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list.ID})
+	require.NoError(t, err)
+
+	get_target_list, err := testStore.GetTargetList(context.Background(), db.GetTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list.ID})
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, get_target_list)
+}
+
+func TestDeleteTargetListInUse(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_list := createRandomTargetList(t)
+
+	_, err := testStore.AddCampaignTargetList(context.Background(), db.AddCampaignTargetListParams{
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+		ListID:       target_list.ID,
+		Rule:         db.RuleTypeExclude,
+	})
+	require.NoError(t, err)
+
+	err = testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list.ID})
+	require.Error(t, err)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+	testStore.DeleteTargetList(context.Background(), db.DeleteTargetListParams{AdvertiserID: testAdvertiser.ID, ID: target_list.ID})
+}
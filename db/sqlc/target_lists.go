@@ -0,0 +1,220 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (store *SQLStore) getCachedCampaignTargetLists(ctx context.Context, key CampaignKey) (*[]CampaignTargetList, error) {
+	return getCached(ctx, store, fmt.Sprintf("campaign_target_list:%s", key), func(ctx context.Context) ([]CampaignTargetList, error) {
+		return store.ListCampaignTargetLists(ctx, ListCampaignTargetListsParams(key))
+	})
+}
+
+func (store *SQLStore) getCachedTargetList(ctx context.Context, id int32) (*TargetList, error) {
+	return getCached(ctx, store, fmt.Sprintf("target_list:%d", id), func(ctx context.Context) (TargetList, error) {
+		return store.getTargetListByID(ctx, id)
+	})
+}
+
+// matchTargetLists applies every shared list referenced by the campaign.
+func (store *SQLStore) matchTargetLists(ctx context.Context, key CampaignKey, arg DeliveryParams) (bool, error) {
+	refs, err := store.getCachedCampaignTargetLists(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ref := range *refs {
+		list, err := store.getCachedTargetList(ctx, ref.ListID)
+		if err != nil {
+			return false, err
+		}
+
+		var value string
+		switch list.ListType {
+		case ListTypeApp:
+			value = arg.AppID
+		case ListTypeCountry:
+			value = arg.Country
+		case ListTypeOs:
+			value = arg.Os
+		}
+
+		if !shouldInclude(list.Items, value, string(ref.Rule)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// invalidateDelivery drops every cached delivery response. Shared lists apply
+// to many campaigns at once, so their edits cannot wait for the cache to expire.
+func (store *SQLStore) invalidateDelivery(ctx context.Context) {
+	store.deleteMatching(ctx, "delivery:*")
+}
+
+// deleteMatching drops every cached key matching pattern.
+func (store *SQLStore) deleteMatching(ctx context.Context, pattern string) {
+	iter := store.rClient.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		if err := store.rClient.Del(ctx, iter.Val()).Err(); err != nil {
+			fmt.Printf("Redis Del error for %s: %v\n", iter.Val(), err)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		fmt.Printf("Redis Scan error: %v\n", err)
+	}
+}
+
+func (store *SQLStore) invalidateTargetList(ctx context.Context, id int32) {
+	cacheKey := fmt.Sprintf("target_list:%d", id)
+	if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+	}
+	store.invalidateDelivery(ctx)
+}
+
+func (store *SQLStore) invalidateCampaignTargetLists(ctx context.Context, key CampaignKey) {
+	cacheKey := fmt.Sprintf("campaign_target_list:%s", key)
+	if err := store.rClient.Del(ctx, cacheKey).Err(); err != nil {
+		fmt.Printf("Redis Del error for %s: %v\n", cacheKey, err)
+	}
+	store.invalidateDelivery(ctx)
+}
+
+func (store *SQLStore) createListHistory(ctx context.Context, q *Queries, args []createTargetListHistoryParams) error {
+	for _, arg := range args {
+		if arg.OldValue != arg.NewValue {
+			err := q.createTargetListHistory(ctx, arg)
+			if err != nil {
+				return fmt.Errorf("failed to create list history for %s: %v", arg.FieldChanged, err)
+			}
+		}
+	}
+	return nil
+}
+
+type UpdateTargetListParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	ID           int32  `json:"id"`
+	Name         string `json:"name"`
+	Items        string `json:"items"`
+}
+
+// UpdateTargetList replaces the name and items of a list, bumping its version.
+func (store *SQLStore) UpdateTargetList(ctx context.Context, arg UpdateTargetListParams) (TargetList, error) {
+	var targetList TargetList
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		oldList, err := q.GetTargetList(ctx, GetTargetListParams{AdvertiserID: arg.AdvertiserID, ID: arg.ID})
+		if err != nil {
+			return err
+		}
+
+		targetList, err = q.updateTargetList(ctx, updateTargetListParams{
+			ID:    arg.ID,
+			Name:  arg.Name,
+			Items: arg.Items,
+		})
+		if err != nil {
+			return err
+		}
+
+		return store.createListHistory(ctx, q, []createTargetListHistoryParams{
+			{
+				ListID:       arg.ID,
+				Version:      targetList.Version,
+				FieldChanged: "name",
+				OldValue:     oldList.Name,
+				NewValue:     targetList.Name,
+			},
+			{
+				ListID:       arg.ID,
+				Version:      targetList.Version,
+				FieldChanged: "items",
+				OldValue:     oldList.Items,
+				NewValue:     targetList.Items,
+			},
+		})
+	})
+	if err == nil {
+		store.invalidateTargetList(ctx, arg.ID)
+	}
+	return targetList, err
+}
+
+type DeleteTargetListParams struct {
+	AdvertiserID int32 `json:"advertiser_id"`
+	ID           int32 `json:"id"`
+}
+
+// DeleteTargetList deletes a list of the advertiser.
+func (store *SQLStore) DeleteTargetList(ctx context.Context, arg DeleteTargetListParams) error {
+	deleted, err := store.deleteTargetList(ctx, deleteTargetListParams(arg))
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return pgx.ErrNoRows
+	}
+	store.invalidateTargetList(ctx, arg.ID)
+	return nil
+}
+
+type AddCampaignTargetListParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	ListID       int32    `json:"list_id"`
+	Rule         RuleType `json:"rule"`
+	Version      int32    `json:"version"`
+}
+
+func (store *SQLStore) AddCampaignTargetList(ctx context.Context, arg AddCampaignTargetListParams) (CampaignTargetList, error) {
+	var ref CampaignTargetList
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	err := store.updateCampaignTx(ctx, key, arg.Version, func(q *Queries) error {
+		var err error
+		ref, err = q.addCampaignTargetList(ctx, addCampaignTargetListParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			ListID:       arg.ListID,
+			Rule:         arg.Rule,
+		})
+		if err != nil {
+			return err
+		}
+		return createRowsHistory(ctx, q, key, listsHistoryField, nil, []CampaignTargetList{ref})
+	})
+	if err == nil {
+		store.invalidateCampaignTargetLists(ctx, key)
+	}
+	return ref, err
+}
+
+type DeleteCampaignTargetListParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+	ListID       int32  `json:"list_id"`
+	Version      int32  `json:"version"`
+}
+
+func (store *SQLStore) DeleteCampaignTargetList(ctx context.Context, arg DeleteCampaignTargetListParams) error {
+	key := CampaignKey{AdvertiserID: arg.AdvertiserID, Cid: arg.Cid}
+	err := store.updateCampaignTx(ctx, key, arg.Version, func(q *Queries) error {
+		deleted, err := q.deleteCampaignTargetList(ctx, deleteCampaignTargetListParams{
+			AdvertiserID: arg.AdvertiserID,
+			Cid:          arg.Cid,
+			ListID:       arg.ListID,
+		})
+		if err != nil {
+			return err
+		}
+		return createRowsHistory(ctx, q, key, listsHistoryField, deleted, nil)
+	})
+	if err == nil {
+		store.invalidateCampaignTargetLists(ctx, key)
+	}
+	return err
+}
@@ -9,69 +9,107 @@ import (
 	"context"
 )
 
-const addTargetOs = `-- name: AddTargetOs :one
+const getTargetOs = `-- name: GetTargetOs :one
+SELECT cid, os, rule, advertiser_id
+FROM target_os
+WHERE advertiser_id = $1 AND cid = $2
+`
+
+type GetTargetOsParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
+}
+
+func (q *Queries) GetTargetOs(ctx context.Context, arg GetTargetOsParams) (TargetOs, error) {
+	row := q.db.QueryRow(ctx, getTargetOs, arg.AdvertiserID, arg.Cid)
+	var i TargetOs
+	err := row.Scan(
+		&i.Cid,
+		&i.Os,
+		&i.Rule,
+		&i.AdvertiserID,
+	)
+	return i, err
+}
+
+const addTargetOs = `-- name: addTargetOs :one
 INSERT INTO target_os (
+    advertiser_id,
     cid,
     os,
     rule
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 )
-RETURNING cid, os, rule
+RETURNING cid, os, rule, advertiser_id
 `
 
-type AddTargetOsParams struct {
-	Cid  string   `json:"cid"`
-	Os   string   `json:"os"`
-	Rule RuleType `json:"rule"`
+type addTargetOsParams struct {
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Os           string   `json:"os"`
+	Rule         RuleType `json:"rule"`
 }
 
-func (q *Queries) AddTargetOs(ctx context.Context, arg AddTargetOsParams) (TargetOs, error) {
-	row := q.db.QueryRow(ctx, addTargetOs, arg.Cid, arg.Os, arg.Rule)
+func (q *Queries) addTargetOs(ctx context.Context, arg addTargetOsParams) (TargetOs, error) {
+	row := q.db.QueryRow(ctx, addTargetOs,
+		arg.AdvertiserID,
+		arg.Cid,
+		arg.Os,
+		arg.Rule,
+	)
 	var i TargetOs
-	err := row.Scan(&i.Cid, &i.Os, &i.Rule)
+	err := row.Scan(
+		&i.Cid,
+		&i.Os,
+		&i.Rule,
+		&i.AdvertiserID,
+	)
 	return i, err
 }
 
-const deleteTargetOs = `-- name: DeleteTargetOs :exec
+const deleteTargetOs = `-- name: deleteTargetOs :exec
 DELETE FROM target_os
-WHERE cid = $1
+WHERE advertiser_id = $1 AND cid = $2
 `
 
-func (q *Queries) DeleteTargetOs(ctx context.Context, cid string) error {
-	_, err := q.db.Exec(ctx, deleteTargetOs, cid)
-	return err
+type deleteTargetOsParams struct {
+	AdvertiserID int32  `json:"advertiser_id"`
+	Cid          string `json:"cid"`
 }
 
-const getTargetOs = `-- name: GetTargetOs :one
-SELECT cid, os, rule
-FROM target_os
-WHERE cid = $1
-`
-
-func (q *Queries) GetTargetOs(ctx context.Context, cid string) (TargetOs, error) {
-	row := q.db.QueryRow(ctx, getTargetOs, cid)
-	var i TargetOs
-	err := row.Scan(&i.Cid, &i.Os, &i.Rule)
-	return i, err
+func (q *Queries) deleteTargetOs(ctx context.Context, arg deleteTargetOsParams) error {
+	_, err := q.db.Exec(ctx, deleteTargetOs, arg.AdvertiserID, arg.Cid)
+	return err
 }
 
 const updateTargetOs = `-- name: updateTargetOs :one
 UPDATE target_os
-SET os = $2, rule = $3
-WHERE cid = $1
-RETURNING cid, os, rule
+SET os = $3, rule = $4
+WHERE advertiser_id = $1 AND cid = $2
+RETURNING cid, os, rule, advertiser_id
 `
 
 type updateTargetOsParams struct {
-	Cid  string   `json:"cid"`
-	Os   string   `json:"os"`
-	Rule RuleType `json:"rule"`
+	AdvertiserID int32    `json:"advertiser_id"`
+	Cid          string   `json:"cid"`
+	Os           string   `json:"os"`
+	Rule         RuleType `json:"rule"`
 }
 
 func (q *Queries) updateTargetOs(ctx context.Context, arg updateTargetOsParams) (TargetOs, error) {
-	row := q.db.QueryRow(ctx, updateTargetOs, arg.Cid, arg.Os, arg.Rule)
+	row := q.db.QueryRow(ctx, updateTargetOs,
+		arg.AdvertiserID,
+		arg.Cid,
+		arg.Os,
+		arg.Rule,
+	)
 	var i TargetOs
-	err := row.Scan(&i.Cid, &i.Os, &i.Rule)
+	err := row.Scan(
+		&i.Cid,
+		&i.Os,
+		&i.Rule,
+		&i.AdvertiserID,
+	)
 	return i, err
 }
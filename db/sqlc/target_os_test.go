@@ -10,11 +10,12 @@ import (
 	"github.com/vivek-344/AdRouter/util"
 )
 
-func addRandomTargetOs(t *testing.T, cid string) db.TargetOs {
+func addRandomTargetOs(t *testing.T, key db.CampaignKey) db.TargetOs {
 	arg := db.AddTargetOsParams{
-		Cid:  cid,
-		Os:   util.RandomOs(),
-		Rule: db.RuleType(util.RandomRule()),
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Os:           util.RandomOs(),
+		Rule:         db.RuleType(util.RandomRule()),
 	}
 
 	target_os, err := testStore.AddTargetOs(context.Background(), arg)
@@ -28,32 +29,32 @@ func addRandomTargetOs(t *testing.T, cid string) db.TargetOs {
 
 func TestAddTargetOs(t *testing.T) {
 	campaign := addRandomCampaign(t)
-	addRandomTargetOs(t, campaign.Cid)
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	addRandomTargetOs(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
 
 func TestGetTargetOs(t *testing.T) {
 	campaign := addRandomCampaign(t)
-	target_os := addRandomTargetOs(t, campaign.Cid)
+	target_os := addRandomTargetOs(t, campaign.Key())
 
-	get_target_os, err := testStore.GetTargetOs(context.Background(), campaign.Cid)
+	get_target_os, err := testStore.GetTargetOs(context.Background(), db.GetTargetOsParams(campaign.Key()))
 	require.NoError(t, err)
 	require.Equal(t, target_os, get_target_os)
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
 
 func TestDeleteTargetOs(t *testing.T) {
 	campaign := addRandomCampaign(t)
-	addRandomTargetOs(t, campaign.Cid)
+	addRandomTargetOs(t, campaign.Key())
 
-	err := testStore.DeleteTargetOs(context.Background(), campaign.Cid)
+	err := testStore.DeleteTargetOs(context.Background(), db.DeleteTargetOsParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
 	require.NoError(t, err)
 
-	target_os, err := testStore.GetTargetOs(context.Background(), campaign.Cid)
+	target_os, err := testStore.GetTargetOs(context.Background(), db.GetTargetOsParams(campaign.Key()))
 	require.Error(t, err)
 	require.EqualError(t, err, pgx.ErrNoRows.Error())
 	require.Empty(t, target_os)
 
-	testStore.DeleteCampaign(context.Background(), campaign.Cid)
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
 }
@@ -0,0 +1,60 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomTargetOsVersion(t *testing.T, key db.CampaignKey) db.TargetOsVersion {
+	arg := db.AddTargetOsVersionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		OsVersion:    util.RandomVersionRange(),
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	target_os_version, err := testStore.AddTargetOsVersion(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Cid, target_os_version.Cid)
+	require.Equal(t, arg.OsVersion, target_os_version.OsVersion)
+	require.Equal(t, arg.Rule, target_os_version.Rule)
+
+	return target_os_version
+}
+
+func TestAddTargetOsVersion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetOsVersion(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestGetTargetOsVersion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_os_version := addRandomTargetOsVersion(t, campaign.Key())
+
+	get_target_os_version, err := testStore.GetTargetOsVersion(context.Background(), db.GetTargetOsVersionParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, target_os_version, get_target_os_version)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestDeleteTargetOsVersion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetOsVersion(t, campaign.Key())
+
+	err := testStore.DeleteTargetOsVersion(context.Background(), db.DeleteTargetOsVersionParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
+	require.NoError(t, err)
+
+	target_os_version, err := testStore.GetTargetOsVersion(context.Background(), db.GetTargetOsVersionParams(campaign.Key()))
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, target_os_version)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
@@ -0,0 +1,87 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomTargetRadius(t *testing.T, key db.CampaignKey) db.TargetRadius {
+	arg := db.AddTargetRadiusParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Lat:          float64(util.RandomInt(-89, 89)),
+		Lon:          float64(util.RandomInt(-179, 179)),
+		RadiusKm:     float64(util.RandomInt(1, 100)),
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	target_radius, err := testStore.AddTargetRadius(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, target_radius.ID)
+	require.Equal(t, arg.Cid, target_radius.Cid)
+	require.Equal(t, arg.Lat, target_radius.Lat)
+	require.Equal(t, arg.Lon, target_radius.Lon)
+	require.Equal(t, arg.RadiusKm, target_radius.RadiusKm)
+	require.Equal(t, arg.Rule, target_radius.Rule)
+
+	return target_radius
+}
+
+func TestAddTargetRadius(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetRadius(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestListTargetRadius(t *testing.T) {
+	campaign := addRandomCampaign(t)
+
+	var targets []db.TargetRadius
+	for i := 0; i < 3; i++ {
+		targets = append(targets, addRandomTargetRadius(t, campaign.Key()))
+	}
+
+	list_target_radius, err := testStore.ListTargetRadius(context.Background(), db.ListTargetRadiusParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, targets, list_target_radius)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestDeleteTargetRadiusByID(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target1 := addRandomTargetRadius(t, campaign.Key())
+	target2 := addRandomTargetRadius(t, campaign.Key())
+
+	err := testStore.DeleteTargetRadiusByID(context.Background(), db.DeleteTargetRadiusByIDParams{
+		ID:           target1.ID,
+		AdvertiserID: campaign.AdvertiserID,
+		Cid:          campaign.Cid,
+	})
+	require.NoError(t, err)
+
+	list_target_radius, err := testStore.ListTargetRadius(context.Background(), db.ListTargetRadiusParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, []db.TargetRadius{target2}, list_target_radius)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestDeleteTargetRadius(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetRadius(t, campaign.Key())
+	addRandomTargetRadius(t, campaign.Key())
+
+	err := testStore.DeleteTargetRadius(context.Background(), db.DeleteTargetRadiusParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
+	require.NoError(t, err)
+
+	list_target_radius, err := testStore.ListTargetRadius(context.Background(), db.ListTargetRadiusParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Empty(t, list_target_radius)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
@@ -0,0 +1,60 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func addRandomTargetRegion(t *testing.T, key db.CampaignKey) db.TargetRegion {
+	arg := db.AddTargetRegionParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Region:       util.RandomRegion(),
+		Rule:         db.RuleType(util.RandomRule()),
+	}
+
+	target_region, err := testStore.AddTargetRegion(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Cid, target_region.Cid)
+	require.Equal(t, arg.Region, target_region.Region)
+	require.Equal(t, arg.Rule, target_region.Rule)
+
+	return target_region
+}
+
+func TestAddTargetRegion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetRegion(t, campaign.Key())
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestGetTargetRegion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	target_region := addRandomTargetRegion(t, campaign.Key())
+
+	get_target_region, err := testStore.GetTargetRegion(context.Background(), db.GetTargetRegionParams(campaign.Key()))
+	require.NoError(t, err)
+	require.Equal(t, target_region, get_target_region)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+func TestDeleteTargetRegion(t *testing.T) {
+	campaign := addRandomCampaign(t)
+	addRandomTargetRegion(t, campaign.Key())
+
+	err := testStore.DeleteTargetRegion(context.Background(), db.DeleteTargetRegionParams{AdvertiserID: campaign.AdvertiserID, Cid: campaign.Cid})
+	require.NoError(t, err)
+
+	target_region, err := testStore.GetTargetRegion(context.Background(), db.GetTargetRegionParams(campaign.Key()))
+	require.Error(t, err)
+	require.EqualError(t, err, pgx.ErrNoRows.Error())
+	require.Empty(t, target_region)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
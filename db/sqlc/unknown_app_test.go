@@ -0,0 +1,51 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func findUnknownApp(t *testing.T, appID string) (db.UnknownApp, bool) {
+	apps, err := testStore.ListUnknownApps(context.Background())
+	require.NoError(t, err)
+
+	for _, app := range apps {
+		if app.AppID == appID {
+			return app, true
+		}
+	}
+	return db.UnknownApp{}, false
+}
+
+func TestRecordUnknownApp(t *testing.T) {
+	appID := util.RandomString(12)
+
+	err := testStore.RecordUnknownApp(context.Background(), appID)
+	require.NoError(t, err)
+	err = testStore.RecordUnknownApp(context.Background(), appID)
+	require.NoError(t, err)
+
+	app, ok := findUnknownApp(t, appID)
+	require.True(t, ok)
+	require.Equal(t, int64(2), app.RequestCount)
+	require.False(t, app.LastSeenAt.Before(app.FirstSeenAt))
+
+	testStore.DeleteUnknownApp(context.Background(), appID)
+}
+
+func TestDeleteUnknownApp(t *testing.T) {
+	appID := util.RandomString(12)
+
+	err := testStore.RecordUnknownApp(context.Background(), appID)
+	require.NoError(t, err)
+
+	err = testStore.DeleteUnknownApp(context.Background(), appID)
+	require.NoError(t, err)
+
+	_, ok := findUnknownApp(t, appID)
+	require.False(t, ok)
+}
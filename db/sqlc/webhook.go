@@ -0,0 +1,60 @@
+package db
+
+import "context"
+
+// Campaign events sent to webhook subscriptions. The campaign_webhook trigger
+// emits them, so they cover every change of a campaign row, whichever route
+// made it.
+const (
+	WebhookCampaignCreated       = "campaign.created"
+	WebhookCampaignUpdated       = "campaign.updated"
+	WebhookCampaignStatusChanged = "campaign.status_changed"
+	WebhookCampaignDeleted       = "campaign.deleted"
+)
+
+// DeadLetterWebhookDelivery gives up on a delivery, moving it to the dead
+// letters with the error of its last attempt.
+func (store *SQLStore) DeadLetterWebhookDelivery(ctx context.Context, id int64, lastError string) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		delivery, err := q.takeWebhookDelivery(ctx, id)
+		if err != nil {
+			return err
+		}
+		_, err = q.createWebhookDeadLetter(ctx, createWebhookDeadLetterParams{
+			SubscriptionID: delivery.SubscriptionID,
+			AdvertiserID:   delivery.AdvertiserID,
+			EventType:      delivery.EventType,
+			Payload:        delivery.Payload,
+			Attempts:       delivery.Attempts,
+			LastError:      lastError,
+			CreatedAt:      delivery.CreatedAt,
+		})
+		return err
+	})
+}
+
+type RedeliverWebhookParams struct {
+	AdvertiserID int32 `json:"advertiser_id"`
+	ID           int64 `json:"id"`
+}
+
+// RedeliverWebhook queues a dead letter for delivery again, with a fresh set
+// of attempts. The payload is sent as it was, so receivers can tell the event
+// by its id.
+func (store *SQLStore) RedeliverWebhook(ctx context.Context, arg RedeliverWebhookParams) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := store.execTx(ctx, func(q *Queries) error {
+		letter, err := q.takeWebhookDeadLetter(ctx, takeWebhookDeadLetterParams(arg))
+		if err != nil {
+			return err
+		}
+		delivery, err = q.createWebhookDelivery(ctx, createWebhookDeliveryParams{
+			SubscriptionID: letter.SubscriptionID,
+			AdvertiserID:   letter.AdvertiserID,
+			EventType:      letter.EventType,
+			Payload:        letter.Payload,
+		})
+		return err
+	})
+	return delivery, err
+}
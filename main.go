@@ -2,13 +2,16 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 	"github.com/vivek-344/AdRouter/api"
 	db "github.com/vivek-344/AdRouter/db/sqlc"
 	"github.com/vivek-344/AdRouter/util"
+	"github.com/vivek-344/AdRouter/webhook"
 )
 
 func main() {
@@ -17,7 +20,7 @@ func main() {
 		log.Fatal("cannot load config: ", err)
 	}
 
-	conn, err := pgxpool.New(context.Background(), config.DBSource)
+	conn, err := db.NewPool(context.Background(), config.DBSource)
 	if err != nil {
 		log.Fatal("cannot connect to the database", err)
 	}
@@ -29,10 +32,59 @@ func main() {
 
 	client := redis.NewClient(opt)
 	store := db.NewStore(conn, client)
-	server := api.NewServer(store)
+
+	// "bootstrap [name]" mints the first global admin key instead of starting the server.
+	if len(os.Args) > 1 && os.Args[1] == "bootstrap" {
+		bootstrap(store, os.Args[2:])
+		return
+	}
+
+	if config.CampaignRetention > 0 {
+		go purgeDeletedCampaigns(store, config.CampaignRetention)
+	}
+	worker := webhook.NewWorker(store)
+	if config.WebhookAllowPrivateNetworks {
+		worker.AllowPrivateNetworks()
+	}
+	go worker.Run(context.Background())
+
+	server, err := api.NewServer(config, store)
+	if err != nil {
+		log.Fatal("cannot create server: ", err)
+	}
 
 	err = server.Start(config.ServerAddress)
 	if err != nil {
 		log.Fatal("cannot start server: ", err)
 	}
 }
+
+// purgeDeletedCampaigns removes campaigns deleted longer than retention ago,
+// once at startup and then every hour.
+func purgeDeletedCampaigns(store db.Store, retention time.Duration) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		purged, err := store.PurgeDeletedCampaigns(context.Background(), time.Now().Add(-retention))
+		if err != nil {
+			log.Println("cannot purge deleted campaigns:", err)
+		} else if purged > 0 {
+			log.Printf("purged %d deleted campaigns", purged)
+		}
+		<-ticker.C
+	}
+}
+
+func bootstrap(store db.Store, args []string) {
+	name := "bootstrap"
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	result, err := store.BootstrapAdminKey(context.Background(), name)
+	if err != nil {
+		log.Fatal("cannot bootstrap global admin key: ", err)
+	}
+
+	fmt.Printf("global admin key %q created, store it now as it will not be shown again:\n%s\n", result.ApiKey.Name, result.Key)
+}
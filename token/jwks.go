@@ -0,0 +1,228 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwksRefreshInterval bounds how often a remote key set is fetched again when
+// a token names a key it does not hold, so that forged key IDs cannot be used
+// to hammer the identity provider.
+const jwksRefreshInterval = time.Minute
+
+var errUnknownKey = errors.New("unknown signing key")
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// KeySet holds the public keys of an identity provider, read from a local
+// JWKS file or fetched from a JWKS URL. Remote key sets are fetched again when
+// a token is signed with a key they do not hold, which picks up key rotation.
+type KeySet struct {
+	source    string
+	client    *http.Client
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// LoadKeySet reads the key set at source, a file path or an http(s) URL.
+func LoadKeySet(source string) (*KeySet, error) {
+	set := &KeySet{
+		source: source,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+	if err := set.load(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+func (set *KeySet) remote() bool {
+	return strings.HasPrefix(set.source, "https://") || strings.HasPrefix(set.source, "http://")
+}
+
+func (set *KeySet) read() ([]byte, error) {
+	if !set.remote() {
+		return os.ReadFile(set.source)
+	}
+
+	rsp, err := set.client.Get(set.source)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", set.source, rsp.Status)
+	}
+	return io.ReadAll(io.LimitReader(rsp.Body, 1<<20))
+}
+
+func (set *KeySet) load() error {
+	data, err := set.read()
+	if err != nil {
+		return fmt.Errorf("cannot read key set: %w", err)
+	}
+
+	keys, err := parseKeySet(data)
+	if err != nil {
+		return err
+	}
+
+	set.mu.Lock()
+	set.keys = keys
+	set.fetchedAt = time.Now()
+	set.mu.Unlock()
+	return nil
+}
+
+// key returns the key with the given ID. Tokens without a key ID are accepted
+// when the set holds a single key.
+func (set *KeySet) key(kid string) (crypto.PublicKey, error) {
+	if key, ok := set.lookup(kid); ok {
+		return key, nil
+	}
+
+	// Failed fetches count against the interval too, so an unreachable
+	// provider is not retried on every request.
+	set.mu.Lock()
+	stale := time.Since(set.fetchedAt) >= jwksRefreshInterval
+	if stale {
+		set.fetchedAt = time.Now()
+	}
+	set.mu.Unlock()
+	if !set.remote() || !stale {
+		return nil, errUnknownKey
+	}
+
+	if err := set.load(); err != nil {
+		return nil, err
+	}
+	if key, ok := set.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, errUnknownKey
+}
+
+func (set *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+
+	if kid == "" && len(set.keys) == 1 {
+		for _, key := range set.keys {
+			return key, true
+		}
+	}
+	key, ok := set.keys[kid]
+	return key, ok
+}
+
+// parseKeySet decodes the RSA and P-256 signing keys of a JWKS document.
+// Encryption keys and other key types are skipped.
+func parseKeySet(data []byte) (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid key set: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var key crypto.PublicKey
+		var err error
+		switch jwk.Kty {
+		case "RSA":
+			key, err = jwk.rsaKey()
+		case "EC":
+			key, err = jwk.ecKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("key set holds no signing keys")
+	}
+	return keys, nil
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("missing key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (jwk jsonWebKey) rsaKey() (*rsa.PublicKey, error) {
+	n, err := decodeInt(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeInt(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+	if n.BitLen() < 2048 {
+		return nil, errors.New("RSA keys must be at least 2048 bits")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (jwk jsonWebKey) ecKey() (*ecdsa.PublicKey, error) {
+	if jwk.Crv != "P-256" {
+		return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+	}
+
+	x, err := decodeInt(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeInt(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := elliptic.P256()
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on curve P-256")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
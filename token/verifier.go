@@ -0,0 +1,205 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// clockSkew is the leeway given to the time based claims of a token.
+const clockSkew = time.Minute
+
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Claims holds the claims of a verified token.
+type Claims struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ExpiresAt time.Time
+	raw       map[string]any
+}
+
+// Strings returns a claim as a list of strings. The name may be a dotted path
+// into nested objects, such as "realm_access.roles", and the claim may hold a
+// single string or an array of strings. Missing claims return nil.
+func (claims *Claims) Strings(name string) []string {
+	var value any = claims.raw
+	for _, field := range strings.Split(name, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[field]
+	}
+
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// Verifier checks RS256 and ES256 signed JWTs issued by an identity provider.
+type Verifier struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+}
+
+// NewVerifier returns a verifier accepting tokens signed by keys. Tokens must
+// carry the given issuer and audience, unless they are empty.
+func NewVerifier(keys *KeySet, issuer, audience string) *Verifier {
+	return &Verifier{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+	}
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify checks the signature and the registered claims of a token. Tokens
+// that are malformed, badly signed or meant for another party return
+// ErrInvalidToken, and tokens past their expiry return ErrExpiredToken.
+func (verifier *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var hdr header
+	if err := decodeSegment(parts[0], &hdr); err != nil {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	key, err := verifier.keys.key(hdr.Kid)
+	if err != nil {
+		if errors.Is(err, errUnknownKey) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !verifySignature(hdr.Alg, key, digest[:], signature) {
+		return nil, ErrInvalidToken
+	}
+
+	var raw map[string]any
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, ErrInvalidToken
+	}
+	claims, err := parseClaims(raw)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := verifier.validate(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// verifySignature only accepts the algorithm matching the type of key, so a
+// token cannot pick a weaker algorithm than the provider signs with.
+func verifySignature(alg string, key crypto.PublicKey, digest, signature []byte) bool {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return alg == "RS256" && rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		if alg != "ES256" || len(signature) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(key, digest, r, s)
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func numericDate(raw map[string]any, name string) (time.Time, error) {
+	value, ok := raw[name]
+	if !ok {
+		return time.Time{}, nil
+	}
+	seconds, ok := value.(float64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("claim %s is not a number", name)
+	}
+	return time.Unix(int64(seconds), 0), nil
+}
+
+func parseClaims(raw map[string]any) (*Claims, error) {
+	claims := &Claims{raw: raw}
+	claims.Issuer, _ = raw["iss"].(string)
+	claims.Subject, _ = raw["sub"].(string)
+	claims.Audience = claims.Strings("aud")
+
+	var err error
+	claims.ExpiresAt, err = numericDate(raw, "exp")
+	if err != nil {
+		return nil, err
+	}
+	if claims.ExpiresAt.IsZero() {
+		return nil, errors.New("token has no expiry")
+	}
+	return claims, nil
+}
+
+func (verifier *Verifier) validate(claims *Claims) error {
+	now := time.Now()
+
+	notBefore, err := numericDate(claims.raw, "nbf")
+	if err != nil || now.Add(clockSkew).Before(notBefore) {
+		return ErrInvalidToken
+	}
+	if verifier.issuer != "" && claims.Issuer != verifier.issuer {
+		return ErrInvalidToken
+	}
+	if verifier.audience != "" && !slices.Contains(claims.Audience, verifier.audience) {
+		return ErrInvalidToken
+	}
+	if claims.Subject == "" {
+		return ErrInvalidToken
+	}
+	if now.Add(-clockSkew).After(claims.ExpiresAt) {
+		return ErrExpiredToken
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+package token_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/token"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "adrouter"
+)
+
+// testKey is a throwaway signing key standing in for the identity provider.
+type testKey struct {
+	kid string
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newRSAKey(t *testing.T, kid string) testKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return testKey{kid: kid, rsa: key}
+}
+
+func newECKey(t *testing.T, kid string) testKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return testKey{kid: kid, ec: key}
+}
+
+func encodeInt(n *big.Int, size int) string {
+	return base64.RawURLEncoding.EncodeToString(n.FillBytes(make([]byte, size)))
+}
+
+func (key testKey) jwk() map[string]string {
+	if key.rsa != nil {
+		return map[string]string{
+			"kty": "RSA",
+			"kid": key.kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.rsa.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.rsa.E)).Bytes()),
+		}
+	}
+	return map[string]string{
+		"kty": "EC",
+		"kid": key.kid,
+		"crv": "P-256",
+		"x":   encodeInt(key.ec.X, 32),
+		"y":   encodeInt(key.ec.Y, 32),
+	}
+}
+
+func keySetJSON(t *testing.T, keys ...testKey) []byte {
+	jwks := make([]map[string]string, len(keys))
+	for i, key := range keys {
+		jwks[i] = key.jwk()
+	}
+	data, err := json.Marshal(map[string]any{"keys": jwks})
+	require.NoError(t, err)
+	return data
+}
+
+func writeKeySet(t *testing.T, keys ...testKey) string {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, keySetJSON(t, keys...), 0o600))
+	return path
+}
+
+func (key testKey) alg() string {
+	if key.rsa != nil {
+		return "RS256"
+	}
+	return "ES256"
+}
+
+func (key testKey) mint(t *testing.T, alg string, claims map[string]any) string {
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signingInput := encode(map[string]string{"alg": alg, "typ": "JWT", "kid": key.kid}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	if key.rsa != nil {
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key.rsa, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	} else {
+		r, s, err := ecdsa.Sign(rand.Reader, key.ec, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// unsigned returns a token declaring the "none" algorithm.
+func unsigned(t *testing.T, claims map[string]any) string {
+	hdr, err := json.Marshal(map[string]string{"alg": "none", "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(hdr) + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss":    testIssuer,
+		"aud":    []string{testAudience, "other"},
+		"sub":    "user-1",
+		"exp":    time.Now().Add(5 * time.Minute).Unix(),
+		"iat":    time.Now().Unix(),
+		"groups": []string{"adrouter-editors"},
+		"realm_access": map[string]any{
+			"roles": []string{"viewer"},
+		},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa-1")
+	ecKey := newECKey(t, "ec-1")
+	otherKey := newRSAKey(t, "rsa-1")
+
+	keys, err := token.LoadKeySet(writeKeySet(t, rsaKey, ecKey))
+	require.NoError(t, err)
+	verifier := token.NewVerifier(keys, testIssuer, testAudience)
+
+	with := func(name string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	testCases := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{
+			name:  "RS256",
+			token: rsaKey.mint(t, "RS256", validClaims()),
+		},
+		{
+			name:  "ES256",
+			token: ecKey.mint(t, "ES256", validClaims()),
+		},
+		{
+			name:  "Audience as a string",
+			token: rsaKey.mint(t, "RS256", with("aud", testAudience)),
+		},
+		{
+			name:  "Expired",
+			token: rsaKey.mint(t, "RS256", with("exp", time.Now().Add(-5*time.Minute).Unix())),
+			err:   token.ErrExpiredToken,
+		},
+		{
+			name:  "Within clock skew",
+			token: rsaKey.mint(t, "RS256", with("exp", time.Now().Add(-10*time.Second).Unix())),
+		},
+		{
+			name:  "No expiry",
+			token: rsaKey.mint(t, "RS256", with("exp", nil)),
+			err:   token.ErrInvalidToken,
+		},
+		{
+			name:  "Not yet valid",
+			token: rsaKey.mint(t, "RS256", with("nbf", time.Now().Add(5*time.Minute).Unix())),
+			err:   token.ErrInvalidToken,
+		},
+		{
+			name:  "Wrong issuer",
+			token: rsaKey.mint(t, "RS256", with("iss", "https://evil.example.com")),
+			err:   token.ErrInvalidToken,
+		},
+		{
+			name:  "Wrong audience",
+			token: rsaKey.mint(t, "RS256", with("aud", "someone-else")),
+			err:   token.ErrInvalidToken,
+		},
+		{
+			name:  "No subject",
+			token: rsaKey.mint(t, "RS256", with("sub", nil)),
+			err:   token.ErrInvalidToken,
+		},
+		{
+			name:  "Signed by another key",
+			token: otherKey.mint(t, "RS256", validClaims()),
+			err:   token.ErrInvalidToken,
+		},
+		{
+			name:  "Unknown key ID",
+			token: newECKey(t, "ec-2").mint(t, "ES256", validClaims()),
+			err:   token.ErrInvalidToken,
+		},
+		{
+			name:  "Algorithm not matching the key",
+			token: rsaKey.mint(t, "PS256", validClaims()),
+			err:   token.ErrInvalidToken,
+		},
+		{
+			name:  "Unsigned",
+			token: unsigned(t, validClaims()),
+			err:   token.ErrInvalidToken,
+		},
+		{
+			name:  "Malformed",
+			token: "not-a-token",
+			err:   token.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := verifier.Verify(tc.token)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				require.Nil(t, claims)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testIssuer, claims.Issuer)
+			require.Equal(t, "user-1", claims.Subject)
+			require.Contains(t, claims.Audience, testAudience)
+			require.WithinDuration(t, time.Now(), claims.ExpiresAt, 5*time.Minute)
+		})
+	}
+}
+
+func TestVerifyTamperedPayload(t *testing.T) {
+	key := newECKey(t, "ec-1")
+	keys, err := token.LoadKeySet(writeKeySet(t, key))
+	require.NoError(t, err)
+	verifier := token.NewVerifier(keys, "", "")
+
+	signed := key.mint(t, key.alg(), validClaims())
+	forged := key.mint(t, key.alg(), map[string]any{"sub": "admin", "exp": time.Now().Add(time.Hour).Unix()})
+
+	a, b := strings.Split(signed, "."), strings.Split(forged, ".")
+
+	_, err = verifier.Verify(a[0] + "." + b[1] + "." + a[2])
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+}
+
+func TestClaimsStrings(t *testing.T) {
+	key := newRSAKey(t, "")
+	keys, err := token.LoadKeySet(writeKeySet(t, key))
+	require.NoError(t, err)
+
+	claims, err := token.NewVerifier(keys, "", "").Verify(key.mint(t, key.alg(), validClaims()))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"adrouter-editors"}, claims.Strings("groups"))
+	require.Equal(t, []string{"viewer"}, claims.Strings("realm_access.roles"))
+	require.Equal(t, []string{"user-1"}, claims.Strings("sub"))
+	require.Nil(t, claims.Strings("missing"))
+	require.Nil(t, claims.Strings("groups.nested"))
+}
+
+func TestLoadKeySetURL(t *testing.T) {
+	oldKey := newRSAKey(t, "old")
+	newKey := newECKey(t, "new")
+
+	var rotated atomic.Bool
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if rotated.Load() {
+			w.Write(keySetJSON(t, newKey))
+			return
+		}
+		w.Write(keySetJSON(t, oldKey))
+	}))
+	defer server.Close()
+
+	keys, err := token.LoadKeySet(server.URL)
+	require.NoError(t, err)
+	verifier := token.NewVerifier(keys, testIssuer, testAudience)
+
+	_, err = verifier.Verify(oldKey.mint(t, oldKey.alg(), validClaims()))
+	require.NoError(t, err)
+
+	// The key set was fetched moments ago, so a new key ID is not looked up yet.
+	rotated.Store(true)
+	_, err = verifier.Verify(newKey.mint(t, newKey.alg(), validClaims()))
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+	require.Equal(t, int32(1), fetches.Load())
+}
+
+func TestLoadKeySetErrors(t *testing.T) {
+	_, err := token.LoadKeySet(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "empty.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`), 0o600))
+	_, err = token.LoadKeySet(path)
+	require.Error(t, err)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	_, err = token.LoadKeySet(server.URL)
+	require.Error(t, err)
+}
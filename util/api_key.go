@@ -0,0 +1,43 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	apiKeyPrefix = "adr_"
+	// APIKeyPrefixLen is how much of a key is kept in the clear so that
+	// operators can tell keys apart without storing them.
+	APIKeyPrefixLen = len(apiKeyPrefix) + 8
+	// SessionTokenPrefix tells session tokens issued on login apart from keys.
+	SessionTokenPrefix = "ses_"
+)
+
+// NewAPIKey returns a random key carrying 256 bits of entropy.
+func NewAPIKey() (string, error) {
+	return newSecret(apiKeyPrefix)
+}
+
+// NewSessionToken returns a random session token carrying 256 bits of entropy.
+// Session tokens are hashed with HashAPIKey like keys.
+func NewSessionToken() (string, error) {
+	return newSecret(SessionTokenPrefix)
+}
+
+func newSecret(prefix string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 digest under which a key is stored.
+// Keys are random rather than user chosen, so a fast unsalted hash is enough.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(key)))
+	return hex.EncodeToString(sum[:])
+}
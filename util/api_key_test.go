@@ -0,0 +1,45 @@
+package util_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key1, err := util.NewAPIKey()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key1, "adr_"))
+	require.Len(t, key1, 68)
+
+	key2, err := util.NewAPIKey()
+	require.NoError(t, err)
+	require.NotEqual(t, key1, key2)
+}
+
+func TestNewSessionToken(t *testing.T) {
+	token, err := util.NewSessionToken()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, util.SessionTokenPrefix))
+	require.Len(t, token, 68)
+
+	key, err := util.NewAPIKey()
+	require.NoError(t, err)
+	require.False(t, strings.HasPrefix(key, util.SessionTokenPrefix))
+}
+
+func TestHashAPIKey(t *testing.T) {
+	key, err := util.NewAPIKey()
+	require.NoError(t, err)
+
+	hash := util.HashAPIKey(key)
+	require.Len(t, hash, 64)
+	require.NotContains(t, hash, key)
+	require.Equal(t, hash, util.HashAPIKey(" "+key+"\n"))
+
+	other, err := util.NewAPIKey()
+	require.NoError(t, err)
+	require.NotEqual(t, hash, util.HashAPIKey(other))
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// maxBloomBits is the largest bitmap Redis can hold in a single string.
+const maxBloomBits = 1 << 32
+
+// BloomFilter describes the shape of a bloom filter. The bitmap itself is kept
+// elsewhere, laid out like a Redis bitmap so offset 0 is the high bit of byte 0.
+type BloomFilter struct {
+	Bits   uint64
+	Hashes uint32
+}
+
+// NewBloomFilter sizes a filter for n items at false positive rate p.
+func NewBloomFilter(n uint64, p float64) BloomFilter {
+	if n == 0 {
+		n = 1
+	}
+	bits := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	bits = math.Min(math.Max(bits, 8), maxBloomBits)
+	hashes := math.Max(1, math.Round(bits/float64(n)*math.Ln2))
+
+	return BloomFilter{Bits: uint64(bits), Hashes: uint32(hashes)}
+}
+
+// Locations returns the bit offsets for item, derived from two halves of a
+// 128 bit FNV hash (Kirsch-Mitzenmacher double hashing).
+func (f BloomFilter) Locations(item string) []uint64 {
+	h := fnv.New128a()
+	h.Write([]byte(item))
+	sum := h.Sum(nil)
+
+	h1 := binary.BigEndian.Uint64(sum[:8])
+	h2 := binary.BigEndian.Uint64(sum[8:]) | 1
+
+	locations := make([]uint64, f.Hashes)
+	for i := range locations {
+		locations[i] = (h1 + uint64(i)*h2) % f.Bits
+	}
+	return locations
+}
+
+// NewBitmap allocates an empty bitmap for the filter.
+func (f BloomFilter) NewBitmap() []byte {
+	return make([]byte, (f.Bits+7)/8)
+}
+
+// Add sets the bits of item in bitmap.
+func (f BloomFilter) Add(bitmap []byte, item string) {
+	for _, offset := range f.Locations(item) {
+		bitmap[offset/8] |= 0x80 >> (offset % 8)
+	}
+}
+
+// Test reports whether item may be in bitmap. False positives are possible,
+// false negatives are not.
+func (f BloomFilter) Test(bitmap []byte, item string) bool {
+	for _, offset := range f.Locations(item) {
+		if bitmap[offset/8]&(0x80>>(offset%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package util_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestNewBloomFilter(t *testing.T) {
+	filter := util.NewBloomFilter(1_000_000, 0.001)
+	require.InDelta(t, 14_377_588, filter.Bits, 1)
+	require.Equal(t, uint32(10), filter.Hashes)
+
+	filter = util.NewBloomFilter(0, 0.01)
+	require.NotZero(t, filter.Bits)
+	require.NotZero(t, filter.Hashes)
+}
+
+func TestBloomFilterLocations(t *testing.T) {
+	filter := util.NewBloomFilter(1000, 0.01)
+
+	locations := filter.Locations("abc")
+	require.Len(t, locations, int(filter.Hashes))
+	require.Equal(t, locations, filter.Locations("abc"))
+	for _, offset := range locations {
+		require.Less(t, offset, filter.Bits)
+	}
+}
+
+func TestBloomFilterFalsePositiveRate(t *testing.T) {
+	n := 10_000
+	filter := util.NewBloomFilter(uint64(n), 0.01)
+	bitmap := filter.NewBitmap()
+
+	for i := 0; i < n; i++ {
+		filter.Add(bitmap, fmt.Sprintf("member-%d", i))
+	}
+	for i := 0; i < n; i++ {
+		require.True(t, filter.Test(bitmap, fmt.Sprintf("member-%d", i)))
+	}
+
+	falsePositives := 0
+	for i := 0; i < n; i++ {
+		if filter.Test(bitmap, fmt.Sprintf("outsider-%d", i)) {
+			falsePositives++
+		}
+	}
+	require.Less(t, float64(falsePositives)/float64(n), 0.02)
+}
@@ -0,0 +1,14 @@
+package util
+
+import "strings"
+
+// MatchCategory reports whether category falls under target. IAB categories
+// are hierarchical, so a target of IAB1 also covers IAB1-2.
+func MatchCategory(target, category string) bool {
+	target = strings.ToLower(strings.TrimSpace(target))
+	category = strings.ToLower(strings.TrimSpace(category))
+	if target == "" || category == "" {
+		return false
+	}
+	return category == target || strings.HasPrefix(category, target+"-")
+}
@@ -0,0 +1,29 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestMatchCategory(t *testing.T) {
+	testCases := []struct {
+		target   string
+		category string
+		match    bool
+	}{
+		{"IAB1", "IAB1", true},
+		{"IAB1", "IAB1-2", true},
+		{"iab1", "IAB1-2", true},
+		{"IAB1-2", "IAB1", false},
+		{"IAB1", "IAB10", false},
+		{"IAB1", "IAB10-1", false},
+		{"IAB1", "", false},
+		{"", "IAB1", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.match, util.MatchCategory(tc.target, tc.category), "%s ~ %s", tc.target, tc.category)
+	}
+}
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -8,6 +10,21 @@ type Config struct {
 	DBSource      string `mapstructure:"DB_SOURCE"`
 	RedisSource   string `mapstructure:"REDIS_SOURCE"`
 	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	// OIDCJWKSSource is the file path or URL of the identity provider's
+	// signing keys. Login is disabled when it is empty.
+	OIDCJWKSSource      string        `mapstructure:"OIDC_JWKS_SOURCE"`
+	OIDCIssuer          string        `mapstructure:"OIDC_ISSUER"`
+	OIDCAudience        string        `mapstructure:"OIDC_AUDIENCE"`
+	OIDCRoleClaim       string        `mapstructure:"OIDC_ROLE_CLAIM"`
+	OIDCRoleMap         string        `mapstructure:"OIDC_ROLE_MAP"`
+	OIDCAdvertiserClaim string        `mapstructure:"OIDC_ADVERTISER_CLAIM"`
+	SessionDuration     time.Duration `mapstructure:"SESSION_DURATION"`
+	// CampaignRetention is how long deleted campaigns can be restored before
+	// they are purged. Zero keeps them forever.
+	CampaignRetention time.Duration `mapstructure:"CAMPAIGN_RETENTION"`
+	// WebhookAllowPrivateNetworks lets webhooks be sent to loopback, private
+	// and link-local addresses, which are refused by default.
+	WebhookAllowPrivateNetworks bool `mapstructure:"WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -15,6 +32,16 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetConfigName("app")
 	viper.SetConfigType("env")
 
+	viper.SetDefault("OIDC_ROLE_CLAIM", "groups")
+	viper.SetDefault("OIDC_ADVERTISER_CLAIM", "advertiser")
+	viper.SetDefault("SESSION_DURATION", 15*time.Minute)
+	viper.SetDefault("CAMPAIGN_RETENTION", 30*24*time.Hour)
+	viper.SetDefault("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false)
+	// Unmarshal only sees environment variables for keys viper already knows.
+	for _, key := range []string{"OIDC_JWKS_SOURCE", "OIDC_ISSUER", "OIDC_AUDIENCE", "OIDC_ROLE_MAP"} {
+		viper.SetDefault(key, "")
+	}
+
 	viper.AutomaticEnv()
 
 	err = viper.ReadInConfig()
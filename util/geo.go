@@ -0,0 +1,129 @@
+package util
+
+import (
+	"math"
+)
+
+const (
+	earthRadiusKm = 6371.0
+	kmPerDegree   = math.Pi * earthRadiusKm / 180
+
+	// maxGridCells caps how many cells a single circle may occupy. Larger
+	// circles are checked on every lookup instead of being bucketed.
+	maxGridCells = 1024
+)
+
+// HaversineKm returns the great-circle distance between two points in kilometres.
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Circle is a point with a radius in kilometres.
+type Circle struct {
+	Lat      float64
+	Lon      float64
+	RadiusKm float64
+}
+
+// Contains reports whether the point lies within the circle.
+func (c Circle) Contains(lat, lon float64) bool {
+	return HaversineKm(c.Lat, c.Lon, lat, lon) <= c.RadiusKm
+}
+
+type gridCell struct {
+	lat int
+	lon int
+}
+
+// GeoGrid buckets circles into fixed size lat/lon cells, so a point lookup
+// only measures distance to the circles whose bounding box covers its cell.
+type GeoGrid struct {
+	cellDeg float64
+	lonCell int
+	circles []Circle
+	cells   map[gridCell][]int
+	wide    []int
+}
+
+// NewGeoGrid indexes circles into cells of cellDeg degrees.
+func NewGeoGrid(circles []Circle, cellDeg float64) *GeoGrid {
+	grid := &GeoGrid{
+		cellDeg: cellDeg,
+		lonCell: int(math.Ceil(360 / cellDeg)),
+		circles: circles,
+		cells:   make(map[gridCell][]int),
+	}
+
+	for i, circle := range circles {
+		grid.insert(i, circle)
+	}
+	return grid
+}
+
+func (g *GeoGrid) insert(i int, circle Circle) {
+	dLat := circle.RadiusKm / kmPerDegree
+	minLat, maxLat := circle.Lat-dLat, circle.Lat+dLat
+
+	// Near the poles or for very large circles the longitude span of the
+	// bounding box degenerates, so those circles are always checked.
+	cosLat := math.Cos(math.Max(math.Abs(minLat), math.Abs(maxLat)) * math.Pi / 180)
+	if minLat <= -90 || maxLat >= 90 || cosLat <= 0 {
+		g.wide = append(g.wide, i)
+		return
+	}
+	dLon := dLat / cosLat
+	if dLon >= 180 {
+		g.wide = append(g.wide, i)
+		return
+	}
+
+	fromLat, toLat := g.latIndex(minLat), g.latIndex(maxLat)
+	fromLon, toLon := g.rawLonIndex(circle.Lon-dLon), g.rawLonIndex(circle.Lon+dLon)
+	if (toLat-fromLat+1)*(toLon-fromLon+1) > maxGridCells {
+		g.wide = append(g.wide, i)
+		return
+	}
+
+	for lat := fromLat; lat <= toLat; lat++ {
+		for lon := fromLon; lon <= toLon; lon++ {
+			cell := gridCell{lat: lat, lon: g.wrapLon(lon)}
+			g.cells[cell] = append(g.cells[cell], i)
+		}
+	}
+}
+
+func (g *GeoGrid) latIndex(lat float64) int {
+	return int(math.Floor((math.Max(-90, math.Min(90, lat)) + 90) / g.cellDeg))
+}
+
+func (g *GeoGrid) rawLonIndex(lon float64) int {
+	return int(math.Floor((lon + 180) / g.cellDeg))
+}
+
+func (g *GeoGrid) wrapLon(index int) int {
+	return ((index % g.lonCell) + g.lonCell) % g.lonCell
+}
+
+// Contains reports whether the point lies within any of the circles.
+func (g *GeoGrid) Contains(lat, lon float64) bool {
+	for _, i := range g.wide {
+		if g.circles[i].Contains(lat, lon) {
+			return true
+		}
+	}
+
+	cell := gridCell{lat: g.latIndex(lat), lon: g.wrapLon(g.rawLonIndex(lon))}
+	for _, i := range g.cells[cell] {
+		if g.circles[i].Contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
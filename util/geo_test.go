@@ -0,0 +1,71 @@
+package util_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestHaversineKm(t *testing.T) {
+	// Paris to London.
+	require.InDelta(t, 343.5, util.HaversineKm(48.8566, 2.3522, 51.5074, -0.1278), 1)
+	require.Zero(t, util.HaversineKm(12.97, 77.59, 12.97, 77.59))
+	// Across the antimeridian.
+	require.InDelta(t, 111.2, util.HaversineKm(0, 179.5, 0, -179.5), 0.5)
+}
+
+func TestGeoGrid(t *testing.T) {
+	circles := []util.Circle{
+		{Lat: 40.7128, Lon: -74.0060, RadiusKm: 10},    // New York
+		{Lat: 0, Lon: 179.9, RadiusKm: 50},             // antimeridian
+		{Lat: 89.5, Lon: 0, RadiusKm: 100},             // near the pole
+		{Lat: -33.8688, Lon: 151.2093, RadiusKm: 5000}, // wider than the cell cap
+	}
+	grid := util.NewGeoGrid(circles, 1)
+
+	require.True(t, grid.Contains(40.73, -73.99))
+	require.False(t, grid.Contains(40.9, -74.5))
+	require.True(t, grid.Contains(0.1, -179.9))
+	require.True(t, grid.Contains(89.9, 120))
+	require.True(t, grid.Contains(-37.8136, 144.9631))
+	require.False(t, grid.Contains(51.5074, -0.1278))
+}
+
+func TestGeoGridMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	circles := make([]util.Circle, 300)
+	for i := range circles {
+		circles[i] = util.Circle{
+			Lat:      r.Float64()*170 - 85,
+			Lon:      r.Float64()*360 - 180,
+			RadiusKm: r.Float64() * 300,
+		}
+	}
+	grid := util.NewGeoGrid(circles, 1)
+
+	for i := 0; i < 5000; i++ {
+		lat, lon := r.Float64()*180-90, r.Float64()*360-180
+		if i%2 == 0 {
+			// Land near a circle so both outcomes are exercised.
+			c := circles[r.Intn(len(circles))]
+			lat, lon = c.Lat+r.Float64()*4-2, c.Lon+r.Float64()*4-2
+			if lon >= 180 {
+				lon -= 360
+			} else if lon < -180 {
+				lon += 360
+			}
+		}
+
+		expected := false
+		for _, c := range circles {
+			if c.Contains(lat, lon) {
+				expected = true
+				break
+			}
+		}
+		require.Equal(t, expected, grid.Contains(lat, lon), "lat %f lon %f", lat, lon)
+	}
+}
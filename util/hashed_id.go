@@ -0,0 +1,19 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var hashedID = regexp.MustCompile(`^[0-9a-f]{32,128}$`)
+
+// NormalizeHashedID lowercases a hex encoded device ID hash such as an MD5,
+// SHA-1 or SHA-256 digest, and rejects anything else.
+func NormalizeHashedID(s string) (string, error) {
+	id := strings.ToLower(strings.TrimSpace(s))
+	if !hashedID.MatchString(id) {
+		return "", fmt.Errorf("invalid hashed id %q", s)
+	}
+	return id, nil
+}
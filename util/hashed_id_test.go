@@ -0,0 +1,19 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestNormalizeHashedID(t *testing.T) {
+	id, err := util.NormalizeHashedID(" 9E107D9D372BB6826BD81D3542A419D6 ")
+	require.NoError(t, err)
+	require.Equal(t, "9e107d9d372bb6826bd81d3542a419d6", id)
+
+	for _, input := range []string{"", "device-123", "9e107d9d372bb6826bd81d3542a419", "zz107d9d372bb6826bd81d3542a419d6"} {
+		_, err := util.NormalizeHashedID(input)
+		require.Error(t, err, input)
+	}
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+func parseLocale(tag string) (language.Tag, error) {
+	return language.Parse(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// NormalizeLocale returns the canonical BCP 47 form of tag, e.g. "pt_br" -> "pt-BR".
+func NormalizeLocale(tag string) (string, error) {
+	t, err := parseLocale(tag)
+	if err != nil {
+		return "", err
+	}
+	return t.String(), nil
+}
+
+// LocaleFallbackChain lists tag followed by its progressively less specific
+// forms, e.g. "zh-Hant-TW" -> ["zh-Hant-TW", "zh-Hant", "zh"]. An empty or
+// invalid tag yields an empty chain.
+func LocaleFallbackChain(tag string) []string {
+	t, err := parseLocale(tag)
+	if err != nil {
+		return nil
+	}
+
+	var chain []string
+	add := func(t language.Tag, err error) {
+		s := t.String()
+		if err == nil && s != "und" && !slices.Contains(chain, s) {
+			chain = append(chain, s)
+		}
+	}
+
+	base, script, region := t.Raw()
+	add(t, nil)
+	add(language.Compose(base, script, region))
+	add(language.Compose(base, script))
+	add(language.Compose(base))
+	return chain
+}
+
+// PreferredLanguage returns the highest weighted language of an
+// Accept-Language header, or an empty string if there is none.
+func PreferredLanguage(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return ""
+	}
+	for _, t := range tags {
+		if t != language.Und && t != language.MustParse("mul") {
+			return t.String()
+		}
+	}
+	return ""
+}
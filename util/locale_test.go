@@ -0,0 +1,42 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	testCases := map[string]string{
+		"pt-BR":      "pt-BR",
+		"pt_br":      "pt-BR",
+		"EN-us":      "en-US",
+		"zh-hant-tw": "zh-Hant-TW",
+	}
+
+	for input, expected := range testCases {
+		locale, err := util.NormalizeLocale(input)
+		require.NoError(t, err)
+		require.Equal(t, expected, locale)
+	}
+
+	_, err := util.NormalizeLocale("not a locale")
+	require.Error(t, err)
+}
+
+func TestLocaleFallbackChain(t *testing.T) {
+	require.Equal(t, []string{"pt-BR", "pt"}, util.LocaleFallbackChain("pt-BR"))
+	require.Equal(t, []string{"zh-Hant-TW", "zh-Hant", "zh"}, util.LocaleFallbackChain("zh-Hant-TW"))
+	require.Equal(t, []string{"de-DE-u-co-phonebk", "de-DE", "de"}, util.LocaleFallbackChain("de-DE-u-co-phonebk"))
+	require.Equal(t, []string{"en"}, util.LocaleFallbackChain("en"))
+	require.Empty(t, util.LocaleFallbackChain(""))
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	require.Equal(t, "fr-CH", util.PreferredLanguage("fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5"))
+	require.Equal(t, "en", util.PreferredLanguage("de;q=0.5, en"))
+	require.Equal(t, "de", util.PreferredLanguage("*, de;q=0.5"))
+	require.Equal(t, "", util.PreferredLanguage(""))
+	require.Equal(t, "", util.PreferredLanguage("*"))
+}
@@ -0,0 +1,48 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestMergePatch(t *testing.T) {
+	// The examples of RFC 7386, appendix A.
+	testCases := []struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tc := range testCases {
+		merged, err := util.MergePatch([]byte(tc.doc), []byte(tc.patch))
+		require.NoError(t, err)
+		require.JSONEq(t, tc.expected, string(merged))
+	}
+
+	_, err := util.MergePatch([]byte(`{}`), []byte(`{`))
+	require.Error(t, err)
+}
+
+func TestMergePatchKeepsNumbers(t *testing.T) {
+	merged, err := util.MergePatch([]byte(`{"id":9007199254740993,"lat":12.345678901}`), []byte(`{"name":"x"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":9007199254740993,"lat":12.345678901,"name":"x"}`, string(merged))
+}
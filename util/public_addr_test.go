@@ -0,0 +1,34 @@
+package util_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestPublicAddr(t *testing.T) {
+	for _, addr := range []string{"8.8.8.8", "203.0.114.1", "2606:4700::1111"} {
+		require.True(t, util.PublicAddr(netip.MustParseAddr(addr)), addr)
+	}
+
+	for _, addr := range []string{
+		"127.0.0.1",
+		"10.1.2.3",
+		"172.16.0.1",
+		"192.168.1.1",
+		"169.254.169.254",
+		"100.64.0.1",
+		"0.0.0.0",
+		"224.0.0.1",
+		"::1",
+		"::",
+		"fe80::1",
+		"fd00::1",
+		"::ffff:127.0.0.1",
+		"::ffff:169.254.169.254",
+	} {
+		require.False(t, util.PublicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
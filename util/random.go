@@ -116,3 +116,115 @@ func RandomRule() string {
 	n := len(rule)
 	return rule[r.Intn(n)]
 }
+
+func RandomVersionRange() string {
+	min := strconv.Itoa(int(RandomInt(1, 15)))
+	max := strconv.Itoa(int(RandomInt(16, 20)))
+	ranges := []string{">=" + min, "<" + min, min + ".x", min + ".0-" + max + ".x", ">=" + min + " <" + max}
+	return ranges[r.Intn(len(ranges))]
+}
+
+func RandomLanguage() string {
+	var sb strings.Builder
+	languages := []string{"en", "pt", "pt-BR", "es", "fr", "de", "hi", "ja"}
+
+	for i := range languages {
+		j := rand.Intn(i + 1)
+		languages[i], languages[j] = languages[j], languages[i]
+	}
+
+	n := int(RandomInt(1, 4))
+
+	for i := 0; i < n; i++ {
+		sb.WriteString(languages[i])
+		if i < n-1 {
+			sb.WriteString(", ")
+		}
+	}
+
+	return sb.String()
+}
+
+func RandomRegion() string {
+	var sb strings.Builder
+	regions := []string{"CA", "NY", "TX", "ON", "BC", "MH", "KA", "NSW", "SP", "BY"}
+
+	for i := range regions {
+		j := rand.Intn(i + 1)
+		regions[i], regions[j] = regions[j], regions[i]
+	}
+
+	n := int(RandomInt(1, 4))
+
+	for i := 0; i < n; i++ {
+		sb.WriteString(regions[i])
+		if i < n-1 {
+			sb.WriteString(", ")
+		}
+	}
+
+	return sb.String()
+}
+
+func RandomCity() string {
+	var sb strings.Builder
+	cities := []string{"Austin", "Toronto", "Mumbai", "Bengaluru", "Sydney", "Munich", "Osaka", "Lyon"}
+
+	for i := range cities {
+		j := rand.Intn(i + 1)
+		cities[i], cities[j] = cities[j], cities[i]
+	}
+
+	n := int(RandomInt(1, 4))
+
+	for i := 0; i < n; i++ {
+		sb.WriteString(cities[i])
+		if i < n-1 {
+			sb.WriteString(", ")
+		}
+	}
+
+	return sb.String()
+}
+
+func RandomCategory() string {
+	var sb strings.Builder
+	categories := []string{"IAB1", "IAB2", "IAB3-1", "IAB9", "IAB9-30", "IAB17", "IAB19-6", "IAB20"}
+
+	for i := range categories {
+		j := rand.Intn(i + 1)
+		categories[i], categories[j] = categories[j], categories[i]
+	}
+
+	n := int(RandomInt(1, 4))
+
+	for i := 0; i < n; i++ {
+		sb.WriteString(categories[i])
+		if i < n-1 {
+			sb.WriteString(", ")
+		}
+	}
+
+	return sb.String()
+}
+
+func RandomKeyword() string {
+	var sb strings.Builder
+	keywords := []string{"puzzle", "racing", "fitness", "recipes", "finance", "news", "music", "travel"}
+
+	for i := range keywords {
+		j := rand.Intn(i + 1)
+		keywords[i], keywords[j] = keywords[j], keywords[i]
+	}
+
+	n := int(RandomInt(1, 4))
+
+	for i := 0; i < n; i++ {
+		sb.WriteString(keywords[i])
+		if i < n-1 {
+			sb.WriteString(", ")
+		}
+	}
+
+	return sb.String()
+}
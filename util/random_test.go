@@ -111,6 +111,83 @@ func TestRandomRule(t *testing.T) {
 	require.Contains(t, rule, util.RandomRule())
 }
 
+func TestRandomLanguage(t *testing.T) {
+	allLanguages := []string{"en", "pt", "pt-BR", "es", "fr", "de", "hi", "ja"}
+
+	language := util.RandomLanguage()
+	languages := csvToSlice(language)
+
+	require.GreaterOrEqual(t, len(languages), 1)
+	require.LessOrEqual(t, len(languages), 4)
+
+	for _, language := range languages {
+		require.Contains(t, allLanguages, language)
+	}
+}
+
+func TestRandomRegion(t *testing.T) {
+	allRegions := []string{"CA", "NY", "TX", "ON", "BC", "MH", "KA", "NSW", "SP", "BY"}
+
+	region := util.RandomRegion()
+	regions := csvToSlice(region)
+
+	require.GreaterOrEqual(t, len(regions), 1)
+	require.LessOrEqual(t, len(regions), 4)
+
+	for _, region := range regions {
+		require.Contains(t, allRegions, region)
+	}
+}
+
+func TestRandomCity(t *testing.T) {
+	allCities := []string{"Austin", "Toronto", "Mumbai", "Bengaluru", "Sydney", "Munich", "Osaka", "Lyon"}
+
+	city := util.RandomCity()
+	cities := csvToSlice(city)
+
+	require.GreaterOrEqual(t, len(cities), 1)
+	require.LessOrEqual(t, len(cities), 4)
+
+	for _, city := range cities {
+		require.Contains(t, allCities, city)
+	}
+}
+
+func TestRandomCategory(t *testing.T) {
+	allCategories := []string{"IAB1", "IAB2", "IAB3-1", "IAB9", "IAB9-30", "IAB17", "IAB19-6", "IAB20"}
+
+	category := util.RandomCategory()
+	categories := csvToSlice(category)
+
+	require.GreaterOrEqual(t, len(categories), 1)
+	require.LessOrEqual(t, len(categories), 4)
+
+	for _, category := range categories {
+		require.Contains(t, allCategories, category)
+	}
+}
+
+func TestRandomKeyword(t *testing.T) {
+	allKeywords := []string{"puzzle", "racing", "fitness", "recipes", "finance", "news", "music", "travel"}
+
+	keyword := util.RandomKeyword()
+	keywords := csvToSlice(keyword)
+
+	require.GreaterOrEqual(t, len(keywords), 1)
+	require.LessOrEqual(t, len(keywords), 4)
+
+	for _, keyword := range keywords {
+		require.Contains(t, allKeywords, keyword)
+	}
+}
+
+func TestRandomVersionRange(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		_, err := util.ParseVersionRanges(util.RandomVersionRange())
+		require.NoError(t, err)
+	}
+}
+
 func csvToSlice(csv string) []string {
 	return strings.Split(csv, ", ")
 }
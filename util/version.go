@@ -0,0 +1,245 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a dotted numeric version such as 17.4.1.
+type Version []int
+
+var leadingVersion = regexp.MustCompile(`^\d+(\.\d+)*`)
+
+// ParseVersion extracts the leading dotted numeric part of s, so loose
+// versions like "17.4.1 (21E236)" or "v3.2.0-beta" are still comparable.
+func ParseVersion(s string) (Version, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+
+	match := leadingVersion.FindString(s)
+	if match == "" {
+		return nil, fmt.Errorf("invalid version %q", s)
+	}
+
+	parts := strings.Split(match, ".")
+	version := make(Version, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %v", s, err)
+		}
+		version[i] = n
+	}
+	return version, nil
+}
+
+// Compare returns -1, 0 or 1. Missing components count as zero, so 10 == 10.0.0.
+func (v Version) Compare(other Version) int {
+	n := max(len(v), len(other))
+	for i := 0; i < n; i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(other) {
+			b = other[i]
+		}
+		if a < b {
+			return -1
+		}
+		if a > b {
+			return 1
+		}
+	}
+	return 0
+}
+
+// next returns the smallest version that no longer has v as a prefix,
+// e.g. 17 -> 18 and 15.0 -> 15.1. An empty prefix has no upper bound.
+func (v Version) next() Version {
+	if len(v) == 0 {
+		return nil
+	}
+	next := append(Version{}, v...)
+	next[len(next)-1]++
+	return next
+}
+
+// versionInterval is the half-open interval [min, max). A nil bound is unbounded.
+type versionInterval struct {
+	min Version
+	max Version
+}
+
+func (i versionInterval) contains(v Version) bool {
+	if i.min != nil && v.Compare(i.min) < 0 {
+		return false
+	}
+	if i.max != nil && v.Compare(i.max) >= 0 {
+		return false
+	}
+	return true
+}
+
+func (i *versionInterval) raiseMin(v Version) {
+	if i.min == nil || v.Compare(i.min) > 0 {
+		i.min = v
+	}
+}
+
+func (i *versionInterval) lowerMax(v Version) {
+	if v == nil {
+		return
+	}
+	if i.max == nil || v.Compare(i.max) < 0 {
+		i.max = v
+	}
+}
+
+// VersionRanges is a parsed version range expression.
+//
+// The expression is a comma separated list of alternatives, any of which may
+// match. Each alternative is a space separated list of comparators that must
+// all hold:
+//
+//	>=10            10 and above
+//	>=3.2 <4        3.2 up to, but excluding, 4
+//	15.0-17.x       15.0 through every 17.x release
+//	17 or 17.x      any 17.x release
+//	*               any version
+//
+// Supported operators are >=, >, <=, < and =. Partial versions and x/* wildcards
+// stand for every version sharing that prefix, so <=17 admits 17.4.1 and >17
+// starts at 18.
+type VersionRanges []versionInterval
+
+var (
+	operatorSpacing = regexp.MustCompile(`(>=|<=|>|<|=)\s+`)
+	hyphenSpacing   = regexp.MustCompile(`\s*[-–]\s*`)
+)
+
+// ParseVersionRanges parses a range expression. See VersionRanges for the syntax.
+func ParseVersionRanges(expr string) (VersionRanges, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty version range")
+	}
+
+	var ranges VersionRanges
+	for _, alternative := range strings.Split(expr, ",") {
+		alternative = strings.TrimSpace(alternative)
+		if alternative == "" {
+			return nil, fmt.Errorf("invalid version range %q: empty alternative", expr)
+		}
+
+		alternative = operatorSpacing.ReplaceAllString(alternative, "$1")
+		alternative = hyphenSpacing.ReplaceAllString(alternative, "-")
+
+		var interval versionInterval
+		for _, comparator := range strings.Fields(alternative) {
+			if err := interval.apply(comparator); err != nil {
+				return nil, fmt.Errorf("invalid version range %q: %v", expr, err)
+			}
+		}
+		ranges = append(ranges, interval)
+	}
+	return ranges, nil
+}
+
+func (i *versionInterval) apply(comparator string) error {
+	if lower, upper, ok := strings.Cut(comparator, "-"); ok {
+		from, err := parseVersionPattern(lower)
+		if err != nil {
+			return err
+		}
+		to, err := parseVersionPattern(upper)
+		if err != nil {
+			return err
+		}
+		i.raiseMin(from)
+		i.lowerMax(to.next())
+		return nil
+	}
+
+	operator := ""
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(comparator, op) {
+			operator = op
+			break
+		}
+	}
+
+	pattern, err := parseVersionPattern(strings.TrimPrefix(comparator, operator))
+	if err != nil {
+		return err
+	}
+	if operator != "" && len(pattern) == 0 {
+		return fmt.Errorf("operator %s needs a version", operator)
+	}
+
+	switch operator {
+	case ">=":
+		i.raiseMin(pattern)
+	case ">":
+		i.raiseMin(pattern.next())
+	case "<":
+		i.lowerMax(pattern)
+	case "<=":
+		i.lowerMax(pattern.next())
+	default:
+		i.raiseMin(pattern)
+		i.lowerMax(pattern.next())
+	}
+	return nil
+}
+
+// parseVersionPattern parses a strict version that may end in x or * wildcards
+// and returns its numeric prefix.
+func parseVersionPattern(s string) (Version, error) {
+	if s == "" {
+		return nil, fmt.Errorf("missing version")
+	}
+
+	var prefix Version
+	wildcard := false
+	for _, part := range strings.Split(s, ".") {
+		if part == "x" || part == "X" || part == "*" {
+			wildcard = true
+			continue
+		}
+		if wildcard {
+			return nil, fmt.Errorf("version %q has digits after a wildcard", s)
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q", s)
+		}
+		prefix = append(prefix, n)
+	}
+	return prefix, nil
+}
+
+// Contains reports whether v falls in any of the ranges.
+func (r VersionRanges) Contains(v Version) bool {
+	for _, interval := range r {
+		if interval.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchVersion reports whether version satisfies the range expression. An
+// invalid expression or an unparseable version never matches.
+func MatchVersion(expr string, version string) bool {
+	ranges, err := ParseVersionRanges(expr)
+	if err != nil {
+		return false
+	}
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+	return ranges.Contains(v)
+}
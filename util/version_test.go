@@ -0,0 +1,84 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+func TestParseVersion(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected util.Version
+	}{
+		{"10", util.Version{10}},
+		{"17.4.1", util.Version{17, 4, 1}},
+		{"17.4.1 (21E236)", util.Version{17, 4, 1}},
+		{"v3.2.0-beta", util.Version{3, 2, 0}},
+		{" 14 ", util.Version{14}},
+	}
+
+	for _, tc := range testCases {
+		version, err := util.ParseVersion(tc.input)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, version)
+	}
+
+	for _, input := range []string{"", "beta", "(21E236)"} {
+		_, err := util.ParseVersion(input)
+		require.Error(t, err)
+	}
+}
+
+func TestCompareVersion(t *testing.T) {
+	require.Equal(t, 0, util.Version{10}.Compare(util.Version{10, 0, 0}))
+	require.Equal(t, -1, util.Version{9, 9}.Compare(util.Version{10}))
+	require.Equal(t, 1, util.Version{17, 4, 1}.Compare(util.Version{17, 4}))
+}
+
+func TestParseVersionRanges(t *testing.T) {
+	valid := []string{">=10", ">= 10", "15.0-17.x", "15.0 – 17.x", ">=3.2 <4", "17.x", "*", "<=13, >=15", "=3.2.1"}
+	for _, expr := range valid {
+		_, err := util.ParseVersionRanges(expr)
+		require.NoError(t, err, expr)
+	}
+
+	invalid := []string{"", "abc", ">=", "10,", "1.x.2", ">=10-12", "-5", "15.0-"}
+	for _, expr := range invalid {
+		_, err := util.ParseVersionRanges(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+func TestMatchVersion(t *testing.T) {
+	testCases := []struct {
+		expr    string
+		version string
+		match   bool
+	}{
+		{">=10", "10", true},
+		{">=10", "14.1", true},
+		{">=10", "9.0.1", false},
+		{"15.0-17.x", "15.0", true},
+		{"15.0-17.x", "17.4.1 (21E236)", true},
+		{"15.0-17.x", "18.0", false},
+		{"15.0-17.x", "14.8", false},
+		{">=3.2 <4", "3.10", true},
+		{">=3.2 <4", "4.0", false},
+		{"17", "17.4.1", true},
+		{"17", "16.9", false},
+		{"<=17", "17.4.1", true},
+		{">17", "17.9", false},
+		{">17", "18", true},
+		{"<=13, >=15", "14", false},
+		{"<=13, >=15", "15.1", true},
+		{"*", "1", true},
+		{">=10", "", false},
+		{">=10", "unknown", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.match, util.MatchVersion(tc.expr, tc.version), "%s ~ %s", tc.expr, tc.version)
+	}
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+
+	"github.com/vivek-344/AdRouter/util"
+)
+
+// CheckURL rejects a webhook URL whose host resolves to an address that is
+// not public, so that subscriptions cannot make the worker reach into the
+// network of the server.
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := u.Hostname()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !util.PublicAddr(addr) {
+			return fmt.Errorf("%s resolves to %s, which is not a public address", host, addr.Unmap())
+		}
+	}
+	return nil
+}
+
+// dialPublic refuses connections to addresses that are not public. Checked
+// at dial time, it also stops hosts that resolve elsewhere than when their
+// subscription was created, and redirects.
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !util.PublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("cannot deliver to %s, which is not a public address", addrPort.Addr().Unmap())
+	}
+	return nil
+}
+
+// newPublicClient returns a client that only connects to public addresses.
+// It connects directly, as the address of a proxy would be checked instead of
+// that of the receiver.
+func newPublicClient() *http.Client {
+	dialer := &net.Dialer{Timeout: requestTimeout, Control: dialPublic}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,35 @@
+package webhook_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+)
+
+var testStore db.Store
+
+func TestMain(m *testing.M) {
+	config, err := util.LoadConfig("..")
+	if err != nil {
+		log.Fatal("cannot load config: ", err)
+	}
+
+	testDB, err := db.NewPool(context.Background(), config.DBSource)
+	if err != nil {
+		log.Fatal("cannot connect to the database", err)
+	}
+
+	opt, err := redis.ParseURL(config.RedisSource)
+	if err != nil {
+		panic(err)
+	}
+
+	testStore = db.NewStore(testDB, redis.NewClient(opt))
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+)
+
+// Headers sent with every delivery. The delivery ID changes when a dead
+// letter is redelivered; the id in the payload stays the same.
+const (
+	EventHeader     = "X-AdRouter-Event"
+	DeliveryHeader  = "X-AdRouter-Delivery"
+	TimestampHeader = "X-AdRouter-Timestamp"
+	SignatureHeader = "X-AdRouter-Signature"
+)
+
+const (
+	pollInterval = 5 * time.Second
+	batchSize    = 100
+	// requestTimeout bounds a single attempt. Claimed deliveries are held
+	// off a little longer, so that no other worker sends them meanwhile.
+	requestTimeout = 10 * time.Second
+	leaseDuration  = requestTimeout + 30*time.Second
+)
+
+// Worker sends the queued webhook deliveries. Failed attempts are retried
+// with exponential backoff, and deliveries that run out of attempts move to
+// the dead letters.
+type Worker struct {
+	store       db.Store
+	client      *http.Client
+	maxAttempts int32
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// NewWorker returns a worker making up to 10 attempts per delivery, waiting
+// 30 seconds after the first failure and twice as long after each further
+// one, up to 6 hours. It only delivers to public addresses.
+func NewWorker(store db.Store) *Worker {
+	return &Worker{
+		store:       store,
+		client:      newPublicClient(),
+		maxAttempts: 10,
+		baseDelay:   30 * time.Second,
+		maxDelay:    6 * time.Hour,
+	}
+}
+
+// AllowPrivateNetworks lets the worker deliver to loopback, private and
+// link-local addresses, for receivers running next to the server in
+// development and tests.
+func (worker *Worker) AllowPrivateNetworks() *Worker {
+	worker.client = &http.Client{}
+	return worker
+}
+
+// Run delivers the webhooks that are due every few seconds until ctx is done.
+func (worker *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := worker.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Println("cannot deliver webhooks:", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends every delivery that is due, in batches sent concurrently.
+// It returns how many were received.
+func (worker *Worker) DeliverDue(ctx context.Context) (int, error) {
+	received := 0
+	for {
+		deliveries, err := worker.store.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+			LeaseUntil: time.Now().Add(leaseDuration),
+			RowLimit:   batchSize,
+		})
+		if err != nil {
+			return received, err
+		}
+
+		// A subscription that cannot be read, such as one deleted since its
+		// deliveries were claimed, fails the attempts of its deliveries
+		// alone; they are settled like any other failure.
+		subscriptions := make(map[int32]db.WebhookSubscription)
+		lookupErrs := make(map[int32]error)
+		for _, delivery := range deliveries {
+			id := delivery.SubscriptionID
+			if _, ok := subscriptions[id]; ok || lookupErrs[id] != nil {
+				continue
+			}
+			subscription, err := worker.store.GetWebhookSubscription(ctx, id)
+			if err != nil {
+				lookupErrs[id] = fmt.Errorf("cannot read subscription %d: %w", id, err)
+				continue
+			}
+			subscriptions[id] = subscription
+		}
+
+		results := make([]error, len(deliveries))
+		var wg sync.WaitGroup
+		for i, delivery := range deliveries {
+			if err := lookupErrs[delivery.SubscriptionID]; err != nil {
+				results[i] = err
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = worker.send(ctx, subscriptions[delivery.SubscriptionID], delivery)
+			}()
+		}
+		wg.Wait()
+
+		for i, delivery := range deliveries {
+			if results[i] == nil {
+				received++
+			}
+			if err := worker.settle(ctx, delivery, results[i]); err != nil {
+				return received, err
+			}
+		}
+		if len(deliveries) < batchSize {
+			return received, nil
+		}
+	}
+}
+
+// send makes one attempt at a delivery. Receivers must answer with a 2xx
+// status for it to count as received.
+func (worker *Worker) send(ctx context.Context, subscription db.WebhookSubscription, delivery db.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	rsp, err := worker.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(rsp.Body, 64<<10))
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fmt.Errorf("receiver answered %s", rsp.Status)
+	}
+	return nil
+}
+
+// settle records the outcome of an attempt: a received delivery is removed,
+// a failed one is retried later or, after its last attempt, dead-lettered.
+func (worker *Worker) settle(ctx context.Context, delivery db.WebhookDelivery, sendErr error) error {
+	switch {
+	case sendErr == nil:
+		return worker.store.DeleteWebhookDelivery(ctx, delivery.ID)
+	case delivery.Attempts >= worker.maxAttempts:
+		return worker.store.DeadLetterWebhookDelivery(ctx, delivery.ID, sendErr.Error())
+	default:
+		return worker.store.RetryWebhookDelivery(ctx, db.RetryWebhookDeliveryParams{
+			ID:            delivery.ID,
+			NextAttemptAt: time.Now().Add(worker.backoff(delivery.Attempts)),
+			LastError:     sendErr.Error(),
+		})
+	}
+}
+
+// backoff returns how long to wait after the given number of failed attempts.
+func (worker *Worker) backoff(attempts int32) time.Duration {
+	delay := worker.baseDelay
+	for i := int32(1); i < attempts && delay < worker.maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, worker.maxDelay)
+}
+
+// Sign returns the signature sent with a payload at timestamp: "sha256="
+// followed by the hex HMAC-SHA256, keyed with the subscription secret, of the
+// timestamp, a dot and the payload. Signing the timestamp lets receivers
+// reject old payloads replayed to them.
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of a payload sent at
+// timestamp, comparing in constant time.
+func Verify(secret string, timestamp string, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
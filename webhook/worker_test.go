@@ -0,0 +1,208 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/vivek-344/AdRouter/db/sqlc"
+	"github.com/vivek-344/AdRouter/util"
+	"github.com/vivek-344/AdRouter/webhook"
+)
+
+type receivedEvent struct {
+	ID       string        `json:"id"`
+	Event    string        `json:"event"`
+	Campaign db.Campaign   `json:"campaign"`
+	Previous db.StatusType `json:"previous_status"`
+}
+
+// subscribe creates an advertiser with a subscription to every event, sent
+// to handler.
+func subscribe(t *testing.T, secret string, handler http.HandlerFunc) db.Advertiser {
+	receiver := httptest.NewServer(handler)
+	t.Cleanup(receiver.Close)
+
+	advertiser, err := testStore.CreateAdvertiser(context.Background(), util.RandomName())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		testStore.DeleteAdvertiser(context.Background(), advertiser.ID)
+	})
+
+	_, err = testStore.CreateWebhookSubscription(context.Background(), db.CreateWebhookSubscriptionParams{
+		AdvertiserID: advertiser.ID,
+		Url:          receiver.URL,
+		Secret:       secret,
+		EventTypes: []string{
+			db.WebhookCampaignCreated,
+			db.WebhookCampaignUpdated,
+			db.WebhookCampaignStatusChanged,
+			db.WebhookCampaignDeleted,
+		},
+	})
+	require.NoError(t, err)
+	return advertiser
+}
+
+func addCampaign(t *testing.T, advertiserID int32) db.Campaign {
+	campaign, err := testStore.AddCampaign(context.Background(), db.AddCampaignParams{
+		AdvertiserID: advertiserID,
+		Cid:          util.RandomCid(),
+		Name:         util.RandomName(),
+		Img:          util.RandomImg(),
+		Cta:          util.RandomCta(),
+		Status:       db.NullStatusType{StatusType: db.StatusTypeActive, Valid: true},
+	})
+	require.NoError(t, err)
+	return campaign
+}
+
+func TestWorkerDeliversSignedEvents(t *testing.T) {
+	secret := util.RandomString(32)
+	var mu sync.Mutex
+	events := make(map[string]receivedEvent)
+	advertiser := subscribe(t, secret, func(w http.ResponseWriter, r *http.Request) {
+		// Payloads that fail the checks are refused, and so never recorded.
+		body, err := io.ReadAll(r.Body)
+		if err != nil || !webhook.Verify(secret, r.Header.Get(webhook.TimestampHeader), body, r.Header.Get(webhook.SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event receivedEvent
+		if json.Unmarshal(body, &event) != nil || event.Event != r.Header.Get(webhook.EventHeader) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		events[event.Event] = event
+		mu.Unlock()
+	})
+
+	campaign := addCampaign(t, advertiser.ID)
+	key := campaign.Key()
+	paused, err := testStore.TransitionCampaign(context.Background(), db.TransitionCampaignParams{
+		AdvertiserID: key.AdvertiserID,
+		Cid:          key.Cid,
+		Transition:   db.TransitionPause,
+	})
+	require.NoError(t, err)
+	err = testStore.DeleteVersionedCampaign(context.Background(), key, paused.Version)
+	require.NoError(t, err)
+
+	received, err := webhook.NewWorker(testStore).AllowPrivateNetworks().DeliverDue(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, received, 3)
+
+	// The version bump and the status change of the pause make one event.
+	require.Len(t, events, 3)
+	require.Equal(t, campaign.Cid, events[db.WebhookCampaignCreated].Campaign.Cid)
+	require.Equal(t, db.StatusTypePaused, events[db.WebhookCampaignStatusChanged].Campaign.Status)
+	require.Equal(t, db.StatusTypeActive, events[db.WebhookCampaignStatusChanged].Previous)
+	require.True(t, events[db.WebhookCampaignDeleted].Campaign.DeletedAt.Valid)
+
+	deliveries, err := testStore.ListWebhookDeliveries(context.Background(), advertiser.ID)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+func TestWorkerRetriesFailedDeliveries(t *testing.T) {
+	advertiser := subscribe(t, util.RandomString(32), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	campaign := addCampaign(t, advertiser.ID)
+
+	_, err := webhook.NewWorker(testStore).AllowPrivateNetworks().DeliverDue(context.Background())
+	require.NoError(t, err)
+
+	deliveries, err := testStore.ListWebhookDeliveries(context.Background(), advertiser.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, int32(1), deliveries[0].Attempts)
+	require.Contains(t, deliveries[0].LastError, "503")
+	require.True(t, deliveries[0].NextAttemptAt.After(time.Now()))
+
+	err = testStore.DeadLetterWebhookDelivery(context.Background(), deliveries[0].ID, deliveries[0].LastError)
+	require.NoError(t, err)
+	letters, err := testStore.ListWebhookDeadLetters(context.Background(), advertiser.ID)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	require.JSONEq(t, string(deliveries[0].Payload), string(letters[0].Payload))
+
+	redelivery, err := testStore.RedeliverWebhook(context.Background(), db.RedeliverWebhookParams{
+		AdvertiserID: advertiser.ID,
+		ID:           letters[0].ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, redelivery.Attempts)
+	letters, err = testStore.ListWebhookDeadLetters(context.Background(), advertiser.ID)
+	require.NoError(t, err)
+	require.Empty(t, letters)
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(campaign.Key()))
+}
+
+// unreadableSubscriptionStore fails to read one subscription, as if it was
+// deleted after its deliveries were claimed.
+type unreadableSubscriptionStore struct {
+	db.Store
+	id int32
+}
+
+func (store unreadableSubscriptionStore) GetWebhookSubscription(ctx context.Context, id int32) (db.WebhookSubscription, error) {
+	if id == store.id {
+		return db.WebhookSubscription{}, errors.New("subscription unavailable")
+	}
+	return store.Store.GetWebhookSubscription(ctx, id)
+}
+
+func TestWorkerSettlesUnreadableSubscriptions(t *testing.T) {
+	var received atomic.Int32
+	healthy := subscribe(t, util.RandomString(32), func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+	})
+	broken := subscribe(t, util.RandomString(32), func(w http.ResponseWriter, r *http.Request) {
+		t.Error("delivered to a subscription that could not be read")
+	})
+	subscriptions, err := testStore.ListWebhookSubscriptions(context.Background(), broken.ID)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+
+	healthyCampaign := addCampaign(t, healthy.ID)
+	brokenCampaign := addCampaign(t, broken.ID)
+
+	store := unreadableSubscriptionStore{Store: testStore, id: subscriptions[0].ID}
+	_, err = webhook.NewWorker(store).AllowPrivateNetworks().DeliverDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int32(1), received.Load())
+
+	deliveries, err := testStore.ListWebhookDeliveries(context.Background(), healthy.ID)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+
+	// The delivery of the other subscription is retried later.
+	deliveries, err = testStore.ListWebhookDeliveries(context.Background(), broken.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, int32(1), deliveries[0].Attempts)
+	require.Contains(t, deliveries[0].LastError, "subscription unavailable")
+
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(healthyCampaign.Key()))
+	testStore.DeleteCampaign(context.Background(), db.DeleteCampaignParams(brokenCampaign.Key()))
+}
+
+func TestSign(t *testing.T) {
+	payload := []byte(`{"event":"campaign.created"}`)
+	signature := webhook.Sign("secret", "1700000000", payload)
+	require.Regexp(t, "^sha256=[0-9a-f]{64}$", signature)
+	require.True(t, webhook.Verify("secret", "1700000000", payload, signature))
+	require.False(t, webhook.Verify("other", "1700000000", payload, signature))
+	require.False(t, webhook.Verify("secret", "1700000001", payload, signature))
+}